@@ -231,6 +231,7 @@ func TestSupervisorStartsCollectorWithRemoteConfig(t *testing.T) {
 func TestSupervisorRestartsCollectorAfterBadConfig(t *testing.T) {
 	var healthReport atomic.Value
 	var agentConfig atomic.Value
+	var remoteConfigStatus atomic.Value
 	server := newOpAMPServer(
 		t,
 		defaultConnectingHandler,
@@ -239,6 +240,9 @@ func TestSupervisorRestartsCollectorAfterBadConfig(t *testing.T) {
 				if message.Health != nil {
 					healthReport.Store(message.Health)
 				}
+				if message.RemoteConfigStatus != nil {
+					remoteConfigStatus.Store(message.RemoteConfigStatus)
+				}
 				if message.EffectiveConfig != nil {
 					config := message.EffectiveConfig.ConfigMap.ConfigMap[""]
 					if config != nil {
@@ -289,6 +293,19 @@ func TestSupervisorRestartsCollectorAfterBadConfig(t *testing.T) {
 		return false
 	}, 5*time.Second, 250*time.Millisecond, "Supervisor never reported that the Collector was unhealthy")
 
+	require.Eventually(t, func() bool {
+		status, ok := remoteConfigStatus.Load().(*protobufs.RemoteConfigStatus)
+
+		return ok && bytes.Equal(status.LastRemoteConfigHash, hash) &&
+			status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED && status.ErrorMessage != ""
+	}, 10*time.Second, 250*time.Millisecond, "Supervisor never reported that the remote config failed")
+
+	require.Eventually(t, func() bool {
+		cfg, ok := agentConfig.Load().(string)
+
+		return ok && !strings.Contains(cfg, "doesntexist")
+	}, 5*time.Second, 250*time.Millisecond, "Collector was not reverted to the last known good config")
+
 	cfg, hash, _, _ = createSimplePipelineCollectorConf(t)
 
 	server.sendToSupervisor(&protobufs.ServerToAgent{
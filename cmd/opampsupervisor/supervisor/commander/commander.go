@@ -6,8 +6,6 @@ package commander
 import (
 	"context"
 	"errors"
-	"fmt"
-	"os"
 	"os/exec"
 	"sync/atomic"
 	"syscall"
@@ -18,6 +16,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
 )
 
+const (
+	// logFilePath is the file the Agent's stdout and stderr are written to.
+	logFilePath = "agent.log"
+	// maxLogFileSize is the size the log file is rotated at, keeping a single rotated file.
+	maxLogFileSize = 10 * 1024 * 1024
+)
+
 // Commander can start/stop/restart the Agent executable and also watch for a signal
 // for the Agent process to finish.
 type Commander struct {
@@ -25,6 +30,7 @@ type Commander struct {
 	cfg     *config.Agent
 	args    []string
 	cmd     *exec.Cmd
+	logFile *rotatingFile
 	doneCh  chan struct{}
 	running *atomic.Int64
 }
@@ -43,7 +49,7 @@ func NewCommander(logger *zap.Logger, cfg *config.Agent, args ...string) (*Comma
 }
 
 // Start the Agent and begin watching the process.
-// Agent's stdout and stderr are written to a file.
+// Agent's stdout and stderr are written to a file, rotated once it reaches maxLogFileSize.
 // Calling this method when a command is already running
 // is a no-op.
 func (c *Commander) Start(ctx context.Context) error {
@@ -54,10 +60,9 @@ func (c *Commander) Start(ctx context.Context) error {
 
 	c.logger.Debug("Starting agent", zap.String("agent", c.cfg.Executable))
 
-	logFilePath := "agent.log"
-	logFile, err := os.Create(logFilePath)
+	logFile, err := newRotatingFile(logFilePath, maxLogFileSize)
 	if err != nil {
-		return fmt.Errorf("cannot create %s: %w", logFilePath, err)
+		return err
 	}
 
 	c.cmd = exec.CommandContext(ctx, c.cfg.Executable, c.args...) // #nosec G204
@@ -67,9 +72,11 @@ func (c *Commander) Start(ctx context.Context) error {
 	c.cmd.Stdout = logFile
 	c.cmd.Stderr = logFile
 
+	c.logFile = logFile
 	c.doneCh = make(chan struct{})
 
 	if err := c.cmd.Start(); err != nil {
+		_ = logFile.Close()
 		return err
 	}
 
@@ -99,6 +106,9 @@ func (c *Commander) watch() {
 	if ok := errors.As(err, &exitError); err != nil && !ok {
 		c.logger.Error("An error occurred while watching the agent process", zap.Error(err))
 	}
+	if err := c.logFile.Close(); err != nil {
+		c.logger.Error("Could not close the agent log file", zap.Error(err))
+	}
 
 	c.running.Store(0)
 	close(c.doneCh)
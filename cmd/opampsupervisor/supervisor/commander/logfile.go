@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package commander
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a file which is rotated once it reaches its maximum size: it is renamed
+// with a ".1" suffix, replacing the previous rotated file, and a new file is started.
+type rotatingFile struct {
+	path    string
+	maxSize int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newRotatingFile(path string, maxSize int64) (*rotatingFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s: %w", path, err)
+	}
+	return &rotatingFile{path: path, maxSize: maxSize, file: file}, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate replaces the file by a new one. The lock must be held.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("cannot rotate %s: %w", r.path, err)
+	}
+	file, err := os.Create(r.path)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", r.path, err)
+	}
+	r.file = file
+	r.size = 0
+	return nil
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
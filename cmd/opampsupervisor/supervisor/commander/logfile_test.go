@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package commander
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.log")
+	f, err := newRotatingFile(path, 10)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "a line longer than the maximum size\n"} {
+		n, err := f.Write([]byte(line))
+		require.NoError(t, err)
+		assert.Equal(t, len(line), n)
+	}
+	require.NoError(t, f.Close())
+
+	// a write is never split, a file only exceeds the maximum size with a single write
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a line longer than the maximum size\n", string(content))
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(rotated))
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	const writers, linesPerWriter = 4, 25
+	path := filepath.Join(t.TempDir(), "agent.log")
+	// 9 bytes per line, the file is rotated once after 66 lines
+	f, err := newRotatingFile(path, 600)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < linesPerWriter; i++ {
+				_, err := f.Write([]byte(fmt.Sprintf("line %d%02d\n", w, i)))
+				assert.NoError(t, err)
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.NoError(t, f.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+
+	// every line is written once, whole, whether it was written before or after the rotation
+	seen := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSuffix(string(rotated)+string(content), "\n"), "\n") {
+		seen[line]++
+	}
+	require.Len(t, seen, writers*linesPerWriter)
+	for w := 0; w < writers; w++ {
+		for i := 0; i < linesPerWriter; i++ {
+			assert.Equal(t, 1, seen[fmt.Sprintf("line %d%02d", w, i)])
+		}
+	}
+}
@@ -4,6 +4,8 @@
 package config
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configtls"
 )
 
@@ -12,6 +14,7 @@ type Supervisor struct {
 	Server       *OpAMPServer
 	Agent        *Agent
 	Capabilities *Capabilities `mapstructure:"capabilities"`
+	Packages     *Packages     `mapstructure:"packages"`
 }
 
 // Capabilities is the set of capabilities that the Supervisor supports.
@@ -19,8 +22,26 @@ type Capabilities struct {
 	AcceptsRemoteConfig    *bool `mapstructure:"accepts_remote_config"`
 	ReportsEffectiveConfig *bool `mapstructure:"reports_effective_config"`
 	ReportsOwnMetrics      *bool `mapstructure:"reports_own_metrics"`
+	ReportsOwnLogs         *bool `mapstructure:"reports_own_logs"`
 	ReportsHealth          *bool `mapstructure:"reports_health"`
 	ReportsRemoteConfig    *bool `mapstructure:"reports_remote_config"`
+	AcceptsPackages        *bool `mapstructure:"accepts_packages"`
+}
+
+// Packages configures how the Supervisor installs the Collector packages offered by the OpAMP Server.
+type Packages struct {
+	// StagingDir is the directory the packages are downloaded to, and the previous
+	// Collector binary is kept in until the new one is healthy.
+	StagingDir string `mapstructure:"staging_dir"`
+	// PublicKey is the path of a PEM encoded Ed25519 public key. Packages must be signed with
+	// the matching private key.
+	PublicKey string `mapstructure:"public_key"`
+	// AllowUnsigned lets packages be installed without verifying their signature when no
+	// PublicKey is set. Packages are refused otherwise.
+	AllowUnsigned bool `mapstructure:"allow_unsigned"`
+	// HealthCheckTimeout is how long a new Collector binary has to become healthy before
+	// the previous one is restored.
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
 }
 
 type OpAMPServer struct {
@@ -30,4 +51,7 @@ type OpAMPServer struct {
 
 type Agent struct {
 	Executable string
+	// ConfigApplyTimeout is how long the Collector has to become healthy with a new config
+	// before the Supervisor reverts it to the last config it was healthy with.
+	ConfigApplyTimeout time.Duration `mapstructure:"config_apply_timeout"`
 }
@@ -0,0 +1,455 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+const (
+	packagesStateFile = "packages.json"
+	stagedSuffix      = ".staged"
+	previousSuffix    = ".previous"
+)
+
+// packagesState is the state of the packages persisted in the staging directory, so that the
+// Supervisor reports the packages actually installed after a restart, and can still roll back
+// the packages it was installing.
+type packagesState struct {
+	AllPackagesHash []byte                        `json:"all_packages_hash"`
+	Packages        map[string]types.PackageState `json:"packages"`
+	ContentHashes   map[string][]byte             `json:"content_hashes"`
+	// Previous holds the state of the packages whose new content is staged or installed but not committed yet.
+	Previous map[string]installedPackage `json:"previous,omitempty"`
+}
+
+// installedPackage is the state of a package before its new content was staged.
+type installedPackage struct {
+	State       types.PackageState `json:"state"`
+	ContentHash []byte             `json:"content_hash"`
+}
+
+// packageManager implements the packages state provider used by the OpAMP client to sync the
+// packages offered by the server. Top-level packages are the Collector binary: they are downloaded
+// to the staging directory and verified, then handed to the Supervisor which swaps the binary and
+// rolls back to the previous one if the new Collector doesn't become healthy.
+type packageManager struct {
+	logger     *zap.Logger
+	dir        string
+	executable string
+	publicKey  ed25519.PublicKey
+
+	mu           sync.Mutex
+	state        packagesState
+	signatures   map[string][]byte
+	lastStatuses *protobufs.PackageStatuses
+
+	// staged receives the name of the packages whose new content has been staged and verified.
+	staged chan string
+}
+
+var _ types.PackagesStateProvider = (*packageManager)(nil)
+
+func newPackageManager(logger *zap.Logger, cfg *config.Packages, executable string) (*packageManager, error) {
+	m := &packageManager{
+		logger:     logger,
+		dir:        cfg.StagingDir,
+		executable: executable,
+		state:      packagesState{Packages: map[string]types.PackageState{}, ContentHashes: map[string][]byte{}, Previous: map[string]installedPackage{}},
+		signatures: map[string][]byte{},
+		staged:     make(chan string, 1),
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create packages staging directory: %w", err)
+	}
+
+	switch {
+	case cfg.PublicKey != "":
+		key, err := loadPublicKey(cfg.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		m.publicKey = key
+	case !cfg.AllowUnsigned:
+		return nil, errors.New("packages must be verified with a public_key, unless allow_unsigned is enabled")
+	default:
+		logger.Warn("Packages are installed without verifying their signature")
+	}
+
+	content, err := os.ReadFile(filepath.Join(m.dir, packagesStateFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("cannot read packages state: %w", err)
+	default:
+		if err = json.Unmarshal(content, &m.state); err != nil {
+			return nil, fmt.Errorf("cannot parse packages state: %w", err)
+		}
+		if m.state.Packages == nil {
+			m.state.Packages = map[string]types.PackageState{}
+		}
+		if m.state.ContentHashes == nil {
+			m.state.ContentHashes = map[string][]byte{}
+		}
+		if m.state.Previous == nil {
+			m.state.Previous = map[string]installedPackage{}
+		}
+	}
+
+	if err = m.discardStaged(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// discardStaged restores the state of the packages whose content was staged but not installed before
+// the Supervisor stopped, as they are not offered again unless their state matches the installed binary.
+// The lock must be held or the manager not shared yet.
+func (m *packageManager) discardStaged() error {
+	discarded := false
+	for name, previous := range m.state.Previous {
+		if _, err := os.Stat(m.previousPath(name)); err == nil {
+			// installed, waiting to be committed or rolled back
+			continue
+		}
+		m.restore(name, previous)
+		_ = os.Remove(m.stagedPath(name))
+		discarded = true
+	}
+	if !discarded {
+		return nil
+	}
+	return m.persist()
+}
+
+// installing returns the package installed before the Supervisor stopped, which still has to be
+// committed or rolled back, if any.
+func (m *packageManager) installing() (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name := range m.state.Previous {
+		return name, true
+	}
+	return "", false
+}
+
+// loadPublicKey reads the PEM encoded Ed25519 public key used to verify the signature of packages.
+func loadPublicKey(path string) (ed25519.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read packages public key: %w", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("packages public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse packages public key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("packages public key must be an Ed25519 key")
+	}
+	return edKey, nil
+}
+
+// setSignatures records the signatures of the files of the packages offered by the server, to
+// verify their content once downloaded.
+func (m *packageManager) setSignatures(available *protobufs.PackagesAvailable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, pkg := range available.GetPackages() {
+		m.signatures[name] = pkg.GetFile().GetSignature()
+	}
+}
+
+func (m *packageManager) AllPackagesHash() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state.AllPackagesHash, nil
+}
+
+func (m *packageManager) SetAllPackagesHash(hash []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.AllPackagesHash = hash
+	return m.persist()
+}
+
+func (m *packageManager) Packages() (map[string]types.PackageState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	packages := make(map[string]types.PackageState, len(m.state.Packages))
+	for name, state := range m.state.Packages {
+		packages[name] = state
+	}
+	return packages, nil
+}
+
+func (m *packageManager) PackageState(packageName string) (types.PackageState, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.state.Packages[packageName]
+	return state, ok, nil
+}
+
+func (m *packageManager) SetPackageState(packageName string, state types.PackageState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.Packages[packageName] = state
+	return m.persist()
+}
+
+func (m *packageManager) CreatePackage(packageName string, typ protobufs.PackageType) error {
+	if typ != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("package %q: only top-level packages are supported", packageName)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.Packages[packageName] = types.PackageState{Exists: true, Type: typ}
+	return m.persist()
+}
+
+func (m *packageManager) FileContentHash(packageName string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state.ContentHashes[packageName], nil
+}
+
+// UpdateContent stages the new content of the package, once its hash and signature are verified,
+// and notifies the Supervisor that it's ready to be installed.
+func (m *packageManager) UpdateContent(_ context.Context, packageName string, data io.Reader, contentHash []byte) error {
+	m.mu.Lock()
+	signature := m.signatures[packageName]
+	m.mu.Unlock()
+
+	staged := m.stagedPath(packageName)
+	if err := m.stage(staged, data, contentHash, signature); err != nil {
+		_ = os.Remove(staged)
+		return fmt.Errorf("package %q: %w", packageName, err)
+	}
+
+	m.mu.Lock()
+	// keep the state of the installed package, to restore it if the new one is rolled back
+	if _, ok := m.state.Previous[packageName]; !ok {
+		m.state.Previous[packageName] = installedPackage{
+			State:       m.state.Packages[packageName],
+			ContentHash: m.state.ContentHashes[packageName],
+		}
+	}
+	m.state.ContentHashes[packageName] = contentHash
+	err := m.persist()
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.staged <- packageName
+	return nil
+}
+
+func (m *packageManager) stage(path string, data io.Reader, contentHash, signature []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0700)
+	if err != nil {
+		return fmt.Errorf("cannot create staged file: %w", err)
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot download package: %w", err)
+	}
+
+	digest := h.Sum(nil)
+	if !bytes.Equal(digest, contentHash) {
+		return fmt.Errorf("content hash mismatch: expected %x, got %x", contentHash, digest)
+	}
+	// packages are only unsigned if allow_unsigned is enabled
+	if m.publicKey != nil && !ed25519.Verify(m.publicKey, digest, signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (m *packageManager) DeletePackage(packageName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// the Collector binary can't be uninstalled, only its state is forgotten
+	delete(m.state.Packages, packageName)
+	delete(m.state.ContentHashes, packageName)
+	return m.persist()
+}
+
+func (m *packageManager) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastStatuses, nil
+}
+
+func (m *packageManager) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastStatuses = statuses
+	return nil
+}
+
+// install replaces the Collector binary by the staged content of the package, keeping the current
+// binary to roll back to. The Collector must be stopped.
+func (m *packageManager) install(packageName string) error {
+	if err := copyFile(m.executable, m.previousPath(packageName)); err != nil {
+		// without a backup, the executable is left untouched and there is nothing to roll back
+		_ = os.Remove(m.previousPath(packageName))
+		return fmt.Errorf("cannot back up the current agent executable: %w", err)
+	}
+	if err := replaceFile(m.stagedPath(packageName), m.executable); err != nil {
+		return fmt.Errorf("cannot install the new agent executable: %w", err)
+	}
+	_ = os.Remove(m.stagedPath(packageName))
+	return nil
+}
+
+// commit forgets the previous binary once the new Collector is healthy.
+func (m *packageManager) commit(packageName string) {
+	m.mu.Lock()
+	delete(m.state.Previous, packageName)
+	err := m.persist()
+	m.mu.Unlock()
+	if err != nil {
+		m.logger.Warn("Cannot persist the packages state", zap.Error(err))
+	}
+	if err := os.Remove(m.previousPath(packageName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.logger.Warn("Cannot remove the previous agent executable", zap.Error(err))
+	}
+}
+
+// rollback restores the previous Collector binary and the state of the package, and returns the
+// package statuses to report to the server. The Collector must be stopped.
+func (m *packageManager) rollback(packageName string, cause error) (*protobufs.PackageStatuses, error) {
+	if err := replaceFile(m.previousPath(packageName), m.executable); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cannot restore the previous agent executable: %w", err)
+	}
+	_ = os.Remove(m.previousPath(packageName))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := m.state.Previous[packageName]
+	m.restore(packageName, previous)
+	if err := m.persist(); err != nil {
+		return nil, err
+	}
+
+	statuses := &protobufs.PackageStatuses{Packages: map[string]*protobufs.PackageStatus{}}
+	if m.lastStatuses != nil {
+		statuses = proto.Clone(m.lastStatuses).(*protobufs.PackageStatuses)
+	}
+	if statuses.Packages == nil {
+		statuses.Packages = map[string]*protobufs.PackageStatus{}
+	}
+	status, ok := statuses.Packages[packageName]
+	if !ok {
+		status = &protobufs.PackageStatus{Name: packageName}
+		statuses.Packages[packageName] = status
+	}
+	status.AgentHasVersion = previous.State.Version
+	status.AgentHasHash = previous.State.Hash
+	status.Status = protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed
+	status.ErrorMessage = fmt.Sprintf("rolled back to the previous version: %v", cause)
+	m.lastStatuses = statuses
+
+	return statuses, nil
+}
+
+// restore puts back the state the package had before its new content was staged. The lock must be held.
+func (m *packageManager) restore(packageName string, previous installedPackage) {
+	delete(m.state.Previous, packageName)
+	if previous.State.Exists {
+		m.state.Packages[packageName] = previous.State
+		m.state.ContentHashes[packageName] = previous.ContentHash
+	} else {
+		delete(m.state.Packages, packageName)
+		delete(m.state.ContentHashes, packageName)
+	}
+}
+
+func (m *packageManager) stagedPath(packageName string) string {
+	return filepath.Join(m.dir, packageFileName(packageName)+stagedSuffix)
+}
+
+func (m *packageManager) previousPath(packageName string) string {
+	return filepath.Join(m.dir, packageFileName(packageName)+previousSuffix)
+}
+
+// packageFileName returns a file name for the package, the name of the top-level package being empty.
+// The base name of the package is followed by a hash of the full name, so that packages with the same
+// base name, e.g. org-a/collector and org-b/collector, get different files.
+func packageFileName(packageName string) string {
+	if packageName == "" {
+		return "agent"
+	}
+	hash := sha256.Sum256([]byte(packageName))
+	return filepath.Base(packageName) + "-" + hex.EncodeToString(hash[:8])
+}
+
+// persist writes the state of the packages. The lock must be held.
+func (m *packageManager) persist() error {
+	content, err := json.Marshal(m.state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.dir, packagesStateFile), content, 0600)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0700)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// replaceFile atomically replaces dst by a copy of src, which may be on another file system.
+func replaceFile(src, dst string) error {
+	tmp := dst + ".tmp"
+	if err := copyFile(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
+)
+
+func newTestPackageManager(t *testing.T, publicKey ed25519.PublicKey) (*packageManager, string) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "otelcol")
+	require.NoError(t, os.WriteFile(executable, []byte("v1"), 0700))
+
+	cfg := &config.Packages{StagingDir: filepath.Join(dir, "packages"), AllowUnsigned: publicKey == nil}
+	if publicKey != nil {
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		require.NoError(t, err)
+		cfg.PublicKey = filepath.Join(dir, "key.pem")
+		require.NoError(t, os.WriteFile(cfg.PublicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	}
+
+	m, err := newPackageManager(zap.NewNop(), cfg, executable)
+	require.NoError(t, err)
+	return m, executable
+}
+
+func offer(m *packageManager, content []byte, signature []byte) []byte {
+	m.setSignatures(&protobufs.PackagesAvailable{
+		Packages: map[string]*protobufs.PackageAvailable{
+			"": {File: &protobufs.DownloadableFile{Signature: signature}},
+		},
+	})
+	hash := sha256.Sum256(content)
+	return hash[:]
+}
+
+func TestPackageManagerUpdateContent(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	content := []byte("v2")
+	digest := sha256.Sum256(content)
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		signature []byte
+		hash      []byte
+		expectErr string
+	}{
+		{
+			name: "unsigned",
+		},
+		{
+			name:      "signed",
+			publicKey: pub,
+			signature: ed25519.Sign(priv, digest[:]),
+		},
+		{
+			name:      "hash mismatch",
+			hash:      []byte("invalid"),
+			expectErr: "content hash mismatch",
+		},
+		{
+			name:      "invalid signature",
+			publicKey: pub,
+			signature: ed25519.Sign(priv, []byte("something else")),
+			expectErr: "invalid signature",
+		},
+		{
+			name:      "missing signature",
+			publicKey: pub,
+			expectErr: "invalid signature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestPackageManager(t, tt.publicKey)
+			hash := offer(m, content, tt.signature)
+			if tt.hash != nil {
+				hash = tt.hash
+			}
+
+			err := m.UpdateContent(context.Background(), "", bytes.NewReader(content), hash)
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+				assert.NoFileExists(t, m.stagedPath(""))
+				assert.Empty(t, m.staged)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "", <-m.staged)
+			staged, err := os.ReadFile(m.stagedPath(""))
+			require.NoError(t, err)
+			assert.Equal(t, content, staged)
+
+			contentHash, err := m.FileContentHash("")
+			require.NoError(t, err)
+			assert.Equal(t, hash, contentHash)
+		})
+	}
+}
+
+func TestPackageManagerCreatePackage(t *testing.T) {
+	m, _ := newTestPackageManager(t, nil)
+
+	require.NoError(t, m.CreatePackage("", protobufs.PackageType_PackageType_TopLevel))
+	state, ok, err := m.PackageState("")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, state.Exists)
+
+	require.Error(t, m.CreatePackage("addon", protobufs.PackageType_PackageType_Addon))
+	_, ok, err = m.PackageState("addon")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPackageManagerFileNames(t *testing.T) {
+	m, _ := newTestPackageManager(t, nil)
+
+	paths := map[string]bool{}
+	for _, name := range []string{"", "org-a/collector", "org-b/collector", "collector"} {
+		for _, path := range []string{m.stagedPath(name), m.previousPath(name)} {
+			assert.Equal(t, m.dir, filepath.Dir(path), name)
+			assert.False(t, paths[path], "%q shares the file %s", name, path)
+			paths[path] = true
+		}
+	}
+}
+
+func TestPackageManagerInstallCommit(t *testing.T) {
+	m, executable := newTestPackageManager(t, nil)
+	content := []byte("v2")
+	require.NoError(t, m.UpdateContent(context.Background(), "", bytes.NewReader(content), offer(m, content, nil)))
+	<-m.staged
+
+	require.NoError(t, m.install(""))
+	installed, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, content, installed)
+	assert.NoFileExists(t, m.stagedPath(""))
+	assert.FileExists(t, m.previousPath(""))
+
+	m.commit("")
+	assert.NoFileExists(t, m.previousPath(""))
+	assert.Empty(t, m.state.Previous)
+}
+
+func TestPackageManagerRollback(t *testing.T) {
+	m, executable := newTestPackageManager(t, nil)
+	previous := types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_TopLevel, Hash: []byte("v1"), Version: "1.0.0"}
+	require.NoError(t, m.SetPackageState("", previous))
+	require.NoError(t, m.SetLastReportedStatuses(&protobufs.PackageStatuses{
+		Packages: map[string]*protobufs.PackageStatus{
+			"": {Name: "", AgentHasVersion: "1.0.0", Status: protobufs.PackageStatusEnum_PackageStatusEnum_Installed},
+		},
+	}))
+
+	content := []byte("v2")
+	require.NoError(t, m.UpdateContent(context.Background(), "", bytes.NewReader(content), offer(m, content, nil)))
+	<-m.staged
+	require.NoError(t, m.SetPackageState("", types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_TopLevel, Hash: []byte("v2"), Version: "2.0.0"}))
+	require.NoError(t, m.install(""))
+
+	statuses, err := m.rollback("", errors.New("agent process exited with code 1"))
+	require.NoError(t, err)
+
+	restored, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), restored)
+	assert.NoFileExists(t, m.previousPath(""))
+
+	state, ok, err := m.PackageState("")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, previous, state)
+
+	status := statuses.Packages[""]
+	require.NotNil(t, status)
+	assert.Equal(t, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, status.Status)
+	assert.Equal(t, "1.0.0", status.AgentHasVersion)
+	assert.Equal(t, []byte("v1"), status.AgentHasHash)
+	assert.Contains(t, status.ErrorMessage, "agent process exited with code 1")
+
+	reported, err := m.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.Equal(t, statuses, reported)
+}
+
+func TestPackageManagerPersistsState(t *testing.T) {
+	m, executable := newTestPackageManager(t, nil)
+	state := types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_TopLevel, Hash: []byte("hash"), Version: "1.0.0"}
+	require.NoError(t, m.SetPackageState("", state))
+	require.NoError(t, m.SetAllPackagesHash([]byte("all")))
+
+	restarted, err := newPackageManager(zap.NewNop(), &config.Packages{StagingDir: m.dir, AllowUnsigned: true, HealthCheckTimeout: time.Minute}, executable)
+	require.NoError(t, err)
+
+	packages, err := restarted.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.PackageState{"": state}, packages)
+	allHash, err := restarted.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("all"), allHash)
+}
+
+func TestPackageManagerRequiresPublicKey(t *testing.T) {
+	dir := t.TempDir()
+	_, err := newPackageManager(zap.NewNop(), &config.Packages{StagingDir: dir}, filepath.Join(dir, "otelcol"))
+	assert.ErrorContains(t, err, "allow_unsigned")
+}
+
+func TestPackageManagerRollbackAfterRestart(t *testing.T) {
+	m, executable := newTestPackageManager(t, nil)
+	previous := types.PackageState{Exists: true, Type: protobufs.PackageType_PackageType_TopLevel, Hash: []byte("v1"), Version: "1.0.0"}
+	require.NoError(t, m.SetPackageState("", previous))
+
+	content := []byte("v2")
+	require.NoError(t, m.UpdateContent(context.Background(), "", bytes.NewReader(content), offer(m, content, nil)))
+	<-m.staged
+	require.NoError(t, m.install(""))
+
+	restarted, err := newPackageManager(zap.NewNop(), &config.Packages{StagingDir: m.dir, AllowUnsigned: true}, executable)
+	require.NoError(t, err)
+	name, ok := restarted.installing()
+	require.True(t, ok, "the installed package must still be pending after a restart")
+	assert.Equal(t, "", name)
+
+	_, err = restarted.rollback("", errors.New("agent is not healthy"))
+	require.NoError(t, err)
+	restored, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), restored)
+
+	state, ok, err := restarted.PackageState("")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, previous, state)
+	_, ok = restarted.installing()
+	assert.False(t, ok)
+}
+
+func TestPackageManagerDiscardsStagedAfterRestart(t *testing.T) {
+	m, executable := newTestPackageManager(t, nil)
+	content := []byte("v2")
+	hash := offer(m, content, nil)
+	require.NoError(t, m.UpdateContent(context.Background(), "", bytes.NewReader(content), hash))
+	<-m.staged
+
+	// the Supervisor stopped before installing the staged content
+	restarted, err := newPackageManager(zap.NewNop(), &config.Packages{StagingDir: m.dir, AllowUnsigned: true}, executable)
+	require.NoError(t, err)
+	_, ok := restarted.installing()
+	assert.False(t, ok)
+	assert.NoFileExists(t, restarted.stagedPath(""))
+	contentHash, err := restarted.FileContentHash("")
+	require.NoError(t, err)
+	assert.Empty(t, contentHash)
+}
@@ -13,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -47,6 +48,19 @@ var (
 
 	//go:embed templates/owntelemetry.yaml
 	ownTelemetryTpl string
+
+	//go:embed templates/ownlogs.yaml
+	ownLogsTpl string
+)
+
+const (
+	defaultConfigApplyTimeout = 30 * time.Second
+	// ownLogsFilePath is the file the commander writes the Collector's stderr, and so its own logs, to.
+	// They are collected from it and forwarded.
+	ownLogsFilePath = "agent.log"
+
+	defaultPackagesStagingDir         = "packages"
+	defaultPackagesHealthCheckTimeout = time.Minute
 )
 
 // Supervisor implements supervising of OpenTelemetry Collector and uses OpAMPClient
@@ -74,6 +88,7 @@ type Supervisor struct {
 	bootstrapTemplate    *template.Template
 	extraConfigTemplate  *template.Template
 	ownTelemetryTemplate *template.Template
+	ownLogsTemplate      *template.Template
 
 	// A config section to be added to the Collector's config to fetch its own metrics.
 	// TODO: store this persistently so that when starting we can compose the effective
@@ -81,6 +96,9 @@ type Supervisor struct {
 	// https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/21078
 	agentConfigOwnMetricsSection *atomic.Value
 
+	// A config section to be added to the Collector's config to forward its own logs.
+	agentConfigOwnLogsSection *atomic.Value
+
 	// agentHealthCheckEndpoint is the endpoint the Collector's health check extension
 	// will listen on for health check requests from the Supervisor.
 	agentHealthCheckEndpoint string
@@ -91,11 +109,19 @@ type Supervisor struct {
 	// Location of the effective config file.
 	effectiveConfigFilePath string
 
+	// Last effective config the Collector was healthy with, and the location of the file it's persisted to.
+	lastKnownGoodConfig         string
+	lastKnownGoodConfigFilePath string
+
+	// Config applied last, until the Collector becomes healthy with it or it is reverted.
+	pendingConfig *pendingConfig
+
 	// Last received remote config.
 	remoteConfig *protobufs.AgentRemoteConfig
 
-	// A channel to indicate there is a new config to apply.
-	hasNewConfig chan struct{}
+	// A channel to indicate there is a new config to apply, with the hash of the remote config
+	// it was composed from, nil if there is none.
+	hasNewConfig chan []byte
 
 	// The OpAMP client to connect to the OpAMP Server.
 	opampClient client.OpAMPClient
@@ -105,14 +131,35 @@ type Supervisor struct {
 
 	agentHasStarted               bool
 	agentStartHealthCheckAttempts int
+
+	// Manager of the packages offered by the OpAMP Server, nil unless packages are accepted.
+	packages *packageManager
+
+	// Package installed last, until the Collector becomes healthy with it or it is rolled back.
+	pendingPackage *pendingPackage
+}
+
+// pendingConfig is a config applied to the Collector, waiting for the Collector to become healthy.
+type pendingConfig struct {
+	// hash of the remote config the effective config was composed from, if any.
+	hash     []byte
+	deadline time.Time
+}
+
+// pendingPackage is a package whose binary has been installed, waiting for the Collector to become healthy.
+type pendingPackage struct {
+	name     string
+	deadline time.Time
 }
 
 func NewSupervisor(logger *zap.Logger, configFile string) (*Supervisor, error) {
 	s := &Supervisor{
 		logger:                       logger,
-		hasNewConfig:                 make(chan struct{}, 1),
+		hasNewConfig:                 make(chan []byte, 1),
 		effectiveConfigFilePath:      "effective.yaml",
+		lastKnownGoodConfigFilePath:  "last_known_good.yaml",
 		agentConfigOwnMetricsSection: &atomic.Value{},
+		agentConfigOwnLogsSection:    &atomic.Value{},
 		effectiveConfig:              &atomic.Value{},
 	}
 
@@ -124,6 +171,10 @@ func NewSupervisor(logger *zap.Logger, configFile string) (*Supervisor, error) {
 		return nil, fmt.Errorf("error loading config: %w", err)
 	}
 
+	if s.config.Agent.ConfigApplyTimeout <= 0 {
+		s.config.Agent.ConfigApplyTimeout = defaultConfigApplyTimeout
+	}
+
 	id, err := s.createInstanceID()
 	if err != nil {
 		return nil, err
@@ -131,6 +182,19 @@ func NewSupervisor(logger *zap.Logger, configFile string) (*Supervisor, error) {
 
 	s.instanceID = id
 
+	if c := s.config.Capabilities; c != nil && c.AcceptsPackages != nil && *c.AcceptsPackages {
+		if s.packages, err = s.createPackageManager(); err != nil {
+			return nil, err
+		}
+		if name, ok := s.packages.installing(); ok {
+			// the Supervisor stopped before the agent became healthy with the package
+			s.pendingPackage = &pendingPackage{
+				name:     name,
+				deadline: time.Now().Add(s.config.Packages.HealthCheckTimeout),
+			}
+		}
+	}
+
 	if err = s.getBootstrapInfo(); err != nil {
 		return nil, fmt.Errorf("could not get bootstrap info from the Collector: %w", err)
 	}
@@ -184,6 +248,9 @@ func (s *Supervisor) createTemplates() error {
 	if s.ownTelemetryTemplate, err = template.New("owntelemetry").Parse(ownTelemetryTpl); err != nil {
 		return err
 	}
+	if s.ownLogsTemplate, err = template.New("ownlogs").Parse(ownLogsTpl); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -209,6 +276,19 @@ func (s *Supervisor) loadConfig(configFile string) error {
 	return nil
 }
 
+func (s *Supervisor) createPackageManager() (*packageManager, error) {
+	if s.config.Packages == nil {
+		s.config.Packages = &config.Packages{}
+	}
+	if s.config.Packages.StagingDir == "" {
+		s.config.Packages.StagingDir = defaultPackagesStagingDir
+	}
+	if s.config.Packages.HealthCheckTimeout <= 0 {
+		s.config.Packages.HealthCheckTimeout = defaultPackagesHealthCheckTimeout
+	}
+	return newPackageManager(s.logger, s.config.Packages, s.config.Agent.Executable)
+}
+
 func (s *Supervisor) getBootstrapInfo() (err error) {
 	port, err := s.findRandomPort()
 	if err != nil {
@@ -338,9 +418,18 @@ func (s *Supervisor) Capabilities() protobufs.AgentCapabilities {
 			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig
 		}
 
+		if c.ReportsOwnLogs != nil && *c.ReportsOwnLogs {
+			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsOwnLogs
+		}
+
 		if c.ReportsRemoteConfig != nil && *c.ReportsRemoteConfig {
 			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig
 		}
+
+		if c.AcceptsPackages != nil && *c.AcceptsPackages {
+			supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+				protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses
+		}
 	}
 	return supportedCapabilities
 }
@@ -394,6 +483,9 @@ func (s *Supervisor) startOpAMP() error {
 		},
 		Capabilities: s.Capabilities(),
 	}
+	if s.packages != nil {
+		settings.PackagesStateProvider = s.packages
+	}
 	err = s.opampClient.SetAgentDescription(s.agentDescription)
 	if err != nil {
 		return err
@@ -467,6 +559,14 @@ func (s *Supervisor) loadAgentEffectiveConfig() {
 	}
 
 	s.effectiveConfig.Store(string(effectiveConfigBytes))
+
+	lastKnownGood, err := os.ReadFile(s.lastKnownGoodConfigFilePath)
+	if err == nil {
+		s.lastKnownGoodConfig = string(lastKnownGood)
+	} else {
+		// Nothing better to revert to than the config the Collector starts with.
+		s.lastKnownGoodConfig = string(effectiveConfigBytes)
+	}
 }
 
 // createEffectiveConfigMsg create an EffectiveConfig with the content of the
@@ -527,6 +627,45 @@ func (s *Supervisor) setupOwnMetrics(_ context.Context, settings *protobufs.Tele
 	return configChanged
 }
 
+// setupOwnLogs enables the pipeline forwarding the logs the Collector writes to ownLogsFilePath, through its stderr, to the
+// destination of the settings, or disables it if there is no destination.
+func (s *Supervisor) setupOwnLogs(_ context.Context, settings *protobufs.TelemetryConnectionSettings) (configChanged bool) {
+	var cfg bytes.Buffer
+	if settings.DestinationEndpoint == "" {
+		// No destination. Disable log collection.
+		s.logger.Debug("Disabling own logs pipeline in the config")
+	} else {
+		s.logger.Debug("Enabling own logs pipeline in the config")
+
+		logsFile, err := filepath.Abs(ownLogsFilePath)
+		if err != nil {
+			s.logger.Error("Could not setup own logs", zap.Error(err))
+			return
+		}
+
+		err = s.ownLogsTemplate.Execute(
+			&cfg,
+			map[string]any{
+				"LogsFile":     logsFile,
+				"LogsEndpoint": settings.DestinationEndpoint,
+			},
+		)
+		if err != nil {
+			s.logger.Error("Could not setup own logs", zap.Error(err))
+			return
+		}
+	}
+	s.agentConfigOwnLogsSection.Store(cfg.String())
+
+	// Need to recalculate the Agent config so that the logs config is included in it.
+	configChanged, err := s.recalcEffectiveConfig()
+	if err != nil {
+		return
+	}
+
+	return configChanged
+}
+
 // composeEffectiveConfig composes the effective config from multiple sources:
 // 1) the remote config from OpAMP Server
 // 2) the own metrics config section
@@ -541,7 +680,7 @@ func (s *Supervisor) composeEffectiveConfig(config *protobufs.AgentRemoteConfig)
 
 	// Sort to make sure the order of merging is stable.
 	var names []string
-	for name := range config.Config.ConfigMap {
+	for name := range config.GetConfig().GetConfigMap() {
 		if name == "" {
 			// skip instance config
 			continue
@@ -556,9 +695,9 @@ func (s *Supervisor) composeEffectiveConfig(config *protobufs.AgentRemoteConfig)
 
 	// Merge received configs.
 	for _, name := range names {
-		item := config.Config.ConfigMap[name]
+		item := config.GetConfig().GetConfigMap()[name]
 		var k2 = koanf.New(".")
-		err = k2.Load(rawbytes.Provider(item.Body), yaml.Parser())
+		err = k2.Load(rawbytes.Provider(item.GetBody()), yaml.Parser())
 		if err != nil {
 			return false, fmt.Errorf("cannot parse config named %s: %w", name, err)
 		}
@@ -576,6 +715,14 @@ func (s *Supervisor) composeEffectiveConfig(config *protobufs.AgentRemoteConfig)
 		}
 	}
 
+	// Merge own logs config.
+	ownLogsCfg, ok := s.agentConfigOwnLogsSection.Load().(string)
+	if ok {
+		if err = k.Load(rawbytes.Provider([]byte(ownLogsCfg)), yaml.Parser()); err != nil {
+			return false, err
+		}
+	}
+
 	// Merge local config last since it has the highest precedence.
 	if err = k.Load(rawbytes.Provider(s.composeExtraLocalConfig()), yaml.Parser()); err != nil {
 		return false, err
@@ -653,6 +800,26 @@ func (s *Supervisor) healthCheck() {
 	err := s.healthChecker.Check(ctx)
 	cancel()
 
+	if s.pendingPackage != nil {
+		if err == nil {
+			s.logger.Info("Agent is healthy with the new package", zap.String("package", s.pendingPackage.name))
+			s.packages.commit(s.pendingPackage.name)
+			s.pendingPackage = nil
+		} else if time.Now().After(s.pendingPackage.deadline) {
+			s.rollbackPackage(fmt.Errorf("agent is not healthy after %s: %w", s.config.Packages.HealthCheckTimeout, err))
+			return
+		}
+	}
+
+	if s.pendingConfig != nil {
+		if err == nil {
+			s.configApplied()
+		} else if time.Now().After(s.pendingConfig.deadline) {
+			s.rollbackConfig(fmt.Errorf("agent is not healthy after %s: %w", s.config.Agent.ConfigApplyTimeout, err))
+			return
+		}
+	}
+
 	if errors.Is(err, s.lastHealthCheckErr) {
 		// No difference from last check. Nothing new to report.
 		return
@@ -691,6 +858,10 @@ func (s *Supervisor) runAgentProcess() {
 	if _, err := os.Stat(s.effectiveConfigFilePath); err == nil {
 		// We have an effective config file saved previously. Use it to start the agent.
 		s.startAgent()
+		if s.effectiveConfig.Load().(string) != s.lastKnownGoodConfig {
+			// The Supervisor stopped before the agent was healthy with this config.
+			s.pendingConfig = &pendingConfig{deadline: time.Now().Add(s.config.Agent.ConfigApplyTimeout)}
+		}
 	}
 
 	restartTimer := time.NewTimer(0)
@@ -698,16 +869,30 @@ func (s *Supervisor) runAgentProcess() {
 
 	for {
 		select {
-		case <-s.hasNewConfig:
+		case hash := <-s.hasNewConfig:
 			restartTimer.Stop()
 			s.stopAgentApplyConfig()
 			s.startAgent()
+			s.pendingConfig = &pendingConfig{
+				hash:     hash,
+				deadline: time.Now().Add(s.config.Agent.ConfigApplyTimeout),
+			}
 
 		case <-s.commander.Done():
 			if s.shuttingDown {
 				return
 			}
 
+			if s.pendingPackage != nil {
+				s.rollbackPackage(fmt.Errorf("agent process exited with code %d", s.commander.ExitCode()))
+				continue
+			}
+
+			if s.pendingConfig != nil {
+				s.rollbackConfig(fmt.Errorf("agent process exited with code %d", s.commander.ExitCode()))
+				continue
+			}
+
 			s.logger.Debug("Agent process exited unexpectedly. Will restart in a bit...", zap.Int("pid", s.commander.Pid()), zap.Int("exit_code", s.commander.ExitCode()))
 			errMsg := fmt.Sprintf(
 				"Agent process PID=%d exited unexpectedly, exit code=%d. Will restart in a bit...",
@@ -729,12 +914,124 @@ func (s *Supervisor) runAgentProcess() {
 		case <-restartTimer.C:
 			s.startAgent()
 
+		case name := <-s.stagedPackages():
+			restartTimer.Stop()
+			s.installPackage(name)
+
 		case <-s.healthCheckTicker.C:
 			s.healthCheck()
 		}
 	}
 }
 
+// configApplied persists the effective config as the last known good one once the agent is healthy
+// with it, and reports the remote config it was composed from as applied.
+func (s *Supervisor) configApplied() {
+	hash := s.pendingConfig.hash
+	s.pendingConfig = nil
+
+	s.lastKnownGoodConfig = s.effectiveConfig.Load().(string)
+	s.writeEffectiveConfigToFile(s.lastKnownGoodConfig, s.lastKnownGoodConfigFilePath)
+
+	if hash == nil {
+		return
+	}
+	err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: hash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
+	})
+	if err != nil {
+		s.logger.Error("Could not report applied OpAMP remote config status", zap.Error(err))
+	}
+}
+
+// rollbackConfig restarts the agent with the last known good config, and reports the remote
+// config the failing config was composed from as failed.
+func (s *Supervisor) rollbackConfig(cause error) {
+	hash := s.pendingConfig.hash
+	s.pendingConfig = nil
+	s.logger.Error("Reverting agent to the last known good config", zap.Error(cause))
+
+	if err := s.commander.Stop(context.Background()); err != nil {
+		s.logger.Error("Could not stop agent process", zap.Error(err))
+	}
+	s.effectiveConfig.Store(s.lastKnownGoodConfig)
+	s.writeEffectiveConfigToFile(s.lastKnownGoodConfig, s.effectiveConfigFilePath)
+
+	err := s.opampClient.SetHealth(&protobufs.ComponentHealth{Healthy: false, LastError: cause.Error()})
+	if err != nil {
+		s.logger.Error("Could not report health to OpAMP server", zap.Error(err))
+	}
+	// Make sure the agent being healthy again is reported.
+	s.lastHealthCheckErr = cause
+
+	if hash != nil {
+		err = s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: hash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+			ErrorMessage:         cause.Error(),
+		})
+		if err != nil {
+			s.logger.Error("Could not report failed OpAMP remote config status", zap.Error(err))
+		}
+	}
+	if err = s.opampClient.UpdateEffectiveConfig(context.Background()); err != nil {
+		s.logger.Error("The OpAMP client failed to update the effective config", zap.Error(err))
+	}
+
+	s.startAgent()
+}
+
+// stagedPackages returns the channel of the packages ready to be installed, or nil if packages are not accepted.
+func (s *Supervisor) stagedPackages() <-chan string {
+	if s.packages == nil {
+		return nil
+	}
+	return s.packages.staged
+}
+
+// installPackage restarts the agent with the staged package. The package is committed once the agent
+// is healthy, or rolled back if it isn't within the health check timeout.
+func (s *Supervisor) installPackage(name string) {
+	s.logger.Info("Installing new agent package", zap.String("package", name))
+	if err := s.commander.Stop(context.Background()); err != nil {
+		s.logger.Error("Could not stop agent process", zap.Error(err))
+	}
+
+	s.pendingPackage = &pendingPackage{
+		name:     name,
+		deadline: time.Now().Add(s.config.Packages.HealthCheckTimeout),
+	}
+	if err := s.packages.install(name); err != nil {
+		s.rollbackPackage(fmt.Errorf("cannot install package: %w", err))
+		return
+	}
+	s.startAgent()
+}
+
+// rollbackPackage restores the agent as it was before the pending package was installed,
+// and reports the failure to the OpAMP Server.
+func (s *Supervisor) rollbackPackage(cause error) {
+	name := s.pendingPackage.name
+	s.pendingPackage = nil
+	s.logger.Error("Rolling back agent package", zap.String("package", name), zap.Error(cause))
+
+	if err := s.commander.Stop(context.Background()); err != nil {
+		s.logger.Error("Could not stop agent process", zap.Error(err))
+	}
+
+	statuses, err := s.packages.rollback(name, cause)
+	if err != nil {
+		s.logger.Error("Could not roll back agent package", zap.Error(err))
+	}
+	if statuses != nil {
+		if err = s.opampClient.SetPackageStatuses(statuses); err != nil {
+			s.logger.Error("Could not report package statuses to OpAMP server", zap.Error(err))
+		}
+	}
+	s.startAgent()
+}
+
 func (s *Supervisor) stopAgentApplyConfig() {
 	s.logger.Debug("Stopping the agent to apply new config")
 	cfg := s.effectiveConfig.Load().(string)
@@ -815,12 +1112,17 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 				s.logger.Error("Could not report failed OpAMP remote config status", zap.Error(err))
 			}
 		} else {
+			status := protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED
+			if configChanged {
+				// Reported as applied once the agent is healthy with the new config.
+				status = protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING
+			}
 			err = s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
 				LastRemoteConfigHash: msg.RemoteConfig.ConfigHash,
-				Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
+				Status:               status,
 			})
 			if err != nil {
-				s.logger.Error("Could not report applied OpAMP remote config status", zap.Error(err))
+				s.logger.Error("Could not report OpAMP remote config status", zap.Error(err))
 			}
 		}
 	}
@@ -829,6 +1131,17 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		configChanged = s.setupOwnMetrics(ctx, msg.OwnMetricsConnSettings) || configChanged
 	}
 
+	if msg.OwnLogsConnSettings != nil {
+		configChanged = s.setupOwnLogs(ctx, msg.OwnLogsConnSettings) || configChanged
+	}
+
+	if msg.PackageSyncer != nil && s.packages != nil {
+		s.packages.setSignatures(msg.PackagesAvailable)
+		if err := msg.PackageSyncer.Sync(ctx); err != nil {
+			s.logger.Error("Could not sync the packages offered by the OpAMP server", zap.Error(err))
+		}
+	}
+
 	if msg.AgentIdentification != nil {
 		newInstanceID, err := ulid.Parse(msg.AgentIdentification.NewInstanceUid)
 		if err != nil {
@@ -854,12 +1167,20 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		}
 
 		s.logger.Debug("Config is changed. Signal to restart the agent")
-		// Signal that there is a new config.
-		select {
-		case s.hasNewConfig <- struct{}{}:
-		default:
-		}
+		s.signalNewConfig(s.remoteConfig.GetConfigHash())
+	}
+}
+
+// signalNewConfig signals that there is a new config to apply, composed from the remote config
+// with the given hash. It replaces the signal of a config that hasn't been applied yet, since
+// the agent is only restarted with the latest effective config. It must only be called from
+// the OpAMP client callbacks, which own the remote config.
+func (s *Supervisor) signalNewConfig(hash []byte) {
+	select {
+	case <-s.hasNewConfig:
+	default:
 	}
+	s.hasNewConfig <- hash
 }
 
 func (s *Supervisor) findRandomPort() (int, error) {
@@ -5,7 +5,9 @@ package supervisor
 
 import (
 	"bytes"
+	"context"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 
@@ -17,9 +19,10 @@ import (
 func Test_composeEffectiveConfig(t *testing.T) {
 	s := Supervisor{
 		logger:                       zap.NewNop(),
-		hasNewConfig:                 make(chan struct{}, 1),
+		hasNewConfig:                 make(chan []byte, 1),
 		effectiveConfigFilePath:      "effective.yaml",
 		agentConfigOwnMetricsSection: &atomic.Value{},
+		agentConfigOwnLogsSection:    &atomic.Value{},
 		effectiveConfig:              &atomic.Value{},
 		agentHealthCheckEndpoint:     "localhost:8000",
 	}
@@ -58,3 +61,67 @@ func Test_composeEffectiveConfig(t *testing.T) {
 	require.True(t, configChanged)
 	require.Equal(t, string(expectedConfig), s.effectiveConfig.Load().(string))
 }
+
+func Test_setupOwnLogs(t *testing.T) {
+	s := Supervisor{
+		logger:                       zap.NewNop(),
+		hasNewConfig:                 make(chan []byte, 1),
+		agentConfigOwnMetricsSection: &atomic.Value{},
+		agentConfigOwnLogsSection:    &atomic.Value{},
+		effectiveConfig:              &atomic.Value{},
+		agentHealthCheckEndpoint:     "localhost:8000",
+		agentDescription:             &protobufs.AgentDescription{},
+	}
+	require.NoError(t, s.createTemplates())
+	s.effectiveConfig.Store("")
+
+	configChanged := s.setupOwnLogs(context.Background(), &protobufs.TelemetryConnectionSettings{
+		DestinationEndpoint: "http://localhost:4318/v1/logs",
+	})
+	require.True(t, configChanged)
+	cfg := s.effectiveConfig.Load().(string)
+	require.Contains(t, cfg, "filelog/own_logs")
+	require.Contains(t, cfg, "http://localhost:4318/v1/logs")
+	require.Contains(t, cfg, ownLogsFilePath)
+
+	configChanged = s.setupOwnLogs(context.Background(), &protobufs.TelemetryConnectionSettings{})
+	require.True(t, configChanged)
+	require.NotContains(t, s.effectiveConfig.Load().(string), "filelog/own_logs")
+}
+
+func Test_signalNewConfig(t *testing.T) {
+	s := Supervisor{hasNewConfig: make(chan []byte, 1)}
+
+	// the agent is restarted once, and the pending config is the last one composed
+	s.signalNewConfig([]byte("first"))
+	s.signalNewConfig([]byte("second"))
+	require.Equal(t, []byte("second"), <-s.hasNewConfig)
+	require.Empty(t, s.hasNewConfig)
+
+	s.signalNewConfig(nil)
+	require.Nil(t, <-s.hasNewConfig)
+}
+
+func Test_loadAgentEffectiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	s := Supervisor{
+		logger:                      zap.NewNop(),
+		effectiveConfig:             &atomic.Value{},
+		effectiveConfigFilePath:     filepath.Join(dir, "effective.yaml"),
+		lastKnownGoodConfigFilePath: filepath.Join(dir, "last_known_good.yaml"),
+		agentHealthCheckEndpoint:    "localhost:8000",
+		agentDescription:            &protobufs.AgentDescription{},
+	}
+	require.NoError(t, s.createTemplates())
+	require.NoError(t, os.WriteFile(s.effectiveConfigFilePath, []byte("new"), 0600))
+
+	// Without a last known good config, the config the agent starts with is the one to revert to.
+	s.loadAgentEffectiveConfig()
+	require.Equal(t, "new", s.effectiveConfig.Load().(string))
+	require.Equal(t, "new", s.lastKnownGoodConfig)
+
+	require.NoError(t, os.WriteFile(s.lastKnownGoodConfigFilePath, []byte("good"), 0600))
+	s.loadAgentEffectiveConfig()
+	require.Equal(t, "new", s.effectiveConfig.Load().(string))
+	require.Equal(t, "good", s.lastKnownGoodConfig)
+}
@@ -15,20 +15,24 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/replay"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/scenario"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/traces"
 )
 
 var (
-	tracesCfg  *traces.Config
-	metricsCfg *metrics.Config
-	logsCfg    *logs.Config
+	tracesCfg   *traces.Config
+	metricsCfg  *metrics.Config
+	logsCfg     *logs.Config
+	scenarioCfg *scenario.Config
+	replayCfg   *replay.Config
 )
 
 // rootCmd is the root command on which will be run children commands
 var rootCmd = &cobra.Command{
 	Use:     "telemetrygen",
 	Short:   "Telemetrygen simulates a client generating traces, metrics, and logs",
-	Example: "telemetrygen traces\ntelemetrygen metrics\ntelemetrygen logs",
+	Example: "telemetrygen traces\ntelemetrygen metrics\ntelemetrygen logs\ntelemetrygen scenario --file scenario.yaml\ntelemetrygen replay --file traces.json",
 }
 
 // tracesCmd is the command responsible for sending traces
@@ -61,8 +65,28 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// scenarioCmd is the command responsible for sending the telemetry of the services described in a scenario file
+var scenarioCmd = &cobra.Command{
+	Use:     "scenario",
+	Short:   fmt.Sprintf("Simulates services calling each other, generating traces, metrics and logs. (Stability level: %s)", metadata.TracesStability),
+	Example: "telemetrygen scenario --file scenario.yaml",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return scenario.Start(scenarioCfg)
+	},
+}
+
+// replayCmd is the command responsible for sending the telemetry captured in OTLP JSON files
+var replayCmd = &cobra.Command{
+	Use:     "replay",
+	Short:   fmt.Sprintf("Replays traces, metrics and logs captured in OTLP JSON files. (Stability level: %s)", metadata.TracesStability),
+	Example: "telemetrygen replay --file traces.json",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return replay.Start(replayCfg)
+	},
+}
+
 func init() {
-	rootCmd.AddCommand(tracesCmd, metricsCmd, logsCmd)
+	rootCmd.AddCommand(tracesCmd, metricsCmd, logsCmd, scenarioCmd, replayCmd)
 
 	tracesCfg = new(traces.Config)
 	tracesCfg.Flags(tracesCmd.Flags())
@@ -73,6 +97,12 @@ func init() {
 	logsCfg = new(logs.Config)
 	logsCfg.Flags(logsCmd.Flags())
 
+	scenarioCfg = new(scenario.Config)
+	scenarioCfg.Flags(scenarioCmd.Flags())
+
+	replayCfg = new(replay.Config)
+	replayCfg.Flags(replayCmd.Flags())
+
 	// Disabling completion command for end user
 	// https://github.com/spf13/cobra/blob/master/shell_completions.md
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
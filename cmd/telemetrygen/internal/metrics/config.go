@@ -12,19 +12,26 @@ import (
 // Config describes the test scenario.
 type Config struct {
 	common.Config
-	NumMetrics int
-	MetricType metricType
+	NumMetrics             int
+	MetricType             metricType
+	AggregationTemporality aggregationTemporality
+	NumSeries              int
+	SeriesChurn            float64
 }
 
 // Flags registers config flags.
 func (c *Config) Flags(fs *pflag.FlagSet) {
 	// Use Gauge as default metric type.
 	c.MetricType = metricTypeGauge
+	c.AggregationTemporality = aggregationTemporalityCumulative
 
 	c.CommonFlags(fs)
 
 	fs.StringVar(&c.HTTPPath, "otlp-http-url-path", "/v1/metrics", "Which URL path to write to")
 
-	fs.Var(&c.MetricType, "metric-type", "Metric type enum. must be one of 'Gauge' or 'Sum'")
+	fs.Var(&c.MetricType, "metric-type", "Metric type enum. must be one of 'Gauge', 'Sum', 'Histogram', 'ExponentialHistogram' or 'Summary'")
+	fs.Var(&c.AggregationTemporality, "aggregation-temporality", "Aggregation temporality of sums and histograms. must be one of 'delta' or 'cumulative'")
 	fs.IntVar(&c.NumMetrics, "metrics", 1, "Number of metrics to generate in each worker (ignored if duration is provided)")
+	fs.IntVar(&c.NumSeries, "series", 1, "Number of unique series, identified by their attributes, each worker generates a data point for with each metric")
+	fs.Float64Var(&c.SeriesChurn, "series-churn", 0, "Fraction of the series replaced by new ones with each metric, between 0 and 1")
 }
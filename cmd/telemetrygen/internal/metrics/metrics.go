@@ -6,6 +6,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -74,6 +75,15 @@ func Run(c *Config, exp func() (sdkmetric.Exporter, error), logger *zap.Logger)
 	} else if c.NumMetrics <= 0 {
 		return fmt.Errorf("either `metrics` or `duration` must be greater than 0")
 	}
+	if c.NumSeries < 0 {
+		return fmt.Errorf("`series` must not be negative")
+	}
+	if c.NumSeries == 0 {
+		c.NumSeries = 1
+	}
+	if c.SeriesChurn < 0 || c.SeriesChurn > 1 {
+		return fmt.Errorf("`series-churn` must be between 0 and 1")
+	}
 
 	limit := rate.Limit(c.Rate)
 	if c.Rate == 0 {
@@ -92,14 +102,18 @@ func Run(c *Config, exp func() (sdkmetric.Exporter, error), logger *zap.Logger)
 	for i := 0; i < c.WorkerCount; i++ {
 		wg.Add(1)
 		w := worker{
-			numMetrics:     c.NumMetrics,
-			metricType:     c.MetricType,
-			limitPerSecond: limit,
-			totalDuration:  c.TotalDuration,
-			running:        running,
-			wg:             &wg,
-			logger:         logger.With(zap.Int("worker", i)),
-			index:          i,
+			numMetrics:             c.NumMetrics,
+			metricType:             c.MetricType,
+			aggregationTemporality: c.AggregationTemporality,
+			numSeries:              c.NumSeries,
+			seriesChurn:            c.SeriesChurn,
+			limitPerSecond:         limit,
+			totalDuration:          c.TotalDuration,
+			running:                running,
+			wg:                     &wg,
+			logger:                 logger.With(zap.Int("worker", i)),
+			index:                  i,
+			rnd:                    rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
 		}
 
 		go w.simulateMetrics(res, exp, c.GetTelemetryAttributes())
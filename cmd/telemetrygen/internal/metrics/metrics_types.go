@@ -5,13 +5,18 @@ package metrics
 
 import (
 	"errors"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 type metricType string
 
 const (
-	metricTypeGauge = "Gauge"
-	metricTypeSum   = "Sum"
+	metricTypeGauge                = "Gauge"
+	metricTypeSum                  = "Sum"
+	metricTypeHistogram            = "Histogram"
+	metricTypeExponentialHistogram = "ExponentialHistogram"
+	metricTypeSummary              = "Summary"
 )
 
 // String is used both by fmt.Print and by Cobra in help text
@@ -22,11 +27,11 @@ func (e *metricType) String() string {
 // Set must have pointer receiver so it doesn't change the value of a copy
 func (e *metricType) Set(v string) error {
 	switch v {
-	case "Gauge", "Sum":
+	case metricTypeGauge, metricTypeSum, metricTypeHistogram, metricTypeExponentialHistogram, metricTypeSummary:
 		*e = metricType(v)
 		return nil
 	default:
-		return errors.New(`must be one of "Gauge" or "Sum"`)
+		return errors.New(`must be one of "Gauge", "Sum", "Histogram", "ExponentialHistogram" or "Summary"`)
 	}
 }
 
@@ -34,3 +39,39 @@ func (e *metricType) Set(v string) error {
 func (e *metricType) Type() string {
 	return "metricType"
 }
+
+type aggregationTemporality string
+
+const (
+	aggregationTemporalityDelta      = "delta"
+	aggregationTemporalityCumulative = "cumulative"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (t *aggregationTemporality) String() string {
+	return string(*t)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (t *aggregationTemporality) Set(v string) error {
+	switch v {
+	case aggregationTemporalityDelta, aggregationTemporalityCumulative:
+		*t = aggregationTemporality(v)
+		return nil
+	default:
+		return errors.New(`must be one of "delta" or "cumulative"`)
+	}
+}
+
+// Type is only used in help text
+func (t *aggregationTemporality) Type() string {
+	return "temporality"
+}
+
+// temporality returns the temporality of the SDK matching the flag, cumulative by default.
+func (t aggregationTemporality) temporality() metricdata.Temporality {
+	if t == aggregationTemporalityDelta {
+		return metricdata.DeltaTemporality
+	}
+	return metricdata.CumulativeTemporality
+}
@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	// seriesAttribute is the attribute distinguishing the series when there is more than one.
+	seriesAttribute = "series.id"
+	// observationMean is the mean of the exponentially distributed values observed by histograms and summaries.
+	observationMean = 100
+	// exponentialHistogramScale is the scale of the generated exponential histograms.
+	exponentialHistogramScale = 2
+)
+
+var (
+	// histogramBounds are the default bounds of explicit bucket histograms of the SDK.
+	histogramBounds = []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+	// summaryQuantiles are the quantiles reported by summaries, besides the minimum and the maximum.
+	summaryQuantiles = []float64{0.5, 0.9, 0.99}
+)
+
+// series holds the state of a series, accumulated since its start for cumulative
+// temporality, or since its previous data point for delta temporality.
+type series struct {
+	attributes attribute.Set
+	start      time.Time // start of the series
+	last       time.Time // time of the previous data point
+
+	value        int64
+	count        uint64
+	sum          float64
+	minimum      float64
+	maximum      float64
+	bucketCounts []uint64
+	expCounts    map[int32]uint64
+}
+
+func newSeries(id int, single bool, signalAttrs []attribute.KeyValue, now time.Time) *series {
+	attrs := signalAttrs
+	if !single {
+		attrs = append(append(make([]attribute.KeyValue, 0, len(signalAttrs)+1), signalAttrs...), attribute.Int(seriesAttribute, id))
+	}
+	s := &series{
+		attributes: attribute.NewSet(attrs...),
+		start:      now,
+		last:       now,
+	}
+	s.reset()
+	return s
+}
+
+// reset clears the values accumulated by the series.
+func (s *series) reset() {
+	s.value = 0
+	s.count = 0
+	s.sum = 0
+	s.minimum = math.Inf(1)
+	s.maximum = math.Inf(-1)
+	s.bucketCounts = make([]uint64, len(histogramBounds)+1)
+	s.expCounts = make(map[int32]uint64)
+}
+
+// observe records a value in the series.
+func (s *series) observe(v float64) {
+	s.value++
+	s.count++
+	s.sum += v
+	s.minimum = math.Min(s.minimum, v)
+	s.maximum = math.Max(s.maximum, v)
+
+	// buckets include their upper bound
+	i := 0
+	for i < len(histogramBounds) && v > histogramBounds[i] {
+		i++
+	}
+	s.bucketCounts[i]++
+	s.expCounts[exponentialBucketIndex(v)]++
+}
+
+// exponentialBucketIndex returns the index of the bucket of v, whose upper bound is inclusive.
+func exponentialBucketIndex(v float64) int32 {
+	return int32(math.Ceil(math.Log2(v)*math.Exp2(exponentialHistogramScale))) - 1
+}
+
+// seriesSet is the set of series a worker generates data points for. Churning the set replaces its
+// oldest series by new ones.
+type seriesSet struct {
+	series      []*series
+	signalAttrs []attribute.KeyValue
+	nextID      int     // identifier of the next series created
+	oldest      int     // index of the oldest series
+	churnCarry  float64 // fraction of a series left to replace
+}
+
+func newSeriesSet(n int, signalAttrs []attribute.KeyValue, now time.Time) *seriesSet {
+	set := &seriesSet{
+		series:      make([]*series, n),
+		signalAttrs: signalAttrs,
+	}
+	for i := range set.series {
+		set.series[i] = set.newSeries(now)
+	}
+	return set
+}
+
+func (set *seriesSet) newSeries(now time.Time) *series {
+	s := newSeries(set.nextID, len(set.series) == 1, set.signalAttrs, now)
+	set.nextID++
+	return s
+}
+
+// churn replaces the given fraction of the series by new ones, carrying fractions of series over to the next call.
+func (set *seriesSet) churn(fraction float64, now time.Time) {
+	set.churnCarry += fraction * float64(len(set.series))
+	replaced := int(set.churnCarry)
+	set.churnCarry -= float64(replaced)
+	for i := 0; i < replaced; i++ {
+		set.series[set.oldest] = set.newSeries(now)
+		set.oldest = (set.oldest + 1) % len(set.series)
+	}
+}
+
+// dataPoints observes a new value in each series, and returns their data points for the metric type.
+func (set *seriesSet) dataPoints(metricType metricType, temporality metricdata.Temporality, i int64, rnd *rand.Rand, now time.Time) metricdata.Aggregation {
+	for _, s := range set.series {
+		s.observe(rnd.ExpFloat64() * observationMean)
+	}
+
+	var data metricdata.Aggregation
+	switch metricType {
+	case metricTypeGauge:
+		gauge := metricdata.Gauge[int64]{}
+		for _, s := range set.series {
+			gauge.DataPoints = append(gauge.DataPoints, metricdata.DataPoint[int64]{
+				Time:       now,
+				Value:      i,
+				Attributes: s.attributes,
+			})
+		}
+		data = gauge
+	case metricTypeSum:
+		sum := metricdata.Sum[int64]{IsMonotonic: true, Temporality: temporality}
+		for _, s := range set.series {
+			sum.DataPoints = append(sum.DataPoints, metricdata.DataPoint[int64]{
+				StartTime:  s.startTime(temporality),
+				Time:       now,
+				Value:      s.value,
+				Attributes: s.attributes,
+			})
+		}
+		data = sum
+	case metricTypeHistogram:
+		histogram := metricdata.Histogram[float64]{Temporality: temporality}
+		for _, s := range set.series {
+			histogram.DataPoints = append(histogram.DataPoints, metricdata.HistogramDataPoint[float64]{
+				Attributes:   s.attributes,
+				StartTime:    s.startTime(temporality),
+				Time:         now,
+				Count:        s.count,
+				Bounds:       histogramBounds,
+				BucketCounts: append([]uint64(nil), s.bucketCounts...),
+				Min:          metricdata.NewExtrema(s.minimum),
+				Max:          metricdata.NewExtrema(s.maximum),
+				Sum:          s.sum,
+			})
+		}
+		data = histogram
+	case metricTypeExponentialHistogram:
+		histogram := metricdata.ExponentialHistogram[float64]{Temporality: temporality}
+		for _, s := range set.series {
+			histogram.DataPoints = append(histogram.DataPoints, metricdata.ExponentialHistogramDataPoint[float64]{
+				Attributes:     s.attributes,
+				StartTime:      s.startTime(temporality),
+				Time:           now,
+				Count:          s.count,
+				Min:            metricdata.NewExtrema(s.minimum),
+				Max:            metricdata.NewExtrema(s.maximum),
+				Sum:            s.sum,
+				Scale:          exponentialHistogramScale,
+				PositiveBucket: s.exponentialBucket(),
+			})
+		}
+		data = histogram
+	case metricTypeSummary:
+		// summaries are always cumulative
+		summary := metricdata.Summary{}
+		for _, s := range set.series {
+			summary.DataPoints = append(summary.DataPoints, metricdata.SummaryDataPoint{
+				Attributes:     s.attributes,
+				StartTime:      s.start,
+				Time:           now,
+				Count:          s.count,
+				Sum:            s.sum,
+				QuantileValues: s.quantiles(),
+			})
+		}
+		data = summary
+	}
+
+	for _, s := range set.series {
+		s.last = now
+		if temporality == metricdata.DeltaTemporality && metricType != metricTypeSummary {
+			s.reset()
+		}
+	}
+	return data
+}
+
+func (s *series) startTime(temporality metricdata.Temporality) time.Time {
+	if temporality == metricdata.DeltaTemporality {
+		return s.last
+	}
+	return s.start
+}
+
+func (s *series) exponentialBucket() metricdata.ExponentialBucket {
+	if len(s.expCounts) == 0 {
+		return metricdata.ExponentialBucket{}
+	}
+	lowest, highest := int32(math.MaxInt32), int32(math.MinInt32)
+	for index := range s.expCounts {
+		if index < lowest {
+			lowest = index
+		}
+		if index > highest {
+			highest = index
+		}
+	}
+	counts := make([]uint64, highest-lowest+1)
+	for index, count := range s.expCounts {
+		counts[index-lowest] = count
+	}
+	return metricdata.ExponentialBucket{Offset: lowest, Counts: counts}
+}
+
+// quantiles returns the minimum, the maximum and the quantiles of the exponential distribution the values are drawn from.
+func (s *series) quantiles() []metricdata.QuantileValue {
+	values := []metricdata.QuantileValue{{Quantile: 0, Value: s.minimum}}
+	for _, q := range summaryQuantiles {
+		v := -observationMean * math.Log(1-q)
+		values = append(values, metricdata.QuantileValue{Quantile: q, Value: math.Min(math.Max(v, s.minimum), s.maximum)})
+	}
+	return append(values, metricdata.QuantileValue{Quantile: 1, Value: s.maximum})
+}
@@ -5,6 +5,7 @@ package metrics
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,14 +19,18 @@ import (
 )
 
 type worker struct {
-	running        *atomic.Bool    // pointer to shared flag that indicates it's time to stop the test
-	metricType     metricType      // type of metric to generate
-	numMetrics     int             // how many metrics the worker has to generate (only when duration==0)
-	totalDuration  time.Duration   // how long to run the test for (overrides `numMetrics`)
-	limitPerSecond rate.Limit      // how many metrics per second to generate
-	wg             *sync.WaitGroup // notify when done
-	logger         *zap.Logger     // logger
-	index          int             // worker index
+	running                *atomic.Bool           // pointer to shared flag that indicates it's time to stop the test
+	metricType             metricType             // type of metric to generate
+	aggregationTemporality aggregationTemporality // temporality of sums and histograms
+	numMetrics             int                    // how many metrics the worker has to generate (only when duration==0)
+	numSeries              int                    // how many series each metric has a data point for
+	seriesChurn            float64                // fraction of the series replaced with each metric
+	totalDuration          time.Duration          // how long to run the test for (overrides `numMetrics`)
+	limitPerSecond         rate.Limit             // how many metrics per second to generate
+	wg                     *sync.WaitGroup        // notify when done
+	logger                 *zap.Logger            // logger
+	index                  int                    // worker index
+	rnd                    *rand.Rand             // source of the observed values
 }
 
 func (w worker) simulateMetrics(res *resource.Resource, exporterFunc func() (sdkmetric.Exporter, error), signalAttrs []attribute.KeyValue) {
@@ -44,47 +49,19 @@ func (w worker) simulateMetrics(res *resource.Resource, exporterFunc func() (sdk
 		}
 	}()
 
+	set := newSeriesSet(w.numSeries, signalAttrs, time.Now())
+
 	var i int64
 	for w.running.Load() {
-		var metrics []metricdata.Metrics
-
-		switch w.metricType {
-		case metricTypeGauge:
-			metrics = append(metrics, metricdata.Metrics{
-				Name: "gen",
-				Data: metricdata.Gauge[int64]{
-					DataPoints: []metricdata.DataPoint[int64]{
-						{
-							Time:       time.Now(),
-							Value:      i,
-							Attributes: attribute.NewSet(signalAttrs...),
-						},
-					},
-				},
-			})
-		case metricTypeSum:
-			metrics = append(metrics, metricdata.Metrics{
-				Name: "gen",
-				Data: metricdata.Sum[int64]{
-					IsMonotonic: true,
-					Temporality: metricdata.CumulativeTemporality,
-					DataPoints: []metricdata.DataPoint[int64]{
-						{
-							StartTime:  time.Now().Add(-1 * time.Second),
-							Time:       time.Now(),
-							Value:      i,
-							Attributes: attribute.NewSet(signalAttrs...),
-						},
-					},
-				},
-			})
-		default:
+		now := time.Now()
+		data := set.dataPoints(w.metricType, w.aggregationTemporality.temporality(), i, w.rnd, now)
+		if data == nil {
 			w.logger.Fatal("unknown metric type")
 		}
 
 		rm := metricdata.ResourceMetrics{
 			Resource:     res,
-			ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: metrics}},
+			ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{Name: "gen", Data: data}}}},
 		}
 
 		if err := exporter.Export(context.Background(), &rm); err != nil {
@@ -94,6 +71,9 @@ func (w worker) simulateMetrics(res *resource.Resource, exporterFunc func() (sdk
 			w.logger.Fatal("limiter wait failed, retry", zap.Error(err))
 		}
 
+		// the new series start where the previous data points ended
+		set.churn(w.seriesChurn, now)
+
 		i++
 		if w.numMetrics != 0 && i >= int64(w.numMetrics) {
 			break
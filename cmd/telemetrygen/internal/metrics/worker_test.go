@@ -329,3 +329,146 @@ func configWithMultipleAttributes(metric metricType, qty int) *Config {
 		MetricType: metric,
 	}
 }
+
+func TestMetricTypes(t *testing.T) {
+	tests := []struct {
+		metricType  metricType
+		temporality aggregationTemporality
+		check       func(t *testing.T, data metricdata.Aggregation, i int)
+	}{
+		{
+			metricType:  metricTypeSum,
+			temporality: aggregationTemporalityDelta,
+			check: func(t *testing.T, data metricdata.Aggregation, _ int) {
+				sum := data.(metricdata.Sum[int64])
+				assert.Equal(t, metricdata.DeltaTemporality, sum.Temporality)
+				assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+			},
+		},
+		{
+			metricType:  metricTypeHistogram,
+			temporality: aggregationTemporalityCumulative,
+			check: func(t *testing.T, data metricdata.Aggregation, i int) {
+				histogram := data.(metricdata.Histogram[float64])
+				assert.Equal(t, metricdata.CumulativeTemporality, histogram.Temporality)
+				dp := histogram.DataPoints[0]
+				assert.Equal(t, uint64(i+1), dp.Count)
+				assert.Len(t, dp.BucketCounts, len(dp.Bounds)+1)
+				total := uint64(0)
+				for _, c := range dp.BucketCounts {
+					total += c
+				}
+				assert.Equal(t, dp.Count, total)
+			},
+		},
+		{
+			metricType:  metricTypeHistogram,
+			temporality: aggregationTemporalityDelta,
+			check: func(t *testing.T, data metricdata.Aggregation, _ int) {
+				histogram := data.(metricdata.Histogram[float64])
+				assert.Equal(t, metricdata.DeltaTemporality, histogram.Temporality)
+				assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+			},
+		},
+		{
+			metricType:  metricTypeExponentialHistogram,
+			temporality: aggregationTemporalityCumulative,
+			check: func(t *testing.T, data metricdata.Aggregation, i int) {
+				histogram := data.(metricdata.ExponentialHistogram[float64])
+				dp := histogram.DataPoints[0]
+				assert.Equal(t, uint64(i+1), dp.Count)
+				assert.Equal(t, int32(exponentialHistogramScale), dp.Scale)
+				total := uint64(0)
+				for _, c := range dp.PositiveBucket.Counts {
+					total += c
+				}
+				assert.Equal(t, dp.Count, total)
+			},
+		},
+		{
+			metricType:  metricTypeSummary,
+			temporality: aggregationTemporalityDelta,
+			check: func(t *testing.T, data metricdata.Aggregation, i int) {
+				dp := data.(metricdata.Summary).DataPoints[0]
+				// summaries stay cumulative
+				assert.Equal(t, uint64(i+1), dp.Count)
+				require.Len(t, dp.QuantileValues, 5)
+				for j := 1; j < len(dp.QuantileValues); j++ {
+					assert.LessOrEqual(t, dp.QuantileValues[j-1].Value, dp.QuantileValues[j].Value)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.metricType)+"/"+string(tt.temporality), func(t *testing.T) {
+			cfg := configWithNoAttributes(tt.metricType, 3)
+			cfg.AggregationTemporality = tt.temporality
+			m := &mockExporter{}
+			expFunc := func() (sdkmetric.Exporter, error) {
+				return m, nil
+			}
+
+			require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+			require.Len(t, m.rms, 3)
+			for i, rm := range m.rms {
+				tt.check(t, rm.ScopeMetrics[0].Metrics[0].Data, i)
+			}
+		})
+	}
+}
+
+func TestSeries(t *testing.T) {
+	cfg := configWithOneAttribute(metricTypeGauge, 2)
+	cfg.NumSeries = 10
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	require.Len(t, m.rms, 2)
+	for _, rm := range m.rms {
+		dps := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[int64]).DataPoints
+		require.Len(t, dps, 10)
+		for i, dp := range dps {
+			assert.Equal(t, 2, dp.Attributes.Len())
+			id, _ := dp.Attributes.Value(seriesAttribute)
+			assert.Equal(t, int64(i), id.AsInt64())
+		}
+	}
+}
+
+func TestSeriesChurn(t *testing.T) {
+	cfg := configWithNoAttributes(metricTypeSum, 5)
+	cfg.NumSeries = 4
+	cfg.SeriesChurn = 0.25
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	require.Len(t, m.rms, 5)
+	for i, rm := range m.rms {
+		ids := make([]int64, 0, 4)
+		for _, dp := range rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints {
+			id, _ := dp.Attributes.Value(seriesAttribute)
+			ids = append(ids, id.AsInt64())
+		}
+		// one series is replaced by a new one with each metric
+		assert.ElementsMatch(t, []int64{int64(i), int64(i + 1), int64(i + 2), int64(i + 3)}, ids)
+	}
+}
+
+func TestInvalidSeries(t *testing.T) {
+	cfg := configWithNoAttributes(metricTypeSum, 1)
+	cfg.SeriesChurn = 2
+	assert.EqualError(t, Run(cfg, nil, zap.NewNop()), "`series-churn` must be between 0 and 1")
+
+	cfg = configWithNoAttributes(metricTypeSum, 1)
+	cfg.NumSeries = -1
+	assert.EqualError(t, Run(cfg, nil, zap.NewNop()), "`series` must not be negative")
+}
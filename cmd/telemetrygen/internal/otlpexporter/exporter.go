@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otlpexporter sends telemetry built with pdata to an OTLP endpoint, for the modes of telemetrygen
+// emitting several signals at once.
+package otlpexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+// URLPaths are the URL paths the HTTP exporter writes each signal to.
+type URLPaths struct {
+	Traces  string
+	Metrics string
+	Logs    string
+}
+
+// Flags registers the flags of the URL paths, one per signal as the modes using this exporter emit several signals.
+func (p *URLPaths) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&p.Traces, "otlp-http-traces-url-path", "/v1/traces", "Which URL path to write traces to")
+	fs.StringVar(&p.Metrics, "otlp-http-metrics-url-path", "/v1/metrics", "Which URL path to write metrics to")
+	fs.StringVar(&p.Logs, "otlp-http-logs-url-path", "/v1/logs", "Which URL path to write logs to")
+}
+
+// Exporter sends traces, metrics and logs to an OTLP endpoint.
+type Exporter interface {
+	ExportTraces(ptrace.Traces) error
+	ExportMetrics(pmetric.Metrics) error
+	ExportLogs(plog.Logs) error
+	Shutdown() error
+}
+
+// New creates an exporter for the endpoint, protocol, security settings and headers of the configuration,
+// writing to the given URL paths over HTTP.
+func New(ctx context.Context, cfg *common.Config, paths URLPaths) (Exporter, error) {
+	// Exporter with HTTP
+	if cfg.UseHTTP {
+		if cfg.Insecure {
+			return &httpClientExporter{
+				client: http.DefaultClient,
+				cfg:    cfg,
+				paths:  paths,
+			}, nil
+		}
+		creds, err := common.GetTLSCredentialsForHTTPExporter(cfg.CaFile, cfg.ClientAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TLS credentials: %w", err)
+		}
+		return &httpClientExporter{
+			client: &http.Client{Transport: &http.Transport{TLSClientConfig: creds}},
+			cfg:    cfg,
+			paths:  paths,
+		}, nil
+	}
+
+	// Exporter with GRPC
+	var err error
+	var clientConn *grpc.ClientConn
+	if cfg.Insecure {
+		clientConn, err = grpc.DialContext(ctx, cfg.Endpoint(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		creds, err := common.GetTLSCredentialsForGRPCExporter(cfg.CaFile, cfg.ClientAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TLS credentials: %w", err)
+		}
+		clientConn, err = grpc.DialContext(ctx, cfg.Endpoint(), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &gRPCClientExporter{
+		conn:     clientConn,
+		metadata: metadata.New(cfg.Headers),
+		traces:   ptraceotlp.NewGRPCClient(clientConn),
+		metrics:  pmetricotlp.NewGRPCClient(clientConn),
+		logs:     plogotlp.NewGRPCClient(clientConn),
+	}, nil
+}
+
+type gRPCClientExporter struct {
+	conn *grpc.ClientConn
+	// metadata holds the headers sent along with each request.
+	metadata metadata.MD
+	traces   ptraceotlp.GRPCClient
+	metrics  pmetricotlp.GRPCClient
+	logs     plogotlp.GRPCClient
+}
+
+func (e *gRPCClientExporter) ExportTraces(traces ptrace.Traces) error {
+	_, err := e.traces.Export(e.context(), ptraceotlp.NewExportRequestFromTraces(traces))
+	return err
+}
+
+func (e *gRPCClientExporter) ExportMetrics(metrics pmetric.Metrics) error {
+	_, err := e.metrics.Export(e.context(), pmetricotlp.NewExportRequestFromMetrics(metrics))
+	return err
+}
+
+func (e *gRPCClientExporter) ExportLogs(logs plog.Logs) error {
+	_, err := e.logs.Export(e.context(), plogotlp.NewExportRequestFromLogs(logs))
+	return err
+}
+
+func (e *gRPCClientExporter) context() context.Context {
+	return metadata.NewOutgoingContext(context.Background(), e.metadata)
+}
+
+func (e *gRPCClientExporter) Shutdown() error {
+	return e.conn.Close()
+}
+
+type httpClientExporter struct {
+	client *http.Client
+	cfg    *common.Config
+	paths  URLPaths
+}
+
+func (e *httpClientExporter) ExportTraces(traces ptrace.Traces) error {
+	body, err := ptraceotlp.NewExportRequestFromTraces(traces).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces to protobuf: %w", err)
+	}
+	return e.export(e.paths.Traces, body)
+}
+
+func (e *httpClientExporter) ExportMetrics(metrics pmetric.Metrics) error {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(metrics).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics to protobuf: %w", err)
+	}
+	return e.export(e.paths.Metrics, body)
+}
+
+func (e *httpClientExporter) ExportLogs(logs plog.Logs) error {
+	body, err := plogotlp.NewExportRequestFromLogs(logs).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal logs to protobuf: %w", err)
+	}
+	return e.export(e.paths.Logs, body)
+}
+
+func (e *httpClientExporter) Shutdown() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+func (e *httpClientExporter) export(path string, body []byte) error {
+	scheme := "https"
+	if e.cfg.Insecure {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, e.cfg.Endpoint(), path)
+
+	httpReq, err := http.NewRequestWithContext(context.Background(), "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for k, v := range e.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var respData bytes.Buffer
+		_, _ = io.Copy(&respData, resp.Body)
+		return fmt.Errorf("request to %s failed with status %s (%s)", path, resp.Status, respData.String())
+	}
+
+	return nil
+}
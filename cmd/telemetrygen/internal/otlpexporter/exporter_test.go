@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func TestHTTPExporterURLPathsAndHeaders(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		paths   []string
+		headers []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		headers = append(headers, r.Header.Get("X-Tenant"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := &common.Config{
+		CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+		UseHTTP:        true,
+		Insecure:       true,
+		Headers:        common.KeyValue{"X-Tenant": "acme"},
+	}
+	var urlPaths URLPaths
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	urlPaths.Flags(fs)
+	require.NoError(t, fs.Parse([]string{"--otlp-http-traces-url-path", "/custom/traces"}))
+
+	exp, err := New(context.Background(), cfg, urlPaths)
+	require.NoError(t, err)
+	require.NoError(t, exp.ExportTraces(ptrace.NewTraces()))
+	require.NoError(t, exp.ExportMetrics(pmetric.NewMetrics()))
+	require.NoError(t, exp.ExportLogs(plog.NewLogs()))
+	require.NoError(t, exp.Shutdown())
+
+	assert.Equal(t, []string{"/custom/traces", "/v1/metrics", "/v1/logs"}, paths)
+	assert.Equal(t, []string{"acme", "acme", "acme"}, headers)
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+// Config describes the test scenario.
+type Config struct {
+	common.Config
+	Files             []string
+	Loops             int
+	RewriteIDs        bool
+	RewriteTimestamps bool
+	URLPaths          otlpexporter.URLPaths
+}
+
+// Flags registers config flags.
+func (c *Config) Flags(fs *pflag.FlagSet) {
+	c.CommonFlags(fs)
+	c.URLPaths.Flags(fs)
+
+	fs.StringSliceVar(&c.Files, "file", nil, "OTLP JSON file to replay, as written by the file exporter. Flag may be repeated to replay several files")
+	fs.IntVar(&c.Loops, "loops", 1, "Number of times each worker replays the files (ignored if duration is provided)")
+	fs.BoolVar(&c.RewriteIDs, "rewrite-ids", true, "Whether to replace the trace and span IDs by new ones on each replay")
+	fs.BoolVar(&c.RewriteTimestamps, "rewrite-timestamps", true, "Whether to shift the timestamps so that the earliest one of the files is the time of each replay")
+}
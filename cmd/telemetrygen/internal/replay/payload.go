@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+// maxLineSize is the size of the longest line of a file that can be replayed.
+const maxLineSize = 64 * 1024 * 1024
+
+// payload is a batch of telemetry of a single signal, read from one line of a file.
+// Exactly one of traces, metrics and logs is set.
+type payload struct {
+	traces  *ptrace.Traces
+	metrics *pmetric.Metrics
+	logs    *plog.Logs
+}
+
+// copy returns a copy of the payload, so that it can be modified and exported while the original is kept for the next replay.
+func (p payload) copy() payload {
+	switch {
+	case p.traces != nil:
+		td := ptrace.NewTraces()
+		p.traces.CopyTo(td)
+		return payload{traces: &td}
+	case p.metrics != nil:
+		md := pmetric.NewMetrics()
+		p.metrics.CopyTo(md)
+		return payload{metrics: &md}
+	default:
+		ld := plog.NewLogs()
+		p.logs.CopyTo(ld)
+		return payload{logs: &ld}
+	}
+}
+
+// export sends the payload with the exporter of its signal.
+func (p payload) export(exp otlpexporter.Exporter) error {
+	switch {
+	case p.traces != nil:
+		return exp.ExportTraces(*p.traces)
+	case p.metrics != nil:
+		return exp.ExportMetrics(*p.metrics)
+	default:
+		return exp.ExportLogs(*p.logs)
+	}
+}
+
+// loadFiles reads the payloads of the files, in order.
+func loadFiles(paths []string) ([]payload, error) {
+	var payloads []payload
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		filePayloads, err := readPayloads(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		payloads = append(payloads, filePayloads...)
+	}
+	return payloads, nil
+}
+
+// readPayloads reads OTLP JSON payloads, one per line, as written by the file exporter.
+func readPayloads(r io.Reader) ([]payload, error) {
+	var payloads []payload
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		p, err := parsePayload(content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		payloads = append(payloads, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return payloads, nil
+}
+
+// parsePayload decodes a payload, detecting its signal from its top level field.
+func parsePayload(content []byte) (payload, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return payload{}, err
+	}
+
+	switch {
+	case fields["resourceSpans"] != nil:
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(content)
+		if err != nil {
+			return payload{}, err
+		}
+		return payload{traces: &td}, nil
+	case fields["resourceMetrics"] != nil:
+		md, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(content)
+		if err != nil {
+			return payload{}, err
+		}
+		return payload{metrics: &md}, nil
+	case fields["resourceLogs"] != nil:
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(content)
+		if err != nil {
+			return payload{}, err
+		}
+		return payload{logs: &ld}, nil
+	default:
+		return payload{}, errors.New("no resourceSpans, resourceMetrics or resourceLogs found")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replay sends the telemetry captured in OTLP JSON files again, as if it was emitted now.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+// Start starts the replay of the files
+func Start(cfg *Config) error {
+	logger, err := common.CreateLogger(cfg.SkipSettingGRPCLogger)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Files) == 0 {
+		return errors.New("at least one `file` must be provided")
+	}
+	payloads, err := loadFiles(cfg.Files)
+	if err != nil {
+		return err
+	}
+
+	exp, err := otlpexporter.New(context.Background(), &cfg.Config, cfg.URLPaths)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		logger.Info("stopping the exporter")
+		if tempError := exp.Shutdown(); tempError != nil {
+			logger.Error("failed to stop the exporter", zap.Error(tempError))
+		}
+	}()
+
+	return Run(cfg, payloads, exp, logger)
+}
+
+// Run executes the test scenario.
+func Run(c *Config, payloads []payload, exp otlpexporter.Exporter, logger *zap.Logger) error {
+	if c.TotalDuration > 0 {
+		c.Loops = 0
+	} else if c.Loops <= 0 {
+		return fmt.Errorf("either `loops` or `duration` must be greater than 0")
+	}
+	if len(payloads) == 0 {
+		return errors.New("no telemetry found in the files")
+	}
+
+	limit := rate.Limit(c.Rate)
+	if c.Rate == 0 {
+		limit = rate.Inf
+		logger.Info("replay isn't being throttled")
+	} else {
+		logger.Info("replay is limited", zap.Float64("payloads-per-second", float64(limit)))
+	}
+
+	minTs := earliest(payloads)
+	wg := sync.WaitGroup{}
+	running := &atomic.Bool{}
+	running.Store(true)
+
+	for i := 0; i < c.WorkerCount; i++ {
+		wg.Add(1)
+		w := worker{
+			payloads:          payloads,
+			earliest:          minTs,
+			loops:             c.Loops,
+			rewriteIDs:        c.RewriteIDs,
+			rewriteTimestamps: c.RewriteTimestamps,
+			rnd:               rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
+			limitPerSecond:    limit,
+			running:           running,
+			wg:                &wg,
+			logger:            logger.With(zap.Int("worker", i)),
+		}
+
+		go w.replay(exp)
+	}
+	if c.TotalDuration > 0 {
+		time.Sleep(c.TotalDuration)
+		running.Store(false)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+type mockExporter struct {
+	mu      sync.Mutex
+	traces  []ptrace.Traces
+	metrics []pmetric.Metrics
+	logs    []plog.Logs
+}
+
+func (m *mockExporter) ExportTraces(traces ptrace.Traces) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.traces = append(m.traces, traces)
+	return nil
+}
+
+func (m *mockExporter) ExportMetrics(metrics pmetric.Metrics) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, metrics)
+	return nil
+}
+
+func (m *mockExporter) ExportLogs(logs plog.Logs) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, logs)
+	return nil
+}
+
+func (m *mockExporter) Shutdown() error {
+	return nil
+}
+
+func loadTestPayloads(t *testing.T) []payload {
+	payloads, err := loadFiles([]string{filepath.Join("testdata", "telemetry.json")})
+	require.NoError(t, err)
+	return payloads
+}
+
+func TestLoadFiles(t *testing.T) {
+	payloads := loadTestPayloads(t)
+	require.Len(t, payloads, 3)
+	require.NotNil(t, payloads[0].traces)
+	assert.Equal(t, 2, payloads[0].traces.SpanCount())
+	require.NotNil(t, payloads[1].metrics)
+	assert.Equal(t, 2, payloads[1].metrics.MetricCount())
+	require.NotNil(t, payloads[2].logs)
+	assert.Equal(t, 2, payloads[2].logs.LogRecordCount())
+
+	assert.Equal(t, pcommon.Timestamp(1700000000000000000), earliest(payloads))
+}
+
+func TestReadPayloadsInvalid(t *testing.T) {
+	_, err := readPayloads(strings.NewReader(`{"resourceSpans":[]}` + "\n" + `{"other":[]}`))
+	assert.EqualError(t, err, "line 2: no resourceSpans, resourceMetrics or resourceLogs found")
+
+	_, err = readPayloads(strings.NewReader(`not json`))
+	assert.ErrorContains(t, err, "line 1: ")
+}
+
+func TestLoops(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount: 2,
+		},
+		Loops:             3,
+		RewriteIDs:        true,
+		RewriteTimestamps: true,
+	}
+	exp := &mockExporter{}
+
+	require.NoError(t, Run(cfg, loadTestPayloads(t), exp, zap.NewNop()))
+
+	assert.Len(t, exp.traces, 6)
+	assert.Len(t, exp.metrics, 6)
+	assert.Len(t, exp.logs, 6)
+
+	// each replay has its own trace IDs
+	traceIDs := make(map[pcommon.TraceID]bool)
+	for _, td := range exp.traces {
+		traceIDs[td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()] = true
+	}
+	assert.Len(t, traceIDs, 6)
+}
+
+func TestRewrite(t *testing.T) {
+	payloads := loadTestPayloads(t)
+	originals := make([]payload, 0, len(payloads))
+	for _, p := range payloads {
+		originals = append(originals, p.copy())
+	}
+
+	now := time.Unix(2000000000, 0).UTC()
+	delta := int64(pcommon.NewTimestampFromTime(now)) - int64(earliest(payloads))
+	ids := newIDMapper(rand.New(rand.NewSource(1)))
+	for _, p := range payloads {
+		p.shiftTimestamps(delta)
+		p.rewriteIDs(ids)
+	}
+
+	spans := payloads[0].traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	root, child := spans.At(0), spans.At(1)
+	assert.Equal(t, now, root.StartTimestamp().AsTime())
+	assert.Equal(t, now.Add(time.Second), root.EndTimestamp().AsTime())
+	assert.Equal(t, now.Add(500*time.Millisecond), root.Events().At(0).Timestamp().AsTime())
+	assert.Equal(t, now.Add(100*time.Millisecond), child.StartTimestamp().AsTime())
+
+	originalRoot := originals[0].traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.NotEqual(t, originalRoot.TraceID(), root.TraceID())
+	assert.NotEqual(t, originalRoot.SpanID(), root.SpanID())
+	assert.Equal(t, root.TraceID(), child.TraceID())
+	assert.Equal(t, root.SpanID(), child.ParentSpanID())
+	assert.NotEqual(t, root.TraceID(), child.Links().At(0).TraceID())
+
+	metrics := payloads[1].metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	dp := metrics.At(0).Sum().DataPoints().At(0)
+	assert.Equal(t, now, dp.StartTimestamp().AsTime())
+	assert.Equal(t, now.Add(10*time.Second), dp.Timestamp().AsTime())
+	assert.Equal(t, root.TraceID(), dp.Exemplars().At(0).TraceID())
+	assert.Equal(t, root.SpanID(), dp.Exemplars().At(0).SpanID())
+	assert.Equal(t, now.Add(10*time.Second), metrics.At(1).Summary().DataPoints().At(0).Timestamp().AsTime())
+
+	records := payloads[2].logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, now.Add(200*time.Millisecond), records.At(0).Timestamp().AsTime())
+	assert.Equal(t, now.Add(300*time.Millisecond), records.At(0).ObservedTimestamp().AsTime())
+	assert.Equal(t, root.TraceID(), records.At(0).TraceID())
+	assert.Equal(t, root.SpanID(), records.At(0).SpanID())
+	// missing timestamps and IDs are left unset
+	assert.Equal(t, pcommon.Timestamp(0), records.At(1).Timestamp())
+	assert.True(t, records.At(1).TraceID().IsEmpty())
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// earliest returns the earliest non zero timestamp of the payloads, or zero if there is none.
+func earliest(payloads []payload) pcommon.Timestamp {
+	var minTs pcommon.Timestamp
+	for _, p := range payloads {
+		p.timestamps(func(ts pcommon.Timestamp) pcommon.Timestamp {
+			if ts != 0 && (minTs == 0 || ts < minTs) {
+				minTs = ts
+			}
+			return ts
+		})
+	}
+	return minTs
+}
+
+// shiftTimestamps moves all the non zero timestamps of the payload by delta nanoseconds.
+func (p payload) shiftTimestamps(delta int64) {
+	p.timestamps(func(ts pcommon.Timestamp) pcommon.Timestamp {
+		if ts == 0 {
+			return 0
+		}
+		return pcommon.Timestamp(int64(ts) + delta)
+	})
+}
+
+// timestamps replaces every timestamp of the payload by the result of f.
+func (p payload) timestamps(f func(pcommon.Timestamp) pcommon.Timestamp) {
+	switch {
+	case p.traces != nil:
+		traceTimestamps(*p.traces, f)
+	case p.metrics != nil:
+		metricTimestamps(*p.metrics, f)
+	case p.logs != nil:
+		logTimestamps(*p.logs, f)
+	}
+}
+
+func traceTimestamps(td ptrace.Traces, f func(pcommon.Timestamp) pcommon.Timestamp) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		sss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetStartTimestamp(f(span.StartTimestamp()))
+				span.SetEndTimestamp(f(span.EndTimestamp()))
+				for e := 0; e < span.Events().Len(); e++ {
+					event := span.Events().At(e)
+					event.SetTimestamp(f(event.Timestamp()))
+				}
+			}
+		}
+	}
+}
+
+func logTimestamps(ld plog.Logs, f func(pcommon.Timestamp) pcommon.Timestamp) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			records := sls.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				record.SetTimestamp(f(record.Timestamp()))
+				record.SetObservedTimestamp(f(record.ObservedTimestamp()))
+			}
+		}
+	}
+}
+
+// dataPoint holds the timestamps common to all the types of data points.
+type dataPoint interface {
+	StartTimestamp() pcommon.Timestamp
+	SetStartTimestamp(pcommon.Timestamp)
+	Timestamp() pcommon.Timestamp
+	SetTimestamp(pcommon.Timestamp)
+}
+
+func metricTimestamps(md pmetric.Metrics, f func(pcommon.Timestamp) pcommon.Timestamp) {
+	shift := func(dp dataPoint) {
+		dp.SetStartTimestamp(f(dp.StartTimestamp()))
+		dp.SetTimestamp(f(dp.Timestamp()))
+	}
+	shiftExemplars := func(exemplars pmetric.ExemplarSlice) {
+		for e := 0; e < exemplars.Len(); e++ {
+			exemplars.At(e).SetTimestamp(f(exemplars.At(e).Timestamp()))
+		}
+	}
+
+	forEachMetric(md, func(m pmetric.Metric) {
+		switch m.Type() {
+		case pmetric.MetricTypeGauge:
+			for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+				shift(m.Gauge().DataPoints().At(i))
+				shiftExemplars(m.Gauge().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeSum:
+			for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+				shift(m.Sum().DataPoints().At(i))
+				shiftExemplars(m.Sum().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeHistogram:
+			for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+				shift(m.Histogram().DataPoints().At(i))
+				shiftExemplars(m.Histogram().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeExponentialHistogram:
+			for i := 0; i < m.ExponentialHistogram().DataPoints().Len(); i++ {
+				shift(m.ExponentialHistogram().DataPoints().At(i))
+				shiftExemplars(m.ExponentialHistogram().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeSummary:
+			for i := 0; i < m.Summary().DataPoints().Len(); i++ {
+				shift(m.Summary().DataPoints().At(i))
+			}
+		}
+	})
+}
+
+func forEachMetric(md pmetric.Metrics, f func(pmetric.Metric)) {
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		sms := md.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				f(metrics.At(k))
+			}
+		}
+	}
+}
+
+// idMapper replaces trace and span IDs by random ones, consistently across all the payloads of a replay
+// so that the relationships between spans, logs and exemplars are kept.
+type idMapper struct {
+	rnd    *rand.Rand
+	traces map[pcommon.TraceID]pcommon.TraceID
+	spans  map[pcommon.SpanID]pcommon.SpanID
+}
+
+func newIDMapper(rnd *rand.Rand) *idMapper {
+	return &idMapper{
+		rnd:    rnd,
+		traces: make(map[pcommon.TraceID]pcommon.TraceID),
+		spans:  make(map[pcommon.SpanID]pcommon.SpanID),
+	}
+}
+
+func (m *idMapper) traceID(id pcommon.TraceID) pcommon.TraceID {
+	if id.IsEmpty() {
+		return id
+	}
+	mapped, ok := m.traces[id]
+	if !ok {
+		_, _ = m.rnd.Read(mapped[:])
+		m.traces[id] = mapped
+	}
+	return mapped
+}
+
+func (m *idMapper) spanID(id pcommon.SpanID) pcommon.SpanID {
+	if id.IsEmpty() {
+		return id
+	}
+	mapped, ok := m.spans[id]
+	if !ok {
+		_, _ = m.rnd.Read(mapped[:])
+		m.spans[id] = mapped
+	}
+	return mapped
+}
+
+// rewriteIDs replaces the trace and span IDs of the payload.
+func (p payload) rewriteIDs(m *idMapper) {
+	switch {
+	case p.traces != nil:
+		m.rewriteTraces(*p.traces)
+	case p.metrics != nil:
+		m.rewriteMetrics(*p.metrics)
+	case p.logs != nil:
+		m.rewriteLogs(*p.logs)
+	}
+}
+
+func (m *idMapper) rewriteTraces(td ptrace.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		sss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetTraceID(m.traceID(span.TraceID()))
+				span.SetSpanID(m.spanID(span.SpanID()))
+				span.SetParentSpanID(m.spanID(span.ParentSpanID()))
+				for l := 0; l < span.Links().Len(); l++ {
+					link := span.Links().At(l)
+					link.SetTraceID(m.traceID(link.TraceID()))
+					link.SetSpanID(m.spanID(link.SpanID()))
+				}
+			}
+		}
+	}
+}
+
+func (m *idMapper) rewriteLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			records := sls.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				record.SetTraceID(m.traceID(record.TraceID()))
+				record.SetSpanID(m.spanID(record.SpanID()))
+			}
+		}
+	}
+}
+
+func (m *idMapper) rewriteMetrics(md pmetric.Metrics) {
+	rewrite := func(exemplars pmetric.ExemplarSlice) {
+		for e := 0; e < exemplars.Len(); e++ {
+			exemplar := exemplars.At(e)
+			exemplar.SetTraceID(m.traceID(exemplar.TraceID()))
+			exemplar.SetSpanID(m.spanID(exemplar.SpanID()))
+		}
+	}
+
+	forEachMetric(md, func(metric pmetric.Metric) {
+		switch metric.Type() {
+		case pmetric.MetricTypeGauge:
+			for i := 0; i < metric.Gauge().DataPoints().Len(); i++ {
+				rewrite(metric.Gauge().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeSum:
+			for i := 0; i < metric.Sum().DataPoints().Len(); i++ {
+				rewrite(metric.Sum().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeHistogram:
+			for i := 0; i < metric.Histogram().DataPoints().Len(); i++ {
+				rewrite(metric.Histogram().DataPoints().At(i).Exemplars())
+			}
+		case pmetric.MetricTypeExponentialHistogram:
+			for i := 0; i < metric.ExponentialHistogram().DataPoints().Len(); i++ {
+				rewrite(metric.ExponentialHistogram().DataPoints().At(i).Exemplars())
+			}
+		}
+	})
+}
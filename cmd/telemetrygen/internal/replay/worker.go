@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+type worker struct {
+	payloads          []payload         // the payloads read from the files, left untouched
+	earliest          pcommon.Timestamp // the earliest timestamp of the payloads
+	loops             int               // how many times the worker replays the files (only when duration==0)
+	rewriteIDs        bool              // whether to replace the trace and span IDs on each replay
+	rewriteTimestamps bool              // whether to shift the timestamps to the time of each replay
+	rnd               *rand.Rand        // source of the new IDs
+	limitPerSecond    rate.Limit        // how many payloads per second to send
+	running           *atomic.Bool      // pointer to shared flag that indicates it's time to stop the test
+	wg                *sync.WaitGroup   // notify when done
+	logger            *zap.Logger       // logger
+}
+
+func (w worker) replay(exp otlpexporter.Exporter) {
+	defer w.wg.Done()
+	limiter := rate.NewLimiter(w.limitPerSecond, 1)
+	var loops, sent int
+
+	for w.running.Load() {
+		var ids *idMapper
+		if w.rewriteIDs {
+			ids = newIDMapper(w.rnd)
+		}
+		delta := int64(pcommon.NewTimestampFromTime(time.Now())) - int64(w.earliest)
+
+		for _, original := range w.payloads {
+			if !w.running.Load() {
+				break
+			}
+			p := original.copy()
+			if w.rewriteTimestamps && w.earliest != 0 {
+				p.shiftTimestamps(delta)
+			}
+			if ids != nil {
+				p.rewriteIDs(ids)
+			}
+
+			if err := p.export(exp); err != nil {
+				w.logger.Fatal("exporter failed", zap.Error(err))
+			}
+			if err := limiter.Wait(context.Background()); err != nil {
+				w.logger.Fatal("limiter wait failed, retry", zap.Error(err))
+			}
+			sent++
+		}
+
+		loops++
+		if w.loops != 0 && loops >= w.loops {
+			break
+		}
+	}
+
+	w.logger.Info("files replayed", zap.Int("loops", loops), zap.Int("payloads", sent))
+}
@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+// Config describes the test scenario.
+type Config struct {
+	common.Config
+	File      string
+	NumTraces int
+	Seed      int64
+	URLPaths  otlpexporter.URLPaths
+}
+
+// Flags registers config flags.
+func (c *Config) Flags(fs *pflag.FlagSet) {
+	c.CommonFlags(fs)
+	c.URLPaths.Flags(fs)
+
+	fs.StringVar(&c.File, "file", "", "Path of the scenario file describing the services and the calls between them")
+	fs.IntVar(&c.NumTraces, "traces", 1, "Number of traces to generate in each worker (ignored if duration is provided)")
+	fs.Int64Var(&c.Seed, "seed", 0, "Seed of the random generator, zero means a random seed")
+}
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.13.0"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const scopeName = "telemetrygen"
+
+// instanceKey identifies an instance of a service.
+type instanceKey struct {
+	service  string
+	instance int
+}
+
+func (k instanceKey) id() string {
+	return fmt.Sprintf("%s-%d", k.service, k.instance)
+}
+
+// generator turns the scenario into traces and logs. It isn't safe for concurrent use, each
+// worker has its own generator sharing the metrics aggregator.
+type generator struct {
+	scenario            *Scenario
+	rnd                 *rand.Rand
+	resourceAttributes  []attribute.KeyValue
+	telemetryAttributes []attribute.KeyValue
+	metrics             *aggregator
+}
+
+// trace holds the telemetry of the trace being generated, grouped by service instance.
+type trace struct {
+	id      pcommon.TraceID
+	traces  ptrace.Traces
+	logs    plog.Logs
+	spans   map[instanceKey]ptrace.SpanSlice
+	records map[instanceKey]plog.LogRecordSlice
+}
+
+// generate builds a trace starting at an entrypoint picked at random, along with its logs.
+func (g *generator) generate(start time.Time) (ptrace.Traces, plog.Logs) {
+	t := &trace{
+		id:      g.traceID(),
+		traces:  ptrace.NewTraces(),
+		logs:    plog.NewLogs(),
+		spans:   make(map[instanceKey]ptrace.SpanSlice),
+		records: make(map[instanceKey]plog.LogRecordSlice),
+	}
+	ep := g.scenario.pickEntrypoint(g.rnd)
+	g.invoke(t, ep.Service, ep.Operation, pcommon.NewSpanIDEmpty(), start)
+	return t.traces, t.logs
+}
+
+// invoke simulates the operation of the service and the calls it makes, returning when it ended and whether it failed.
+func (g *generator) invoke(t *trace, service, operation string, parent pcommon.SpanID, start time.Time) (time.Time, bool) {
+	svc := g.scenario.services[service]
+	op := svc.operations[operation]
+	key := instanceKey{service: service, instance: g.rnd.Intn(svc.Instances)}
+
+	span := g.spans(t, key, svc).AppendEmpty()
+	span.SetTraceID(t.id)
+	span.SetSpanID(g.spanID())
+	span.SetParentSpanID(parent)
+	span.SetName(op.Name)
+	span.SetKind(spanKind(op.Kind))
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	for name, attr := range op.Attributes {
+		span.Attributes().PutStr(name, attr.value(g.rnd))
+	}
+	for _, attr := range g.telemetryAttributes {
+		span.Attributes().PutStr(string(attr.Key), attr.Value.AsString())
+	}
+
+	// the operation spends half of its own latency before making its calls, and the other half after
+	own := op.Latency.sample(g.rnd)
+	now := start.Add(own / 2)
+	for _, call := range op.Calls {
+		if g.rnd.Float64() >= *call.Probability {
+			continue
+		}
+		now = g.call(t, key, svc, span.SpanID(), call, now)
+	}
+	end := now.Add(own - own/2)
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+
+	failed := g.rnd.Float64() < op.ErrorRate
+	if failed {
+		span.Status().SetCode(ptrace.StatusCodeError)
+		span.Status().SetMessage(fmt.Sprintf("%s failed", op.Name))
+	}
+
+	g.emitLogs(t, key, svc, span, op.Logs, failed)
+	g.metrics.record(key, svc, op.Name, failed, end.Sub(start))
+
+	return end, failed
+}
+
+// call simulates a call to another operation, returning when the caller can proceed. Synchronous calls are
+// wrapped in a client span of the caller, messages sent to consumers in a producer span, and calls to
+// internal operations are made directly.
+func (g *generator) call(t *trace, caller instanceKey, svc *Service, parent pcommon.SpanID, call *Call, start time.Time) time.Time {
+	callee := g.scenario.services[call.Service].operations[call.Operation]
+	if callee.Kind == kindInternal {
+		end, _ := g.invoke(t, call.Service, call.Operation, parent, start)
+		return end
+	}
+
+	span := g.spans(t, caller, svc).AppendEmpty()
+	span.SetTraceID(t.id)
+	span.SetSpanID(g.spanID())
+	span.SetParentSpanID(parent)
+	span.SetName(call.Operation)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.Attributes().PutStr(semconv.AttributePeerService, call.Service)
+	for _, attr := range g.telemetryAttributes {
+		span.Attributes().PutStr(string(attr.Key), attr.Value.AsString())
+	}
+
+	if callee.Kind == kindConsumer {
+		// the message is consumed asynchronously, the producer doesn't wait for it
+		span.SetKind(ptrace.SpanKindProducer)
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start))
+		g.invoke(t, call.Service, call.Operation, span.SpanID(), start)
+		return start
+	}
+
+	span.SetKind(ptrace.SpanKindClient)
+	end, failed := g.invoke(t, call.Service, call.Operation, span.SpanID(), start)
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+	if failed {
+		span.Status().SetCode(ptrace.StatusCodeError)
+	}
+	return end
+}
+
+// emitLogs emits count log records spread over the duration of the span.
+func (g *generator) emitLogs(t *trace, key instanceKey, svc *Service, span ptrace.Span, count int, failed bool) {
+	if count == 0 {
+		return
+	}
+	records := g.logRecords(t, key, svc)
+	start := span.StartTimestamp().AsTime()
+	step := span.EndTimestamp().AsTime().Sub(start) / time.Duration(count)
+	for i := 0; i < count; i++ {
+		record := records.AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(time.Duration(i) * step)))
+		record.SetObservedTimestamp(record.Timestamp())
+		record.SetTraceID(span.TraceID())
+		record.SetSpanID(span.SpanID())
+		record.Attributes().PutStr("operation", span.Name())
+		for _, attr := range g.telemetryAttributes {
+			record.Attributes().PutStr(string(attr.Key), attr.Value.AsString())
+		}
+		if failed && i == count-1 {
+			record.SetSeverityNumber(plog.SeverityNumberError)
+			record.SetSeverityText("Error")
+			record.Body().SetStr(fmt.Sprintf("%s failed", span.Name()))
+			continue
+		}
+		record.SetSeverityNumber(plog.SeverityNumberInfo)
+		record.SetSeverityText("Info")
+		record.Body().SetStr(fmt.Sprintf("%s in progress", span.Name()))
+	}
+}
+
+func (g *generator) spans(t *trace, key instanceKey, svc *Service) ptrace.SpanSlice {
+	spans, ok := t.spans[key]
+	if !ok {
+		rs := t.traces.ResourceSpans().AppendEmpty()
+		fillResource(rs.Resource(), key, svc, g.resourceAttributes)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName(scopeName)
+		spans = ss.Spans()
+		t.spans[key] = spans
+	}
+	return spans
+}
+
+func (g *generator) logRecords(t *trace, key instanceKey, svc *Service) plog.LogRecordSlice {
+	records, ok := t.records[key]
+	if !ok {
+		rl := t.logs.ResourceLogs().AppendEmpty()
+		fillResource(rl.Resource(), key, svc, g.resourceAttributes)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(scopeName)
+		records = sl.LogRecords()
+		t.records[key] = records
+	}
+	return records
+}
+
+func fillResource(res pcommon.Resource, key instanceKey, svc *Service, resourceAttributes []attribute.KeyValue) {
+	for _, attr := range resourceAttributes {
+		res.Attributes().PutStr(string(attr.Key), attr.Value.AsString())
+	}
+	for k, v := range svc.ResourceAttributes {
+		res.Attributes().PutStr(k, v)
+	}
+	res.Attributes().PutStr(semconv.AttributeServiceName, key.service)
+	res.Attributes().PutStr(semconv.AttributeServiceInstanceID, key.id())
+}
+
+func (g *generator) traceID() pcommon.TraceID {
+	var id pcommon.TraceID
+	_, _ = g.rnd.Read(id[:])
+	return id
+}
+
+func (g *generator) spanID() pcommon.SpanID {
+	var id pcommon.SpanID
+	_, _ = g.rnd.Read(id[:])
+	return id
+}
+
+func spanKind(kind string) ptrace.SpanKind {
+	switch kind {
+	case kindConsumer:
+		return ptrace.SpanKindConsumer
+	case kindInternal:
+		return ptrace.SpanKindInternal
+	default:
+		return ptrace.SpanKindServer
+	}
+}
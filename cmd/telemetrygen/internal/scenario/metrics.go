@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	requestsMetric = "requests"
+	durationMetric = "request.duration"
+
+	statusOk    = "ok"
+	statusError = "error"
+)
+
+// durationBounds are the bounds of the buckets of the duration histogram, in milliseconds.
+var durationBounds = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type seriesKey struct {
+	operation string
+	status    string
+}
+
+type series struct {
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+type instanceSeries struct {
+	service *Service
+	series  map[seriesKey]*series
+}
+
+// aggregator accumulates the requests served by each service instance into cumulative metrics.
+// It's shared by all the workers.
+type aggregator struct {
+	mu                 sync.Mutex
+	start              time.Time
+	resourceAttributes []attribute.KeyValue
+	instances          map[instanceKey]*instanceSeries
+}
+
+func newAggregator(start time.Time, resourceAttributes []attribute.KeyValue) *aggregator {
+	return &aggregator{
+		start:              start,
+		resourceAttributes: resourceAttributes,
+		instances:          make(map[instanceKey]*instanceSeries),
+	}
+}
+
+func (a *aggregator) record(key instanceKey, svc *Service, operation string, failed bool, duration time.Duration) {
+	status := statusOk
+	if failed {
+		status = statusError
+	}
+	ms := float64(duration) / float64(time.Millisecond)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	inst, ok := a.instances[key]
+	if !ok {
+		inst = &instanceSeries{service: svc, series: make(map[seriesKey]*series)}
+		a.instances[key] = inst
+	}
+	s, ok := inst.series[seriesKey{operation: operation, status: status}]
+	if !ok {
+		s = &series{bucketCounts: make([]uint64, len(durationBounds)+1)}
+		inst.series[seriesKey{operation: operation, status: status}] = s
+	}
+	s.count++
+	s.sum += ms
+	s.bucketCounts[sort.SearchFloat64s(durationBounds, ms)]++
+}
+
+// collect returns the current value of the metrics of all the service instances.
+func (a *aggregator) collect(now time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	startTs := pcommon.NewTimestampFromTime(a.start)
+	nowTs := pcommon.NewTimestampFromTime(now)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, inst := range a.instances {
+		rm := md.ResourceMetrics().AppendEmpty()
+		fillResource(rm.Resource(), key, inst.service, a.resourceAttributes)
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+
+		requests := sm.Metrics().AppendEmpty()
+		requests.SetName(requestsMetric)
+		requests.SetDescription("Number of requests served")
+		requests.SetUnit("{request}")
+		sum := requests.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		duration := sm.Metrics().AppendEmpty()
+		duration.SetName(durationMetric)
+		duration.SetDescription("Duration of the requests served")
+		duration.SetUnit("ms")
+		histogram := duration.SetEmptyHistogram()
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		for sk, s := range inst.series {
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(startTs)
+			dp.SetTimestamp(nowTs)
+			dp.SetIntValue(int64(s.count))
+			dp.Attributes().PutStr("operation", sk.operation)
+			dp.Attributes().PutStr("status", sk.status)
+
+			hdp := histogram.DataPoints().AppendEmpty()
+			hdp.SetStartTimestamp(startTs)
+			hdp.SetTimestamp(nowTs)
+			hdp.SetCount(s.count)
+			hdp.SetSum(s.sum)
+			hdp.ExplicitBounds().FromRaw(durationBounds)
+			hdp.BucketCounts().FromRaw(s.bucketCounts)
+			hdp.Attributes().PutStr("operation", sk.operation)
+			hdp.Attributes().PutStr("status", sk.status)
+		}
+	}
+	return md
+}
@@ -0,0 +1,343 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	kindServer   = "server"
+	kindConsumer = "consumer"
+	kindInternal = "internal"
+
+	distributionConstant    = "constant"
+	distributionUniform     = "uniform"
+	distributionNormal      = "normal"
+	distributionExponential = "exponential"
+)
+
+// Scenario describes the topology of the simulated system: its services, the operations they
+// serve and the calls between them.
+type Scenario struct {
+	Services    []*Service    `yaml:"services"`
+	Entrypoints []*Entrypoint `yaml:"entrypoints"`
+
+	services map[string]*Service
+}
+
+// Service is a simulated service, running as one or more instances.
+type Service struct {
+	Name               string            `yaml:"name"`
+	Instances          int               `yaml:"instances"`
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+	Operations         []*Operation      `yaml:"operations"`
+
+	operations map[string]*Operation
+}
+
+// Operation is an operation served by a service, producing one span each time it is invoked.
+type Operation struct {
+	Name       string               `yaml:"name"`
+	Kind       string               `yaml:"kind"`
+	Latency    Latency              `yaml:"latency"`
+	ErrorRate  float64              `yaml:"error_rate"`
+	Attributes map[string]Attribute `yaml:"attributes"`
+	Logs       int                  `yaml:"logs"`
+	Calls      []*Call              `yaml:"calls"`
+}
+
+// Call is an edge of the call graph, from the operation it belongs to to an operation of another service.
+type Call struct {
+	Service     string   `yaml:"service"`
+	Operation   string   `yaml:"operation"`
+	Probability *float64 `yaml:"probability"`
+}
+
+// Entrypoint is an operation traces start from. Entrypoints are picked in proportion to their weight.
+type Entrypoint struct {
+	Service   string  `yaml:"service"`
+	Operation string  `yaml:"operation"`
+	Weight    float64 `yaml:"weight"`
+}
+
+// Latency is the distribution of the time an operation spends on its own, excluding the calls it makes.
+type Latency struct {
+	Distribution string        `yaml:"distribution"`
+	Mean         time.Duration `yaml:"mean"`
+	StdDev       time.Duration `yaml:"stddev"`
+	Min          time.Duration `yaml:"min"`
+	Max          time.Duration `yaml:"max"`
+}
+
+// Attribute is a span attribute, either a fixed value, one of a list of values, or one of
+// cardinality values generated from the value used as prefix.
+type Attribute struct {
+	Value       string   `yaml:"value"`
+	Values      []string `yaml:"values"`
+	Cardinality int      `yaml:"cardinality"`
+}
+
+// UnmarshalYAML allows attributes with a fixed value to be written as a plain scalar.
+func (a *Attribute) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		a.Value = node.Value
+		return nil
+	}
+	type plain Attribute
+	return node.Decode((*plain)(a))
+}
+
+// Load reads and validates the scenario file at path.
+func Load(path string) (*Scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+	return Parse(content)
+}
+
+// Parse decodes and validates a scenario.
+func Parse(content []byte) (*Scenario, error) {
+	s := &Scenario{}
+	if err := yaml.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the scenario is consistent and fills in the defaults.
+func (s *Scenario) Validate() error {
+	if len(s.Services) == 0 {
+		return errors.New("scenario must define at least one service")
+	}
+	if len(s.Entrypoints) == 0 {
+		return errors.New("scenario must define at least one entrypoint")
+	}
+
+	s.services = make(map[string]*Service, len(s.Services))
+	for _, svc := range s.Services {
+		if svc.Name == "" {
+			return errors.New("service name must not be empty")
+		}
+		if _, ok := s.services[svc.Name]; ok {
+			return fmt.Errorf("duplicate service %q", svc.Name)
+		}
+		if err := svc.validate(); err != nil {
+			return fmt.Errorf("service %q: %w", svc.Name, err)
+		}
+		s.services[svc.Name] = svc
+	}
+
+	for _, svc := range s.Services {
+		for _, op := range svc.Operations {
+			for _, call := range op.Calls {
+				if _, err := s.operation(call.Service, call.Operation); err != nil {
+					return fmt.Errorf("service %q, operation %q: %w", svc.Name, op.Name, err)
+				}
+			}
+		}
+	}
+
+	for _, ep := range s.Entrypoints {
+		if _, err := s.operation(ep.Service, ep.Operation); err != nil {
+			return fmt.Errorf("entrypoint: %w", err)
+		}
+		if ep.Weight < 0 {
+			return fmt.Errorf("entrypoint %s/%s: weight must not be negative", ep.Service, ep.Operation)
+		}
+		if ep.Weight == 0 {
+			ep.Weight = 1
+		}
+	}
+
+	return s.checkCycles()
+}
+
+func (svc *Service) validate() error {
+	if svc.Instances < 0 {
+		return errors.New("instances must not be negative")
+	}
+	if svc.Instances == 0 {
+		svc.Instances = 1
+	}
+	if len(svc.Operations) == 0 {
+		return errors.New("service must define at least one operation")
+	}
+
+	svc.operations = make(map[string]*Operation, len(svc.Operations))
+	for _, op := range svc.Operations {
+		if op.Name == "" {
+			return errors.New("operation name must not be empty")
+		}
+		if _, ok := svc.operations[op.Name]; ok {
+			return fmt.Errorf("duplicate operation %q", op.Name)
+		}
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("operation %q: %w", op.Name, err)
+		}
+		svc.operations[op.Name] = op
+	}
+	return nil
+}
+
+func (op *Operation) validate() error {
+	switch op.Kind {
+	case "":
+		op.Kind = kindServer
+	case kindServer, kindConsumer, kindInternal:
+	default:
+		return fmt.Errorf("unsupported kind %q", op.Kind)
+	}
+	if op.ErrorRate < 0 || op.ErrorRate > 1 {
+		return errors.New("error_rate must be between 0 and 1")
+	}
+	if op.Logs < 0 {
+		return errors.New("logs must not be negative")
+	}
+	for name, attr := range op.Attributes {
+		if attr.Cardinality < 0 {
+			return fmt.Errorf("attribute %q: cardinality must not be negative", name)
+		}
+	}
+	for _, call := range op.Calls {
+		if call.Probability == nil {
+			p := 1.0
+			call.Probability = &p
+		}
+		if *call.Probability < 0 || *call.Probability > 1 {
+			return fmt.Errorf("call to %s/%s: probability must be between 0 and 1", call.Service, call.Operation)
+		}
+	}
+	return op.Latency.validate()
+}
+
+func (l *Latency) validate() error {
+	if l.Mean < 0 || l.StdDev < 0 || l.Min < 0 || l.Max < 0 {
+		return errors.New("latency must not be negative")
+	}
+	if l.Max > 0 && l.Min > l.Max {
+		return errors.New("latency min must not be greater than max")
+	}
+	switch l.Distribution {
+	case "":
+		l.Distribution = distributionConstant
+	case distributionConstant, distributionNormal, distributionExponential:
+	case distributionUniform:
+		if l.Max == 0 {
+			return errors.New("uniform latency requires max")
+		}
+	default:
+		return fmt.Errorf("unsupported latency distribution %q", l.Distribution)
+	}
+	return nil
+}
+
+func (s *Scenario) operation(service, operation string) (*Operation, error) {
+	svc, ok := s.services[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", service)
+	}
+	op, ok := svc.operations[operation]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %q of service %q", operation, service)
+	}
+	return op, nil
+}
+
+// checkCycles makes sure the call graph is acyclic, so that every trace is finite.
+func (s *Scenario) checkCycles() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*Operation]int)
+
+	var visit func(svc string, op *Operation) error
+	visit = func(svc string, op *Operation) error {
+		switch state[op] {
+		case visiting:
+			return fmt.Errorf("call graph has a cycle through %s/%s", svc, op.Name)
+		case visited:
+			return nil
+		}
+		state[op] = visiting
+		for _, call := range op.Calls {
+			callee, _ := s.operation(call.Service, call.Operation)
+			if err := visit(call.Service, callee); err != nil {
+				return err
+			}
+		}
+		state[op] = visited
+		return nil
+	}
+
+	for _, svc := range s.Services {
+		for _, op := range svc.Operations {
+			if err := visit(svc.Name, op); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sample draws a duration from the distribution, clamped to [Min, Max] when they are set.
+func (l Latency) sample(rnd *rand.Rand) time.Duration {
+	var d float64
+	switch l.Distribution {
+	case distributionUniform:
+		d = float64(l.Min) + rnd.Float64()*float64(l.Max-l.Min)
+	case distributionNormal:
+		d = float64(l.Mean) + rnd.NormFloat64()*float64(l.StdDev)
+	case distributionExponential:
+		d = rnd.ExpFloat64() * float64(l.Mean)
+	default:
+		d = float64(l.Mean)
+	}
+
+	d = math.Max(d, float64(l.Min))
+	if l.Max > 0 {
+		d = math.Min(d, float64(l.Max))
+	}
+	return time.Duration(d)
+}
+
+// value picks the value of the attribute.
+func (a Attribute) value(rnd *rand.Rand) string {
+	switch {
+	case len(a.Values) > 0:
+		return a.Values[rnd.Intn(len(a.Values))]
+	case a.Cardinality > 0:
+		return fmt.Sprintf("%s%d", a.Value, rnd.Intn(a.Cardinality))
+	default:
+		return a.Value
+	}
+}
+
+// pickEntrypoint picks an entrypoint in proportion to the weights.
+func (s *Scenario) pickEntrypoint(rnd *rand.Rand) *Entrypoint {
+	total := 0.0
+	for _, ep := range s.Entrypoints {
+		total += ep.Weight
+	}
+	x := rnd.Float64() * total
+	for _, ep := range s.Entrypoints {
+		if x < ep.Weight {
+			return ep
+		}
+		x -= ep.Weight
+	}
+	return s.Entrypoints[len(s.Entrypoints)-1]
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	s, err := Load(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	require.Len(t, s.Services, 4)
+	frontend := s.services["frontend"]
+	assert.Equal(t, 2, frontend.Instances)
+	assert.Equal(t, map[string]string{"deployment.environment": "test"}, frontend.ResourceAttributes)
+
+	checkout := frontend.operations["GET /checkout"]
+	assert.Equal(t, kindServer, checkout.Kind)
+	assert.Equal(t, Latency{Distribution: distributionNormal, Mean: 20 * time.Millisecond, StdDev: 5 * time.Millisecond, Min: time.Millisecond}, checkout.Latency)
+	assert.Equal(t, Attribute{Value: "GET"}, checkout.Attributes["http.method"])
+	assert.Equal(t, Attribute{Value: "user-", Cardinality: 100}, checkout.Attributes["user.id"])
+	assert.Equal(t, 1.0, *checkout.Calls[0].Probability)
+
+	assert.Equal(t, 1, s.services["checkout"].Instances)
+	assert.Equal(t, kindInternal, s.services["checkout"].operations["validate"].Kind)
+	assert.Equal(t, distributionConstant, s.services["checkout"].operations["validate"].Latency.Distribution)
+	assert.Equal(t, 0.9, *s.services["checkout"].operations["PlaceOrder"].Calls[2].Probability)
+	assert.Equal(t, 1.0, s.Entrypoints[0].Weight)
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		err      string
+	}{
+		{
+			name:     "no services",
+			scenario: "entrypoints: [{service: a, operation: op}]",
+			err:      "scenario must define at least one service",
+		},
+		{
+			name:     "no entrypoints",
+			scenario: "services: [{name: a, operations: [{name: op}]}]",
+			err:      "scenario must define at least one entrypoint",
+		},
+		{
+			name:     "duplicate service",
+			scenario: "services: [{name: a, operations: [{name: op}]}, {name: a, operations: [{name: op}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `duplicate service "a"`,
+		},
+		{
+			name:     "no operations",
+			scenario: "services: [{name: a}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a": service must define at least one operation`,
+		},
+		{
+			name:     "invalid kind",
+			scenario: "services: [{name: a, operations: [{name: op, kind: client}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a": operation "op": unsupported kind "client"`,
+		},
+		{
+			name:     "invalid error rate",
+			scenario: "services: [{name: a, operations: [{name: op, error_rate: 2}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a": operation "op": error_rate must be between 0 and 1`,
+		},
+		{
+			name:     "invalid distribution",
+			scenario: "services: [{name: a, operations: [{name: op, latency: {distribution: pareto}}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a": operation "op": unsupported latency distribution "pareto"`,
+		},
+		{
+			name:     "uniform without max",
+			scenario: "services: [{name: a, operations: [{name: op, latency: {distribution: uniform, min: 1ms}}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a": operation "op": uniform latency requires max`,
+		},
+		{
+			name:     "unknown callee",
+			scenario: "services: [{name: a, operations: [{name: op, calls: [{service: b, operation: op}]}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      `service "a", operation "op": unknown service "b"`,
+		},
+		{
+			name:     "unknown entrypoint",
+			scenario: "services: [{name: a, operations: [{name: op}]}]\nentrypoints: [{service: a, operation: other}]",
+			err:      `entrypoint: unknown operation "other" of service "a"`,
+		},
+		{
+			name:     "cycle",
+			scenario: "services: [{name: a, operations: [{name: op, calls: [{service: b, operation: op}]}]}, {name: b, operations: [{name: op, calls: [{service: a, operation: op}]}]}]\nentrypoints: [{service: a, operation: op}]",
+			err:      "call graph has a cycle through a/op",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.scenario))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestLatencySample(t *testing.T) {
+	rnd := newTestRand()
+
+	assert.Equal(t, 10*time.Millisecond, Latency{Distribution: distributionConstant, Mean: 10 * time.Millisecond}.sample(rnd))
+
+	for i := 0; i < 100; i++ {
+		d := Latency{Distribution: distributionUniform, Min: time.Millisecond, Max: 2 * time.Millisecond}.sample(rnd)
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+		assert.LessOrEqual(t, d, 2*time.Millisecond)
+
+		d = Latency{Distribution: distributionNormal, Mean: time.Millisecond, StdDev: time.Second}.sample(rnd)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+
+		d = Latency{Distribution: distributionExponential, Mean: time.Second, Max: 2 * time.Second}.sample(rnd)
+		assert.LessOrEqual(t, d, 2*time.Second)
+	}
+}
+
+func TestAttributeValue(t *testing.T) {
+	rnd := newTestRand()
+
+	assert.Equal(t, "value", Attribute{Value: "value"}.value(rnd))
+	assert.Contains(t, []string{"a", "b"}, Attribute{Values: []string{"a", "b"}}.value(rnd))
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		seen[Attribute{Value: "user-", Cardinality: 3}.value(rnd)] = true
+	}
+	assert.Equal(t, map[string]bool{"user-0": true, "user-1": true, "user-2": true}, seen)
+}
+
+func TestPickEntrypoint(t *testing.T) {
+	s, err := Parse([]byte(`
+services: [{name: a, operations: [{name: x}, {name: y}]}]
+entrypoints: [{service: a, operation: x, weight: 3}, {service: a, operation: y}]
+`))
+	require.NoError(t, err)
+
+	rnd := newTestRand()
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		counts[s.pickEntrypoint(rnd).Operation]++
+	}
+	assert.InDelta(t, 3000, counts["x"], 200)
+	assert.InDelta(t, 1000, counts["y"], 200)
+}
+
+func newTestRand() *rand.Rand {
+	return rand.New(rand.NewSource(1))
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scenario generates the traces, logs and metrics of a system of services described by a scenario file.
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+// Start starts the scenario telemetry generator
+func Start(cfg *Config) error {
+	logger, err := common.CreateLogger(cfg.SkipSettingGRPCLogger)
+	if err != nil {
+		return err
+	}
+
+	if cfg.File == "" {
+		return fmt.Errorf("a scenario `file` must be provided")
+	}
+	s, err := Load(cfg.File)
+	if err != nil {
+		return err
+	}
+
+	exp, err := otlpexporter.New(context.Background(), &cfg.Config, cfg.URLPaths)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		logger.Info("stopping the exporter")
+		if tempError := exp.Shutdown(); tempError != nil {
+			logger.Error("failed to stop the exporter", zap.Error(tempError))
+		}
+	}()
+
+	return Run(cfg, s, exp, logger)
+}
+
+// Run executes the test scenario.
+func Run(c *Config, s *Scenario, exp otlpexporter.Exporter, logger *zap.Logger) error {
+	if c.TotalDuration > 0 {
+		c.NumTraces = 0
+	} else if c.NumTraces <= 0 {
+		return fmt.Errorf("either `traces` or `duration` must be greater than 0")
+	}
+
+	limit := rate.Limit(c.Rate)
+	if c.Rate == 0 {
+		limit = rate.Inf
+		logger.Info("generation of traces isn't being throttled")
+	} else {
+		logger.Info("generation of traces is limited", zap.Float64("per-second", float64(limit)))
+	}
+
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	metrics := newAggregator(time.Now(), c.GetAttributes())
+	wg := sync.WaitGroup{}
+	running := &atomic.Bool{}
+	running.Store(true)
+
+	for i := 0; i < c.WorkerCount; i++ {
+		wg.Add(1)
+		w := worker{
+			generator: &generator{
+				scenario:            s,
+				rnd:                 rand.New(rand.NewSource(seed + int64(i))),
+				resourceAttributes:  c.GetAttributes(),
+				telemetryAttributes: c.GetTelemetryAttributes(),
+				metrics:             metrics,
+			},
+			numTraces:      c.NumTraces,
+			limitPerSecond: limit,
+			totalDuration:  c.TotalDuration,
+			running:        running,
+			wg:             &wg,
+			logger:         logger.With(zap.Int("worker", i)),
+		}
+
+		go w.simulate(exp)
+	}
+
+	done := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		reportMetrics(c.ReportingInterval, metrics, exp, done, logger)
+	}()
+
+	if c.TotalDuration > 0 {
+		time.Sleep(c.TotalDuration)
+		running.Store(false)
+	}
+	wg.Wait()
+	close(done)
+	<-reported
+	return nil
+}
+
+// reportMetrics exports the metrics every interval, and a last time once done is closed.
+func reportMetrics(interval time.Duration, metrics *aggregator, exp otlpexporter.Exporter, done <-chan struct{}, logger *zap.Logger) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	export := func() {
+		if err := exp.ExportMetrics(metrics.collect(time.Now())); err != nil {
+			logger.Error("failed to export metrics", zap.Error(err))
+		}
+	}
+	for {
+		select {
+		case <-tick:
+			export()
+		case <-done:
+			export()
+			return
+		}
+	}
+}
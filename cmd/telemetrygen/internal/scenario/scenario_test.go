@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+type mockExporter struct {
+	mu      sync.Mutex
+	traces  []ptrace.Traces
+	metrics []pmetric.Metrics
+	logs    []plog.Logs
+}
+
+func (m *mockExporter) ExportTraces(traces ptrace.Traces) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.traces = append(m.traces, traces)
+	return nil
+}
+
+func (m *mockExporter) ExportMetrics(metrics pmetric.Metrics) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, metrics)
+	return nil
+}
+
+func (m *mockExporter) ExportLogs(logs plog.Logs) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, logs)
+	return nil
+}
+
+func (m *mockExporter) Shutdown() error {
+	return nil
+}
+
+func loadTestScenario(t *testing.T) *Scenario {
+	s, err := Load(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+	return s
+}
+
+func TestFixedNumberOfTraces(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount: 2,
+		},
+		NumTraces: 5,
+		Seed:      1,
+	}
+	exp := &mockExporter{}
+
+	require.NoError(t, Run(cfg, loadTestScenario(t), exp, zap.NewNop()))
+
+	require.Len(t, exp.traces, 10)
+	require.NotEmpty(t, exp.logs)
+	// the metrics are exported once when done
+	require.Len(t, exp.metrics, 1)
+
+	requests := int64(0)
+	rms := exp.metrics[0].ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		service, _ := rm.Resource().Attributes().Get("service.name")
+		if service.Str() != "frontend" {
+			continue
+		}
+		dps := rm.ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			requests += dps.At(j).IntValue()
+		}
+	}
+	assert.Equal(t, int64(10), requests)
+}
+
+func TestRateOfTraces(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			Rate:          10,
+			TotalDuration: time.Second / 2,
+			WorkerCount:   1,
+		},
+	}
+	exp := &mockExporter{}
+
+	require.NoError(t, Run(cfg, loadTestScenario(t), exp, zap.NewNop()))
+
+	// the minimum acceptable number of traces for the rate of 10/sec for half a second
+	assert.True(t, len(exp.traces) >= 5, "there should have been 5 or more traces, had %d", len(exp.traces))
+	// the maximum acceptable number of traces for the rate of 10/sec for half a second
+	assert.True(t, len(exp.traces) <= 20, "there should have been less than 20 traces, had %d", len(exp.traces))
+}
+
+func TestGenerateTopology(t *testing.T) {
+	s := loadTestScenario(t)
+	// always call the consumer, and never fail
+	p := 1.0
+	s.services["checkout"].operations["PlaceOrder"].Calls[2].Probability = &p
+	for _, svc := range s.Services {
+		for _, op := range svc.Operations {
+			op.ErrorRate = 0
+		}
+	}
+
+	start := time.Unix(1000, 0)
+	g := &generator{
+		scenario: s,
+		rnd:      newTestRand(),
+		metrics:  newAggregator(start, nil),
+	}
+	traces, logs := g.generate(start)
+
+	spans := make(map[string]ptrace.Span)
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		service, _ := rs.Resource().Attributes().Get("service.name")
+		instance, _ := rs.Resource().Attributes().Get("service.instance.id")
+		assert.Contains(t, instance.Str(), service.Str()+"-")
+		ss := rs.ScopeSpans().At(0).Spans()
+		for j := 0; j < ss.Len(); j++ {
+			key := service.Str() + "/" + ss.At(j).Name() + "/" + ss.At(j).Kind().String()
+			spans[key] = ss.At(j)
+		}
+	}
+	require.Len(t, spans, 8)
+
+	root := spans["frontend/GET /checkout/Server"]
+	assert.True(t, root.ParentSpanID().IsEmpty())
+	assert.Equal(t, "GET", getStr(root.Attributes(), "http.method"))
+	assert.Contains(t, getStr(root.Attributes(), "user.id"), "user-")
+
+	client := spans["frontend/PlaceOrder/Client"]
+	assert.Equal(t, root.SpanID(), client.ParentSpanID())
+	assert.Equal(t, "checkout", getStr(client.Attributes(), "peer.service"))
+
+	placeOrder := spans["checkout/PlaceOrder/Server"]
+	assert.Equal(t, client.SpanID(), placeOrder.ParentSpanID())
+	assert.Equal(t, client.EndTimestamp(), placeOrder.EndTimestamp())
+	assert.Equal(t, placeOrder.SpanID(), spans["checkout/validate/Internal"].ParentSpanID())
+	assert.Equal(t, placeOrder.SpanID(), spans["checkout/Reserve/Client"].ParentSpanID())
+	assert.Equal(t, spans["checkout/Reserve/Client"].SpanID(), spans["inventory/Reserve/Server"].ParentSpanID())
+
+	producer := spans["checkout/OrderPlaced/Producer"]
+	assert.Equal(t, producer.StartTimestamp(), producer.EndTimestamp())
+	assert.Equal(t, producer.SpanID(), spans["email/OrderPlaced/Consumer"].ParentSpanID())
+
+	for key, span := range spans {
+		assert.Equal(t, root.TraceID(), span.TraceID(), key)
+		assert.LessOrEqual(t, span.EndTimestamp(), root.EndTimestamp(), key)
+	}
+
+	// one log for the frontend and two for the checkout
+	assert.Equal(t, 3, logs.LogRecordCount())
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		records := logs.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords()
+		for j := 0; j < records.Len(); j++ {
+			assert.Equal(t, root.TraceID(), records.At(j).TraceID())
+			assert.False(t, records.At(j).SpanID().IsEmpty())
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	s, err := Parse([]byte(`
+services:
+  - name: a
+    operations: [{name: op, logs: 1, calls: [{service: b, operation: op}]}]
+  - name: b
+    operations: [{name: op, error_rate: 1}]
+entrypoints: [{service: a, operation: op}]
+`))
+	require.NoError(t, err)
+
+	start := time.Unix(1000, 0)
+	g := &generator{
+		scenario: s,
+		rnd:      newTestRand(),
+		metrics:  newAggregator(start, nil),
+	}
+	traces, logs := g.generate(start)
+
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		spans := traces.ResourceSpans().At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			// the error of the server is seen by its client, but the caller doesn't fail itself
+			if span.Kind() == ptrace.SpanKindServer && span.ParentSpanID().IsEmpty() {
+				assert.Equal(t, ptrace.StatusCodeUnset, span.Status().Code())
+			} else {
+				assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+			}
+		}
+	}
+	assert.Equal(t, plog.SeverityNumberInfo, logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).SeverityNumber())
+
+	md := g.metrics.collect(start.Add(time.Second))
+	assert.Equal(t, 2, md.ResourceMetrics().Len())
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service, _ := rm.Resource().Attributes().Get("service.name")
+		requests := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, requestsMetric, requests.Name())
+		dp := requests.Sum().DataPoints().At(0)
+		assert.Equal(t, int64(1), dp.IntValue())
+		status := statusOk
+		if service.Str() == "b" {
+			status = statusError
+		}
+		assert.Equal(t, status, getStr(dp.Attributes(), "status"))
+
+		duration := rm.ScopeMetrics().At(0).Metrics().At(1)
+		assert.Equal(t, durationMetric, duration.Name())
+		assert.Equal(t, uint64(1), duration.Histogram().DataPoints().At(0).Count())
+	}
+}
+
+func getStr(attrs pcommon.Map, key string) string {
+	v, _ := attrs.Get(key)
+	return v.Str()
+}
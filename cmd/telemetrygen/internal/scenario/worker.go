@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scenario
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/otlpexporter"
+)
+
+type worker struct {
+	generator      *generator      // generator of the traces and logs of the scenario
+	running        *atomic.Bool    // pointer to shared flag that indicates it's time to stop the test
+	numTraces      int             // how many traces the worker has to generate (only when duration==0)
+	totalDuration  time.Duration   // how long to run the test for (overrides `numTraces`)
+	limitPerSecond rate.Limit      // how many traces per second to generate
+	wg             *sync.WaitGroup // notify when done
+	logger         *zap.Logger     // logger
+}
+
+func (w worker) simulate(exp otlpexporter.Exporter) {
+	limiter := rate.NewLimiter(w.limitPerSecond, 1)
+	var i int
+
+	for w.running.Load() {
+		traces, logs := w.generator.generate(time.Now())
+
+		if err := exp.ExportTraces(traces); err != nil {
+			w.logger.Fatal("exporter failed", zap.Error(err))
+		}
+		if logs.LogRecordCount() > 0 {
+			if err := exp.ExportLogs(logs); err != nil {
+				w.logger.Fatal("exporter failed", zap.Error(err))
+			}
+		}
+		if err := limiter.Wait(context.Background()); err != nil {
+			w.logger.Fatal("limiter wait failed, retry", zap.Error(err))
+		}
+
+		i++
+		if w.numTraces != 0 && i >= w.numTraces {
+			break
+		}
+	}
+
+	w.logger.Info("traces generated", zap.Int("traces", i))
+	w.wg.Done()
+}
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	errNoPipelines           = errors.New("no pipelines are defined")
+	errNoDeadLetterPipelines = errors.New("no dead letter pipelines are defined")
+)
+
+type Config struct {
+	// Pipelines are the pipelines the data is routed to in a fanout.
+	Pipelines []component.ID `mapstructure:"pipelines"`
+
+	// DeadLetterPipelines are the pipelines the data permanently rejected by any of the Pipelines is routed to,
+	// annotated with the error which caused the rejection.
+	DeadLetterPipelines []component.ID `mapstructure:"dead_letter_pipelines"`
+}
+
+// Validate checks that both lists of pipelines are defined and don't overlap.
+func (c *Config) Validate() error {
+	if len(c.Pipelines) == 0 {
+		return errNoPipelines
+	}
+	if len(c.DeadLetterPipelines) == 0 {
+		return errNoDeadLetterPipelines
+	}
+	for _, id := range c.DeadLetterPipelines {
+		for _, pipeline := range c.Pipelines {
+			if id == pipeline {
+				return fmt.Errorf("pipeline %q can't be both a pipeline and a dead letter pipeline", id)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "full").String())
+	require.NoError(t, err)
+	require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, &Config{
+		Pipelines: []component.ID{
+			component.NewIDWithName(component.DataTypeTraces, "first"),
+			component.NewIDWithName(component.DataTypeTraces, "second"),
+		},
+		DeadLetterPipelines: []component.ID{
+			component.NewIDWithName(component.DataTypeTraces, "dlq"),
+		},
+	}, cfg)
+}
+
+func TestValidateConfig(t *testing.T) {
+	testcases := []struct {
+		name string
+		id   component.ID
+		err  string
+	}{
+		{
+			name: "no pipelines provided",
+			id:   component.NewIDWithName(metadata.Type, ""),
+			err:  errNoPipelines.Error(),
+		},
+		{
+			name: "no dead letter pipelines provided",
+			id:   component.NewIDWithName(metadata.Type, "no_dead_letter_pipelines"),
+			err:  errNoDeadLetterPipelines.Error(),
+		},
+		{
+			name: "pipeline in both lists",
+			id:   component.NewIDWithName(metadata.Type, "overlap"),
+			err:  `pipeline "traces/first" can't be both a pipeline and a dead letter pipeline`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tc.id.String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+			assert.EqualError(t, component.ValidateConfig(cfg), tc.err)
+		})
+	}
+}
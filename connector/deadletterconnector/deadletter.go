@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+const (
+	// errorAttribute is the resource attribute holding the error message of the rejection.
+	errorAttribute = "deadletter.error.message"
+	// pipelineAttribute is the resource attribute holding the pipeline which rejected the data.
+	pipelineAttribute = "deadletter.pipeline"
+)
+
+var errRouter = errors.New("consumer is not a router")
+
+type baseConsumer interface {
+	Capabilities() consumer.Capabilities
+}
+
+type consumerProvider[C any] func(...component.ID) (C, error)
+
+// signal holds the operations on the data D of a signal consumed by C which the router relies on.
+type signal[C baseConsumer, D any] struct {
+	consume func(C, context.Context, D) error
+	// failed returns the part of the data which failed, if the error reports it.
+	failed    func(error) (D, bool)
+	clone     func(D) D
+	resources func(D, func(pcommon.Resource))
+	count     func(D) int
+}
+
+// pipeline is the consumer of one of the pipelines the data is routed to.
+type pipeline[C any] struct {
+	name     string
+	consumer C
+}
+
+// deadLetterRouter holds the consumers of the pipelines and of the dead letter pipelines.
+type deadLetterRouter[C baseConsumer, D any] struct {
+	signal     signal[C, D]
+	pipelines  []pipeline[C]
+	deadLetter C
+	logger     *zap.Logger
+}
+
+func newDeadLetterRouter[C baseConsumer, D any](provider consumerProvider[C], s signal[C, D], cfg *Config, logger *zap.Logger) (*deadLetterRouter[C, D], error) {
+	// the pipelines are consumed one by one rather than in a fanout, to know which of them rejected the data
+	pipelines := make([]pipeline[C], 0, len(cfg.Pipelines))
+	for _, id := range cfg.Pipelines {
+		c, err := provider(id)
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, pipeline[C]{name: id.String(), consumer: c})
+	}
+	deadLetter, err := provider(cfg.DeadLetterPipelines...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetterRouter[C, D]{
+		signal:     s,
+		pipelines:  pipelines,
+		deadLetter: deadLetter,
+		logger:     logger,
+	}, nil
+}
+
+// route routes the data to each of the pipelines, and the data permanently rejected by any of them to the dead letter
+// pipelines. The other errors are returned, so that the data can be retried.
+func (r *deadLetterRouter[C, D]) route(ctx context.Context, data D) error {
+	var errs error
+	for _, p := range r.pipelines {
+		d := data
+		if p.consumer.Capabilities().MutatesData {
+			// the data is left untouched for the other pipelines and the dead letter pipelines
+			d = r.signal.clone(data)
+		}
+		err := r.signal.consume(p.consumer, ctx, d)
+		if err != nil && consumererror.IsPermanent(err) {
+			err = r.reject(ctx, p.name, data, err)
+		}
+		errs = errors.Join(errs, err)
+	}
+	return errs
+}
+
+// reject routes the data rejected by the pipeline to the dead letter pipelines, annotated with the error which caused
+// the rejection and the pipeline. The rejection is only reported if the dead letter pipelines failed as well.
+func (r *deadLetterRouter[C, D]) reject(ctx context.Context, pipeline string, data D, err error) error {
+	// only route the part of the data which failed if the pipeline reported it
+	if failed, ok := r.signal.failed(err); ok {
+		data = failed
+	}
+
+	rejected := r.signal.clone(data)
+	r.signal.resources(rejected, func(res pcommon.Resource) {
+		res.Attributes().PutStr(errorAttribute, err.Error())
+		res.Attributes().PutStr(pipelineAttribute, pipeline)
+	})
+	items := r.signal.count(rejected)
+
+	if deadLetterErr := r.signal.consume(r.deadLetter, ctx, rejected); deadLetterErr != nil {
+		r.logger.Error("Failed to route rejected data to the dead letter pipelines, dropping data",
+			zap.String("pipeline", pipeline), zap.Error(err), zap.NamedError("dead_letter_error", deadLetterErr), zap.Int("items", items))
+		return errors.Join(err, deadLetterErr)
+	}
+	r.logger.Debug("Routed rejected data to the dead letter pipelines",
+		zap.String("pipeline", pipeline), zap.Error(err), zap.Int("items", items))
+	return nil
+}
@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package deadletterconnector routes the data permanently rejected by a set of pipelines to dead letter pipelines.
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
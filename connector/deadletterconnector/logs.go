@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+var logsSignal = signal[consumer.Logs, plog.Logs]{
+	consume: consumer.Logs.ConsumeLogs,
+	failed: func(err error) (plog.Logs, bool) {
+		var failed consumererror.Logs
+		if errors.As(err, &failed) {
+			return failed.Data(), true
+		}
+		return plog.Logs{}, false
+	},
+	clone: func(ld plog.Logs) plog.Logs {
+		clone := plog.NewLogs()
+		ld.CopyTo(clone)
+		return clone
+	},
+	resources: func(ld plog.Logs, fn func(pcommon.Resource)) {
+		for i := 0; i < ld.ResourceLogs().Len(); i++ {
+			fn(ld.ResourceLogs().At(i).Resource())
+		}
+	},
+	count: plog.Logs.LogRecordCount,
+}
+
+type logsDeadLetter struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	router *deadLetterRouter[consumer.Logs, plog.Logs]
+}
+
+func (c *logsDeadLetter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeLogs routes the logs to the pipelines, and the logs they permanently reject to the dead letter pipelines
+func (c *logsDeadLetter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return c.router.route(ctx, ld)
+}
+
+func newLogsToLogs(set connector.CreateSettings, cfg component.Config, logs consumer.Logs) (connector.Logs, error) {
+	lr, ok := logs.(connector.LogsRouterAndConsumer)
+	if !ok {
+		return nil, errRouter
+	}
+
+	router, err := newDeadLetterRouter(lr.Consumer, logsSignal, cfg.(*Config), set.TelemetrySettings.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return &logsDeadLetter{router: router}, nil
+}
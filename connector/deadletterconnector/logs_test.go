@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestLogsPermanentError(t *testing.T) {
+	logsFirst := component.NewIDWithName(component.DataTypeLogs, "first")
+	logsDLQ := component.NewIDWithName(component.DataTypeLogs, "dlq")
+	cfg := &Config{
+		Pipelines:           []component.ID{logsFirst},
+		DeadLetterPipelines: []component.ID{logsDLQ},
+	}
+
+	var deadLetterSink consumertest.LogsSink
+	router := connector.NewLogsRouter(map[component.ID]consumer.Logs{
+		logsFirst: consumertest.NewErr(consumererror.NewPermanent(errors.New("malformed log"))),
+		logsDLQ:   &deadLetterSink,
+	})
+	conn, err := NewFactory().CreateLogsToLogs(context.Background(),
+		connectortest.NewNopCreateSettings(), cfg, router.(consumer.Logs))
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("log")
+	require.NoError(t, conn.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, deadLetterSink.AllLogs(), 1)
+	attrs := deadLetterSink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes()
+	v, ok := attrs.Get(errorAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "Permanent error: malformed log", v.Str())
+	v, ok = attrs.Get(pipelineAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "logs/first", v.Str())
+}
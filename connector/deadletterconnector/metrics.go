@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+var metricsSignal = signal[consumer.Metrics, pmetric.Metrics]{
+	consume: consumer.Metrics.ConsumeMetrics,
+	failed: func(err error) (pmetric.Metrics, bool) {
+		var failed consumererror.Metrics
+		if errors.As(err, &failed) {
+			return failed.Data(), true
+		}
+		return pmetric.Metrics{}, false
+	},
+	clone: func(md pmetric.Metrics) pmetric.Metrics {
+		clone := pmetric.NewMetrics()
+		md.CopyTo(clone)
+		return clone
+	},
+	resources: func(md pmetric.Metrics, fn func(pcommon.Resource)) {
+		for i := 0; i < md.ResourceMetrics().Len(); i++ {
+			fn(md.ResourceMetrics().At(i).Resource())
+		}
+	},
+	count: pmetric.Metrics.DataPointCount,
+}
+
+type metricsDeadLetter struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	router *deadLetterRouter[consumer.Metrics, pmetric.Metrics]
+}
+
+func (c *metricsDeadLetter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeMetrics routes the metrics to the pipelines, and the metrics they permanently reject to the dead letter pipelines
+func (c *metricsDeadLetter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return c.router.route(ctx, md)
+}
+
+func newMetricsToMetrics(set connector.CreateSettings, cfg component.Config, metrics consumer.Metrics) (connector.Metrics, error) {
+	mr, ok := metrics.(connector.MetricsRouterAndConsumer)
+	if !ok {
+		return nil, errRouter
+	}
+
+	router, err := newDeadLetterRouter(mr.Consumer, metricsSignal, cfg.(*Config), set.TelemetrySettings.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsDeadLetter{router: router}, nil
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestMetricsPermanentError(t *testing.T) {
+	metricsFirst := component.NewIDWithName(component.DataTypeMetrics, "first")
+	metricsDLQ := component.NewIDWithName(component.DataTypeMetrics, "dlq")
+	cfg := &Config{
+		Pipelines:           []component.ID{metricsFirst},
+		DeadLetterPipelines: []component.ID{metricsDLQ},
+	}
+
+	var deadLetterSink consumertest.MetricsSink
+	router := connector.NewMetricsRouter(map[component.ID]consumer.Metrics{
+		metricsFirst: consumertest.NewErr(consumererror.NewPermanent(errors.New("malformed metric"))),
+		metricsDLQ:   &deadLetterSink,
+	})
+	conn, err := NewFactory().CreateMetricsToMetrics(context.Background(),
+		connectortest.NewNopCreateSettings(), cfg, router.(consumer.Metrics))
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	require.NoError(t, conn.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, deadLetterSink.AllMetrics(), 1)
+	attrs := deadLetterSink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes()
+	v, ok := attrs.Get(errorAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "Permanent error: malformed metric", v.Str())
+	v, ok = attrs.Get(pipelineAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "metrics/first", v.Str())
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/deadletterconnector"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var tracesSignal = signal[consumer.Traces, ptrace.Traces]{
+	consume: consumer.Traces.ConsumeTraces,
+	failed: func(err error) (ptrace.Traces, bool) {
+		var failed consumererror.Traces
+		if errors.As(err, &failed) {
+			return failed.Data(), true
+		}
+		return ptrace.Traces{}, false
+	},
+	clone: func(td ptrace.Traces) ptrace.Traces {
+		clone := ptrace.NewTraces()
+		td.CopyTo(clone)
+		return clone
+	},
+	resources: func(td ptrace.Traces, fn func(pcommon.Resource)) {
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			fn(td.ResourceSpans().At(i).Resource())
+		}
+	},
+	count: ptrace.Traces.SpanCount,
+}
+
+type tracesDeadLetter struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	router *deadLetterRouter[consumer.Traces, ptrace.Traces]
+}
+
+func (c *tracesDeadLetter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces routes the traces to the pipelines, and the traces they permanently reject to the dead letter pipelines
+func (c *tracesDeadLetter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return c.router.route(ctx, td)
+}
+
+func newTracesToTraces(set connector.CreateSettings, cfg component.Config, traces consumer.Traces) (connector.Traces, error) {
+	tr, ok := traces.(connector.TracesRouterAndConsumer)
+	if !ok {
+		return nil, errRouter
+	}
+
+	router, err := newDeadLetterRouter(tr.Consumer, tracesSignal, cfg.(*Config), set.TelemetrySettings.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return &tracesDeadLetter{router: router}, nil
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletterconnector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	tracesFirst  = component.NewIDWithName(component.DataTypeTraces, "first")
+	tracesSecond = component.NewIDWithName(component.DataTypeTraces, "second")
+	tracesDLQ    = component.NewIDWithName(component.DataTypeTraces, "dlq")
+)
+
+func sampleTraces(spanNames ...string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "test")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for _, name := range spanNames {
+		spans.AppendEmpty().SetName(name)
+	}
+	return td
+}
+
+func newTestTracesConnector(t *testing.T, next consumer.Traces, deadLetter consumer.Traces) connector.Traces {
+	cfg := &Config{
+		Pipelines:           []component.ID{tracesFirst},
+		DeadLetterPipelines: []component.ID{tracesDLQ},
+	}
+	router := connector.NewTracesRouter(map[component.ID]consumer.Traces{
+		tracesFirst: next,
+		tracesDLQ:   deadLetter,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopCreateSettings(), cfg, router.(consumer.Traces))
+	require.NoError(t, err)
+	return conn
+}
+
+func TestTracesAccepted(t *testing.T) {
+	var sink, deadLetterSink consumertest.TracesSink
+	conn := newTestTracesConnector(t, &sink, &deadLetterSink)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTraces("span")))
+	assert.Len(t, sink.AllTraces(), 1)
+	assert.Empty(t, deadLetterSink.AllTraces())
+}
+
+func TestTracesPermanentError(t *testing.T) {
+	var deadLetterSink consumertest.TracesSink
+	conn := newTestTracesConnector(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("malformed span"))), &deadLetterSink)
+
+	td := sampleTraces("span")
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, deadLetterSink.AllTraces(), 1)
+	attrs := deadLetterSink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().AsRaw()
+	assert.Equal(t, map[string]any{
+		"service.name":    "test",
+		errorAttribute:    "Permanent error: malformed span",
+		pipelineAttribute: "traces/first",
+	}, attrs)
+
+	// the original data isn't modified
+	assert.Equal(t, 1, td.ResourceSpans().At(0).Resource().Attributes().Len())
+}
+
+func TestTracesPartiallyRejected(t *testing.T) {
+	var deadLetterSink consumertest.TracesSink
+	failed := sampleTraces("rejected")
+	err := consumererror.NewTraces(consumererror.NewPermanent(errors.New("malformed span")), failed)
+	conn := newTestTracesConnector(t, consumertest.NewErr(err), &deadLetterSink)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTraces("accepted", "rejected")))
+
+	require.Len(t, deadLetterSink.AllTraces(), 1)
+	spans := deadLetterSink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 1, spans.Len())
+	assert.Equal(t, "rejected", spans.At(0).Name())
+}
+
+func TestTracesFanoutRejectedByOnePipeline(t *testing.T) {
+	var sink, deadLetterSink consumertest.TracesSink
+	failed := sampleTraces("rejected")
+	err := consumererror.NewTraces(consumererror.NewPermanent(errors.New("malformed span")), failed)
+	cfg := &Config{
+		Pipelines:           []component.ID{tracesFirst, tracesSecond},
+		DeadLetterPipelines: []component.ID{tracesDLQ},
+	}
+	router := connector.NewTracesRouter(map[component.ID]consumer.Traces{
+		tracesFirst:  &sink,
+		tracesSecond: consumertest.NewErr(err),
+		tracesDLQ:    &deadLetterSink,
+	})
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopCreateSettings(), cfg, router.(consumer.Traces))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), sampleTraces("accepted", "rejected")))
+
+	// the pipeline which accepted the traces isn't affected by the rejection of the other one
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 2, sink.AllTraces()[0].SpanCount())
+
+	// only the traces rejected by the failing pipeline are routed, naming this pipeline
+	require.Len(t, deadLetterSink.AllTraces(), 1)
+	rejected := deadLetterSink.AllTraces()[0]
+	assert.Equal(t, 1, rejected.SpanCount())
+	v, ok := rejected.ResourceSpans().At(0).Resource().Attributes().Get(pipelineAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "traces/second", v.Str())
+}
+
+func TestTracesRetryableError(t *testing.T) {
+	var deadLetterSink consumertest.TracesSink
+	retryable := errors.New("connection refused")
+	conn := newTestTracesConnector(t, consumertest.NewErr(retryable), &deadLetterSink)
+
+	assert.ErrorIs(t, conn.ConsumeTraces(context.Background(), sampleTraces("span")), retryable)
+	assert.Empty(t, deadLetterSink.AllTraces())
+}
+
+func TestTracesDeadLetterError(t *testing.T) {
+	deadLetterErr := errors.New("disk full")
+	conn := newTestTracesConnector(t,
+		consumertest.NewErr(consumererror.NewPermanent(errors.New("malformed span"))),
+		consumertest.NewErr(deadLetterErr))
+
+	err := conn.ConsumeTraces(context.Background(), sampleTraces("span"))
+	assert.ErrorIs(t, err, deadLetterErr)
+	assert.True(t, consumererror.IsPermanent(err))
+}
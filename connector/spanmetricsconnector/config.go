@@ -79,6 +79,9 @@ type Config struct {
 
 	// Events defines the configuration for events section of spans.
 	Events EventsConfig `mapstructure:"events"`
+
+	// AdjustedCounts defines the configuration for weighing spans by the inverse of their sampling probability.
+	AdjustedCounts AdjustedCountsConfig `mapstructure:"adjusted_counts"`
 }
 
 type HistogramConfig struct {
@@ -109,6 +112,15 @@ type EventsConfig struct {
 	Dimensions []Dimension `mapstructure:"dimensions"`
 }
 
+type AdjustedCountsConfig struct {
+	// Enabled is a flag to count each span, and observe its duration, as many times as the number of spans
+	// it stands for: the inverse of the probability found in the `th` value of the OpenTelemetry trace state.
+	Enabled bool `mapstructure:"enabled"`
+	// FallbackAttribute is the numeric span attribute holding the adjusted count of spans without a `th` value,
+	// e.g. the legacy `sampling.priority`. Spans with neither are counted once.
+	FallbackAttribute string `mapstructure:"fallback_attribute"`
+}
+
 var _ component.ConfigValidator = (*Config)(nil)
 
 // Validate checks if the processor configuration is valid
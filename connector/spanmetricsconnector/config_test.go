@@ -125,6 +125,17 @@ func TestLoadConfig(t *testing.T) {
 				Histogram:                    HistogramConfig{Disable: false, Unit: defaultUnit},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "adjusted_counts"),
+			expected: &Config{
+				AggregationTemporality:   "AGGREGATION_TEMPORALITY_CUMULATIVE",
+				DimensionsCacheSize:      defaultDimensionsCacheSize,
+				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
+				MetricsFlushInterval:     15 * time.Second,
+				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				AdjustedCounts:           AdjustedCountsConfig{Enabled: true, FallbackAttribute: "sampling.priority"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -6,6 +6,8 @@ package spanmetricsconnector // import "github.com/open-telemetry/opentelemetry-
 import (
 	"bytes"
 	"context"
+	"math"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 const (
@@ -72,6 +75,9 @@ type connectorImp struct {
 	eDimensions []dimension
 
 	events EventsConfig
+
+	// rand rounds the adjusted counts, it is only used while holding the lock.
+	rand *rand.Rand
 }
 
 type resourceMetrics struct {
@@ -136,6 +142,7 @@ func newConnector(logger *zap.Logger, config component.Config, ticker *clock.Tic
 		ticker:                       ticker,
 		done:                         make(chan struct{}),
 		eDimensions:                  newDimensions(cfg.Events.Dimensions),
+		rand:                         rand.New(rand.NewSource(time.Now().UnixNano())),
 		events:                       cfg.Events,
 	}, nil
 }
@@ -351,6 +358,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 					duration = float64(endTime-startTime) / float64(unitDivider)
 				}
 				key := p.buildKey(serviceName, span, p.dimensions, resourceAttr)
+				count := p.adjustedCount(span)
 
 				attributes, ok := p.metricKeyToDimensions.Get(key)
 				if !ok {
@@ -361,7 +369,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 					// aggregate histogram metrics
 					h := histograms.GetOrCreate(key, attributes)
 					p.addExemplar(span, duration, h)
-					h.Observe(duration)
+					h.Observe(duration, count)
 
 				}
 				// aggregate sums metrics
@@ -369,7 +377,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 				if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 					s.AddExemplar(span.TraceID(), span.SpanID(), duration)
 				}
-				s.Add(1)
+				s.Add(count)
 
 				// aggregate events metrics
 				if p.events.Enabled {
@@ -393,7 +401,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 						if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 							e.AddExemplar(span.TraceID(), span.SpanID(), duration)
 						}
-						e.Add(1)
+						e.Add(count)
 					}
 				}
 			}
@@ -412,6 +420,51 @@ func (p *connectorImp) addExemplar(span ptrace.Span, duration float64, h metrics
 	h.AddExemplar(span.TraceID(), span.SpanID(), duration)
 }
 
+// adjustedCount returns the number of spans the span stands for when adjusted counts are enabled,
+// rounded randomly to one of the nearest integers since histogram buckets only hold whole counts.
+func (p *connectorImp) adjustedCount(span ptrace.Span) uint64 {
+	if !p.config.AdjustedCounts.Enabled {
+		return 1
+	}
+
+	if raw := span.TraceState().AsRaw(); raw != "" {
+		ts, err := sampling.NewW3CTraceState(raw)
+		if err == nil && ts.OTelValue().TValue() != "" {
+			return p.roundAdjustedCount(ts.OTelValue().AdjustedCount())
+		}
+	}
+
+	if p.config.AdjustedCounts.FallbackAttribute == "" {
+		return 1
+	}
+	v, ok := span.Attributes().Get(p.config.AdjustedCounts.FallbackAttribute)
+	if !ok {
+		return 1
+	}
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return p.roundAdjustedCount(float64(v.Int()))
+	case pcommon.ValueTypeDouble:
+		return p.roundAdjustedCount(v.Double())
+	default:
+		return 1
+	}
+}
+
+// roundAdjustedCount rounds the adjusted count down, or up with a probability equal to its fractional
+// part, so that the sum of the rounded counts is the sum of the adjusted counts on average. Spans are
+// counted at least once.
+func (p *connectorImp) roundAdjustedCount(count float64) uint64 {
+	if !(count > 1) {
+		return 1
+	}
+	whole, frac := math.Modf(count)
+	if p.rand.Float64() < frac {
+		whole++
+	}
+	return uint64(whole)
+}
+
 type resourceKey [16]byte
 
 func (p *connectorImp) createResourceKey(attr pcommon.Map) resourceKey {
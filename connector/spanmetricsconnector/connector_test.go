@@ -6,6 +6,7 @@ package spanmetricsconnector
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 const (
@@ -1550,3 +1552,124 @@ func TestExemplarsForSumMetrics(t *testing.T) {
 		}
 	}
 }
+
+func TestAdjustedCounts(t *testing.T) {
+	buildTraces := func() ptrace.Traces {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-a")
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+		// sampled with a probability of 1/2
+		span := spans.AppendEmpty()
+		span.SetName("op")
+		span.TraceState().FromRaw("ot=th:8")
+		span.SetEndTimestamp(pcommon.Timestamp(time.Millisecond))
+
+		// legacy adjusted count
+		span = spans.AppendEmpty()
+		span.SetName("op")
+		span.Attributes().PutInt("sampling.priority", 10)
+		span.SetEndTimestamp(pcommon.Timestamp(time.Millisecond))
+
+		span = spans.AppendEmpty()
+		span.SetName("op")
+		span.SetEndTimestamp(pcommon.Timestamp(time.Millisecond))
+		return traces
+	}
+
+	tests := []struct {
+		name           string
+		adjustedCounts AdjustedCountsConfig
+		histogram      func() HistogramConfig
+		wantCount      uint64
+	}{
+		{
+			name:      "disabled",
+			histogram: explicitHistogramsConfig,
+			wantCount: 3,
+		},
+		{
+			name:           "enabled without fallback attribute",
+			adjustedCounts: AdjustedCountsConfig{Enabled: true},
+			histogram:      explicitHistogramsConfig,
+			wantCount:      4,
+		},
+		{
+			name:           "enabled with explicit histogram",
+			adjustedCounts: AdjustedCountsConfig{Enabled: true, FallbackAttribute: "sampling.priority"},
+			histogram:      explicitHistogramsConfig,
+			wantCount:      13,
+		},
+		{
+			name:           "enabled with exponential histogram",
+			adjustedCounts: AdjustedCountsConfig{Enabled: true, FallbackAttribute: "sampling.priority"},
+			histogram:      exponentialHistogramsConfig,
+			wantCount:      13,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Histogram = tt.histogram()
+			cfg.AdjustedCounts = tt.adjustedCounts
+			c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+			require.NoError(t, err)
+			require.NoError(t, c.ConsumeTraces(context.Background(), buildTraces()))
+
+			metrics := c.buildMetrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 2, metrics.Len())
+			for i := 0; i < metrics.Len(); i++ {
+				metric := metrics.At(i)
+				switch metric.Type() {
+				case pmetric.MetricTypeSum:
+					require.Equal(t, 1, metric.Sum().DataPoints().Len())
+					assert.Equal(t, int64(tt.wantCount), metric.Sum().DataPoints().At(0).IntValue())
+				case pmetric.MetricTypeHistogram:
+					require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+					dp := metric.Histogram().DataPoints().At(0)
+					assert.Equal(t, tt.wantCount, dp.Count())
+					assert.InDelta(t, float64(tt.wantCount), dp.Sum(), 1e-9)
+				case pmetric.MetricTypeExponentialHistogram:
+					require.Equal(t, 1, metric.ExponentialHistogram().DataPoints().Len())
+					dp := metric.ExponentialHistogram().DataPoints().At(0)
+					assert.Equal(t, tt.wantCount, dp.Count())
+					assert.InDelta(t, float64(tt.wantCount), dp.Sum(), 1e-9)
+				default:
+					t.Fatalf("unexpected metric type %v", metric.Type())
+				}
+			}
+		})
+	}
+}
+
+func TestAdjustedCountsNotPowerOfTwo(t *testing.T) {
+	threshold, err := sampling.ProbabilityToThreshold(0.3)
+	require.NoError(t, err)
+
+	const spanCount = 30000
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-a")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < spanCount; i++ {
+		span := spans.AppendEmpty()
+		span.SetName("op")
+		span.TraceState().FromRaw("ot=th:" + threshold.TValue())
+	}
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.AdjustedCounts = AdjustedCountsConfig{Enabled: true}
+	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	require.NoError(t, err)
+	c.rand = rand.New(rand.NewSource(1))
+	require.NoError(t, c.ConsumeTraces(context.Background(), traces))
+
+	// each span counts as 3 or 4 spans, 3.33 on average
+	metrics := c.buildMetrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metric := metrics.At(i); metric.Type() == pmetric.MetricTypeSum {
+			assert.InEpsilon(t, spanCount/0.3, float64(metric.Sum().DataPoints().At(0).IntValue()), 0.01)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"errors"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
@@ -17,6 +18,7 @@ const (
 	svcRouting
 	metricNameRouting
 	resourceRouting
+	expressionRouting
 )
 
 // Config defines configuration for the exporter.
@@ -24,6 +26,28 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+	// RoutingExpression is an OTTL value expression the routing key is evaluated with, in the span context for
+	// traces, the datapoint context for metrics and the log context for logs. Exclusive with RoutingKey.
+	RoutingExpression string `mapstructure:"routing_expression"`
+
+	Balancing BalancingSettings `mapstructure:"balancing"`
+}
+
+// BalancingSettings defines how the routing keys are balanced among the backends
+type BalancingSettings struct {
+	// CapacityFactor bounds the number of active keys of each backend to this factor of the average, e.g. 1.25.
+	// A key is routed to the next backend of the ring when its backend is full. Disabled when 0.
+	CapacityFactor float64 `mapstructure:"capacity_factor"`
+	// KeyTimeout is the duration after which a key that wasn't routed anymore is no longer active.
+	KeyTimeout time.Duration `mapstructure:"key_timeout"`
+	// DrainingPeriod is the duration for which the active keys keep being routed to their backend after
+	// the list of backends changed. Disabled when 0.
+	DrainingPeriod time.Duration `mapstructure:"draining_period"`
+}
+
+// enabled returns whether the keys have to be tracked.
+func (b BalancingSettings) enabled() bool {
+	return b.CapacityFactor > 0 || b.DrainingPeriod > 0
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -66,3 +90,20 @@ type AWSCloudMapResolver struct {
 	Timeout       time.Duration            `mapstructure:"timeout"`
 	Port          *uint16                  `mapstructure:"port"`
 }
+
+// Validate checks if the exporter configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.RoutingExpression != "" && cfg.RoutingKey != "" {
+		return errors.New("routing_key and routing_expression can't be both set")
+	}
+	if cfg.Balancing.CapacityFactor != 0 && cfg.Balancing.CapacityFactor < 1 {
+		return errors.New("balancing capacity_factor must be greater than or equal to 1")
+	}
+	if cfg.Balancing.DrainingPeriod < 0 {
+		return errors.New("balancing draining_period must not be negative")
+	}
+	if cfg.Balancing.enabled() && cfg.Balancing.KeyTimeout <= 0 {
+		return errors.New("balancing key_timeout must be positive")
+	}
+	return nil
+}
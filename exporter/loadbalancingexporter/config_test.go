@@ -6,7 +6,9 @@ package loadbalancingexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
@@ -25,3 +27,63 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, component.UnmarshalConfig(sub, cfg))
 	require.NotNil(t, cfg)
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name              string
+		balancing         BalancingSettings
+		routingKey        string
+		routingExpression string
+		expectedErr       string
+	}{
+		{
+			name:      "default",
+			balancing: BalancingSettings{KeyTimeout: defaultKeyTimeout},
+		},
+		{
+			name:      "bounded loads and draining",
+			balancing: BalancingSettings{CapacityFactor: 1.25, KeyTimeout: time.Minute, DrainingPeriod: time.Minute},
+		},
+		{
+			name:        "capacity factor too low",
+			balancing:   BalancingSettings{CapacityFactor: 0.5, KeyTimeout: time.Minute},
+			expectedErr: "balancing capacity_factor must be greater than or equal to 1",
+		},
+		{
+			name:        "negative draining period",
+			balancing:   BalancingSettings{KeyTimeout: time.Minute, DrainingPeriod: -time.Minute},
+			expectedErr: "balancing draining_period must not be negative",
+		},
+		{
+			name:        "no key timeout",
+			balancing:   BalancingSettings{DrainingPeriod: time.Minute},
+			expectedErr: "balancing key_timeout must be positive",
+		},
+		{
+			name:              "routing expression",
+			balancing:         BalancingSettings{KeyTimeout: defaultKeyTimeout},
+			routingExpression: `attributes["tenant"]`,
+		},
+		{
+			name:              "routing key and routing expression",
+			balancing:         BalancingSettings{KeyTimeout: defaultKeyTimeout},
+			routingKey:        "service",
+			routingExpression: `attributes["tenant"]`,
+			expectedErr:       "routing_key and routing_expression can't be both set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Balancing = tt.balancing
+			cfg.RoutingKey = tt.routingKey
+			cfg.RoutingExpression = tt.routingExpression
+			err := cfg.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
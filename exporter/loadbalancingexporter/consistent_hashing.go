@@ -41,12 +41,42 @@ func (h *hashRing) endpointFor(identifier []byte) string {
 		// perhaps the ring itself couldn't get initialized yet?
 		return ""
 	}
+	return h.findEndpoint(positionForIdentifier(identifier))
+}
+
+// boundedEndpointFor calculates which backend is responsible for the given identifier among the ones with capacity
+// left: starting from the position of the identifier, the ring is walked clockwise until an endpoint for which
+// hasCapacity returns true is found. When no endpoint has capacity left, the closest endpoint is returned.
+func (h *hashRing) boundedEndpointFor(identifier []byte, hasCapacity func(endpoint string) bool) string {
+	if h == nil || len(h.items) == 0 {
+		return ""
+	}
+	ringSize := len(h.items)
+	pos := positionForIdentifier(identifier)
+	start := sort.Search(ringSize, func(i int) bool {
+		return h.items[i].pos >= pos
+	}) % ringSize
+
+	checked := map[string]bool{}
+	for i := 0; i < ringSize; i++ {
+		endpoint := h.items[(start+i)%ringSize].endpoint
+		if checked[endpoint] {
+			continue
+		}
+		if hasCapacity(endpoint) {
+			return endpoint
+		}
+		checked[endpoint] = true
+	}
+	return h.items[start].endpoint
+}
+
+// positionForIdentifier calculates the position of the given identifier in the ring.
+func positionForIdentifier(identifier []byte) position {
 	hasher := crc32.NewIEEE()
 	hasher.Write(identifier)
 	hash := hasher.Sum32()
-	pos := hash % maxPositions
-
-	return h.findEndpoint(position(pos))
+	return position(hash % maxPositions)
 }
 
 // findEndpoint returns the "next" endpoint starting from the given position, or an empty string in case no endpoints are available
@@ -47,6 +47,29 @@ func TestEndpointFor(t *testing.T) {
 	}
 }
 
+func TestBoundedEndpointFor(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	ring := newHashRing(endpoints)
+	id := []byte{1, 2, 0, 0}
+	owner := ring.endpointFor(id)
+
+	// test and verify
+	assert.Equal(t, owner, ring.boundedEndpointFor(id, func(string) bool { return true }))
+	assert.Equal(t, owner, ring.boundedEndpointFor(id, func(string) bool { return false }))
+
+	var checked []string
+	spilled := ring.boundedEndpointFor(id, func(endpoint string) bool {
+		checked = append(checked, endpoint)
+		return endpoint != owner
+	})
+	assert.NotEqual(t, owner, spilled)
+	assert.Equal(t, []string{owner, spilled}, checked)
+
+	var nilRing *hashRing
+	assert.Equal(t, "", nilRing.boundedEndpointFor(id, func(string) bool { return true }))
+}
+
 func TestPositionsFor(t *testing.T) {
 	// prepare
 	endpoint := "host1"
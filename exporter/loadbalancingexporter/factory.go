@@ -38,6 +38,9 @@ func createDefaultConfig() component.Config {
 		Protocol: Protocol{
 			OTLP: *otlpDefaultCfg,
 		},
+		Balancing: BalancingSettings{
+			KeyTimeout: defaultKeyTimeout,
+		},
 	}
 }
 
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// assignment records the endpoint a key is routed to, and the generation of the ring it was assigned with.
+type assignment struct {
+	endpoint   string
+	generation uint64
+}
+
+// keyAssignments keeps track of the endpoints the active keys are routed to. A key stays active until it isn't
+// routed for keyTimeout, and keeps its endpoint while active:
+//   - with a capacity factor, a key is assigned to the first endpoint of the ring with less than
+//     ceil(capacityFactor * (active keys + 1) / endpoints) active keys, following Mirrokni et al.
+//   - with a draining period, the keys assigned before a change of the endpoints keep their endpoint
+//     until the draining period is over, so that in-flight traces aren't split among backends.
+type keyAssignments struct {
+	capacityFactor float64
+	keyTimeout     time.Duration
+	drainingPeriod time.Duration
+	now            func() time.Time
+
+	mu sync.Mutex
+	// current holds the keys routed since the last rotation, previous the ones routed in the period before.
+	current   map[string]assignment
+	previous  map[string]assignment
+	rotatedAt time.Time
+	// loads holds the number of active keys of each endpoint
+	loads map[string]int
+	total int
+
+	generation    uint64
+	endpoints     int
+	drainingUntil time.Time
+}
+
+func newKeyAssignments(cfg BalancingSettings) *keyAssignments {
+	return &keyAssignments{
+		capacityFactor: cfg.CapacityFactor,
+		keyTimeout:     cfg.KeyTimeout,
+		drainingPeriod: cfg.DrainingPeriod,
+		now:            time.Now,
+		current:        map[string]assignment{},
+		previous:       map[string]assignment{},
+		loads:          map[string]int{},
+	}
+}
+
+// onBackendChanges starts a new generation of assignments for the given number of endpoints. The keys assigned
+// with the previous generations are reassigned on their next lookup, after the draining period if any.
+func (a *keyAssignments) onBackendChanges(endpoints int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.generation++
+	a.endpoints = endpoints
+	if a.drainingPeriod > 0 {
+		a.drainingUntil = a.now().Add(a.drainingPeriod)
+	}
+}
+
+// endpointFor returns the endpoint the given identifier is routed to. The endpoint the identifier is assigned to is
+// kept as long as it's available, and it was assigned with the current ring or the ring is being drained.
+func (a *keyAssignments) endpointFor(ring *hashRing, identifier []byte, available func(endpoint string) bool) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.expire(now)
+
+	key := string(identifier)
+	if as, ok := a.lookup(key); ok {
+		if available(as.endpoint) && (as.generation == a.generation || now.Before(a.drainingUntil)) {
+			return as.endpoint
+		}
+		a.unassign(key, as)
+	}
+
+	var endpoint string
+	if a.capacityFactor > 0 {
+		capacity := a.capacity()
+		endpoint = ring.boundedEndpointFor(identifier, func(candidate string) bool {
+			return a.loads[candidate] < capacity
+		})
+	} else {
+		endpoint = ring.endpointFor(identifier)
+	}
+	if endpoint == "" {
+		return endpoint
+	}
+
+	a.current[key] = assignment{endpoint: endpoint, generation: a.generation}
+	a.loads[endpoint]++
+	a.total++
+	return endpoint
+}
+
+// capacity returns the maximum number of active keys an endpoint can be assigned.
+func (a *keyAssignments) capacity() int {
+	endpoints := max(a.endpoints, 1)
+	return int(math.Ceil(a.capacityFactor * float64(a.total+1) / float64(endpoints)))
+}
+
+// lookup returns the assignment of the key, moving it to the current period.
+func (a *keyAssignments) lookup(key string) (assignment, bool) {
+	if as, ok := a.current[key]; ok {
+		return as, true
+	}
+	as, ok := a.previous[key]
+	if ok {
+		delete(a.previous, key)
+		a.current[key] = as
+	}
+	return as, ok
+}
+
+func (a *keyAssignments) unassign(key string, as assignment) {
+	delete(a.current, key)
+	a.release(as)
+}
+
+func (a *keyAssignments) release(as assignment) {
+	a.total--
+	a.loads[as.endpoint]--
+	if a.loads[as.endpoint] <= 0 {
+		delete(a.loads, as.endpoint)
+	}
+}
+
+// expire forgets the keys that weren't routed for at least the key timeout.
+func (a *keyAssignments) expire(now time.Time) {
+	if now.Sub(a.rotatedAt) < a.keyTimeout {
+		return
+	}
+	for _, as := range a.previous {
+		a.release(as)
+	}
+	a.previous, a.current = a.current, map[string]assignment{}
+	if now.Sub(a.rotatedAt) >= 2*a.keyTimeout {
+		// the keys of the current period weren't routed for a whole period either
+		for _, as := range a.previous {
+			a.release(as)
+		}
+		a.previous = map[string]assignment{}
+	}
+	a.rotatedAt = now
+}
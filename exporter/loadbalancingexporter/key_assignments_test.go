@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestKeyAssignments(cfg BalancingSettings, endpoints []string) (*keyAssignments, *hashRing, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	a := newKeyAssignments(cfg)
+	a.now = clock.Now
+	a.onBackendChanges(len(endpoints))
+	return a, newHashRing(endpoints), clock
+}
+
+func allAvailable(string) bool {
+	return true
+}
+
+func TestKeyAssignmentsBoundedLoads(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	a, ring, _ := newTestKeyAssignments(BalancingSettings{CapacityFactor: 1.25, KeyTimeout: time.Minute}, endpoints)
+
+	// test
+	routed := map[string]string{}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		routed[key] = a.endpointFor(ring, []byte(key), allAvailable)
+	}
+
+	// verify
+	loads := map[string]int{}
+	for _, endpoint := range routed {
+		loads[endpoint]++
+	}
+	assert.Equal(t, loads, a.loads)
+	assert.Equal(t, 300, a.total)
+	for _, endpoint := range endpoints {
+		assert.LessOrEqual(t, loads[endpoint], 125, endpoint)
+	}
+
+	// the active keys keep their endpoint
+	for key, endpoint := range routed {
+		assert.Equal(t, endpoint, a.endpointFor(ring, []byte(key), allAvailable))
+	}
+	assert.Equal(t, 300, a.total)
+}
+
+func TestKeyAssignmentsHotEndpoint(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	a, ring, _ := newTestKeyAssignments(BalancingSettings{CapacityFactor: 1, KeyTimeout: time.Minute}, endpoints)
+
+	// test
+	var spilled int
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		if a.endpointFor(ring, key, allAvailable) != ring.endpointFor(key) {
+			spilled++
+		}
+	}
+
+	// verify
+	assert.Equal(t, 50, a.loads["endpoint-1"])
+	assert.Equal(t, 50, a.loads["endpoint-2"])
+	assert.Positive(t, spilled)
+}
+
+func TestKeyAssignmentsExpire(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	a, ring, clock := newTestKeyAssignments(BalancingSettings{CapacityFactor: 1.25, KeyTimeout: time.Minute}, endpoints)
+	a.endpointFor(ring, []byte("key-1"), allAvailable)
+	a.endpointFor(ring, []byte("key-2"), allAvailable)
+
+	// test: key-1 is routed again before its timeout, key-2 isn't
+	clock.now = clock.now.Add(50 * time.Second)
+	a.endpointFor(ring, []byte("key-1"), allAvailable)
+	clock.now = clock.now.Add(50 * time.Second)
+	a.endpointFor(ring, []byte("key-1"), allAvailable)
+	clock.now = clock.now.Add(60 * time.Second)
+	a.endpointFor(ring, []byte("key-3"), allAvailable)
+
+	// verify
+	assert.Equal(t, 2, a.total)
+	_, found := a.lookup("key-2")
+	assert.False(t, found)
+	_, found = a.lookup("key-1")
+	assert.True(t, found)
+
+	// test: nothing is routed for a while
+	clock.now = clock.now.Add(3 * time.Minute)
+	a.expire(clock.now)
+
+	// verify
+	assert.Equal(t, 0, a.total)
+	assert.Empty(t, a.loads)
+	assert.Empty(t, a.current)
+	assert.Empty(t, a.previous)
+}
+
+func TestKeyAssignmentsDraining(t *testing.T) {
+	// prepare
+	a, ring, clock := newTestKeyAssignments(BalancingSettings{KeyTimeout: time.Hour, DrainingPeriod: time.Minute}, []string{"endpoint-1"})
+	for i := 0; i < 100; i++ {
+		require.Equal(t, "endpoint-1", a.endpointFor(ring, []byte(fmt.Sprintf("key-%d", i)), allAvailable))
+	}
+
+	// test: scale out
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	ring = newHashRing(endpoints)
+	a.onBackendChanges(len(endpoints))
+
+	// verify: the active keys stay on their endpoint while draining, new ones follow the new ring
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "endpoint-1", a.endpointFor(ring, []byte(fmt.Sprintf("key-%d", i)), allAvailable))
+	}
+	newKey := []byte("new-key")
+	assert.Equal(t, ring.endpointFor(newKey), a.endpointFor(ring, newKey, allAvailable))
+
+	// verify: the keys are reassigned once the draining is over
+	clock.now = clock.now.Add(time.Minute)
+	var moved int
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		endpoint := a.endpointFor(ring, key, allAvailable)
+		assert.Equal(t, ring.endpointFor(key), endpoint)
+		if endpoint == "endpoint-2" {
+			moved++
+		}
+	}
+	assert.Positive(t, moved)
+	assert.Equal(t, 101, a.total)
+}
+
+func TestKeyAssignmentsUnavailableEndpoint(t *testing.T) {
+	// prepare
+	a, ring, _ := newTestKeyAssignments(BalancingSettings{KeyTimeout: time.Hour, DrainingPeriod: time.Minute}, []string{"endpoint-1"})
+	a.endpointFor(ring, []byte("key-1"), allAvailable)
+
+	ring = newHashRing([]string{"endpoint-2"})
+	a.onBackendChanges(1)
+
+	// test
+	endpoint := a.endpointFor(ring, []byte("key-1"), func(endpoint string) bool {
+		return endpoint != "endpoint-1"
+	})
+
+	// verify
+	assert.Equal(t, "endpoint-2", endpoint)
+	assert.Equal(t, map[string]int{"endpoint-2": 1}, a.loads)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
@@ -16,7 +17,8 @@ import (
 )
 
 const (
-	defaultPort = "4317"
+	defaultPort       = "4317"
+	defaultKeyTimeout = 30 * time.Second
 )
 
 var (
@@ -33,6 +35,13 @@ type loadBalancer struct {
 	res  resolver
 	ring *hashRing
 
+	// assignments tracks the backends of the active keys, when bounded loads or draining are enabled
+	assignments    *keyAssignments
+	drainingPeriod time.Duration
+	// resolved holds the latest list of backends, whose extra exporters are removed once the draining is over
+	resolved   []string
+	drainTimer *time.Timer
+
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
 
@@ -104,9 +113,16 @@ func newLoadBalancer(params exporter.CreateSettings, cfg component.Config, facto
 		return nil, errNoResolver
 	}
 
+	var assignments *keyAssignments
+	if oCfg.Balancing.enabled() {
+		assignments = newKeyAssignments(oCfg.Balancing)
+	}
+
 	return &loadBalancer{
 		logger:           params.Logger,
 		res:              res,
+		assignments:      assignments,
+		drainingPeriod:   oCfg.Balancing.DrainingPeriod,
 		componentFactory: factory,
 		exporters:        map[string]*wrappedExporter{},
 	}, nil
@@ -126,16 +142,40 @@ func (lb *loadBalancer) onBackendChanges(resolved []string) {
 		defer lb.updateLock.Unlock()
 
 		lb.ring = newRing
+		if lb.assignments != nil {
+			lb.assignments.onBackendChanges(len(resolved))
+		}
 
 		// TODO: set a timeout?
 		ctx := context.Background()
 
 		// add the missing exporters first
 		lb.addMissingExporters(ctx, resolved)
-		lb.removeExtraExporters(ctx, resolved)
+		if lb.drainingPeriod > 0 {
+			lb.drainExtraExporters(resolved)
+		} else {
+			lb.removeExtraExporters(ctx, resolved)
+		}
 	}
 }
 
+// drainExtraExporters keeps the exporters of the backends no longer resolved until the end of the
+// draining period, for the keys routed to them before the change.
+func (lb *loadBalancer) drainExtraExporters(resolved []string) {
+	lb.resolved = resolved
+	if lb.drainTimer != nil {
+		lb.drainTimer.Stop()
+	}
+	lb.drainTimer = time.AfterFunc(lb.drainingPeriod, func() {
+		lb.updateLock.Lock()
+		defer lb.updateLock.Unlock()
+		if lb.stopped {
+			return
+		}
+		lb.removeExtraExporters(context.Background(), lb.resolved)
+	})
+}
+
 func (lb *loadBalancer) addMissingExporters(ctx context.Context, endpoints []string) {
 	for _, endpoint := range endpoints {
 		endpoint = endpointWithPort(endpoint)
@@ -192,7 +232,12 @@ func endpointFound(endpoint string, endpoints []string) bool {
 
 func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 	err := lb.res.shutdown(ctx)
+	lb.updateLock.Lock()
 	lb.stopped = true
+	if lb.drainTimer != nil {
+		lb.drainTimer.Stop()
+	}
+	lb.updateLock.Unlock()
 	return err
 }
 
@@ -203,7 +248,12 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
-	endpoint := lb.ring.endpointFor(identifier)
+	var endpoint string
+	if lb.assignments != nil {
+		endpoint = lb.assignments.endpointFor(lb.ring, identifier, lb.hasExporter)
+	} else {
+		endpoint = lb.ring.endpointFor(identifier)
+	}
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
@@ -212,3 +262,9 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 
 	return exp, endpoint, nil
 }
+
+// hasExporter returns whether there is an exporter for the given endpoint. The update lock must be held.
+func (lb *loadBalancer) hasExporter(endpoint string) bool {
+	_, found := lb.exporters[endpointWithPort(endpoint)]
+	return found
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -241,6 +242,44 @@ func TestRemoveExtraExporters(t *testing.T) {
 	assert.NotContains(t, p.exporters, endpointWithPort("endpoint-2"))
 }
 
+func TestOnBackendChangesWithDraining(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.Balancing = BalancingSettings{KeyTimeout: time.Hour, DrainingPeriod: 100 * time.Millisecond}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(exportertest.NewNopCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	p.onBackendChanges([]string{"endpoint-1"})
+	traceID := []byte{1, 2, 3, 4}
+	_, endpoint, err := p.exporterAndEndpoint(traceID)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-2"})
+
+	// verify: the in-flight trace keeps going to the previous backend while draining
+	_, endpoint, err = p.exporterAndEndpoint(traceID)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-1", endpoint)
+
+	// verify: the exporter of the previous backend is removed once the draining is over
+	assert.Eventually(t, func() bool {
+		p.updateLock.RLock()
+		defer p.updateLock.RUnlock()
+		return len(p.exporters) == 1
+	}, time.Second, 10*time.Millisecond)
+	_, endpoint, err = p.exporterAndEndpoint(traceID)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
 func TestAddMissingExporters(t *testing.T) {
 	// prepare
 	cfg := simpleConfig()
@@ -20,12 +20,15 @@ import (
 	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 )
 
 var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
-	loadBalancer *loadBalancer
+	loadBalancer      *loadBalancer
+	routingExpression *ottl.ValueExpression[ottllog.TransformContext]
 
 	started    bool
 	shutdownWg sync.WaitGroup
@@ -43,9 +46,15 @@ func newLogsExporter(params exporter.CreateSettings, cfg component.Config) (*log
 		return nil, err
 	}
 
-	return &logExporterImp{
-		loadBalancer: lb,
-	}, nil
+	logExporter := logExporterImp{loadBalancer: lb}
+
+	if expression := cfg.(*Config).RoutingExpression; expression != "" {
+		logExporter.routingExpression, err = newLogRoutingExpression(expression, params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -69,6 +78,17 @@ func (e *logExporterImp) Shutdown(ctx context.Context) error {
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	var errs error
+	if e.routingExpression != nil {
+		batches, err := splitLogsByExpression(ctx, ld, e.routingExpression)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			errs = multierr.Append(errs, e.consumeLogWithKey(ctx, []byte(rid), batch))
+		}
+		return errs
+	}
+
 	batches := batchpersignal.SplitLogs(ld)
 	for _, batch := range batches {
 		errs = multierr.Append(errs, e.consumeLog(ctx, batch))
@@ -87,7 +107,11 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 		balancingKey = random()
 	}
 
-	le, endpoint, err := e.loadBalancer.exporterAndEndpoint(balancingKey[:])
+	return e.consumeLogWithKey(ctx, balancingKey[:], ld)
+}
+
+func (e *logExporterImp) consumeLogWithKey(ctx context.Context, balancingKey []byte, ld plog.Logs) error {
+	le, endpoint, err := e.loadBalancer.exporterAndEndpoint(balancingKey)
 	if err != nil {
 		return err
 	}
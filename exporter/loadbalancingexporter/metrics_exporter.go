@@ -24,6 +24,8 @@ import (
 	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 )
 
 var _ exporter.Metrics = (*metricExporterImp)(nil)
@@ -31,8 +33,9 @@ var _ exporter.Metrics = (*metricExporterImp)(nil)
 type exporterMetrics map[*wrappedExporter]pmetric.Metrics
 
 type metricExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingExpression *ottl.ValueExpression[ottldatapoint.TransformContext]
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -51,6 +54,15 @@ func newMetricsExporter(params exporter.CreateSettings, cfg component.Config) (*
 
 	metricExporter := metricExporterImp{loadBalancer: lb, routingKey: svcRouting}
 
+	if expression := cfg.(*Config).RoutingExpression; expression != "" {
+		metricExporter.routingKey = expressionRouting
+		metricExporter.routingExpression, err = newDataPointRoutingExpression(expression, params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		return &metricExporter, nil
+	}
+
 	switch cfg.(*Config).RoutingKey {
 	case "service", "":
 		// default case for empty routing key
@@ -82,31 +94,47 @@ func (e *metricExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
-	batches := batchpersignal.SplitMetrics(md)
-
 	exporterSegregatedMetrics := make(exporterMetrics)
 	endpoints := make(map[*wrappedExporter]string)
-
-	for _, batch := range batches {
-		routingIds, err := routingIdentifiersFromMetrics(batch, e.routingKey)
+	route := func(rid string, batch pmetric.Metrics) error {
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
 		if err != nil {
 			return err
 		}
 
-		for rid := range routingIds {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+		_, ok := exporterSegregatedMetrics[exp]
+		if !ok {
+			exp.consumeWG.Add(1)
+			exporterSegregatedMetrics[exp] = pmetric.NewMetrics()
+		}
+		exporterSegregatedMetrics[exp] = mergeMetrics(exporterSegregatedMetrics[exp], batch)
+
+		endpoints[exp] = endpoint
+		return nil
+	}
+
+	if e.routingKey == expressionRouting {
+		batches, err := splitMetricsByExpression(ctx, md, e.routingExpression)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			if err := route(rid, batch); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, batch := range batchpersignal.SplitMetrics(md) {
+			routingIds, err := routingIdentifiersFromMetrics(batch, e.routingKey)
 			if err != nil {
 				return err
 			}
 
-			_, ok := exporterSegregatedMetrics[exp]
-			if !ok {
-				exp.consumeWG.Add(1)
-				exporterSegregatedMetrics[exp] = pmetric.NewMetrics()
+			for rid := range routingIds {
+				if err := route(rid, batch); err != nil {
+					return err
+				}
 			}
-			exporterSegregatedMetrics[exp] = mergeMetrics(exporterSegregatedMetrics[exp], batch)
-
-			endpoints[exp] = endpoint
 		}
 	}
 
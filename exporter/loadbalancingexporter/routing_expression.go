@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+func newSpanRoutingExpression(expression string, settings component.TelemetrySettings) (*ottl.ValueExpression[ottlspan.TransformContext], error) {
+	parser, err := ottlspan.NewParser(ottlfuncs.StandardConverters[ottlspan.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+	return parseRoutingExpression(&parser, expression)
+}
+
+func newDataPointRoutingExpression(expression string, settings component.TelemetrySettings) (*ottl.ValueExpression[ottldatapoint.TransformContext], error) {
+	parser, err := ottldatapoint.NewParser(ottlfuncs.StandardConverters[ottldatapoint.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+	return parseRoutingExpression(&parser, expression)
+}
+
+func newLogRoutingExpression(expression string, settings component.TelemetrySettings) (*ottl.ValueExpression[ottllog.TransformContext], error) {
+	parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+	return parseRoutingExpression(&parser, expression)
+}
+
+func parseRoutingExpression[K any](parser *ottl.Parser[K], expression string) (*ottl.ValueExpression[K], error) {
+	expr, err := parser.ParseValueExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing_expression %q: %w", expression, err)
+	}
+	return expr, nil
+}
+
+// evalRoutingKey evaluates the routing expression in the given context. Values are routed by their string
+// representation, maps and slices by their JSON representation, and nil values by an empty key.
+func evalRoutingKey[K any](ctx context.Context, expr *ottl.ValueExpression[K], tCtx K) (string, error) {
+	v, err := expr.Eval(ctx, tCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate the routing_expression: %w", err)
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case pcommon.Value:
+		return val.AsString(), nil
+	case pcommon.Map:
+		m := pcommon.NewValueMap()
+		val.CopyTo(m.Map())
+		return m.AsString(), nil
+	case pcommon.Slice:
+		s := pcommon.NewValueSlice()
+		val.CopyTo(s.Slice())
+		return s.AsString(), nil
+	default:
+		raw := pcommon.NewValueEmpty()
+		if err := raw.FromRaw(val); err != nil {
+			return "", fmt.Errorf("unsupported routing_expression value of type %T", val)
+		}
+		return raw.AsString(), nil
+	}
+}
+
+// splitTracesByExpression splits the traces by the routing key each of their spans evaluates to.
+func splitTracesByExpression(ctx context.Context, td ptrace.Traces, expr *ottl.ValueExpression[ottlspan.TransformContext]) (map[string]ptrace.Traces, error) {
+	result := map[string]ptrace.Traces{}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resources := map[string]ptrace.ResourceSpans{}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			scopes := map[string]ptrace.ScopeSpans{}
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				key, err := evalRoutingKey(ctx, expr, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource()))
+				if err != nil {
+					return nil, err
+				}
+
+				dest, ok := scopes[key]
+				if !ok {
+					destResource, ok := resources[key]
+					if !ok {
+						traces, ok := result[key]
+						if !ok {
+							traces = ptrace.NewTraces()
+							result[key] = traces
+						}
+						destResource = traces.ResourceSpans().AppendEmpty()
+						rs.Resource().CopyTo(destResource.Resource())
+						destResource.SetSchemaUrl(rs.SchemaUrl())
+						resources[key] = destResource
+					}
+					dest = destResource.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(dest.Scope())
+					dest.SetSchemaUrl(ss.SchemaUrl())
+					scopes[key] = dest
+				}
+				span.CopyTo(dest.Spans().AppendEmpty())
+			}
+		}
+	}
+	return result, nil
+}
+
+// splitLogsByExpression splits the logs by the routing key each of their log records evaluates to.
+func splitLogsByExpression(ctx context.Context, ld plog.Logs, expr *ottl.ValueExpression[ottllog.TransformContext]) (map[string]plog.Logs, error) {
+	result := map[string]plog.Logs{}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resources := map[string]plog.ResourceLogs{}
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			scopes := map[string]plog.ScopeLogs{}
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				key, err := evalRoutingKey(ctx, expr, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource()))
+				if err != nil {
+					return nil, err
+				}
+
+				dest, ok := scopes[key]
+				if !ok {
+					destResource, ok := resources[key]
+					if !ok {
+						logs, ok := result[key]
+						if !ok {
+							logs = plog.NewLogs()
+							result[key] = logs
+						}
+						destResource = logs.ResourceLogs().AppendEmpty()
+						rl.Resource().CopyTo(destResource.Resource())
+						destResource.SetSchemaUrl(rl.SchemaUrl())
+						resources[key] = destResource
+					}
+					dest = destResource.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(dest.Scope())
+					dest.SetSchemaUrl(sl.SchemaUrl())
+					scopes[key] = dest
+				}
+				lr.CopyTo(dest.LogRecords().AppendEmpty())
+			}
+		}
+	}
+	return result, nil
+}
+
+// splitMetricsByExpression splits the metrics by the routing key each of their data points evaluates to.
+func splitMetricsByExpression(ctx context.Context, md pmetric.Metrics, expr *ottl.ValueExpression[ottldatapoint.TransformContext]) (map[string]pmetric.Metrics, error) {
+	result := map[string]pmetric.Metrics{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resources := map[string]pmetric.ResourceMetrics{}
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			scopes := map[string]pmetric.ScopeMetrics{}
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+				metrics := map[string]pmetric.Metric{}
+
+				// destination returns the metric of the routing key of the data point
+				destination := func(dp any) (pmetric.Metric, error) {
+					key, err := evalRoutingKey(ctx, expr, ottldatapoint.NewTransformContext(dp, m, sm.Metrics(), sm.Scope(), rm.Resource()))
+					if err != nil {
+						return pmetric.Metric{}, err
+					}
+					if dest, ok := metrics[key]; ok {
+						return dest, nil
+					}
+
+					destScope, ok := scopes[key]
+					if !ok {
+						destResource, ok := resources[key]
+						if !ok {
+							batch, ok := result[key]
+							if !ok {
+								batch = pmetric.NewMetrics()
+								result[key] = batch
+							}
+							destResource = batch.ResourceMetrics().AppendEmpty()
+							rm.Resource().CopyTo(destResource.Resource())
+							destResource.SetSchemaUrl(rm.SchemaUrl())
+							resources[key] = destResource
+						}
+						destScope = destResource.ScopeMetrics().AppendEmpty()
+						sm.Scope().CopyTo(destScope.Scope())
+						destScope.SetSchemaUrl(sm.SchemaUrl())
+						scopes[key] = destScope
+					}
+					dest := destScope.Metrics().AppendEmpty()
+					copyMetricDescription(m, dest)
+					metrics[key] = dest
+					return dest, nil
+				}
+
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := m.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l))
+						if err != nil {
+							return nil, err
+						}
+						dps.At(l).CopyTo(dest.Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l))
+						if err != nil {
+							return nil, err
+						}
+						dps.At(l).CopyTo(dest.Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l))
+						if err != nil {
+							return nil, err
+						}
+						dps.At(l).CopyTo(dest.Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l))
+						if err != nil {
+							return nil, err
+						}
+						dps.At(l).CopyTo(dest.ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l))
+						if err != nil {
+							return nil, err
+						}
+						dps.At(l).CopyTo(dest.Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// copyMetricDescription copies the metric to dest, without its data points.
+func copyMetricDescription(m pmetric.Metric, dest pmetric.Metric) {
+	dest.SetName(m.Name())
+	dest.SetDescription(m.Description())
+	dest.SetUnit(m.Unit())
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dest.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := dest.SetEmptySum()
+		sum.SetAggregationTemporality(m.Sum().AggregationTemporality())
+		sum.SetIsMonotonic(m.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dest.SetEmptyHistogram().SetAggregationTemporality(m.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dest.SetEmptyExponentialHistogram().SetAggregationTemporality(m.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dest.SetEmptySummary()
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestRoutingExpressionInvalid(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.RoutingExpression = `set(attributes["tenant"], "acme")`
+
+	_, err := newTracesExporter(exportertest.NewNopCreateSettings(), cfg)
+	assert.ErrorContains(t, err, "invalid routing_expression")
+
+	_, err = newMetricsExporter(exportertest.NewNopCreateSettings(), cfg)
+	assert.ErrorContains(t, err, "invalid routing_expression")
+
+	_, err = newLogsExporter(exportertest.NewNopCreateSettings(), cfg)
+	assert.ErrorContains(t, err, "invalid routing_expression")
+}
+
+func TestEvalRoutingKey(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		expression string
+		expected   string
+	}{
+		{
+			desc:       "string attribute",
+			expression: `attributes["tenant"]`,
+			expected:   "acme",
+		},
+		{
+			desc:       "int attribute",
+			expression: `attributes["shard"]`,
+			expected:   "3",
+		},
+		{
+			desc:       "missing attribute",
+			expression: `attributes["missing"]`,
+			expected:   "",
+		},
+		{
+			desc:       "converter",
+			expression: `Concat([resource.attributes["service.name"], name], "/")`,
+			expected:   "svc/op",
+		},
+		{
+			desc:       "map",
+			expression: `attributes`,
+			expected:   `{"shard":3,"tenant":"acme"}`,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			expr, err := newSpanRoutingExpression(tt.expression, componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+
+			td := tracesWithTenants("acme")
+			rs := td.ResourceSpans().At(0)
+			rs.Resource().Attributes().PutStr("service.name", "svc")
+			span := rs.ScopeSpans().At(0).Spans().At(0)
+			span.SetName("op")
+			span.Attributes().PutInt("shard", 3)
+
+			splits, err := splitTracesByExpression(context.Background(), td, expr)
+			require.NoError(t, err)
+			require.Len(t, splits, 1)
+			assert.Contains(t, splits, tt.expected)
+		})
+	}
+}
+
+func TestSplitTracesByExpression(t *testing.T) {
+	expr, err := newSpanRoutingExpression(`attributes["tenant"]`, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	td := tracesWithTenants("acme", "globex", "acme")
+	splits, err := splitTracesByExpression(context.Background(), td, expr)
+	require.NoError(t, err)
+
+	require.Len(t, splits, 2)
+	assert.Equal(t, 2, splits["acme"].SpanCount())
+	assert.Equal(t, 1, splits["globex"].SpanCount())
+	// the spans of the same scope are kept together
+	assert.Equal(t, 1, splits["acme"].ResourceSpans().Len())
+	assert.Equal(t, 1, splits["acme"].ResourceSpans().At(0).ScopeSpans().Len())
+	assert.Equal(t, "scope", splits["globex"].ResourceSpans().At(0).ScopeSpans().At(0).Scope().Name())
+}
+
+func TestSplitLogsByExpression(t *testing.T) {
+	expr, err := newLogRoutingExpression(`attributes["tenant"]`, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for _, tenant := range []string{"acme", "globex", "acme"} {
+		sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	splits, err := splitLogsByExpression(context.Background(), ld, expr)
+	require.NoError(t, err)
+
+	require.Len(t, splits, 2)
+	assert.Equal(t, 2, splits["acme"].LogRecordCount())
+	assert.Equal(t, 1, splits["globex"].LogRecordCount())
+}
+
+func TestSplitMetricsByExpression(t *testing.T) {
+	expr, err := newDataPointRoutingExpression(`attributes["tenant"]`, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(true)
+	for _, tenant := range []string{"acme", "globex", "acme"} {
+		sum.DataPoints().AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	splits, err := splitMetricsByExpression(context.Background(), md, expr)
+	require.NoError(t, err)
+
+	require.Len(t, splits, 2)
+	assert.Equal(t, 2, splits["acme"].DataPointCount())
+	assert.Equal(t, 1, splits["globex"].DataPointCount())
+
+	dest := splits["globex"].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", dest.Name())
+	assert.Equal(t, "1", dest.Unit())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, dest.Sum().AggregationTemporality())
+	assert.True(t, dest.Sum().IsMonotonic())
+}
+
+func TestConsumeTracesExpressionBased(t *testing.T) {
+	sink := map[string]int{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Component, error) {
+		return newMockTracesExporter(func(ctx context.Context, td ptrace.Traces) error {
+			sink[endpoint] += td.SpanCount()
+			return nil
+		}), nil
+	}
+	cfg := serviceBasedRoutingConfig()
+	cfg.RoutingKey = ""
+	cfg.RoutingExpression = `attributes["tenant"]`
+	lb, err := newLoadBalancer(exportertest.NewNopCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newTracesExporter(exportertest.NewNopCreateSettings(), cfg)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, expressionRouting, p.routingKey)
+
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1", "endpoint-2"})
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	err = p.ConsumeTraces(context.Background(), tracesWithTenants("acme", "acme", "acme"))
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, sink, 1)
+	for _, spans := range sink {
+		assert.Equal(t, 3, spans)
+	}
+}
+
+func tracesWithTenants(tenants ...string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope")
+	for _, tenant := range tenants {
+		ss.Spans().AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+	return td
+}
@@ -20,6 +20,8 @@ import (
 	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 var _ exporter.Traces = (*traceExporterImp)(nil)
@@ -27,8 +29,9 @@ var _ exporter.Traces = (*traceExporterImp)(nil)
 type exporterTraces map[*wrappedExporter]ptrace.Traces
 
 type traceExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingExpression *ottl.ValueExpression[ottlspan.TransformContext]
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -48,6 +51,15 @@ func newTracesExporter(params exporter.CreateSettings, cfg component.Config) (*t
 
 	traceExporter := traceExporterImp{loadBalancer: lb, routingKey: traceIDRouting}
 
+	if expression := cfg.(*Config).RoutingExpression; expression != "" {
+		traceExporter.routingKey = expressionRouting
+		traceExporter.routingExpression, err = newSpanRoutingExpression(expression, params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		return &traceExporter, nil
+	}
+
 	switch cfg.(*Config).RoutingKey {
 	case "service":
 		traceExporter.routingKey = svcRouting
@@ -80,30 +92,47 @@ func (e *traceExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	batches := batchpersignal.SplitTraces(td)
-
 	exporterSegregatedTraces := make(exporterTraces)
 	endpoints := make(map[*wrappedExporter]string)
-	for _, batch := range batches {
-		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
+	route := func(rid string, batch ptrace.Traces) error {
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
 		if err != nil {
 			return err
 		}
 
-		for rid := range routingID {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+		_, ok := exporterSegregatedTraces[exp]
+		if !ok {
+			exp.consumeWG.Add(1)
+			exporterSegregatedTraces[exp] = ptrace.NewTraces()
+		}
+		exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
+
+		endpoints[exp] = endpoint
+		return nil
+	}
+
+	if e.routingKey == expressionRouting {
+		batches, err := splitTracesByExpression(ctx, td, e.routingExpression)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			if err := route(rid, batch); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, batch := range batchpersignal.SplitTraces(td) {
+			routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
 			if err != nil {
 				return err
 			}
 
-			_, ok := exporterSegregatedTraces[exp]
-			if !ok {
-				exp.consumeWG.Add(1)
-				exporterSegregatedTraces[exp] = ptrace.NewTraces()
+			for rid := range routingID {
+				if err := route(rid, batch); err != nil {
+					return err
+				}
 			}
-			exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
-
-			endpoints[exp] = endpoint
 		}
 	}
 
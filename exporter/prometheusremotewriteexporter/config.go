@@ -12,8 +12,12 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
+// protoMsgV1 is the protobuf message of the remote write 1.0 requests.
+const protoMsgV1 = "prometheus.WriteRequest"
+
 // Config defines configuration for Remote Write exporter.
 type Config struct {
 	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
@@ -52,6 +56,10 @@ type Config struct {
 
 	// SendMetadata controls whether prometheus metadata will be generated and sent
 	SendMetadata bool `mapstructure:"send_metadata"`
+
+	// RemoteWriteProtoMsg is the protobuf message of the requests: "prometheus.WriteRequest" for remote write 1.0,
+	// or "io.prometheus.write.v2.Request" for remote write 2.0.
+	RemoteWriteProtoMsg string `mapstructure:"protobuf_message"`
 }
 
 type CreatedMetric struct {
@@ -97,6 +105,16 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("remote write consumer number can't be negative")
 	}
 
+	switch cfg.RemoteWriteProtoMsg {
+	case "", protoMsgV1:
+	case writev2.ProtoMsg:
+		if cfg.WAL != nil {
+			return fmt.Errorf("the WAL isn't supported with the %s protobuf message", writev2.ProtoMsg)
+		}
+	default:
+		return fmt.Errorf("unsupported protobuf_message %q, must be %q or %q", cfg.RemoteWriteProtoMsg, protoMsgV1, writev2.ProtoMsg)
+	}
+
 	if cfg.TargetInfo == nil {
 		cfg.TargetInfo = &TargetInfo{
 			Enabled: true,
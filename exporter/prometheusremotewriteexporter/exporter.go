@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
@@ -33,8 +34,12 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
+// errUnsupportedMediaType is returned when the endpoint rejects the content type of the requests.
+var errUnsupportedMediaType = errors.New("the endpoint doesn't support the content type of the request")
+
 type prwTelemetry interface {
 	recordTranslationFailure(ctx context.Context)
 	recordTranslatedTimeSeries(ctx context.Context, numTS int)
@@ -69,6 +74,9 @@ type prwExporter struct {
 	wal               *prweWAL
 	exporterSettings  prometheusremotewrite.Settings
 	telemetry         prwTelemetry
+	// remoteWriteV2 is true while the requests are sent with the remote write 2.0 protocol, until the endpoint
+	// rejects them. The exporter doesn't switch back to remote write 2.0 afterwards.
+	remoteWriteV2 atomic.Bool
 }
 
 func newPRWTelemetry(set exporter.CreateSettings) (prwTelemetry, error) {
@@ -135,6 +143,8 @@ func newPRWExporter(cfg *Config, set exporter.CreateSettings) (*prwExporter, err
 		telemetry: prwTelemetry,
 	}
 
+	prwe.remoteWriteV2.Store(cfg.RemoteWriteProtoMsg == writev2.ProtoMsg)
+
 	prwe.wal = newWAL(cfg.WAL, prwe.export)
 	return prwe, nil
 }
@@ -179,6 +189,9 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
+		if prwe.remoteWriteV2.Load() {
+			return prwe.pushMetricsV2(ctx, md)
+		}
 
 		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
 		if err != nil {
@@ -198,6 +211,25 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	}
 }
 
+// pushMetricsV2 converts metrics to Prometheus remote write 2.0 TimeSeries and sends them to the remote endpoint.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics) error {
+	tsMap, symbols, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(tsMap)))
+	}
+
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(tsMap))
+
+	// There are no metrics to export, so return.
+	if len(tsMap) == 0 {
+		return nil
+	}
+
+	// Call export even if a conversion error, since there may be points that were successfully converted.
+	return prwe.exportV2(ctx, batchTimeSeriesV2(tsMap, symbols.Symbols(), prwe.maxBatchSizeBytes))
+}
+
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
 	sanitizedLabels := make(map[string]string)
 	for key, value := range cfg.ExternalLabels {
@@ -236,7 +268,17 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
 func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.execute)
+}
+
+// exportV2 sends Snappy-compressed remote write 2.0 requests to a remote write endpoint in order
+func (prwe *prwExporter) exportV2(ctx context.Context, requests []*writev2.Request) error {
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.executeV2)
+}
+
+// exportConcurrently executes the requests with up to concurrency workers.
+func exportConcurrently[T any](ctx context.Context, concurrency int, requests []T, execute func(context.Context, T) error) error {
+	input := make(chan T, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -244,7 +286,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 
 	var wg sync.WaitGroup
 
-	concurrencyLimit := int(math.Min(float64(prwe.concurrency), float64(len(requests))))
+	concurrencyLimit := int(math.Min(float64(concurrency), float64(len(requests))))
 	wg.Add(concurrencyLimit) // used to wait for workers to be finished
 
 	var mu sync.Mutex
@@ -263,7 +305,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 					if !ok {
 						return
 					}
-					if errExecute := prwe.execute(ctx, request); errExecute != nil {
+					if errExecute := execute(ctx, request); errExecute != nil {
 						mu.Lock()
 						errs = multierr.Append(errs, consumererror.NewPermanent(errExecute))
 						mu.Unlock()
@@ -283,6 +325,33 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	if errMarshal != nil {
 		return consumererror.NewPermanent(errMarshal)
 	}
+	return prwe.send(ctx, data, "application/x-protobuf", "0.1.0")
+}
+
+func (prwe *prwExporter) executeV2(ctx context.Context, writeReq *writev2.Request) error {
+	data, errMarshal := writeReq.Marshal()
+	if errMarshal != nil {
+		return consumererror.NewPermanent(errMarshal)
+	}
+	err := prwe.send(ctx, data, writev2.ContentType, writev2.Version)
+	if !errors.Is(err, errUnsupportedMediaType) {
+		return err
+	}
+
+	// The endpoint doesn't support remote write 2.0: fall back to remote write 1.0 for this request and the next ones.
+	if prwe.remoteWriteV2.CompareAndSwap(true, false) {
+		prwe.settings.Logger.Warn("The endpoint doesn't support remote write 2.0, falling back to remote write 1.0",
+			zap.String("endpoint", prwe.endpointURL.String()), zap.Error(err))
+	}
+	v1Req, errConvert := requestToV1(writeReq)
+	if errConvert != nil {
+		return consumererror.NewPermanent(errConvert)
+	}
+	return prwe.execute(ctx, v1Req)
+}
+
+// send sends the Snappy-compressed request with the given content type and remote write version headers.
+func (prwe *prwExporter) send(ctx context.Context, data []byte, contentType string, version string) error {
 	buf := make([]byte, len(data), cap(data))
 	compressedData := snappy.Encode(buf, data)
 
@@ -306,8 +375,8 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Prometheus-Remote-Write-Version", version)
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
 
 		resp, err := prwe.client.Do(req)
@@ -326,6 +395,9 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 
 		body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
 		rerr := fmt.Errorf("remote write returned HTTP status %v; err = %w: %s", resp.Status, err, body)
+		if resp.StatusCode == http.StatusUnsupportedMediaType && contentType == writev2.ContentType {
+			return backoff.Permanent(fmt.Errorf("%w: %w", errUnsupportedMediaType, rerr))
+		}
 		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
 			return rerr
 		}
@@ -31,6 +31,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_NewPRWExporter checks that a new exporter instance with non-nil fields is initialized
@@ -336,6 +337,94 @@ func TestNoMetricsNoError(t *testing.T) {
 	assert.NoError(t, runExportPipeline(nil, serverURL))
 }
 
+// TestPushMetricsRemoteWriteV2 checks that the metrics are sent with remote write 2.0 to a stub server, and that the
+// exporter falls back to remote write 1.0 when the server doesn't support it.
+func TestPushMetricsRemoteWriteV2(t *testing.T) {
+	for _, tt := range []struct {
+		name                 string
+		supportsV2           bool
+		expectedContentTypes []string
+	}{
+		{
+			name:                 "remote write 2.0",
+			supportsV2:           true,
+			expectedContentTypes: []string{writev2.ContentType, writev2.ContentType},
+		},
+		{
+			name:                 "fallback to remote write 1.0",
+			supportsV2:           false,
+			expectedContentTypes: []string{writev2.ContentType, "application/x-protobuf", "application/x-protobuf"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var contentTypes []string
+			var v2Requests []writev2.Request
+			var v1Requests []prompb.WriteRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				contentType := r.Header.Get("Content-Type")
+				contentTypes = append(contentTypes, contentType)
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				data, err := snappy.Decode(nil, body)
+				require.NoError(t, err)
+
+				switch {
+				case contentType == writev2.ContentType && tt.supportsV2:
+					assert.Equal(t, "2.0.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					var req writev2.Request
+					require.NoError(t, req.Unmarshal(data))
+					v2Requests = append(v2Requests, req)
+				case contentType == writev2.ContentType:
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				default:
+					assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					var req prompb.WriteRequest
+					require.NoError(t, proto.Unmarshal(data, &req))
+					v1Requests = append(v1Requests, req)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.ClientConfig.Endpoint = server.URL
+			cfg.RemoteWriteQueue.NumConsumers = 1
+			cfg.RemoteWriteProtoMsg = writev2.ProtoMsg
+			prwe, err := newPRWExporter(cfg, exportertest.NewNopCreateSettings())
+			require.NoError(t, err)
+			require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, prwe.Shutdown(context.Background()))
+			}()
+
+			md := testdata.GenerateMetricsOneMetric()
+			require.NoError(t, prwe.PushMetrics(context.Background(), md))
+			require.NoError(t, prwe.PushMetrics(context.Background(), md))
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.expectedContentTypes, contentTypes)
+			if tt.supportsV2 {
+				require.Len(t, v2Requests, 2)
+				assert.Equal(t, "", v2Requests[0].Symbols[0])
+				assert.NotEmpty(t, v2Requests[0].Timeseries)
+				assert.Empty(t, v1Requests)
+				return
+			}
+			require.Len(t, v1Requests, 2)
+			assert.NotEmpty(t, v1Requests[0].Timeseries)
+			assert.Equal(t, len(v1Requests[0].Timeseries), len(v1Requests[1].Timeseries))
+			assert.Empty(t, v2Requests)
+			assert.False(t, prwe.remoteWriteV2.Load())
+		})
+	}
+}
+
 func runExportPipeline(ts *prompb.TimeSeries, endpoint *url.URL) error {
 	// First we will construct a TimeSeries array from the testutils package
 	testmap := make(map[string]*prompb.TimeSeries)
@@ -71,13 +71,14 @@ func createDefaultConfig() component.Config {
 	retrySettings.InitialInterval = 50 * time.Millisecond
 
 	return &Config{
-		Namespace:         "",
-		ExternalLabels:    map[string]string{},
-		MaxBatchSizeBytes: 3000000,
-		TimeoutSettings:   exporterhelper.NewDefaultTimeoutSettings(),
-		BackOffConfig:     retrySettings,
-		AddMetricSuffixes: true,
-		SendMetadata:      false,
+		Namespace:           "",
+		ExternalLabels:      map[string]string{},
+		MaxBatchSizeBytes:   3000000,
+		TimeoutSettings:     exporterhelper.NewDefaultTimeoutSettings(),
+		BackOffConfig:       retrySettings,
+		AddMetricSuffixes:   true,
+		SendMetadata:        false,
+		RemoteWriteProtoMsg: protoMsgV1,
 		ClientConfig: confighttp.ClientConfig{
 			Endpoint: "http://some.url:9411/api/prom/push",
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
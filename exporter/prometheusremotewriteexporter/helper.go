@@ -8,6 +8,8 @@ import (
 	"sort"
 
 	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // batchTimeSeries splits series into multiple batch write requests.
@@ -96,3 +98,163 @@ func orderBySampleTimestamp(tsArray []prompb.TimeSeries) []prompb.TimeSeries {
 	}
 	return tsArray
 }
+
+// batchTimeSeriesV2 splits series into multiple remote write 2.0 requests, each of them with its own symbols table.
+func batchTimeSeriesV2(tsMap map[string]*writev2.TimeSeries, symbols []string, maxBatchByteSize int) []*writev2.Request {
+	var requests []*writev2.Request
+	table := writev2.NewSymbolsTable()
+	tsArray := make([]writev2.TimeSeries, 0, len(tsMap))
+	sizeOfCurrentBatch := 0
+
+	i := 0
+	for _, v := range tsMap {
+		// the size of the series with all of its symbols, some of them being already in the table
+		sizeOfSeries := v.Size() + symbolsSize(v, symbols)
+
+		if len(tsArray) > 0 && sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize {
+			requests = append(requests, &writev2.Request{Symbols: table.Symbols(), Timeseries: tsArray})
+
+			table = writev2.NewSymbolsTable()
+			tsArray = make([]writev2.TimeSeries, 0, len(tsMap)-i)
+			sizeOfCurrentBatch = 0
+		}
+
+		tsArray = append(tsArray, resymbolize(v, symbols, &table))
+		sizeOfCurrentBatch += sizeOfSeries
+		i++
+	}
+
+	if len(tsArray) != 0 {
+		requests = append(requests, &writev2.Request{Symbols: table.Symbols(), Timeseries: tsArray})
+	}
+	return requests
+}
+
+// symbolsSize returns the size of the symbols the series refers to.
+func symbolsSize(ts *writev2.TimeSeries, symbols []string) int {
+	size := len(symbols[ts.Metadata.HelpRef]) + len(symbols[ts.Metadata.UnitRef]) + 4
+	for _, ref := range ts.LabelsRefs {
+		size += len(symbols[ref]) + 2
+	}
+	for _, e := range ts.Exemplars {
+		for _, ref := range e.LabelsRefs {
+			size += len(symbols[ref]) + 2
+		}
+	}
+	return size
+}
+
+// resymbolize returns a copy of the series, whose references are in table instead of symbols, and whose samples are
+// sorted by timestamp.
+func resymbolize(ts *writev2.TimeSeries, symbols []string, table *writev2.SymbolsTable) writev2.TimeSeries {
+	resymbolizeRefs := func(refs []uint32) []uint32 {
+		result := make([]uint32, len(refs))
+		for i, ref := range refs {
+			result[i] = table.Symbolize(symbols[ref])
+		}
+		return result
+	}
+
+	result := *ts
+	result.LabelsRefs = resymbolizeRefs(ts.LabelsRefs)
+	result.Metadata.HelpRef = table.Symbolize(symbols[ts.Metadata.HelpRef])
+	result.Metadata.UnitRef = table.Symbolize(symbols[ts.Metadata.UnitRef])
+	if len(ts.Exemplars) > 0 {
+		result.Exemplars = make([]writev2.Exemplar, len(ts.Exemplars))
+		for i, e := range ts.Exemplars {
+			e.LabelsRefs = resymbolizeRefs(e.LabelsRefs)
+			result.Exemplars[i] = e
+		}
+	}
+	// Prometheus requires time series to be sorted by Timestamp to avoid out of order problems.
+	// The samples are copied first, the series of the caller are left untouched.
+	result.Samples = append([]writev2.Sample(nil), ts.Samples...)
+	sort.Slice(result.Samples, func(i, j int) bool {
+		return result.Samples[i].Timestamp < result.Samples[j].Timestamp
+	})
+	return result
+}
+
+// requestToV1 converts a remote write 2.0 request to a remote write 1.0 one. The metadata and created timestamps of
+// the series are dropped.
+func requestToV1(req *writev2.Request) (*prompb.WriteRequest, error) {
+	labelsToV1 := func(refs []uint32) ([]prompb.Label, error) {
+		labels := make([]prompb.Label, 0, len(refs)/2)
+		for i := 0; i+1 < len(refs); i += 2 {
+			name, err := writev2.Desymbolize(req.Symbols, refs[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := writev2.Desymbolize(req.Symbols, refs[i+1])
+			if err != nil {
+				return nil, err
+			}
+			labels = append(labels, prompb.Label{Name: name, Value: value})
+		}
+		return labels, nil
+	}
+
+	v1 := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(req.Timeseries))}
+	for _, ts := range req.Timeseries {
+		labels, err := labelsToV1(ts.LabelsRefs)
+		if err != nil {
+			return nil, err
+		}
+		series := prompb.TimeSeries{Labels: labels}
+		for _, s := range ts.Samples {
+			series.Samples = append(series.Samples, prompb.Sample{Value: s.Value, Timestamp: s.Timestamp})
+		}
+		for _, h := range ts.Histograms {
+			series.Histograms = append(series.Histograms, histogramToV1(h))
+		}
+		for _, e := range ts.Exemplars {
+			exemplarLabels, err := labelsToV1(e.LabelsRefs)
+			if err != nil {
+				return nil, err
+			}
+			series.Exemplars = append(series.Exemplars, prompb.Exemplar{Labels: exemplarLabels, Value: e.Value, Timestamp: e.Timestamp})
+		}
+		v1.Timeseries = append(v1.Timeseries, series)
+	}
+	return v1, nil
+}
+
+func histogramToV1(h writev2.Histogram) prompb.Histogram {
+	v1 := prompb.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  bucketSpansToV1(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  bucketSpansToV1(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      prompb.Histogram_ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	switch count := h.Count.(type) {
+	case *writev2.Histogram_CountInt:
+		v1.Count = &prompb.Histogram_CountInt{CountInt: count.CountInt}
+	case *writev2.Histogram_CountFloat:
+		v1.Count = &prompb.Histogram_CountFloat{CountFloat: count.CountFloat}
+	}
+	switch zeroCount := h.ZeroCount.(type) {
+	case *writev2.Histogram_ZeroCountInt:
+		v1.ZeroCount = &prompb.Histogram_ZeroCountInt{ZeroCountInt: zeroCount.ZeroCountInt}
+	case *writev2.Histogram_ZeroCountFloat:
+		v1.ZeroCount = &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: zeroCount.ZeroCountFloat}
+	}
+	return v1
+}
+
+func bucketSpansToV1(spans []writev2.BucketSpan) []prompb.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	v1 := make([]prompb.BucketSpan, len(spans))
+	for i, span := range spans {
+		v1[i] = prompb.BucketSpan{Offset: span.Offset, Length: span.Length}
+	}
+	return v1
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_batchTimeSeries checks batchTimeSeries return the correct number of requests
@@ -180,3 +183,107 @@ func TestEnsureTimeseriesPointsAreSortedByTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func Test_batchTimeSeriesV2(t *testing.T) {
+	symbols := []string{"", "__name__", "up", "job", "api", "worker", "help", "trace_id", "abc"}
+	tsMap := map[string]*writev2.TimeSeries{
+		"api": {
+			LabelsRefs: []uint32{1, 2, 3, 4},
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 2000}, {Value: 0, Timestamp: 1000}},
+			Exemplars:  []writev2.Exemplar{{LabelsRefs: []uint32{7, 8}, Value: 1, Timestamp: 1000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE, HelpRef: 6},
+		},
+		"worker": {
+			LabelsRefs: []uint32{1, 2, 3, 5},
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE, HelpRef: 6},
+		},
+	}
+
+	requests := batchTimeSeriesV2(tsMap, symbols, 3000000)
+	require.Len(t, requests, 1)
+	assert.Len(t, requests[0].Timeseries, 2)
+	assert.Len(t, requests[0].Symbols, len(symbols))
+	for _, ts := range requests[0].Timeseries {
+		labels := desymbolize(t, requests[0].Symbols, ts.LabelsRefs)
+		assert.Equal(t, "up", labels["__name__"])
+		assert.Equal(t, "help", requests[0].Symbols[ts.Metadata.HelpRef])
+		if labels["job"] == "api" {
+			assert.Equal(t, []writev2.Sample{{Value: 0, Timestamp: 1000}, {Value: 1, Timestamp: 2000}}, ts.Samples)
+			require.Len(t, ts.Exemplars, 1)
+			assert.Equal(t, map[string]string{"trace_id": "abc"}, desymbolize(t, requests[0].Symbols, ts.Exemplars[0].LabelsRefs))
+		}
+	}
+	// the series given are left untouched
+	assert.Equal(t, []writev2.Sample{{Value: 1, Timestamp: 2000}, {Value: 0, Timestamp: 1000}}, tsMap["api"].Samples)
+
+	// each series is sent in its own request, with the symbols it refers to only
+	requests = batchTimeSeriesV2(tsMap, symbols, 10)
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		assert.Len(t, req.Timeseries, 1)
+		assert.Equal(t, "", req.Symbols[0])
+		labels := desymbolize(t, req.Symbols, req.Timeseries[0].LabelsRefs)
+		assert.Equal(t, "up", labels["__name__"])
+		assert.NotContains(t, req.Symbols, map[string]string{"api": "worker", "worker": "api"}[labels["job"]])
+	}
+}
+
+func Test_requestToV1(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "latency", "trace_id", "abc"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2},
+				Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+				Exemplars:  []writev2.Exemplar{{LabelsRefs: []uint32{3, 4}, Value: 1, Timestamp: 1000}},
+				Histograms: []writev2.Histogram{{
+					Count:          &writev2.Histogram_CountInt{CountInt: 3},
+					Sum:            4,
+					ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveDeltas: []int64{1, 0},
+					Timestamp:      1000,
+				}},
+				Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+				CreatedTimestamp: 500,
+			},
+		},
+	}
+
+	v1, err := requestToV1(req)
+	require.NoError(t, err)
+	assert.Equal(t, &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:    []prompb.Label{{Name: "__name__", Value: "latency"}},
+				Samples:   []prompb.Sample{{Value: 1, Timestamp: 1000}},
+				Exemplars: []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 1, Timestamp: 1000}},
+				Histograms: []prompb.Histogram{{
+					Count:          &prompb.Histogram_CountInt{CountInt: 3},
+					Sum:            4,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveDeltas: []int64{1, 0},
+					Timestamp:      1000,
+				}},
+			},
+		},
+	}, v1)
+
+	req.Timeseries[0].LabelsRefs = []uint32{1, 5}
+	_, err = requestToV1(req)
+	assert.EqualError(t, err, "symbol reference 5 is out of range, the request has 5 symbols")
+}
+
+func desymbolize(t *testing.T, symbols []string, refs []uint32) map[string]string {
+	labels := map[string]string{}
+	for i := 0; i+1 < len(refs); i += 2 {
+		name, err := writev2.Desymbolize(symbols, refs[i])
+		require.NoError(t, err)
+		value, err := writev2.Desymbolize(symbols, refs[i+1])
+		require.NoError(t, err)
+		labels[name] = value
+	}
+	return labels
+}
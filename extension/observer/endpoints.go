@@ -25,6 +25,8 @@ const (
 	PodType EndpointType = "pod"
 	// K8sServiceType is a service endpoint.
 	K8sServiceType EndpointType = "k8s.service"
+	// K8sIngressType is an ingress endpoint.
+	K8sIngressType EndpointType = "k8s.ingress"
 	// K8sNodeType is a Kubernetes Node endpoint.
 	K8sNodeType EndpointType = "k8s.node"
 	// HostPortType is a hostport endpoint.
@@ -37,6 +39,7 @@ var (
 	_ EndpointDetails = (*Pod)(nil)
 	_ EndpointDetails = (*Port)(nil)
 	_ EndpointDetails = (*K8sService)(nil)
+	_ EndpointDetails = (*K8sIngress)(nil)
 	_ EndpointDetails = (*K8sNode)(nil)
 	_ EndpointDetails = (*HostPort)(nil)
 	_ EndpointDetails = (*Container)(nil)
@@ -111,6 +114,9 @@ type K8sService struct {
 	ClusterIP string
 	// ServiceType is the type of the service: ClusterIP, NodePort, LoadBalancer, ExternalName
 	ServiceType string
+	// Ports maps the names of the ports of the service to their number. An unnamed port,
+	// which only a service with a single port may have, is keyed by its number.
+	Ports map[string]uint16
 }
 
 func (s *K8sService) Env() EndpointEnv {
@@ -122,6 +128,7 @@ func (s *K8sService) Env() EndpointEnv {
 		"namespace":    s.Namespace,
 		"cluster_ip":   s.ClusterIP,
 		"service_type": s.ServiceType,
+		"ports":        s.Ports,
 	}
 }
 
@@ -129,6 +136,43 @@ func (s *K8sService) Type() EndpointType {
 	return K8sServiceType
 }
 
+// K8sIngress is a discovered k8s ingress, with one endpoint per host and path of its rules.
+type K8sIngress struct {
+	// Name of the ingress.
+	Name string
+	// UID is the unique ID in the cluster for the ingress.
+	UID string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// Namespace must be unique for ingresses with same name.
+	Namespace string
+	// Scheme is the scheme the ingress serves the host with: http or https.
+	Scheme string
+	// Host is the host of the rule.
+	Host string
+	// Path is the path of the rule.
+	Path string
+}
+
+func (i *K8sIngress) Env() EndpointEnv {
+	return map[string]any{
+		"uid":         i.UID,
+		"name":        i.Name,
+		"labels":      i.Labels,
+		"annotations": i.Annotations,
+		"namespace":   i.Namespace,
+		"scheme":      i.Scheme,
+		"host":        i.Host,
+		"path":        i.Path,
+	}
+}
+
+func (i *K8sIngress) Type() EndpointType {
+	return K8sIngressType
+}
+
 // Pod is a discovered k8s pod.
 type Pod struct {
 	// Name of the pod.
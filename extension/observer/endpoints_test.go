@@ -107,6 +107,7 @@ func TestEndpointEnv(t *testing.T) {
 					Namespace:   "service-namespace",
 					ServiceType: "LoadBalancer",
 					ClusterIP:   "192.68.73.2",
+					Ports:       map[string]uint16{"http": 80, "metrics": 9090},
 				},
 			},
 			want: EndpointEnv{
@@ -124,6 +125,45 @@ func TestEndpointEnv(t *testing.T) {
 				"namespace":    "service-namespace",
 				"cluster_ip":   "192.68.73.2",
 				"service_type": "LoadBalancer",
+				"ports":        map[string]uint16{"http": 80, "metrics": 9090},
+			},
+		},
+		{
+			name: "Ingress",
+			endpoint: Endpoint{
+				ID:     EndpointID("ingress_id"),
+				Target: "https://host-1/",
+				Details: &K8sIngress{
+					Name: "ingress_name",
+					UID:  "ingress-uid",
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+					Annotations: map[string]string{
+						"annotation_1": "value_1",
+					},
+					Namespace: "ingress-namespace",
+					Scheme:    "https",
+					Host:      "host-1",
+					Path:      "/",
+				},
+			},
+			want: EndpointEnv{
+				"type":     "k8s.ingress",
+				"endpoint": "https://host-1/",
+				"id":       "ingress_id",
+				"name":     "ingress_name",
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+				"annotations": map[string]string{
+					"annotation_1": "value_1",
+				},
+				"uid":       "ingress-uid",
+				"namespace": "ingress-namespace",
+				"scheme":    "https",
+				"host":      "host-1",
+				"path":      "/",
 			},
 		},
 		{
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/extension"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/tools/cache"
 
@@ -28,6 +29,7 @@ type k8sObserver struct {
 	telemetry            component.TelemetrySettings
 	podListerWatcher     cache.ListerWatcher
 	serviceListerWatcher cache.ListerWatcher
+	ingressListerWatcher cache.ListerWatcher
 	nodeListerWatcher    cache.ListerWatcher
 	handler              *handler
 	once                 *sync.Once
@@ -61,6 +63,14 @@ func (k *k8sObserver) Start(_ context.Context, _ component.Host) error {
 			}
 			go serviceInformer.Run(k.stop)
 		}
+		if k.ingressListerWatcher != nil {
+			k.telemetry.Logger.Debug("creating and starting ingress informer")
+			ingressInformer := cache.NewSharedInformer(k.ingressListerWatcher, &networkingv1.Ingress{}, 0)
+			if _, err := ingressInformer.AddEventHandler(k.handler); err != nil {
+				k.telemetry.Logger.Error("error adding event handler to ingress informer", zap.Error(err))
+			}
+			go ingressInformer.Run(k.stop)
+		}
 		if k.nodeListerWatcher != nil {
 			k.telemetry.Logger.Debug("creating and starting node informer")
 			nodeInformer := cache.NewSharedInformer(k.nodeListerWatcher, &v1.Node{}, 0)
@@ -106,6 +116,13 @@ func newObserver(config *Config, set extension.CreateSettings) (extension.Extens
 		serviceListerWatcher = cache.NewListWatchFromClient(restClient, "services", v1.NamespaceAll, serviceSelector)
 	}
 
+	var ingressListerWatcher cache.ListerWatcher
+	if config.ObserveIngresses {
+		var ingressSelector = fields.Everything()
+		set.Logger.Debug("observing ingresses")
+		ingressListerWatcher = cache.NewListWatchFromClient(client.NetworkingV1().RESTClient(), "ingresses", v1.NamespaceAll, ingressSelector)
+	}
+
 	var nodeListerWatcher cache.ListerWatcher
 	if config.ObserveNodes {
 		var nodeSelector fields.Selector
@@ -123,6 +140,7 @@ func newObserver(config *Config, set extension.CreateSettings) (extension.Extens
 		telemetry:            set.TelemetrySettings,
 		podListerWatcher:     podListerWatcher,
 		serviceListerWatcher: serviceListerWatcher,
+		ingressListerWatcher: ingressListerWatcher,
 		nodeListerWatcher:    nodeListerWatcher,
 		stop:                 make(chan struct{}),
 		config:               config,
@@ -129,6 +129,49 @@ func TestExtensionObserveServices(t *testing.T) {
 	require.NoError(t, ext.Shutdown(context.Background()))
 }
 
+func TestExtensionObserveIngresses(t *testing.T) {
+	factory := NewFactory()
+	config := factory.CreateDefaultConfig().(*Config)
+	config.ObservePods = false // avoid causing data race when multiple test cases running in the same process using podListerWatcher
+	config.ObserveIngresses = true
+	mockServiceHost(t, config)
+
+	set := extensiontest.NewNopCreateSettings()
+	set.ID = component.NewID(metadata.Type)
+	ext, err := newObserver(config, set)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+
+	obs := ext.(*k8sObserver)
+	ingressListerWatcher := framework.NewFakeControllerSource()
+	obs.ingressListerWatcher = ingressListerWatcher
+
+	ingressListerWatcher.Add(ingress1)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	requireSink(t, sink, func() bool {
+		return len(sink.added) == 3
+	})
+
+	assert.ElementsMatch(t, []observer.Endpoint{
+		newIngressEndpoint("k8s_observer", "http", "example.com", "/api"),
+		newIngressEndpoint("k8s_observer", "http", "example.com", "/"),
+		newIngressEndpoint("k8s_observer", "https", "secure.example.com", "/metrics"),
+	}, sink.added)
+
+	ingressListerWatcher.Delete(ingress1)
+
+	requireSink(t, sink, func() bool {
+		return len(sink.removed) == 3
+	})
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
 func TestExtensionObservePods(t *testing.T) {
 	factory := NewFactory()
 	config := factory.CreateDefaultConfig().(*Config)
@@ -165,6 +165,37 @@ func TestServiceEndpointsChanged(t *testing.T) {
 	}, th.ListEndpoints())
 }
 
+func TestIngressEndpointsAdded(t *testing.T) {
+	th := newTestHandler()
+	th.OnAdd(ingress1, true)
+	assert.ElementsMatch(t, []observer.Endpoint{
+		newIngressEndpoint("test-1", "http", "example.com", "/api"),
+		newIngressEndpoint("test-1", "http", "example.com", "/"),
+		newIngressEndpoint("test-1", "https", "secure.example.com", "/metrics"),
+	}, th.ListEndpoints())
+}
+
+func TestIngressEndpointsRemoved(t *testing.T) {
+	th := newTestHandler()
+	th.OnAdd(ingress1, true)
+	th.OnDelete(ingress1)
+	assert.Empty(t, th.ListEndpoints())
+}
+
+func TestIngressEndpointsChanged(t *testing.T) {
+	th := newTestHandler()
+	th.OnAdd(ingress1, true)
+
+	// Path removed, one endpoint removed.
+	updatedIngress := ingress1.DeepCopy()
+	updatedIngress.Spec.Rules[0].HTTP.Paths = updatedIngress.Spec.Rules[0].HTTP.Paths[:1]
+	th.OnUpdate(ingress1, updatedIngress)
+	assert.ElementsMatch(t, []observer.Endpoint{
+		newIngressEndpoint("test-1", "http", "example.com", "/api"),
+		newIngressEndpoint("test-1", "https", "secure.example.com", "/metrics"),
+	}, th.ListEndpoints())
+}
+
 func TestNodeEndpointsAdded(t *testing.T) {
 	th := newTestHandler()
 	th.OnAdd(node1V1, true)
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8sobserver // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/k8sobserver"
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/networking/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// convertIngressToEndpoints converts an ingress instance into a slice of endpoints, one for each
+// host and path of its rules. Rules without host are served on the address of the load balancer.
+func convertIngressToEndpoints(idNamespace string, ingress *v1.Ingress) []observer.Endpoint {
+	tlsHosts := map[string]bool{}
+	for _, tls := range ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			tlsHosts[host] = true
+		}
+	}
+
+	var endpoints []observer.Endpoint
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = loadBalancerAddress(ingress)
+		}
+		if host == "" || rule.HTTP == nil {
+			continue
+		}
+
+		scheme := "http"
+		if tlsHosts[rule.Host] {
+			scheme = "https"
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			p := path.Path
+			if p == "" {
+				p = "/"
+			}
+			endpoints = append(endpoints, observer.Endpoint{
+				ID:     observer.EndpointID(fmt.Sprintf("%s/%s/%s%s", idNamespace, ingress.UID, host, p)),
+				Target: fmt.Sprintf("%s://%s%s", scheme, host, p),
+				Details: &observer.K8sIngress{
+					Name:        ingress.Name,
+					UID:         string(ingress.UID),
+					Labels:      ingress.Labels,
+					Annotations: ingress.Annotations,
+					Namespace:   ingress.Namespace,
+					Scheme:      scheme,
+					Host:        host,
+					Path:        p,
+				},
+			})
+		}
+	}
+	return endpoints
+}
+
+// loadBalancerAddress returns the IP or hostname of the load balancer of the ingress, if any.
+func loadBalancerAddress(ingress *v1.Ingress) string {
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			return lb.IP
+		}
+		if lb.Hostname != "" {
+			return lb.Hostname
+		}
+	}
+	return ""
+}
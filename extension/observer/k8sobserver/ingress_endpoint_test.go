@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8sobserver // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/k8sobserver"
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func newIngressEndpoint(idNamespace, scheme, host, path string) observer.Endpoint {
+	return observer.Endpoint{
+		ID:     observer.EndpointID(idNamespace + "/ingress-1-UID/" + host + path),
+		Target: scheme + "://" + host + path,
+		Details: &observer.K8sIngress{
+			Name:      "ingress-1",
+			Namespace: "default",
+			UID:       "ingress-1-UID",
+			Labels:    map[string]string{"env": "prod"},
+			Scheme:    scheme,
+			Host:      host,
+			Path:      path,
+		},
+	}
+}
+
+func TestIngressObjectToEndpoint(t *testing.T) {
+	expectedEndpoints := []observer.Endpoint{
+		newIngressEndpoint("namespace", "http", "example.com", "/api"),
+		newIngressEndpoint("namespace", "http", "example.com", "/"),
+		newIngressEndpoint("namespace", "https", "secure.example.com", "/metrics"),
+	}
+
+	endpoints := convertIngressToEndpoints("namespace", ingress1)
+	require.Equal(t, expectedEndpoints, endpoints)
+}
+
+func TestIngressWithLoadBalancerObjectToEndpoint(t *testing.T) {
+	ingress := ingress1.DeepCopy()
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "1.2.3.4"}}
+
+	endpoints := convertIngressToEndpoints("namespace", ingress)
+	require.Len(t, endpoints, 4)
+	require.Equal(t, newIngressEndpoint("namespace", "http", "1.2.3.4", "/"), endpoints[3])
+
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}}
+	endpoints = convertIngressToEndpoints("namespace", ingress)
+	require.Len(t, endpoints, 4)
+	require.Equal(t, newIngressEndpoint("namespace", "http", "lb.example.com", "/"), endpoints[3])
+}
@@ -5,6 +5,7 @@ package k8sobserver
 
 import (
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -134,6 +135,66 @@ var serviceWithClusterIPV2 = func() *v1.Service {
 	return service
 }()
 
+var serviceWithPorts = func() *v1.Service {
+	service := newService("service-2")
+	service.Spec.Ports = []v1.ServicePort{
+		{Name: "http", Port: 80, Protocol: v1.ProtocolTCP},
+		{Port: 9090, Protocol: v1.ProtocolTCP},
+	}
+	return service
+}()
+
+// newIngress is a helper function for creating Ingresses for testing.
+func newIngress(name string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name + "-UID"),
+			Labels: map[string]string{
+				"env": "prod",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{
+				{Hosts: []string{"secure.example.com"}},
+			},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{Path: "/api"}, {}},
+						},
+					},
+				},
+				{
+					Host: "secure.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{Path: "/metrics"}},
+						},
+					},
+				},
+				{
+					// without load balancer address, skipped
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{Path: "/"}},
+						},
+					},
+				},
+				{
+					// without http section, skipped
+					Host: "other.example.com",
+				},
+			},
+		},
+	}
+}
+
+var ingress1 = newIngress("ingress-1")
+
 // newNode is a helper function for creating Nodes for testing.
 func newNode(name, hostname string) *v1.Node {
 	return &v1.Node{
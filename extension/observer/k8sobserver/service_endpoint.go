@@ -5,6 +5,7 @@ package k8sobserver // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"fmt"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -25,6 +26,17 @@ func convertServiceToEndpoints(idNamespace string, service *v1.Service) []observ
 		ClusterIP:   service.Spec.ClusterIP,
 		ServiceType: string(service.Spec.Type),
 	}
+	if len(service.Spec.Ports) > 0 {
+		serviceDetails.Ports = make(map[string]uint16, len(service.Spec.Ports))
+		for _, port := range service.Spec.Ports {
+			// a service with a single port may leave it unnamed, it is then keyed by its number
+			name := port.Name
+			if name == "" {
+				name = strconv.Itoa(int(port.Port))
+			}
+			serviceDetails.Ports[name] = uint16(port.Port)
+		}
+	}
 
 	endpoints := []observer.Endpoint{{
 		ID:      serviceID,
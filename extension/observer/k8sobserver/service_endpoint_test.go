@@ -29,3 +29,23 @@ func TestServiceObjectToEndpoint(t *testing.T) {
 	endpoints := convertServiceToEndpoints("namespace", serviceWithClusterIP)
 	require.Equal(t, expectedEndpoints, endpoints)
 }
+
+func TestServiceWithPortsObjectToEndpoint(t *testing.T) {
+	expectedEndpoints := []observer.Endpoint{
+		{
+			ID:     "namespace/service-2-UID",
+			Target: "service-2.default.svc.cluster.local",
+			Details: &observer.K8sService{
+				Name:        "service-2",
+				Namespace:   "default",
+				UID:         "service-2-UID",
+				Labels:      map[string]string{"env": "prod"},
+				ServiceType: "ClusterIP",
+				ClusterIP:   "1.2.3.4",
+				Ports:       map[string]uint16{"http": 80, "9090": 9090},
+			}},
+	}
+
+	endpoints := convertServiceToEndpoints("namespace", serviceWithPorts)
+	require.Equal(t, expectedEndpoints, endpoints)
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Attribute names of the URL semantic conventions.
+const (
+	AttributeURLOriginal  = "url.original"
+	AttributeURLScheme    = "url.scheme"
+	AttributeURLDomain    = "url.domain"
+	AttributeURLPort      = "url.port"
+	AttributeURLPath      = "url.path"
+	AttributeURLQuery     = "url.query"
+	AttributeURLFragment  = "url.fragment"
+	AttributeURLExtension = "url.extension"
+	AttributeURLUsername  = "url.username"
+)
+
+// ParseURI takes an absolute or relative uri and returns the parsed values.
+// When semConvFormat is set, the values are returned under the keys of the URL semantic conventions,
+// otherwise they are returned under the keys historically used by the stanza uri_parser.
+func ParseURI(value string, semConvFormat bool) (map[string]any, error) {
+	m := make(map[string]any)
+
+	if strings.HasPrefix(value, "?") {
+		// remove the query string '?' prefix before parsing
+		v, err := url.ParseQuery(value[1:])
+		if err != nil {
+			return nil, err
+		}
+		if semConvFormat {
+			m[AttributeURLOriginal] = value
+			m[AttributeURLQuery] = value[1:]
+			return m, nil
+		}
+		return queryToMap(v, m), nil
+	}
+
+	if semConvFormat {
+		// url.ParseRequestURI assumes that there is no fragment, split it off first
+		request, fragment, _ := strings.Cut(value, "#")
+		x, err := url.ParseRequestURI(request)
+		if err != nil {
+			return nil, err
+		}
+		x.Fragment = fragment
+		m[AttributeURLOriginal] = value
+		return urlToSemConvMap(x, m), nil
+	}
+
+	x, err := url.ParseRequestURI(value)
+	if err != nil {
+		return nil, err
+	}
+	return urlToMap(x, m), nil
+}
+
+// urlToMap converts a url.URL to a map, excludes any values that are not set.
+func urlToMap(p *url.URL, m map[string]any) map[string]any {
+	scheme := p.Scheme
+	if scheme != "" {
+		m["scheme"] = scheme
+	}
+
+	user := p.User.Username()
+	if user != "" {
+		m["user"] = user
+	}
+
+	host := p.Hostname()
+	if host != "" {
+		m["host"] = host
+	}
+
+	port := p.Port()
+	if port != "" {
+		m["port"] = port
+	}
+
+	path := p.EscapedPath()
+	if path != "" {
+		m["path"] = path
+	}
+
+	return queryToMap(p.Query(), m)
+}
+
+// urlToSemConvMap converts a url.URL to a map following the URL semantic conventions,
+// excludes any values that are not set.
+func urlToSemConvMap(p *url.URL, m map[string]any) map[string]any {
+	if p.Scheme != "" {
+		m[AttributeURLScheme] = p.Scheme
+	}
+
+	// the password is intentionally left out
+	if user := p.User.Username(); user != "" {
+		m[AttributeURLUsername] = user
+	}
+
+	if host := p.Hostname(); host != "" {
+		m[AttributeURLDomain] = host
+	}
+
+	if port := p.Port(); port != "" {
+		if portNum, err := strconv.ParseInt(port, 10, 64); err == nil {
+			m[AttributeURLPort] = portNum
+		}
+	}
+
+	if escapedPath := p.EscapedPath(); escapedPath != "" {
+		m[AttributeURLPath] = escapedPath
+		if ext := path.Ext(p.Path); len(ext) > 1 {
+			m[AttributeURLExtension] = ext[1:]
+		}
+	}
+
+	if p.RawQuery != "" {
+		m[AttributeURLQuery] = p.RawQuery
+	}
+
+	if p.Fragment != "" {
+		m[AttributeURLFragment] = p.Fragment
+	}
+
+	return m
+}
+
+// queryToMap converts a query string url.Values to a map.
+func queryToMap(query url.Values, m map[string]any) map[string]any {
+	// no-op if query is empty, do not create the key m["query"]
+	if len(query) == 0 {
+		return m
+	}
+
+	/* 'parameter' will represent url.Values
+	map[string]any{
+		"parameter-a": []any{
+			"a",
+			"b",
+		},
+		"parameter-b": []any{
+			"x",
+			"y",
+		},
+	}
+	*/
+	parameters := map[string]any{}
+	for param, values := range query {
+		parameters[param] = queryParamValuesToMap(values)
+	}
+	m["query"] = parameters
+	return m
+}
+
+// queryParamValuesToMap takes query string parameter values and
+// returns an []interface populated with the values
+func queryParamValuesToMap(values []string) []any {
+	v := make([]any, len(values))
+	for i, value := range values {
+		v[i] = value
+	}
+	return v
+}
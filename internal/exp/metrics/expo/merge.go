@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/expo"
+
+// Merge combines the counts of buckets a and b into a.
+// Both buckets MUST be of same scale
+func Merge(arel, brel Buckets) {
+	if brel.BucketCounts().Len() == 0 {
+		return
+	}
+	if arel.BucketCounts().Len() == 0 {
+		brel.CopyTo(arel)
+		return
+	}
+
+	a, b := arel.BucketCounts(), brel.BucketCounts()
+
+	lo := min(arel.Offset(), brel.Offset())
+	hi := max(arel.Offset()+int32(a.Len()), brel.Offset()+int32(b.Len()))
+
+	out := make([]uint64, hi-lo)
+	for i := 0; i < a.Len(); i++ {
+		out[arel.Offset()-lo+int32(i)] += a.At(i)
+	}
+	for i := 0; i < b.Len(); i++ {
+		out[brel.Offset()-lo+int32(i)] += b.At(i)
+	}
+
+	arel.SetOffset(lo)
+	a.FromRaw(out)
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/expo"
+
+import (
+	"fmt"
+	"math"
+)
+
+type Scale int32
+
+// Idx gives the bucket index v belongs into. Bucket i spans (base^i, base^(i+1)],
+// with base = 2^(2^-scale)
+func (scale Scale) Idx(v float64) int32 {
+	// math.Log2 is exact for powers of two, so the lower boundaries of buckets
+	// belong into the previous bucket, as the bucket intervals are upper-inclusive.
+	return int32(math.Ceil(math.Ldexp(math.Log2(v), int(scale)))) - 1
+}
+
+// Bounds returns the half-open interval (min,max] of the bucket at index.
+func (scale Scale) Bounds(index int32) (min, max float64) {
+	at := func(i int32) float64 {
+		return math.Exp2(math.Ldexp(float64(i), -int(scale)))
+	}
+	return at(index), at(index + 1)
+}
+
+// Downscale collapses the buckets of bs until scale 'to' is reached.
+//
+// Lowering the scale by one merges each pair of adjacent buckets into one,
+// so bucket i of scale 'from' is bucket i>>(from-to) of scale 'to'.
+func Downscale(bs Buckets, from, to Scale) {
+	switch {
+	case from == to:
+		return
+	case from < to:
+		// because even distribution within the buckets cannot be assumed, it is
+		// not possible to correctly upscale (split) buckets.
+		// any attempt to do so would yield erroneous data.
+		panic(fmt.Sprintf("cannot upscale without introducing error (%d -> %d)", from, to))
+	}
+
+	shift := from - to
+	counts := bs.BucketCounts()
+	if counts.Len() == 0 {
+		bs.SetOffset(bs.Offset() >> shift)
+		return
+	}
+
+	// arithmetic shifts round towards negative infinity, which is what
+	// negative indexes require as well
+	lo := bs.Offset() >> shift
+	hi := (bs.Offset() + int32(counts.Len()) - 1) >> shift
+
+	out := make([]uint64, hi-lo+1)
+	for i := 0; i < counts.Len(); i++ {
+		idx := (bs.Offset() + int32(i)) >> shift
+		out[idx-lo] += counts.At(i)
+	}
+
+	bs.SetOffset(lo)
+	counts.FromRaw(out)
+}
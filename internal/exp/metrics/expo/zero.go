@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/expo"
+
+import (
+	"fmt"
+)
+
+// WidenZero widens the zero-bucket to span at least [-width,width], possibly wider
+// if width falls in the middle of a bucket.
+//
+// The counts of all buckets within the new zero-bucket are moved into the zero count.
+func WidenZero(dp DataPoint, width float64) {
+	switch {
+	case width == dp.ZeroThreshold():
+		return
+	case width < dp.ZeroThreshold():
+		panic(fmt.Sprintf("min must be larger than current threshold (%f)", dp.ZeroThreshold()))
+	}
+
+	scale := Scale(dp.Scale())
+	zero := scale.Idx(width) // the largest bucket index inside the zero-bucket
+
+	widen := func(bs Buckets) {
+		counts := bs.BucketCounts()
+		n := int(zero - bs.Offset() + 1) // number of buckets inside the zero-bucket
+		if n <= 0 {
+			return
+		}
+		n = min(n, counts.Len())
+
+		var moved uint64
+		for i := 0; i < n; i++ {
+			moved += counts.At(i)
+		}
+		dp.SetZeroCount(dp.ZeroCount() + moved)
+
+		counts.FromRaw(counts.AsRaw()[n:])
+		bs.SetOffset(zero + 1)
+	}
+
+	widen(dp.Positive())
+	widen(dp.Negative())
+
+	_, upper := scale.Bounds(zero)
+	dp.SetZeroThreshold(upper)
+}
@@ -34,6 +34,23 @@ func Test_e2e_editors(t *testing.T) {
 		statement string
 		want      func(tCtx ottllog.TransformContext)
 	}{
+		{
+			statement: `append(attributes["foo"]["slice"], "sample_value")`,
+			want: func(tCtx ottllog.TransformContext) {
+				v, _ := tCtx.GetLogRecord().Attributes().Get("foo")
+				s, _ := v.Map().Get("slice")
+				s.Slice().AppendEmpty().SetStr("sample_value")
+			},
+		},
+		{
+			statement: `append(attributes["http.method"], values=["post", "put"])`,
+			want: func(tCtx ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("http.method")
+				s.AppendEmpty().SetStr("get")
+				s.AppendEmpty().SetStr("post")
+				s.AppendEmpty().SetStr("put")
+			},
+		},
 		{
 			statement: `delete_key(attributes, "http.method")`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -315,6 +332,15 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutStr("test", "pass")
 			},
 		},
+		{
+			statement: `set(attributes["test"], ExtractGrokPatterns("12 GET /index.html", "%{INT:count:int} %{WORD:verb} %{URIPATHPARAM:request}", true))`,
+			want: func(tCtx ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutInt("count", 12)
+				m.PutStr("verb", "GET")
+				m.PutStr("request", "/index.html")
+			},
+		},
 		{
 			statement: `set(attributes["test"], ExtractPatterns("aa123bb", "(?P<numbers>\\d+)"))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -340,6 +366,18 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutDouble("test", 1.5)
 			},
 		},
+		{
+			statement: `set(attributes["test"], Index(attributes["flags"], "B"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutInt("test", 2)
+			},
+		},
+		{
+			statement: `set(attributes["test"], Index(Split(attributes["flags"], "|"), "C"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutInt("test", 2)
+			},
+		},
 		{
 			statement: `set(attributes["test"], Int(1.0))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -485,6 +523,26 @@ func Test_e2e_converters(t *testing.T) {
 				m.PutStr("k2", "v2__!__v2")
 			},
 		},
+		{
+			statement: `set(attributes["test"], ParseSeverity("OOPS", ParseJSON("{\"error\":[\"e\",\"oops\"]}")))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutInt("test", int64(plog.SeverityNumberError))
+			},
+		},
+		{
+			statement: `set(attributes["test"], ParseURL("https://www.example.com:8443/docs/guide.pdf?lang=en#intro"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("url.original", "https://www.example.com:8443/docs/guide.pdf?lang=en#intro")
+				m.PutStr("url.scheme", "https")
+				m.PutStr("url.domain", "www.example.com")
+				m.PutInt("url.port", 8443)
+				m.PutStr("url.path", "/docs/guide.pdf")
+				m.PutStr("url.extension", "pdf")
+				m.PutStr("url.query", "lang=en")
+				m.PutStr("url.fragment", "intro")
+			},
+		},
 		{
 			statement: `set(attributes["test"], ParseXML("<Log id=\"1\"><Message>This is a log message!</Message></Log>"))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -525,6 +583,15 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().SetSpanID(pcommon.NewSpanIDEmpty())
 			},
 		},
+		{
+			statement: `set(attributes["test"], Sort(Split(attributes["flags"], "|"), "desc"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("test")
+				s.AppendEmpty().SetStr("C")
+				s.AppendEmpty().SetStr("B")
+				s.AppendEmpty().SetStr("A")
+			},
+		},
 		{
 			statement: `set(attributes["test"], Split(attributes["flags"], "|"))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -576,6 +643,15 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutStr("test", "pass")
 			},
 		},
+		{
+			statement: `set(attributes["test"], UserAgent("curl/8.4.0"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("user_agent.original", "curl/8.4.0")
+				m.PutStr("user_agent.name", "curl")
+				m.PutStr("user_agent.version", "8.4.0")
+			},
+		},
 		{
 			statement: `set(attributes["test"], "pass") where IsString(UUID())`,
 			want: func(tCtx ottllog.TransformContext) {
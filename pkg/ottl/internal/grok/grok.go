@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package grok compiles grok expressions, regular expressions referencing named patterns
+// with the %{SYNTAX:SEMANTIC:TYPE} notation, into Go regular expressions.
+package grok // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/grok"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxDepth bounds the nesting of pattern references, which detects recursive definitions.
+	maxDepth = 64
+
+	groupPrefix = "grok"
+
+	typeInt   = "int"
+	typeFloat = "float"
+)
+
+var (
+	referenceRegexp  = regexp.MustCompile(`%\{(\w+)(?::([\w.@\[\]-]+))?(?::(\w+))?\}`)
+	patternNameRegex = regexp.MustCompile(`^\w+$`)
+
+	errRecursion = errors.New("pattern definitions are too deeply nested or recursive")
+)
+
+// field describes a value extracted by a pattern.
+type field struct {
+	name string
+	typ  string
+}
+
+// Pattern is a compiled grok expression.
+type Pattern struct {
+	re     *regexp.Regexp
+	fields map[string]field
+}
+
+// ParseDefinitions parses pattern definitions of the form NAME=REGEX.
+func ParseDefinitions(definitions []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(definitions))
+	for _, def := range definitions {
+		name, pattern, ok := strings.Cut(def, "=")
+		if !ok || !patternNameRegex.MatchString(name) || pattern == "" {
+			return nil, fmt.Errorf("invalid pattern definition %q, must be of the form NAME=PATTERN", def)
+		}
+		parsed[name] = pattern
+	}
+	return parsed, nil
+}
+
+// Compile compiles the grok expression. The patterns it references are looked up in the
+// given definitions first, and then in the built-in patterns.
+// A reference is extracted when it is given a semantic, as in %{NUMBER:bytes}. When namedCapturesOnly
+// is false, the patterns directly referenced without semantic are also extracted, under their name.
+func Compile(expr string, definitions map[string]string, namedCapturesOnly bool) (*Pattern, error) {
+	c := compiler{
+		definitions:       definitions,
+		namedCapturesOnly: namedCapturesOnly,
+		fields:            make(map[string]field),
+	}
+	expanded, err := c.expand(expr, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("the expanded pattern is not a valid regular expression: %w", err)
+	}
+
+	// named groups written directly in the expression are extracted too
+	for _, name := range re.SubexpNames() {
+		if _, ok := c.fields[name]; !ok && name != "" {
+			c.fields[name] = field{name: name}
+		}
+	}
+	if len(c.fields) == 0 {
+		return nil, errors.New("at least one named capture or pattern with a semantic must be supplied")
+	}
+
+	return &Pattern{re: re, fields: c.fields}, nil
+}
+
+// Match returns the values extracted from s, or nil when s doesn't match.
+// Values of the int and float types are converted to int64 and float64,
+// and left as strings when they can't be converted.
+func (p *Pattern) Match(s string) map[string]any {
+	loc := p.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(p.fields))
+	for i, name := range p.re.SubexpNames() {
+		f, ok := p.fields[name]
+		if !ok || loc[2*i] < 0 {
+			// unnamed group, or a group which did not participate in the match
+			continue
+		}
+		result[f.name] = convert(s[loc[2*i]:loc[2*i+1]], f.typ)
+	}
+	return result
+}
+
+func convert(val, typ string) any {
+	switch typ {
+	case typeInt:
+		if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return i
+		}
+	case typeFloat:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return val
+}
+
+type compiler struct {
+	definitions       map[string]string
+	namedCapturesOnly bool
+	fields            map[string]field
+}
+
+func (c *compiler) lookup(name string) (string, bool) {
+	if def, ok := c.definitions[name]; ok {
+		return def, true
+	}
+	def, ok := builtinPatterns[name]
+	return def, ok
+}
+
+// expand replaces the pattern references by their definitions, recursively.
+func (c *compiler) expand(pattern string, depth int) (string, error) {
+	if depth > maxDepth {
+		return "", errRecursion
+	}
+
+	var err error
+	expanded := referenceRegexp.ReplaceAllStringFunc(pattern, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		m := referenceRegexp.FindStringSubmatch(ref)
+		name, semantic, typ := m[1], m[2], m[3]
+
+		def, ok := c.lookup(name)
+		if !ok {
+			err = fmt.Errorf("pattern %q is not defined", name)
+			return ""
+		}
+		if typ != "" && typ != typeInt && typ != typeFloat {
+			err = fmt.Errorf("invalid type %q for pattern %q, must be either %q or %q", typ, name, typeInt, typeFloat)
+			return ""
+		}
+
+		var sub string
+		if sub, err = c.expand(def, depth+1); err != nil {
+			return ""
+		}
+
+		if semantic == "" {
+			if c.namedCapturesOnly || depth > 0 {
+				return "(?:" + sub + ")"
+			}
+			semantic = name
+		}
+		group := groupPrefix + strconv.Itoa(len(c.fields))
+		c.fields[group] = field{name: semantic, typ: typ}
+		return "(?P<" + group + ">" + sub + ")"
+	})
+	return expanded, err
+}
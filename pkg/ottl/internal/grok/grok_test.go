@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package grok
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinPatternsCompile(t *testing.T) {
+	for name := range builtinPatterns {
+		t.Run(name, func(t *testing.T) {
+			c := compiler{fields: make(map[string]field)}
+			expanded, err := c.expand("%{"+name+"}", 0)
+			require.NoError(t, err)
+			_, err = regexp.Compile(expanded)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		expr              string
+		definitions       map[string]string
+		namedCapturesOnly bool
+		input             string
+		expected          map[string]any
+	}{
+		{
+			name:              "named captures",
+			expr:              `%{WORD:verb} %{URIPATHPARAM:path}`,
+			namedCapturesOnly: true,
+			input:             "GET /api/v1?id=1",
+			expected:          map[string]any{"verb": "GET", "path": "/api/v1?id=1"},
+		},
+		{
+			name:              "unnamed patterns extracted",
+			expr:              `%{IPV4} %{WORD:method}`,
+			namedCapturesOnly: false,
+			input:             "10.0.0.1 POST",
+			expected:          map[string]any{"IPV4": "10.0.0.1", "method": "POST"},
+		},
+		{
+			name:              "unnamed patterns ignored",
+			expr:              `%{IPV4} %{WORD:method}`,
+			namedCapturesOnly: true,
+			input:             "10.0.0.1 POST",
+			expected:          map[string]any{"method": "POST"},
+		},
+		{
+			name:              "typed values",
+			expr:              `%{NUMBER:duration:float}ms %{INT:status:int}`,
+			namedCapturesOnly: true,
+			input:             "12.5ms 404",
+			expected:          map[string]any{"duration": 12.5, "status": int64(404)},
+		},
+		{
+			name:              "dotted semantic",
+			expr:              `%{LOGLEVEL:log.level}: %{GREEDYDATA:message}`,
+			namedCapturesOnly: true,
+			input:             "WARNING: disk almost full",
+			expected:          map[string]any{"log.level": "WARNING", "message": "disk almost full"},
+		},
+		{
+			name:              "custom definitions",
+			expr:              `%{REQUEST_ID:id} %{GREEDYDATA:message}`,
+			definitions:       map[string]string{"REQUEST_ID": `req-%{INT}`},
+			namedCapturesOnly: true,
+			input:             "req-42 done",
+			expected:          map[string]any{"id": "req-42", "message": "done"},
+		},
+		{
+			name:              "regex named groups",
+			expr:              `(?P<level>\w+) %{GREEDYDATA:message}`,
+			namedCapturesOnly: true,
+			input:             "info started",
+			expected:          map[string]any{"level": "info", "message": "started"},
+		},
+		{
+			name:              "optional group not matched",
+			expr:              `%{WORD:first}(?: %{WORD:second})?`,
+			namedCapturesOnly: true,
+			input:             "one",
+			expected:          map[string]any{"first": "one"},
+		},
+		{
+			name:              "apache common log",
+			expr:              `%{COMMONAPACHELOG}`,
+			namedCapturesOnly: true,
+			input:             `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			expected: map[string]any{
+				"clientip":    "127.0.0.1",
+				"ident":       "-",
+				"auth":        "frank",
+				"timestamp":   "10/Oct/2000:13:55:36 -0700",
+				"verb":        "GET",
+				"request":     "/apache_pb.gif",
+				"httpversion": "1.0",
+				"response":    "200",
+				"bytes":       "2326",
+			},
+		},
+		{
+			name:              "syslog",
+			expr:              `%{SYSLOGBASE} %{GREEDYDATA:message}`,
+			namedCapturesOnly: true,
+			input:             "Mar  7 04:02:16 host1 sshd[3912]: Accepted publickey",
+			expected: map[string]any{
+				"timestamp": "Mar  7 04:02:16",
+				"logsource": "host1",
+				"program":   "sshd",
+				"pid":       "3912",
+				"message":   "Accepted publickey",
+			},
+		},
+		{
+			name:              "ipv6",
+			expr:              `%{IP:ip}`,
+			namedCapturesOnly: true,
+			input:             "2001:db8::1:2",
+			expected:          map[string]any{"ip": "2001:db8::1:2"},
+		},
+		{
+			name:              "timestamp",
+			expr:              `%{TIMESTAMP_ISO8601:time}`,
+			namedCapturesOnly: true,
+			input:             "2024-03-25T10:11:12.345Z",
+			expected:          map[string]any{"time": "2024-03-25T10:11:12.345Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Compile(tt.expr, tt.definitions, tt.namedCapturesOnly)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, p.Match(tt.input))
+		})
+	}
+}
+
+func TestNoMatch(t *testing.T) {
+	p, err := Compile(`^%{INT:value}$`, nil, true)
+	require.NoError(t, err)
+	assert.Nil(t, p.Match("abc"))
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		definitions map[string]string
+	}{
+		{
+			name: "undefined pattern",
+			expr: `%{UNKNOWN:value}`,
+		},
+		{
+			name: "invalid type",
+			expr: `%{INT:value:bool}`,
+		},
+		{
+			name:        "recursive definition",
+			expr:        `%{LOOP:value}`,
+			definitions: map[string]string{"LOOP": `a%{LOOP}`},
+		},
+		{
+			name:        "invalid regex",
+			expr:        `%{BROKEN:value}`,
+			definitions: map[string]string{"BROKEN": `(`},
+		},
+		{
+			name: "nothing extracted",
+			expr: `%{INT}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.expr, tt.definitions, true)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseDefinitions(t *testing.T) {
+	defs, err := ParseDefinitions([]string{"MY_ID=id-%{INT}", "EQ=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"MY_ID": "id-%{INT}", "EQ": "a=b"}, defs)
+
+	_, err = ParseDefinitions([]string{"no separator"})
+	assert.Error(t, err)
+
+	_, err = ParseDefinitions([]string{"INVALID NAME=x"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type AppendArguments[K any] struct {
+	Target ottl.GetSetter[K]
+	Value  ottl.Optional[ottl.Getter[K]]
+	Values ottl.Optional[[]ottl.Getter[K]]
+}
+
+func NewAppendFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("append", &AppendArguments[K]{}, createAppendFunction[K])
+}
+
+func createAppendFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*AppendArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("AppendFactory args must be of type *AppendArguments[K]")
+	}
+
+	return appendTo(args.Target, args.Value, args.Values)
+}
+
+func appendTo[K any](target ottl.GetSetter[K], value ottl.Optional[ottl.Getter[K]], values ottl.Optional[[]ottl.Getter[K]]) (ottl.ExprFunc[K], error) {
+	if value.IsEmpty() && values.IsEmpty() {
+		return nil, fmt.Errorf("at least one of the optional arguments ('value' or 'values') must be provided")
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		t, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		// the target is replaced by a new slice, leaving the original value untouched
+		// in case the evaluation of the values fails
+		res := pcommon.NewSlice()
+		if err = appendValue(res, t, true); err != nil {
+			return nil, err
+		}
+
+		if !value.IsEmpty() {
+			v, err := value.Get().Get(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			if err = appendValue(res, v, false); err != nil {
+				return nil, err
+			}
+		}
+		if !values.IsEmpty() {
+			for _, getter := range values.Get() {
+				v, err := getter.Get(ctx, tCtx)
+				if err != nil {
+					return nil, err
+				}
+				if err = appendValue(res, v, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return nil, target.Set(ctx, tCtx, res)
+	}, nil
+}
+
+// appendValue appends val to the slice. When flatten is set, the elements of a slice
+// are appended one by one, so that an existing list is extended rather than nested.
+// A nil value is skipped in that case, as it denotes a target that isn't set yet.
+func appendValue(dst pcommon.Slice, val any, flatten bool) error {
+	if flatten {
+		switch v := val.(type) {
+		case nil:
+			return nil
+		case pcommon.Slice:
+			for i := 0; i < v.Len(); i++ {
+				v.At(i).CopyTo(dst.AppendEmpty())
+			}
+			return nil
+		case pcommon.Value:
+			switch v.Type() {
+			case pcommon.ValueTypeEmpty:
+				return nil
+			case pcommon.ValueTypeSlice:
+				return appendValue(dst, v.Slice(), true)
+			}
+		case []string, []int64, []float64, []bool, []any:
+			list := pcommon.NewValueEmpty()
+			if err := setValue(list, v); err != nil {
+				return err
+			}
+			return appendValue(dst, list.Slice(), true)
+		}
+	}
+	return setValue(dst.AppendEmpty(), val)
+}
+
+// setValue sets the value to val, handling the pdata types in addition to the raw types.
+func setValue(dst pcommon.Value, val any) error {
+	switch v := val.(type) {
+	case pcommon.Value:
+		v.CopyTo(dst)
+	case pcommon.Map:
+		v.CopyTo(dst.SetEmptyMap())
+	case pcommon.Slice:
+		v.CopyTo(dst.SetEmptySlice())
+	case []string:
+		s := dst.SetEmptySlice()
+		s.EnsureCapacity(len(v))
+		for _, str := range v {
+			s.AppendEmpty().SetStr(str)
+		}
+	case []int64:
+		s := dst.SetEmptySlice()
+		s.EnsureCapacity(len(v))
+		for _, i := range v {
+			s.AppendEmpty().SetInt(i)
+		}
+	case []float64:
+		s := dst.SetEmptySlice()
+		s.EnsureCapacity(len(v))
+		for _, f := range v {
+			s.AppendEmpty().SetDouble(f)
+		}
+	case []bool:
+		s := dst.SetEmptySlice()
+		s.EnsureCapacity(len(v))
+		for _, b := range v {
+			s.AppendEmpty().SetBool(b)
+		}
+	case []any:
+		s := dst.SetEmptySlice()
+		s.EnsureCapacity(len(v))
+		for _, a := range v {
+			if err := setValue(s.AppendEmpty(), a); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := dst.FromRaw(v); err != nil {
+			return fmt.Errorf("unsupported value of type %T: %w", val, err)
+		}
+	}
+	return nil
+}
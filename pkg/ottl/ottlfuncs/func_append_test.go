@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_append(t *testing.T) {
+	literal := func(val any) ottl.Getter[any] {
+		return ottl.StandardGetSetter[any]{
+			Getter: func(context.Context, any) (any, error) {
+				return val, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		target   any
+		value    ottl.Optional[ottl.Getter[any]]
+		values   ottl.Optional[[]ottl.Getter[any]]
+		expected []any
+	}{
+		{
+			name:     "append to unset target",
+			target:   nil,
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal("a")),
+			expected: []any{"a"},
+		},
+		{
+			name:     "append to single value",
+			target:   pcommon.NewValueStr("a"),
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(int64(1))),
+			expected: []any{"a", int64(1)},
+		},
+		{
+			name: "append to pcommon.Slice",
+			target: func() pcommon.Slice {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("a")
+				s.AppendEmpty().SetBool(true)
+				return s
+			}(),
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(1.5)),
+			expected: []any{"a", true, 1.5},
+		},
+		{
+			name: "append to slice value",
+			target: func() pcommon.Value {
+				v := pcommon.NewValueSlice()
+				v.Slice().AppendEmpty().SetInt(1)
+				return v
+			}(),
+			values:   ottl.NewTestingOptional([]ottl.Getter[any]{literal(int64(2)), literal(int64(3))}),
+			expected: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:     "append to []string",
+			target:   []string{"a", "b"},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal("c")),
+			values:   ottl.NewTestingOptional([]ottl.Getter[any]{literal("d")}),
+			expected: []any{"a", "b", "c", "d"},
+		},
+		{
+			name:     "append to []int64",
+			target:   []int64{1, 2},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(int64(3))),
+			expected: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:     "append to []float64",
+			target:   []float64{1.5},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(2.5)),
+			expected: []any{1.5, 2.5},
+		},
+		{
+			name:     "append to []bool",
+			target:   []bool{true},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(false)),
+			expected: []any{true, false},
+		},
+		{
+			name:     "append to []any",
+			target:   []any{"a", int64(1)},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal(true)),
+			expected: []any{"a", int64(1), true},
+		},
+		{
+			name:     "append list as a single element",
+			target:   []any{"a"},
+			value:    ottl.NewTestingOptional[ottl.Getter[any]](literal([]any{"b", "c"})),
+			expected: []any{"a", []any{"b", "c"}},
+		},
+		{
+			name:   "append map",
+			target: nil,
+			value: ottl.NewTestingOptional[ottl.Getter[any]](literal(func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("k", "v")
+				return m
+			}())),
+			expected: []any{map[string]any{"k": "v"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result pcommon.Slice
+			target := &ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+				Setter: func(_ context.Context, _ any, val any) error {
+					result = val.(pcommon.Slice)
+					return nil
+				},
+			}
+
+			exprFunc, err := appendTo[any](target, tt.value, tt.values)
+			require.NoError(t, err)
+
+			_, err = exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.AsRaw())
+		})
+	}
+}
+
+func Test_append_no_value(t *testing.T) {
+	target := &ottl.StandardGetSetter[any]{}
+	_, err := appendTo[any](target, ottl.Optional[ottl.Getter[any]]{}, ottl.Optional[[]ottl.Getter[any]]{})
+	assert.Error(t, err)
+}
+
+func Test_append_unsupported_value(t *testing.T) {
+	target := &ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return nil, nil
+		},
+		Setter: func(context.Context, any, any) error {
+			return nil
+		},
+	}
+	value := ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return struct{}{}, nil
+		},
+	}
+
+	exprFunc, err := appendTo[any](target, ottl.NewTestingOptional[ottl.Getter[any]](value), ottl.Optional[[]ottl.Getter[any]]{})
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/grok"
+)
+
+type ExtractGrokPatternsArguments[K any] struct {
+	Target             ottl.StringGetter[K]
+	Pattern            string
+	NamedCapturesOnly  ottl.Optional[bool]
+	PatternDefinitions ottl.Optional[[]string]
+}
+
+func NewExtractGrokPatternsFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ExtractGrokPatterns", &ExtractGrokPatternsArguments[K]{}, createExtractGrokPatternsFunction[K])
+}
+
+func createExtractGrokPatternsFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ExtractGrokPatternsArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ExtractGrokPatternsFactory args must be of type *ExtractGrokPatternsArguments[K]")
+	}
+
+	return extractGrokPatterns(args.Target, args.Pattern, args.NamedCapturesOnly, args.PatternDefinitions)
+}
+
+func extractGrokPatterns[K any](target ottl.StringGetter[K], pattern string, nco ottl.Optional[bool], pd ottl.Optional[[]string]) (ottl.ExprFunc[K], error) {
+	namedCapturesOnly := !nco.IsEmpty() && nco.Get()
+
+	var definitions map[string]string
+	if !pd.IsEmpty() {
+		var err error
+		if definitions, err = grok.ParseDefinitions(pd.Get()); err != nil {
+			return nil, fmt.Errorf("the pattern definitions supplied to ExtractGrokPatterns are not valid: %w", err)
+		}
+	}
+
+	p, err := grok.Compile(pattern, definitions, namedCapturesOnly)
+	if err != nil {
+		return nil, fmt.Errorf("the pattern supplied to ExtractGrokPatterns is not a valid pattern: %w", err)
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		result := pcommon.NewMap()
+		matches := p.Match(val)
+		if matches == nil {
+			return result, nil
+		}
+		if err = result.FromRaw(matches); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_extractGrokPatterns(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		pattern            string
+		namedCapturesOnly  ottl.Optional[bool]
+		patternDefinitions ottl.Optional[[]string]
+		want               map[string]any
+	}{
+		{
+			name:              "named captures",
+			target:            `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326`,
+			pattern:           `%{COMMONAPACHELOG}`,
+			namedCapturesOnly: ottl.NewTestingOptional[bool](true),
+			want: map[string]any{
+				"clientip":    "127.0.0.1",
+				"ident":       "-",
+				"auth":        "-",
+				"timestamp":   "10/Oct/2000:13:55:36 -0700",
+				"verb":        "GET",
+				"request":     "/index.html",
+				"httpversion": "1.1",
+				"response":    "200",
+				"bytes":       "2326",
+			},
+		},
+		{
+			name:    "unnamed patterns",
+			target:  "2024-03-25T10:11:12Z ERROR connection lost",
+			pattern: `%{TIMESTAMP_ISO8601} %{LOGLEVEL:level} %{GREEDYDATA:message}`,
+			want: map[string]any{
+				"TIMESTAMP_ISO8601": "2024-03-25T10:11:12Z",
+				"level":             "ERROR",
+				"message":           "connection lost",
+			},
+		},
+		{
+			name:              "typed values",
+			target:            "took 150ms for 3 items",
+			pattern:           `took %{INT:duration_ms:int}ms for %{NUMBER:count:float} items`,
+			namedCapturesOnly: ottl.NewTestingOptional[bool](true),
+			want: map[string]any{
+				"duration_ms": int64(150),
+				"count":       float64(3),
+			},
+		},
+		{
+			name:               "pattern definitions",
+			target:             "user=alice id=U-1234",
+			pattern:            `user=%{USERNAME:user} id=%{USER_ID:id}`,
+			namedCapturesOnly:  ottl.NewTestingOptional[bool](true),
+			patternDefinitions: ottl.NewTestingOptional[[]string]([]string{"USER_ID=U-%{INT}"}),
+			want: map[string]any{
+				"user": "alice",
+				"id":   "U-1234",
+			},
+		},
+		{
+			name:              "no match",
+			target:            "not a number",
+			pattern:           `^%{INT:value}$`,
+			namedCapturesOnly: ottl.NewTestingOptional[bool](true),
+			want:              map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardStringGetter[any]{
+				Getter: func(ctx context.Context, tCtx any) (any, error) {
+					return tt.target, nil
+				},
+			}
+
+			exprFunc, err := extractGrokPatterns[any](target, tt.pattern, tt.namedCapturesOnly, tt.patternDefinitions)
+			require.NoError(t, err)
+
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_extractGrokPatterns_validation(t *testing.T) {
+	tests := []struct {
+		name               string
+		pattern            string
+		patternDefinitions ottl.Optional[[]string]
+	}{
+		{
+			name:    "undefined pattern",
+			pattern: `%{NOT_A_PATTERN:value}`,
+		},
+		{
+			name:    "no captures",
+			pattern: `%{INT}`,
+		},
+		{
+			name:               "invalid definition",
+			pattern:            `%{INT:value}`,
+			patternDefinitions: ottl.NewTestingOptional[[]string]([]string{"MISSING_SEPARATOR"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardStringGetter[any]{}
+			_, err := extractGrokPatterns[any](target, tt.pattern, ottl.NewTestingOptional[bool](true), tt.patternDefinitions)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_extractGrokPatterns_bad_input(t *testing.T) {
+	target := &ottl.StandardStringGetter[any]{
+		Getter: func(ctx context.Context, tCtx any) (any, error) {
+			return 1, nil
+		},
+	}
+
+	exprFunc, err := extractGrokPatterns[any](target, `%{INT:value}`, ottl.Optional[bool]{}, ottl.Optional[[]string]{})
+	require.NoError(t, err)
+
+	result, err := exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
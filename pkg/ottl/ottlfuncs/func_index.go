@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type IndexArguments[K any] struct {
+	Target ottl.Getter[K]
+	Value  ottl.Getter[K]
+}
+
+func NewIndexFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Index", &IndexArguments[K]{}, createIndexFunction[K])
+}
+
+func createIndexFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*IndexArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("IndexFactory args must be of type *IndexArguments[K]")
+	}
+
+	return index(args.Target, args.Value), nil
+}
+
+func index[K any](target ottl.Getter[K], value ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		t, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		v, err := value.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		v = toRaw(v)
+
+		if str, ok := toRaw(t).(string); ok {
+			substr, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("the value looked up in a string must be a string, got %T", v)
+			}
+			// the position is counted in characters, like the positions of the list elements
+			i := strings.Index(str, substr)
+			if i < 0 {
+				return int64(-1), nil
+			}
+			return int64(utf8.RuneCountInString(str[:i])), nil
+		}
+
+		elems, err := toRawSlice(t)
+		if err != nil {
+			return nil, fmt.Errorf("target must be a string or a list: %w", err)
+		}
+		for i, e := range elems {
+			if rawEqual(e, v) {
+				return int64(i), nil
+			}
+		}
+		return int64(-1), nil
+	}
+}
+
+// toRaw returns the raw value held by the pdata types, or the value itself otherwise.
+func toRaw(val any) any {
+	switch v := val.(type) {
+	case pcommon.Value:
+		return v.AsRaw()
+	case pcommon.Map:
+		return v.AsRaw()
+	case pcommon.Slice:
+		return v.AsRaw()
+	}
+	return val
+}
+
+// rawEqual compares two raw values, integers and doubles being compared as numbers.
+func rawEqual(a, b any) bool {
+	switch av := a.(type) {
+	case int64:
+		if bv, ok := b.(float64); ok {
+			return float64(av) == bv
+		}
+	case float64:
+		if bv, ok := b.(int64); ok {
+			return av == float64(bv)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
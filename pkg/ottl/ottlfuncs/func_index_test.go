@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_Index(t *testing.T) {
+	pSlice := pcommon.NewSlice()
+	pSlice.AppendEmpty().SetStr("a")
+	pSlice.AppendEmpty().SetInt(2)
+	pSlice.AppendEmpty().SetBool(true)
+
+	tests := []struct {
+		name     string
+		target   any
+		value    any
+		expected int64
+	}{
+		{
+			name:     "substring",
+			target:   "hello world",
+			value:    "world",
+			expected: 6,
+		},
+		{
+			name:     "substring in value",
+			target:   pcommon.NewValueStr("hello world"),
+			value:    "o",
+			expected: 4,
+		},
+		{
+			name:     "substring after multibyte characters",
+			target:   "héllo wörld",
+			value:    "wörld",
+			expected: 6,
+		},
+		{
+			name:     "substring not found",
+			target:   "hello world",
+			value:    "foo",
+			expected: -1,
+		},
+		{
+			name:     "string in list",
+			target:   []string{"a", "b", "c"},
+			value:    "c",
+			expected: 2,
+		},
+		{
+			name:     "integer in pcommon.Slice",
+			target:   pSlice,
+			value:    int64(2),
+			expected: 1,
+		},
+		{
+			name:     "double matches integer",
+			target:   pSlice,
+			value:    2.0,
+			expected: 1,
+		},
+		{
+			name:     "boolean value",
+			target:   pSlice,
+			value:    pcommon.NewValueBool(true),
+			expected: 2,
+		},
+		{
+			name:     "not found in list",
+			target:   pSlice,
+			value:    "b",
+			expected: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			value := ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.value, nil
+				},
+			}
+
+			result, err := index[any](target, value)(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_Index_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		target any
+		value  any
+	}{
+		{
+			name:   "non string value in string",
+			target: "hello",
+			value:  int64(1),
+		},
+		{
+			name:   "unsupported target",
+			target: int64(1),
+			value:  int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			value := ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.value, nil
+				},
+			}
+
+			_, err := index[any](target, value)(context.Background(), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+type ParseSeverityArguments[K any] struct {
+	Target  ottl.Getter[K]
+	Mapping ottl.PMapGetter[K]
+}
+
+func NewParseSeverityFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ParseSeverity", &ParseSeverityArguments[K]{}, createParseSeverityFunction[K])
+}
+
+func createParseSeverityFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ParseSeverityArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ParseSeverityFactory args must be of type *ParseSeverityArguments[K]")
+	}
+
+	return parseSeverity(args.Target, args.Mapping), nil
+}
+
+// severityLevels are the names and numbers accepted as keys of the mapping, as well as the values
+// recognized without being mapped. The numbers are those of the plog.SeverityNumber values.
+var severityLevels = func() map[string]plog.SeverityNumber {
+	names := []string{
+		"trace", "trace2", "trace3", "trace4",
+		"debug", "debug2", "debug3", "debug4",
+		"info", "info2", "info3", "info4",
+		"warn", "warn2", "warn3", "warn4",
+		"error", "error2", "error3", "error4",
+		"fatal", "fatal2", "fatal3", "fatal4",
+	}
+	levels := make(map[string]plog.SeverityNumber, 2*len(names))
+	for i, name := range names {
+		sev := plog.SeverityNumber(i + 1)
+		levels[name] = sev
+		levels[strconv.Itoa(i+1)] = sev
+	}
+	return levels
+}()
+
+// severityAliases are the additional values recognized without being mapped.
+var severityAliases = map[string]plog.SeverityNumber{
+	"warning":  plog.SeverityNumberWarn,
+	"warning2": plog.SeverityNumberWarn2,
+	"warning3": plog.SeverityNumberWarn3,
+	"warning4": plog.SeverityNumberWarn4,
+	"err":      plog.SeverityNumberError,
+	"err2":     plog.SeverityNumberError2,
+	"err3":     plog.SeverityNumberError3,
+	"err4":     plog.SeverityNumberError4,
+}
+
+func parseSeverity[K any](target ottl.Getter[K], mapping ottl.PMapGetter[K]) ottl.ExprFunc[K] {
+	var built atomic.Pointer[severityMapping]
+	return func(ctx context.Context, tCtx K) (any, error) {
+		m, err := mapping.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		// The mapping is typically the same for every evaluation, such as one parsed from
+		// a string literal, it is only built again when its content changes.
+		hash := pdatautil.MapHash(m)
+		last := built.Load()
+		if last == nil || last.hash != hash {
+			severities, buildErr := buildSeverityMapping(m)
+			if buildErr != nil {
+				return nil, buildErr
+			}
+			last = &severityMapping{hash: hash, severities: severities}
+			built.Store(last)
+		}
+		severities := last.severities
+
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		key, err := severityKey(val)
+		if err != nil {
+			return nil, err
+		}
+		return int64(severities[key]), nil
+	}
+}
+
+// severityMapping is a mapping built by buildSeverityMapping, along with the hash of its source.
+type severityMapping struct {
+	hash       [16]byte
+	severities map[string]plog.SeverityNumber
+}
+
+// buildSeverityMapping returns the mapping of the values to their severity, made of the
+// levels, their aliases and the values of the given mapping, which take precedence.
+func buildSeverityMapping(m pcommon.Map) (map[string]plog.SeverityNumber, error) {
+	severities := make(map[string]plog.SeverityNumber, len(severityLevels)+len(severityAliases)+m.Len())
+	for k, v := range severityLevels {
+		severities[k] = v
+	}
+	for k, v := range severityAliases {
+		severities[k] = v
+	}
+
+	var err error
+	m.Range(func(level string, values pcommon.Value) bool {
+		sev, ok := severityLevels[strings.ToLower(level)]
+		if !ok {
+			err = fmt.Errorf("%q is not a valid severity level", level)
+			return false
+		}
+
+		raw := values.AsRaw()
+		if list, isList := raw.([]any); isList {
+			for _, v := range list {
+				if err = addSeverityValues(severities, sev, v); err != nil {
+					return false
+				}
+			}
+			return true
+		}
+		err = addSeverityValues(severities, sev, raw)
+		return err == nil
+	})
+	return severities, err
+}
+
+// addSeverityValues maps a value, or the values it describes, to the severity.
+// A value is either a string, a whole number, a range such as {"min": 400, "max": 499},
+// or one of the 2xx, 3xx, 4xx and 5xx shorthands for the ranges of HTTP status codes.
+func addSeverityValues(severities map[string]plog.SeverityNumber, sev plog.SeverityNumber, value any) error {
+	switch v := value.(type) {
+	case string:
+		if lo, hi, ok := httpStatusRange(v); ok {
+			addSeverityRange(severities, sev, lo, hi)
+			return nil
+		}
+		severities[strings.ToLower(v)] = sev
+	case int64:
+		severities[strconv.FormatInt(v, 10)] = sev
+	case float64:
+		if v != float64(int64(v)) {
+			return fmt.Errorf("%v cannot be a severity value unless it is a whole number", v)
+		}
+		severities[strconv.FormatInt(int64(v), 10)] = sev
+	case map[string]any:
+		lo, loOK := wholeNumber(v["min"])
+		hi, hiOK := wholeNumber(v["max"])
+		if !loOK || !hiOK {
+			return fmt.Errorf("a range of severity values must have whole numbers as 'min' and 'max'")
+		}
+		addSeverityRange(severities, sev, lo, hi)
+	default:
+		return fmt.Errorf("type %T cannot be parsed as a severity value", v)
+	}
+	return nil
+}
+
+func addSeverityRange(severities map[string]plog.SeverityNumber, sev plog.SeverityNumber, lo, hi int64) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for i := lo; i <= hi; i++ {
+		severities[strconv.FormatInt(i, 10)] = sev
+	}
+}
+
+func httpStatusRange(v string) (int64, int64, bool) {
+	switch v {
+	case "2xx":
+		return 200, 299, true
+	case "3xx":
+		return 300, 399, true
+	case "4xx":
+		return 400, 499, true
+	case "5xx":
+		return 500, 599, true
+	}
+	return 0, 0, false
+}
+
+func wholeNumber(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), n == float64(int64(n))
+	}
+	return 0, false
+}
+
+// severityKey returns the key under which the value is looked up in the mapping.
+func severityKey(val any) (string, error) {
+	switch v := val.(type) {
+	case pcommon.Value:
+		return severityKey(v.AsRaw())
+	case string:
+		return strings.ToLower(v), nil
+	case []byte:
+		return strings.ToLower(string(v)), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return "", fmt.Errorf("%v cannot be a severity unless it is a whole number", v)
+		}
+		return strconv.FormatInt(int64(v), 10), nil
+	default:
+		return "", fmt.Errorf("type %T cannot be a severity", v)
+	}
+}
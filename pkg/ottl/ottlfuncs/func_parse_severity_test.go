@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseSeverity(t *testing.T) {
+	mapping := map[string]any{
+		"error": []any{"e", "oops", "5xx"},
+		"warn":  []any{"w", map[string]any{"min": int64(400), "max": int64(404)}},
+		"info2": "i2",
+		"debug": int64(100),
+		"fatal": map[string]any{"min": float64(1000), "max": float64(1001)},
+	}
+
+	tests := []struct {
+		name     string
+		target   any
+		mapping  map[string]any
+		expected plog.SeverityNumber
+	}{
+		{
+			name:     "level name",
+			target:   "INFO",
+			expected: plog.SeverityNumberInfo,
+		},
+		{
+			name:     "level number",
+			target:   int64(10),
+			expected: plog.SeverityNumberInfo2,
+		},
+		{
+			name:     "alias",
+			target:   "Warning",
+			expected: plog.SeverityNumberWarn,
+		},
+		{
+			name:     "mapped string",
+			target:   "OOPS",
+			mapping:  mapping,
+			expected: plog.SeverityNumberError,
+		},
+		{
+			name:     "mapped single value",
+			target:   "i2",
+			mapping:  mapping,
+			expected: plog.SeverityNumberInfo2,
+		},
+		{
+			name:     "mapped int",
+			target:   int64(100),
+			mapping:  mapping,
+			expected: plog.SeverityNumberDebug,
+		},
+		{
+			name:     "mapped whole double",
+			target:   float64(100),
+			mapping:  mapping,
+			expected: plog.SeverityNumberDebug,
+		},
+		{
+			name:     "http status range",
+			target:   int64(503),
+			mapping:  mapping,
+			expected: plog.SeverityNumberError,
+		},
+		{
+			name:     "custom range",
+			target:   "403",
+			mapping:  mapping,
+			expected: plog.SeverityNumberWarn,
+		},
+		{
+			name:     "custom range of doubles",
+			target:   pcommon.NewValueInt(1001),
+			mapping:  mapping,
+			expected: plog.SeverityNumberFatal,
+		},
+		{
+			name:     "not found",
+			target:   "unknown",
+			mapping:  mapping,
+			expected: plog.SeverityNumberUnspecified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			m := pcommon.NewMap()
+			require.NoError(t, m.FromRaw(tt.mapping))
+			mappingGetter := &ottl.StandardPMapGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return m, nil
+				},
+			}
+
+			result, err := parseSeverity[any](target, mappingGetter)(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, int64(tt.expected), result)
+		})
+	}
+}
+
+func Test_parseSeverity_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  any
+		mapping map[string]any
+	}{
+		{
+			name:    "invalid level",
+			target:  "info",
+			mapping: map[string]any{"critical": "crit"},
+		},
+		{
+			name:    "invalid value",
+			target:  "info",
+			mapping: map[string]any{"error": true},
+		},
+		{
+			name:    "invalid range",
+			target:  "info",
+			mapping: map[string]any{"error": map[string]any{"min": "a", "max": int64(2)}},
+		},
+		{
+			name:   "fractional target",
+			target: 1.5,
+		},
+		{
+			name:   "unsupported target",
+			target: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			m := pcommon.NewMap()
+			require.NoError(t, m.FromRaw(tt.mapping))
+			mappingGetter := &ottl.StandardPMapGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return m, nil
+				},
+			}
+
+			_, err := parseSeverity[any](target, mappingGetter)(context.Background(), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_parseSeverity_mappingChanges(t *testing.T) {
+	target := &ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "oops", nil
+		},
+	}
+	m := pcommon.NewMap()
+	m.PutStr("error", "oops")
+	mappingGetter := &ottl.StandardPMapGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return m, nil
+		},
+	}
+	exprFunc := parseSeverity[any](target, mappingGetter)
+
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(plog.SeverityNumberError), result)
+
+	// the mapping is built again once its content changes
+	m.PutStr("error", "e")
+	m.PutStr("fatal", "oops")
+	result, err = exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(plog.SeverityNumberFatal), result)
+}
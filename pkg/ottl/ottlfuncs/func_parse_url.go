@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ParseURLArguments[K any] struct {
+	Target ottl.StringGetter[K]
+}
+
+func NewParseURLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ParseURL", &ParseURLArguments[K]{}, createParseURLFunction[K])
+}
+
+func createParseURLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ParseURLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ParseURLFactory args must be of type *ParseURLArguments[K]")
+	}
+
+	return parseURL(args.Target), nil
+}
+
+func parseURL[K any](target ottl.StringGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if val == "" {
+			return nil, errors.New("url must not be empty")
+		}
+
+		parsed, err := parseutils.ParseURI(val, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse url: %w", err)
+		}
+
+		result := pcommon.NewMap()
+		if err = result.FromRaw(parsed); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+type SortArguments[K any] struct {
+	Target ottl.Getter[K]
+	Order  ottl.Optional[string]
+}
+
+func NewSortFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Sort", &SortArguments[K]{}, createSortFunction[K])
+}
+
+func createSortFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*SortArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("SortFactory args must be of type *SortArguments[K]")
+	}
+
+	order := sortAscending
+	if !args.Order.IsEmpty() {
+		order = args.Order.Get()
+	}
+
+	return sortSlice(args.Target, order)
+}
+
+func sortSlice[K any](target ottl.Getter[K], order string) (ottl.ExprFunc[K], error) {
+	if order != sortAscending && order != sortDescending {
+		return nil, fmt.Errorf("invalid sort order %q, must be either %q or %q", order, sortAscending, sortDescending)
+	}
+	desc := order == sortDescending
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		elems, err := toRawSlice(val)
+		if err != nil {
+			return nil, err
+		}
+
+		return sortTyped(elems, desc), nil
+	}, nil
+}
+
+// toRawSlice returns the elements of a list value as raw values.
+func toRawSlice(val any) ([]any, error) {
+	switch v := val.(type) {
+	case pcommon.Slice:
+		return v.AsRaw(), nil
+	case pcommon.Value:
+		if v.Type() == pcommon.ValueTypeSlice {
+			return v.Slice().AsRaw(), nil
+		}
+	case []any:
+		return v, nil
+	case []string:
+		return toAnySlice(v), nil
+	case []int64:
+		return toAnySlice(v), nil
+	case []float64:
+		return toAnySlice(v), nil
+	case []bool:
+		return toAnySlice(v), nil
+	}
+	return nil, fmt.Errorf("expected a list but got %T", val)
+}
+
+func toAnySlice[T any](s []T) []any {
+	res := make([]any, len(s))
+	for i, v := range s {
+		res[i] = v
+	}
+	return res
+}
+
+// sortTyped sorts the elements according to their type, returning a slice of that type.
+// Integers and doubles are compared as numbers, and are returned as doubles when mixed.
+// Elements of mixed types are otherwise ordered by their string representation,
+// keeping their original types.
+func sortTyped(elems []any, desc bool) any {
+	var strs []string
+	var ints []int64
+	var floats []float64
+	var bools []bool
+	numeric, mixed := true, false
+	for _, e := range elems {
+		switch v := e.(type) {
+		case string:
+			strs = append(strs, v)
+			numeric = false
+		case int64:
+			ints = append(ints, v)
+			floats = append(floats, float64(v))
+		case float64:
+			floats = append(floats, v)
+		case bool:
+			bools = append(bools, v)
+			numeric = false
+		default:
+			mixed = true
+		}
+	}
+
+	switch {
+	case len(elems) == 0:
+		return []any{}
+	case mixed:
+	case len(strs) == len(elems):
+		return sortOrdered(strs, func(a, b string) bool { return a < b }, desc)
+	case len(ints) == len(elems):
+		return sortOrdered(ints, func(a, b int64) bool { return a < b }, desc)
+	case numeric && len(floats) == len(elems):
+		return sortOrdered(floats, func(a, b float64) bool { return a < b }, desc)
+	case len(bools) == len(elems):
+		return sortOrdered(bools, func(a, b bool) bool { return !a && b }, desc)
+	}
+
+	res := make([]any, len(elems))
+	copy(res, elems)
+	return sortOrdered(res, func(a, b any) bool { return fmt.Sprint(a) < fmt.Sprint(b) }, desc)
+}
+
+func sortOrdered[T any](s []T, less func(a, b T) bool, desc bool) []T {
+	sort.SliceStable(s, func(i, j int) bool {
+		if desc {
+			return less(s[j], s[i])
+		}
+		return less(s[i], s[j])
+	})
+	return s
+}
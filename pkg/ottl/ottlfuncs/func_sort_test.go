@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_Sort(t *testing.T) {
+	pSlice := pcommon.NewSlice()
+	pSlice.AppendEmpty().SetInt(3)
+	pSlice.AppendEmpty().SetDouble(1.5)
+	pSlice.AppendEmpty().SetInt(2)
+
+	tests := []struct {
+		name     string
+		target   any
+		order    string
+		expected any
+	}{
+		{
+			name:     "strings",
+			target:   []any{"b", "c", "a"},
+			order:    sortAscending,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "strings descending",
+			target:   []string{"b", "c", "a"},
+			order:    sortDescending,
+			expected: []string{"c", "b", "a"},
+		},
+		{
+			name:     "integers",
+			target:   []any{int64(3), int64(-1), int64(2)},
+			order:    sortAscending,
+			expected: []int64{-1, 2, 3},
+		},
+		{
+			name:     "mixed numbers",
+			target:   pSlice,
+			order:    sortAscending,
+			expected: []float64{1.5, 2, 3},
+		},
+		{
+			name:     "booleans",
+			target:   []bool{true, false, true},
+			order:    sortAscending,
+			expected: []bool{false, true, true},
+		},
+		{
+			name: "slice value",
+			target: func() pcommon.Value {
+				v := pcommon.NewValueSlice()
+				v.Slice().AppendEmpty().SetStr("y")
+				v.Slice().AppendEmpty().SetStr("x")
+				return v
+			}(),
+			order:    sortAscending,
+			expected: []string{"x", "y"},
+		},
+		{
+			name:     "mixed types",
+			target:   []any{"b", int64(1), true, "a"},
+			order:    sortAscending,
+			expected: []any{int64(1), "a", "b", true},
+		},
+		{
+			name:     "empty",
+			target:   []any{},
+			order:    sortAscending,
+			expected: []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := sortSlice[any](target, tt.order)
+			require.NoError(t, err)
+
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_Sort_does_not_modify_target(t *testing.T) {
+	input := []any{"b", "a"}
+	target := ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return input, nil
+		},
+	}
+	exprFunc, err := sortSlice[any](target, sortAscending)
+	require.NoError(t, err)
+
+	_, err = exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"b", "a"}, input)
+}
+
+func Test_Sort_invalid_order(t *testing.T) {
+	_, err := sortSlice[any](ottl.StandardGetSetter[any]{}, "random")
+	assert.Error(t, err)
+}
+
+func Test_Sort_invalid_target(t *testing.T) {
+	target := ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "not a list", nil
+		},
+	}
+	exprFunc, err := sortSlice[any](target, sortAscending)
+	require.NoError(t, err)
+
+	_, err = exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const (
+	userAgentOriginal = "user_agent.original"
+	userAgentName     = "user_agent.name"
+	userAgentVersion  = "user_agent.version"
+	osName            = "os.name"
+	osVersion         = "os.version"
+)
+
+type UserAgentArguments[K any] struct {
+	Target ottl.StringGetter[K]
+}
+
+func NewUserAgentFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("UserAgent", &UserAgentArguments[K]{}, createUserAgentFunction[K])
+}
+
+func createUserAgentFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*UserAgentArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("UserAgentFactory args must be of type *UserAgentArguments[K]")
+	}
+
+	return userAgent(args.Target), nil
+}
+
+func userAgent[K any](target ottl.StringGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		result := pcommon.NewMap()
+		result.PutStr(userAgentOriginal, val)
+
+		ua := tokenizeUserAgent(val)
+		if name, version := ua.agent(); name != "" {
+			result.PutStr(userAgentName, name)
+			if version != "" {
+				result.PutStr(userAgentVersion, version)
+			}
+		}
+		if name, version := ua.os(); name != "" {
+			result.PutStr(osName, name)
+			if version != "" {
+				result.PutStr(osVersion, version)
+			}
+		}
+		return result, nil
+	}
+}
+
+// userAgentProduct is a product token of a user agent, e.g. Firefox/123.0.
+type userAgentProduct struct {
+	name    string
+	version string
+}
+
+// parsedUserAgent holds the product tokens of a user agent and the entries of its comments,
+// e.g. the comment of "Mozilla/5.0 (X11; Linux x86_64)" holds the entries "X11" and "Linux x86_64".
+type parsedUserAgent struct {
+	products []userAgentProduct
+	comments []string
+}
+
+func tokenizeUserAgent(s string) parsedUserAgent {
+	var ua parsedUserAgent
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		if s[0] == '(' {
+			comment, rest, _ := strings.Cut(s[1:], ")")
+			for _, entry := range strings.Split(comment, ";") {
+				if entry = strings.TrimSpace(entry); entry != "" {
+					ua.comments = append(ua.comments, entry)
+				}
+			}
+			s = rest
+			continue
+		}
+
+		end := strings.IndexAny(s, " (")
+		if end < 0 {
+			end = len(s)
+		}
+		name, version, _ := strings.Cut(s[:end], "/")
+		ua.products = append(ua.products, userAgentProduct{name: name, version: version})
+		s = s[end:]
+	}
+	return ua
+}
+
+func (ua parsedUserAgent) product(names ...string) (userAgentProduct, bool) {
+	for _, p := range ua.products {
+		for _, name := range names {
+			if p.name == name {
+				return p, true
+			}
+		}
+	}
+	return userAgentProduct{}, false
+}
+
+// comment returns the remainder of the first comment entry starting with the prefix.
+func (ua parsedUserAgent) comment(prefix string) (string, bool) {
+	for _, c := range ua.comments {
+		if strings.HasPrefix(c, prefix) {
+			return strings.TrimSpace(c[len(prefix):]), true
+		}
+	}
+	return "", false
+}
+
+// userAgentBrowsers are the product tokens identifying a browser, in order of precedence since
+// browsers also announce the engines and browsers they are compatible with.
+var userAgentBrowsers = []struct {
+	name   string
+	tokens []string
+}{
+	{name: "Edge", tokens: []string{"Edg", "EdgA", "EdgiOS", "Edge"}},
+	{name: "Opera", tokens: []string{"OPR", "OPiOS", "Opera"}},
+	{name: "Samsung Internet", tokens: []string{"SamsungBrowser"}},
+	{name: "Firefox", tokens: []string{"Firefox", "FxiOS"}},
+	{name: "Chromium", tokens: []string{"Chromium"}},
+	{name: "Chrome", tokens: []string{"Chrome", "CriOS"}},
+}
+
+// agent returns the name and version of the browser, bot or tool which sent the user agent.
+func (ua parsedUserAgent) agent() (string, string) {
+	for _, browser := range userAgentBrowsers {
+		if p, ok := ua.product(browser.tokens...); ok {
+			return browser.name, p.version
+		}
+	}
+
+	if _, ok := ua.product("Safari"); ok {
+		if v, ok := ua.product("Version"); ok {
+			return "Safari", v.version
+		}
+	}
+
+	if v, ok := ua.comment("MSIE "); ok {
+		return "IE", v
+	}
+	if _, ok := ua.comment("Trident/"); ok {
+		if v, ok := ua.comment("rv:"); ok {
+			return "IE", v
+		}
+	}
+
+	// bots usually announce themselves within the comment, e.g. (compatible; Googlebot/2.1; +http://www.google.com/bot.html)
+	if _, ok := ua.comment("compatible"); ok {
+		for _, c := range ua.comments {
+			if name, version, found := strings.Cut(c, "/"); found && !strings.Contains(name, " ") {
+				return name, version
+			}
+		}
+	}
+
+	// tools such as curl or language HTTP clients only send their own product token
+	for _, p := range ua.products {
+		if p.name != "Mozilla" {
+			return p.name, p.version
+		}
+	}
+	return "", ""
+}
+
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.2":  "XP",
+	"5.1":  "XP",
+}
+
+// os returns the name and version of the operating system announced in the comments of the user agent.
+func (ua parsedUserAgent) os() (string, string) {
+	if v, ok := ua.comment("Windows NT "); ok {
+		if name, known := windowsVersions[v]; known {
+			return "Windows", name
+		}
+		return "Windows", v
+	}
+	if v, ok := ua.comment("Android"); ok {
+		return "Android", v
+	}
+	for _, c := range ua.comments {
+		// iPhones announce "CPU iPhone OS 17_4 like Mac OS X", iPads "CPU OS 17_4 like Mac OS X"
+		if strings.HasPrefix(c, "CPU ") && strings.Contains(c, " like Mac OS X") {
+			fields := strings.Fields(strings.TrimSuffix(c, " like Mac OS X"))
+			return "iOS", strings.ReplaceAll(fields[len(fields)-1], "_", ".")
+		}
+	}
+	for _, c := range ua.comments {
+		if _, v, ok := strings.Cut(c, "Mac OS X"); ok {
+			return "Mac OS X", strings.ReplaceAll(strings.TrimSpace(v), "_", ".")
+		}
+	}
+	if v, ok := ua.comment("CrOS "); ok {
+		// CrOS x86_64 14541.0.0
+		fields := strings.Fields(v)
+		return "Chrome OS", fields[len(fields)-1]
+	}
+	for _, c := range ua.comments {
+		if strings.HasPrefix(c, "Linux") || strings.HasPrefix(c, "Ubuntu") {
+			return "Linux", ""
+		}
+	}
+	return "", ""
+}
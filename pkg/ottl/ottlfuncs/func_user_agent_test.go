@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_userAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]any
+	}{
+		{
+			name:   "chrome on windows",
+			target: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+			expected: map[string]any{
+				"user_agent.name":    "Chrome",
+				"user_agent.version": "123.0.0.0",
+				"os.name":            "Windows",
+				"os.version":         "10",
+			},
+		},
+		{
+			name:   "edge on windows",
+			target: "Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.78",
+			expected: map[string]any{
+				"user_agent.name":    "Edge",
+				"user_agent.version": "109.0.1518.78",
+				"os.name":            "Windows",
+				"os.version":         "7",
+			},
+		},
+		{
+			name:   "firefox on linux",
+			target: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:124.0) Gecko/20100101 Firefox/124.0",
+			expected: map[string]any{
+				"user_agent.name":    "Firefox",
+				"user_agent.version": "124.0",
+				"os.name":            "Linux",
+			},
+		},
+		{
+			name:   "safari on mac",
+			target: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			expected: map[string]any{
+				"user_agent.name":    "Safari",
+				"user_agent.version": "17.4",
+				"os.name":            "Mac OS X",
+				"os.version":         "10.15.7",
+			},
+		},
+		{
+			name:   "safari on iphone",
+			target: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			expected: map[string]any{
+				"user_agent.name":    "Safari",
+				"user_agent.version": "17.4",
+				"os.name":            "iOS",
+				"os.version":         "17.4",
+			},
+		},
+		{
+			name:   "samsung internet on android",
+			target: "Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			expected: map[string]any{
+				"user_agent.name":    "Samsung Internet",
+				"user_agent.version": "24.0",
+				"os.name":            "Android",
+				"os.version":         "14",
+			},
+		},
+		{
+			name:   "internet explorer",
+			target: "Mozilla/5.0 (Windows NT 6.3; Trident/7.0; rv:11.0) like Gecko",
+			expected: map[string]any{
+				"user_agent.name":    "IE",
+				"user_agent.version": "11.0",
+				"os.name":            "Windows",
+				"os.version":         "8.1",
+			},
+		},
+		{
+			name:   "bot",
+			target: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: map[string]any{
+				"user_agent.name":    "Googlebot",
+				"user_agent.version": "2.1",
+			},
+		},
+		{
+			name:   "curl",
+			target: "curl/8.4.0",
+			expected: map[string]any{
+				"user_agent.name":    "curl",
+				"user_agent.version": "8.4.0",
+			},
+		},
+		{
+			name:     "unknown",
+			target:   "",
+			expected: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+
+			result, err := userAgent[any](target)(context.Background(), nil)
+			require.NoError(t, err)
+
+			tt.expected["user_agent.original"] = tt.target
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_userAgent_bad_input(t *testing.T) {
+	target := &ottl.StandardStringGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return 1, nil
+		},
+	}
+
+	result, err := userAgent[any](target)(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
@@ -10,6 +10,7 @@ import (
 func StandardFuncs[K any]() map[string]ottl.Factory[K] {
 	f := []ottl.Factory[K]{
 		// Editors
+		NewAppendFactory[K](),
 		NewDeleteKeyFactory[K](),
 		NewDeleteMatchingKeysFactory[K](),
 		NewFlattenFactory[K](),
@@ -40,10 +41,12 @@ func converters[K any]() []ottl.Factory[K] {
 		NewConvertCaseFactory[K](),
 		NewDoubleFactory[K](),
 		NewDurationFactory[K](),
+		NewExtractGrokPatternsFactory[K](),
 		NewExtractPatternsFactory[K](),
 		NewFnvFactory[K](),
 		NewHourFactory[K](),
 		NewHoursFactory[K](),
+		NewIndexFactory[K](),
 		NewIntFactory[K](),
 		NewIsBoolFactory[K](),
 		NewIsDoubleFactory[K](),
@@ -61,10 +64,13 @@ func converters[K any]() []ottl.Factory[K] {
 		NewParseCSVFactory[K](),
 		NewParseJSONFactory[K](),
 		NewParseKeyValueFactory[K](),
+		NewParseSeverityFactory[K](),
+		NewParseURLFactory[K](),
 		NewParseXMLFactory[K](),
 		NewSecondsFactory[K](),
 		NewSHA1Factory[K](),
 		NewSHA256Factory[K](),
+		NewSortFactory[K](),
 		NewSpanIDFactory[K](),
 		NewSplitFactory[K](),
 		NewSubstringFactory[K](),
@@ -75,6 +81,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewUnixMilliFactory[K](),
 		NewUnixNanoFactory[K](),
 		NewUnixSecondsFactory[K](),
+		NewUserAgentFactory[K](),
 		NewUUIDFactory[K](),
 	}
 }
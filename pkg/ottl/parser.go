@@ -51,6 +51,17 @@ func (c *Condition[K]) Eval(ctx context.Context, tCtx K) (bool, error) {
 	return c.condition.Eval(ctx, tCtx)
 }
 
+// ValueExpression holds a top level value expression, such as a path, a literal, a converter invocation or a
+// math expression. A ValueExpression allows components to extract a value from the telemetry data with OTTL.
+type ValueExpression[K any] struct {
+	getter Getter[K]
+}
+
+// Eval returns the value the expression resolves to for the given TransformContext.
+func (e *ValueExpression[K]) Eval(ctx context.Context, tCtx K) (any, error) {
+	return e.getter.Get(ctx, tCtx)
+}
+
 // Parser provides the means to parse OTTL StatementSequence and Conditions given a specific set of functions,
 // a PathExpressionParser, and an EnumParser.
 type Parser[K any] struct {
@@ -178,8 +189,26 @@ func (p *Parser[K]) ParseCondition(condition string) (*Condition[K], error) {
 	}, nil
 }
 
+// ParseValueExpression parses a single string value expression into a ValueExpression object ready for evaluation.
+// Returns a ValueExpression and a nil error on successful parsing.
+// If parsing fails, returns nil and an error.
+func (p *Parser[K]) ParseValueExpression(expression string) (*ValueExpression[K], error) {
+	parsed, err := parseValueExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	getter, err := p.newGetter(*parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &ValueExpression[K]{
+		getter: getter,
+	}, nil
+}
+
 var parser = newParser[parsedStatement]()
 var conditionParser = newParser[booleanExpression]()
+var valueExpressionParser = newParser[value]()
 
 func parseStatement(raw string) (*parsedStatement, error) {
 	parsed, err := parser.ParseString("", raw)
@@ -209,6 +238,20 @@ func parseCondition(raw string) (*booleanExpression, error) {
 	return parsed, nil
 }
 
+func parseValueExpression(raw string) (*value, error) {
+	parsed, err := valueExpressionParser.ParseString("", raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("value expression has invalid syntax: %w", err)
+	}
+	err = parsed.checkForCustomError()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
 // newParser returns a parser that can be used to read a string into a parsedStatement. An error will be returned if the string
 // is not formatted for the DSL.
 func newParser[G any]() *participle.Parser[G] {
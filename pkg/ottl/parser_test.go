@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
@@ -1967,6 +1968,44 @@ func Test_parseCondition(t *testing.T) {
 	}
 }
 
+func Test_ParseValueExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   any
+		wantErr    bool
+	}{
+		{expression: `name`, expected: "tCtx"},
+		{expression: `attributes["tenant"]`, expected: "tCtx"},
+		{expression: `"literal"`, expected: "literal"},
+		{expression: `1 + 2`, expected: int64(3)},
+		{expression: `nil`, expected: nil},
+		{expression: `set(`, wantErr: true},
+		{expression: `name == "fido"`, wantErr: true},
+		{expression: `unknown`, wantErr: true},
+	}
+	p, _ := NewParser(
+		CreateFactoryMap[any](),
+		testParsePath[any],
+		componenttest.NewNopTelemetrySettings(),
+		WithEnumParser[any](testParseEnum),
+	)
+	pat := regexp.MustCompile("[^a-zA-Z0-9]+")
+	for _, tt := range tests {
+		t.Run(pat.ReplaceAllString(tt.expression, "_"), func(t *testing.T) {
+			expression, err := p.ParseValueExpression(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := expression.Eval(context.Background(), "tCtx")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func Test_Statement_Execute(t *testing.T) {
 	tests := []struct {
 		name              string
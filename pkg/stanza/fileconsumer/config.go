@@ -80,6 +80,7 @@ type Config struct {
 	FlushPeriod        time.Duration   `mapstructure:"force_flush_period,omitempty"`
 	Header             *HeaderConfig   `mapstructure:"header,omitempty"`
 	DeleteAfterRead    bool            `mapstructure:"delete_after_read,omitempty"`
+	Compression        string          `mapstructure:"compression,omitempty"`
 }
 
 type HeaderConfig struct {
@@ -160,6 +161,7 @@ func (c Config) Build(logger *zap.SugaredLogger, emit emit.Callback, opts ...Opt
 		Attributes:        c.Resolver,
 		HeaderConfig:      hCfg,
 		DeleteAtEOF:       c.DeleteAfterRead,
+		Compression:       c.Compression,
 	}
 	knownFiles := make([]*fileset.Fileset[*reader.Metadata], 3)
 	for i := 0; i < len(knownFiles); i++ {
@@ -204,6 +206,13 @@ func (c Config) validate() error {
 		return err
 	}
 
+	switch c.Compression {
+	case reader.CompressionNone, reader.CompressionGzip, reader.CompressionZstd, reader.CompressionAuto:
+	default:
+		return fmt.Errorf("invalid 'compression' value %q, must be one of %q, %q or %q",
+			c.Compression, reader.CompressionGzip, reader.CompressionZstd, reader.CompressionAuto)
+	}
+
 	if c.DeleteAfterRead {
 		if !allowFileDeletion.IsEnabled() {
 			return fmt.Errorf("'delete_after_read' requires feature gate '%s'", allowFileDeletion.ID())
@@ -400,6 +400,14 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "compression_gzip",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Compression = "gzip"
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 			{
 				Name: "header_config",
 				Expect: func() *mockOperatorConfig {
@@ -562,6 +570,24 @@ func TestBuild(t *testing.T) {
 				require.Equal(t, 6, m.maxBatches)
 			},
 		},
+		{
+			"InvalidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "lz4"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"ValidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "auto"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "auto", m.readerFactory.Compression)
+			},
+		},
 		{
 			"HeaderConfigNoFlag",
 			func(cfg *Config) {
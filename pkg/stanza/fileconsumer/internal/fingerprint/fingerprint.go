@@ -35,6 +35,17 @@ func NewFromFile(file *os.File, size int) (*Fingerprint, error) {
 	return New(buf[:n]), nil
 }
 
+// NewFromReader creates a fingerprint from the first bytes read from r.
+// This is used to fingerprint the decompressed content of compressed files.
+func NewFromReader(r io.Reader, size int) (*Fingerprint, error) {
+	buf := make([]byte, size)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+	return New(buf[:n]), nil
+}
+
 // Copy creates a new copy of the fingerprint
 func (f Fingerprint) Copy() *Fingerprint {
 	buf := make([]byte, len(f.firstBytes), cap(f.firstBytes))
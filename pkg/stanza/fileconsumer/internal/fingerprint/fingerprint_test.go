@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -56,6 +57,17 @@ func TestNew(t *testing.T) {
 	require.Equal(t, []byte("hello"), fp.firstBytes)
 }
 
+func TestNewFromReader(t *testing.T) {
+	fp, err := NewFromReader(strings.NewReader("hello world"), 5)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), fp.firstBytes)
+
+	// shorter content is not an error
+	fp, err = NewFromReader(strings.NewReader("hi"), 5)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hi"), fp.firstBytes)
+}
+
 func TestNewFromFile(t *testing.T) {
 	cases := []struct {
 		name            string
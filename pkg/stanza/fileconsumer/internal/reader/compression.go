@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+)
+
+// Supported compression of the files. The fingerprint and the offset of a compressed
+// file are both defined on its decompressed content, so that a file that has been
+// compressed by the log rotation is recognized as the file it was before.
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	// CompressionAuto detects the compression of each file from its magic bytes,
+	// reading the files that aren't recognized as uncompressed.
+	CompressionAuto = "auto"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// detectCompression returns the compression of the file. When auto-detection is
+// enabled, it is found by looking at the first bytes of the file.
+func detectCompression(file *os.File, compression string) string {
+	if compression != CompressionAuto {
+		return compression
+	}
+
+	buf := make([]byte, len(zstdMagic))
+	n, _ := file.ReadAt(buf, 0)
+	switch {
+	case bytes.HasPrefix(buf[:n], gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(buf[:n], zstdMagic):
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// newDecompressor returns a reader of the decompressed content of the file, starting from
+// its beginning. It reads the file independently of its current offset, which is left untouched.
+func newDecompressor(file io.ReaderAt, compression string) (io.ReadCloser, error) {
+	src := io.NewSectionReader(file, 0, math.MaxInt64)
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(src)
+	case CompressionZstd:
+		dec, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+// newFingerprint creates the fingerprint of the file, based on its decompressed content if compressed.
+func newFingerprint(file *os.File, size int, compression string) (*fingerprint.Fingerprint, error) {
+	if compression == CompressionNone {
+		return fingerprint.NewFromFile(file, size)
+	}
+
+	dr, err := newDecompressor(file, compression)
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+	defer dr.Close()
+	return fingerprint.NewFromReader(dr, size)
+}
+
+// decompressedSize returns the size of the decompressed content of the file.
+func decompressedSize(file *os.File, compression string) (int64, error) {
+	dr, err := newDecompressor(file, compression)
+	if err != nil {
+		return 0, err
+	}
+	defer dr.Close()
+	return io.Copy(io.Discard, truncatedAsEOF{dr})
+}
+
+// fileReaderAt reads from the current handle of a file. A new handle of the file is opened
+// on each poll, this allows the decompression of the file to carry on with the new handle.
+type fileReaderAt struct {
+	file *os.File
+}
+
+func (f *fileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return f.file.ReadAt(p, off)
+}
+
+// decompressionState streams the decompressed content of a file across polls, so that
+// only the content added since the previous poll is decompressed.
+type decompressionState struct {
+	compression string
+	src         *fileReaderAt
+	dec         io.ReadCloser
+	// pos is the offset in the decompressed content up to which dec has been read
+	pos int64
+	// pending holds the content read from dec right before pos, which hasn't been
+	// consumed by the reader yet and is read again when resuming from its offset
+	pending []byte
+	// replay is the position in pending of the next byte to read
+	replay int
+	// end is the compressed size of the file when dec reached the end of the content,
+	// or -1 if more content can be read from dec
+	end int64
+}
+
+func newDecompressionState(compression string) *decompressionState {
+	return &decompressionState{compression: compression, src: &fileReaderAt{}, end: -1}
+}
+
+// resume positions the decompressed content at the given offset of the file. The decompression
+// only restarts from the beginning of the file when it can't carry on from its current position:
+// when the offset was already decompressed and isn't pending, or when the compressed file changed
+// after the end of its content had been reached. Compressed streams can't be read past their end
+// once reached, even when the file has been appended to since then.
+func (d *decompressionState) resume(file *os.File, offset int64) error {
+	d.src.file = file
+	restart := d.dec == nil || offset < d.pos-int64(len(d.pending))
+	if !restart && d.end >= 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		restart = info.Size() != d.end
+	}
+	if restart {
+		d.close()
+		dec, err := newDecompressor(d.src, d.compression)
+		if err != nil {
+			return err
+		}
+		d.dec, d.pos, d.pending, d.end = dec, 0, d.pending[:0], -1
+	}
+
+	if offset > d.pos {
+		// Skip the content up to the offset, which may be past the end of the content
+		// when the file has been replaced by a shorter one, in which case there is
+		// nothing to read until it changes again.
+		d.pending = d.pending[:0]
+		n, err := io.CopyN(io.Discard, truncatedAsEOF{d.dec}, offset-d.pos)
+		d.pos += n
+		if err != nil {
+			return d.endOfContent(err)
+		}
+	}
+	// the pending content now starts at the offset, or is empty if the offset is past the end
+	d.trim(offset)
+	d.replay = 0
+	return nil
+}
+
+// trim drops the pending content before the given offset, which has been consumed already.
+func (d *decompressionState) trim(offset int64) {
+	drop := min(len(d.pending)-int(d.pos-offset), len(d.pending))
+	if drop <= 0 {
+		return
+	}
+	n := copy(d.pending, d.pending[drop:])
+	d.pending = d.pending[:n]
+	d.replay = max(d.replay-drop, 0)
+}
+
+// Read reads the pending content first, then the content decompressed from the file.
+func (d *decompressionState) Read(p []byte) (int, error) {
+	if d.replay < len(d.pending) {
+		n := copy(p, d.pending[d.replay:])
+		d.replay += n
+		return n, nil
+	}
+	if d.end >= 0 {
+		return 0, io.EOF
+	}
+
+	n, err := truncatedAsEOF{d.dec}.Read(p)
+	d.pos += int64(n)
+	d.pending = append(d.pending, p[:n]...)
+	d.replay = len(d.pending)
+	if err != nil {
+		err = d.endOfContent(err)
+		if err == nil {
+			err = io.EOF
+		}
+	}
+	return n, err
+}
+
+// endOfContent records the end of the content when the error is io.EOF, which is then
+// swallowed. Other errors are returned, and the decompression restarts on the next resume.
+func (d *decompressionState) endOfContent(err error) error {
+	if !errors.Is(err, io.EOF) {
+		d.close()
+		return err
+	}
+	info, statErr := d.src.file.Stat()
+	if statErr != nil {
+		d.close()
+		return statErr
+	}
+	d.end = info.Size()
+	return nil
+}
+
+func (d *decompressionState) close() {
+	if d.dec != nil {
+		_ = d.dec.Close()
+		d.dec = nil
+	}
+}
+
+// truncatedAsEOF treats a compressed stream ending prematurely as the end of the content,
+// as it is typically the case of a file which is still being written.
+type truncatedAsEOF struct {
+	io.Reader
+}
+
+func (t truncatedAsEOF) Read(p []byte) (int, error) {
+	n, err := t.Reader.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/filetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+)
+
+func gzipContent(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdContent(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func writeBytes(t *testing.T, file *os.File, b []byte) {
+	_, err := file.Write(b)
+	require.NoError(t, err)
+}
+
+func TestDetectCompression(t *testing.T) {
+	tempDir := t.TempDir()
+
+	plain := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, plain, "testlog1\n")
+	gz := filetest.OpenTemp(t, tempDir)
+	writeBytes(t, gz, gzipContent(t, "testlog1\n"))
+	zst := filetest.OpenTemp(t, tempDir)
+	writeBytes(t, zst, zstdContent(t, "testlog1\n"))
+	empty := filetest.OpenTemp(t, tempDir)
+
+	assert.Equal(t, CompressionNone, detectCompression(plain, CompressionAuto))
+	assert.Equal(t, CompressionGzip, detectCompression(gz, CompressionAuto))
+	assert.Equal(t, CompressionZstd, detectCompression(zst, CompressionAuto))
+	assert.Equal(t, CompressionNone, detectCompression(empty, CompressionAuto))
+
+	// an explicit compression is never overridden
+	assert.Equal(t, CompressionGzip, detectCompression(plain, CompressionGzip))
+}
+
+func TestReadCompressed(t *testing.T) {
+	t.Parallel()
+
+	content := "testlog1\ntestlog2\n"
+	testCases := []struct {
+		name        string
+		compression string
+		encoded     func(*testing.T, string) []byte
+	}{
+		{"gzip", CompressionGzip, gzipContent},
+		{"zstd", CompressionZstd, zstdContent},
+		{"auto_gzip", CompressionAuto, gzipContent},
+		{"auto_zstd", CompressionAuto, zstdContent},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			temp := filetest.OpenTemp(t, t.TempDir())
+			writeBytes(t, temp, tc.encoded(t, content))
+
+			f, sink := testFactory(t, withCompression(tc.compression))
+			fp, err := f.NewFingerprint(temp)
+			require.NoError(t, err)
+
+			// the fingerprint is that of the decompressed content
+			assert.True(t, fp.Equal(fingerprint.New([]byte(content))))
+
+			r, err := f.NewReader(temp, fp)
+			require.NoError(t, err)
+			defer r.Close()
+
+			r.ReadToEnd(context.Background())
+			sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+			assert.Equal(t, int64(len(content)), r.Offset)
+		})
+	}
+}
+
+func TestReadCompressedResumesFromOffset(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	writeBytes(t, temp, gzipContent(t, "testlog1\n"))
+
+	f, sink := testFactory(t, withCompression(CompressionGzip))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+
+	r.ReadToEnd(context.Background())
+	sink.ExpectToken(t, []byte("testlog1"))
+	m := r.Close()
+
+	// the file is replaced by a longer archive sharing the same beginning
+	require.NoError(t, os.WriteFile(temp.Name(), gzipContent(t, "testlog1\ntestlog2\n"), 0600))
+
+	reopened := filetest.OpenFile(t, temp.Name())
+	r, err = f.NewReaderFromMetadata(reopened, m)
+	require.NoError(t, err)
+	defer r.Close()
+
+	r.ReadToEnd(context.Background())
+	sink.ExpectToken(t, []byte("testlog2"))
+	sink.ExpectNoCalls(t)
+}
+
+func TestReadCompressedAcrossPolls(t *testing.T) {
+	t.Parallel()
+
+	// the archive is still being written, its first part ends in the middle of a line
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte("testlog1\ntestl"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	written := buf.Len()
+	_, err = w.Write([]byte("og2\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	temp := filetest.OpenTemp(t, t.TempDir())
+	writeBytes(t, temp, buf.Bytes()[:written])
+
+	f, sink := testFactory(t, withCompression(CompressionGzip))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+
+	r.ReadToEnd(context.Background())
+	sink.ExpectToken(t, []byte("testlog1"))
+	assert.Equal(t, int64(len("testlog1\n")), r.Offset)
+	m := r.Close()
+	dec := m.decompression.dec
+
+	// the next poll carries on with the same decompression while the file is unchanged
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	r.ReadToEnd(context.Background())
+	sink.ExpectNoCalls(t)
+	m = r.Close()
+	assert.Same(t, dec, m.decompression.dec)
+
+	writeBytes(t, temp, buf.Bytes()[written:])
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	defer r.Close()
+	r.ReadToEnd(context.Background())
+	sink.ExpectToken(t, []byte("testlog2"))
+	sink.ExpectNoCalls(t)
+	assert.Equal(t, int64(len("testlog1\ntestlog2\n")), r.Offset)
+}
+
+func TestReadCompressedOffsetPastEnd(t *testing.T) {
+	t.Parallel()
+
+	temp := filetest.OpenTemp(t, t.TempDir())
+	writeBytes(t, temp, gzipContent(t, "testlog1\n"))
+
+	f, sink := testFactory(t, withCompression(CompressionGzip))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReaderFromMetadata(temp, &Metadata{Fingerprint: fp, Offset: 100, FileAttributes: map[string]any{}})
+	require.NoError(t, err)
+	defer r.Close()
+
+	// the offset is past the end of the content, there is nothing to read until the file changes
+	require.NoError(t, r.seek())
+	r.ReadToEnd(context.Background())
+	sink.ExpectNoCalls(t)
+	assert.Equal(t, int64(100), r.Offset)
+}
+
+func TestCompressedFromEnd(t *testing.T) {
+	t.Parallel()
+
+	content := "testlog1\ntestlog2\n"
+	temp := filetest.OpenTemp(t, t.TempDir())
+	writeBytes(t, temp, zstdContent(t, content))
+
+	f, sink := testFactory(t, withCompression(CompressionZstd), fromEnd())
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+	defer r.Close()
+
+	// the offset is measured on the decompressed content
+	assert.Equal(t, int64(len(content)), r.Offset)
+	r.ReadToEnd(context.Background())
+	sink.ExpectNoCalls(t)
+}
+
+func TestCompressedMatchesUncompressedFingerprint(t *testing.T) {
+	t.Parallel()
+
+	content := "testlog1\ntestlog2\n"
+	tempDir := t.TempDir()
+	plain := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, plain, content)
+	gz := filetest.OpenTemp(t, tempDir)
+	writeBytes(t, gz, gzipContent(t, content))
+
+	f, _ := testFactory(t, withCompression(CompressionAuto))
+	plainFP, err := f.NewFingerprint(plain)
+	require.NoError(t, err)
+	gzFP, err := f.NewFingerprint(gz)
+	require.NoError(t, err)
+
+	// a file compressed by the rotation is recognized as the file it was
+	assert.True(t, gzFP.Equal(plainFP))
+}
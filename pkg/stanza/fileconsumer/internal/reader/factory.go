@@ -41,10 +41,11 @@ type Factory struct {
 	EmitFunc          emit.Callback
 	Attributes        attrs.Resolver
 	DeleteAtEOF       bool
+	Compression       string
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
-	return fingerprint.NewFromFile(file, f.FingerprintSize)
+	return newFingerprint(file, f.FingerprintSize, detectCompression(file, f.Compression))
 }
 
 func (f *Factory) NewReader(file *os.File, fp *fingerprint.Fingerprint) (*Reader, error) {
@@ -71,11 +72,13 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		decoder:           decode.New(f.Encoding),
 		lineSplitFunc:     f.SplitFunc,
 		deleteAtEOF:       f.DeleteAtEOF,
+		compression:       detectCompression(file, f.Compression),
 	}
+	r.src = file
 
 	if r.Fingerprint.Len() > r.fingerprintSize {
 		// User has reconfigured fingerprint_size
-		shorter, rereadErr := fingerprint.NewFromFile(file, r.fingerprintSize)
+		shorter, rereadErr := newFingerprint(file, r.fingerprintSize, r.compression)
 		if rereadErr != nil {
 			return nil, fmt.Errorf("reread fingerprint: %w", err)
 		}
@@ -86,11 +89,17 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 	}
 
 	if !f.FromBeginning {
-		var info os.FileInfo
-		if info, err = r.file.Stat(); err != nil {
-			return nil, fmt.Errorf("stat: %w", err)
+		if r.compression != CompressionNone {
+			if r.Offset, err = decompressedSize(file, r.compression); err != nil {
+				return nil, fmt.Errorf("decompress: %w", err)
+			}
+		} else {
+			var info os.FileInfo
+			if info, err = r.file.Stat(); err != nil {
+				return nil, fmt.Errorf("stat: %w", err)
+			}
+			r.Offset = info.Size()
 		}
-		r.Offset = info.Size()
 	}
 
 	flushFunc := m.FlushState.Func(f.SplitFunc, f.FlushTimeout)
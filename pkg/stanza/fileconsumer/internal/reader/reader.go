@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"io"
 	"os"
 
 	"go.uber.org/zap"
@@ -25,6 +26,10 @@ type Metadata struct {
 	FileAttributes  map[string]any
 	HeaderFinalized bool
 	FlushState      *flush.State
+
+	// decompression carries the decompression of a compressed file over to the
+	// reader of the next poll, it isn't part of the persisted checkpoint.
+	decompression *decompressionState
 }
 
 // Reader manages a single file
@@ -44,11 +49,15 @@ type Reader struct {
 	emitFunc               emit.Callback
 	deleteAtEOF            bool
 	needsUpdateFingerprint bool
+
+	// compression of the file, in which case src reads the decompressed content
+	compression string
+	src         io.Reader
 }
 
 // ReadToEnd will read until the end of the file
 func (r *Reader) ReadToEnd(ctx context.Context) {
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if err := r.seek(); err != nil {
 		r.logger.Errorw("Failed to seek", zap.Error(err))
 		return
 	}
@@ -112,7 +121,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		// Recreate the scanner with the normal split func.
 		// Do not use the updated offset from the old scanner, as the most recent token
 		// could be split differently with the new splitter.
-		if _, err = r.file.Seek(r.Offset, 0); err != nil {
+		if err = r.seek(); err != nil {
 			r.logger.Errorw("Failed to seek post-header", zap.Error(err))
 			return
 		}
@@ -120,9 +129,29 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 	}
 }
 
+// seek positions the reader at the current offset. As compressed content can't be seeked,
+// a compressed file resumes its decompression from where the previous poll left it.
+func (r *Reader) seek() error {
+	if r.compression == CompressionNone {
+		r.src = r.file
+		_, err := r.file.Seek(r.Offset, 0)
+		return err
+	}
+
+	if r.decompression == nil || r.decompression.compression != r.compression {
+		r.decompression = newDecompressionState(r.compression)
+	}
+	r.src = r.decompression
+	return r.decompression.resume(r.file, r.Offset)
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
+	if r.decompression != nil {
+		r.decompression.close()
+		r.decompression = nil
+	}
 	if err := os.Remove(r.fileName); err != nil {
 		r.logger.Errorf("could not delete %s", r.fileName)
 	}
@@ -153,7 +182,11 @@ func (r *Reader) close() {
 
 // Read from the file and update the fingerprint if necessary
 func (r *Reader) Read(dst []byte) (n int, err error) {
-	n, err = r.file.Read(dst)
+	if r.decompression != nil {
+		// the content before the offset won't be read again
+		r.decompression.trim(r.Offset)
+	}
+	n, err = r.src.Read(dst)
 	if n == 0 || err != nil {
 		return
 	}
@@ -173,7 +206,7 @@ func (r *Reader) Validate() bool {
 	if r.file == nil {
 		return false
 	}
-	refreshedFingerprint, err := fingerprint.NewFromFile(r.file, r.fingerprintSize)
+	refreshedFingerprint, err := newFingerprint(r.file, r.fingerprintSize, r.compression)
 	if err != nil {
 		return false
 	}
@@ -192,7 +225,7 @@ func (r *Reader) updateFingerprint() {
 	if r.file == nil {
 		return
 	}
-	refreshedFingerprint, err := fingerprint.NewFromFile(r.file, r.fingerprintSize)
+	refreshedFingerprint, err := newFingerprint(r.file, r.fingerprintSize, r.compression)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+// FromMetricsV2 converts pmetric.Metrics to Prometheus remote write 2.0 format. The labels of the series, and the
+// help and unit of their metadata, are references to the strings of the returned symbols table.
+// Unlike FromMetrics, the metadata of the metrics is sent with each series, and the start time of the cumulative
+// data points is sent as the created timestamp of the series instead of _created series.
+func FromMetricsV2(md pmetric.Metrics, settings Settings) (tsMap map[string]*writev2.TimeSeries, symbols writev2.SymbolsTable, errs error) {
+	c := &v2Converter{
+		tsMap:   make(map[string]*writev2.TimeSeries),
+		symbols: writev2.NewSymbolsTable(),
+		scratch: make(map[string]*prompb.TimeSeries),
+	}
+	// the created timestamps replace the _created series
+	settings.ExportCreatedMetric = false
+
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		resource := resourceMetrics.Resource()
+		scopeMetricsSlice := resourceMetrics.ScopeMetrics()
+		// keep track of the most recent timestamp in the ResourceMetrics for
+		// use with the "target" info metric
+		var mostRecentTimestamp pcommon.Timestamp
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			metricSlice := scopeMetricsSlice.At(j).Metrics()
+			for k := 0; k < metricSlice.Len(); k++ {
+				metric := metricSlice.At(k)
+				mostRecentTimestamp = maxTimestamp(mostRecentTimestamp, mostRecentTimestampInMetric(metric))
+
+				if !isValidAggregationTemporality(metric) {
+					errs = multierr.Append(errs, fmt.Errorf("invalid temporality and type combination for metric %q", metric.Name()))
+					continue
+				}
+
+				promName := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
+				metadata := writev2.Metadata{
+					Type:    writev2.Metadata_MetricType(otelMetricTypeToPromMetricType(metric)),
+					HelpRef: c.symbols.Symbolize(metric.Description()),
+					UnitRef: c.symbols.Symbolize(metric.Unit()),
+				}
+
+				// each data point is converted on its own, so that its series get its start time
+				//exhaustive:enforce
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dataPoints := metric.Gauge().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleGaugeNumberDataPoint(dataPoints.At(x), resource, metric, settings, c.scratch, promName)
+						c.flush(metadata, 0)
+					}
+				case pmetric.MetricTypeSum:
+					dataPoints := metric.Sum().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleSumNumberDataPoint(dataPoints.At(x), resource, metric, settings, c.scratch, promName)
+						c.flush(metadata, dataPoints.At(x).StartTimestamp())
+					}
+				case pmetric.MetricTypeHistogram:
+					dataPoints := metric.Histogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleHistogramDataPoint(dataPoints.At(x), resource, metric, settings, c.scratch, promName)
+						c.flush(metadata, dataPoints.At(x).StartTimestamp())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dataPoints := metric.ExponentialHistogram().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						errs = multierr.Append(
+							errs,
+							addSingleExponentialHistogramDataPoint(
+								promName,
+								dataPoints.At(x),
+								resource,
+								settings,
+								c.scratch,
+							),
+						)
+						c.flush(metadata, dataPoints.At(x).StartTimestamp())
+					}
+				case pmetric.MetricTypeSummary:
+					dataPoints := metric.Summary().DataPoints()
+					if dataPoints.Len() == 0 {
+						errs = multierr.Append(errs, fmt.Errorf("empty data points. %s is dropped", metric.Name()))
+					}
+					for x := 0; x < dataPoints.Len(); x++ {
+						addSingleSummaryDataPoint(dataPoints.At(x), resource, metric, settings, c.scratch, promName)
+						c.flush(metadata, dataPoints.At(x).StartTimestamp())
+					}
+				default:
+					errs = multierr.Append(errs, errors.New("unsupported metric type"))
+				}
+			}
+		}
+		addResourceTargetInfo(resource, settings, mostRecentTimestamp, c.scratch)
+		c.flush(writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE}, 0)
+	}
+
+	return c.tsMap, c.symbols, errs
+}
+
+// v2Converter converts the series built for remote write 1.0 to remote write 2.0 series.
+type v2Converter struct {
+	tsMap   map[string]*writev2.TimeSeries
+	symbols writev2.SymbolsTable
+	// scratch holds the series of the data point being converted
+	scratch map[string]*prompb.TimeSeries
+}
+
+// flush moves the series of scratch to tsMap, with the given metadata and start time.
+func (c *v2Converter) flush(metadata writev2.Metadata, startTimestamp pcommon.Timestamp) {
+	for sig, ts := range c.scratch {
+		v2, ok := c.tsMap[sig]
+		if !ok {
+			v2 = &writev2.TimeSeries{
+				LabelsRefs: c.symbolizeLabels(ts.Labels),
+				Metadata:   metadata,
+			}
+			c.tsMap[sig] = v2
+		}
+		if startTimestamp != 0 {
+			v2.CreatedTimestamp = convertTimeStamp(startTimestamp)
+		}
+		for _, sample := range ts.Samples {
+			v2.Samples = append(v2.Samples, writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+		}
+		for _, histogram := range ts.Histograms {
+			v2.Histograms = append(v2.Histograms, histogramToV2(histogram))
+		}
+		for _, exemplar := range ts.Exemplars {
+			v2.Exemplars = append(v2.Exemplars, writev2.Exemplar{
+				LabelsRefs: c.symbolizeLabels(exemplar.Labels),
+				Value:      exemplar.Value,
+				Timestamp:  exemplar.Timestamp,
+			})
+		}
+	}
+	clear(c.scratch)
+}
+
+func (c *v2Converter) symbolizeLabels(labels []prompb.Label) []uint32 {
+	refs := make([]uint32, 0, 2*len(labels))
+	for _, l := range labels {
+		refs = append(refs, c.symbols.Symbolize(l.Name), c.symbols.Symbolize(l.Value))
+	}
+	return refs
+}
+
+func histogramToV2(h prompb.Histogram) writev2.Histogram {
+	v2 := writev2.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  bucketSpansToV2(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  bucketSpansToV2(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      writev2.Histogram_ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	switch count := h.Count.(type) {
+	case *prompb.Histogram_CountInt:
+		v2.Count = &writev2.Histogram_CountInt{CountInt: count.CountInt}
+	case *prompb.Histogram_CountFloat:
+		v2.Count = &writev2.Histogram_CountFloat{CountFloat: count.CountFloat}
+	}
+	switch zeroCount := h.ZeroCount.(type) {
+	case *prompb.Histogram_ZeroCountInt:
+		v2.ZeroCount = &writev2.Histogram_ZeroCountInt{ZeroCountInt: zeroCount.ZeroCountInt}
+	case *prompb.Histogram_ZeroCountFloat:
+		v2.ZeroCount = &writev2.Histogram_ZeroCountFloat{ZeroCountFloat: zeroCount.ZeroCountFloat}
+	}
+	return v2
+}
+
+func bucketSpansToV2(spans []prompb.BucketSpan) []writev2.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	v2 := make([]writev2.BucketSpan, len(spans))
+	for i, span := range spans {
+		v2[i] = writev2.BucketSpan{Offset: span.Offset, Length: span.Length}
+	}
+	return v2
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestFromMetricsV2(t *testing.T) {
+	start := pcommon.NewTimestampFromTime(time.UnixMilli(1000))
+	ts := pcommon.NewTimestampFromTime(time.UnixMilli(2000))
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("host.name", "host-1")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests")
+	counter.SetDescription("Number of requests")
+	counter.SetUnit("1")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for _, method := range []string{"GET", "POST"} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(ts)
+		dp.SetIntValue(10)
+		dp.Attributes().PutStr("method", method)
+	}
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	gauge.SetUnit("Cel")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(21.5)
+
+	expHistogram := metrics.AppendEmpty()
+	expHistogram.SetName("latency")
+	eh := expHistogram.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	ehdp := eh.DataPoints().AppendEmpty()
+	ehdp.SetStartTimestamp(start)
+	ehdp.SetTimestamp(ts)
+	ehdp.SetCount(4)
+	ehdp.SetSum(10)
+	ehdp.SetScale(0)
+	ehdp.SetZeroCount(1)
+	ehdp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	tsMap, symbols, err := FromMetricsV2(md, Settings{ExportCreatedMetric: true})
+	require.NoError(t, err)
+	// two counter series, one gauge series, one native histogram series and target_info
+	require.Len(t, tsMap, 5)
+
+	series := map[string]*writev2.TimeSeries{}
+	for _, s := range tsMap {
+		labels := desymbolizeLabels(t, symbols.Symbols(), s.LabelsRefs)
+		series[labels["__name__"]+"/"+labels["method"]] = s
+		assert.Equal(t, "api", labels["job"])
+	}
+
+	get := series["requests/GET"]
+	require.NotNil(t, get)
+	assert.Equal(t, []writev2.Sample{{Value: 10, Timestamp: 2000}}, get.Samples)
+	assert.Equal(t, int64(1000), get.CreatedTimestamp)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_COUNTER, get.Metadata.Type)
+	assert.Equal(t, "Number of requests", symbols.Symbols()[get.Metadata.HelpRef])
+	assert.Equal(t, "1", symbols.Symbols()[get.Metadata.UnitRef])
+	require.NotNil(t, series["requests/POST"])
+	assert.NotContains(t, series, "requests_created/GET", "the start time is sent as created timestamp")
+
+	temperature := series["temperature/"]
+	require.NotNil(t, temperature)
+	assert.Equal(t, []writev2.Sample{{Value: 21.5, Timestamp: 2000}}, temperature.Samples)
+	assert.Equal(t, int64(0), temperature.CreatedTimestamp)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_GAUGE, temperature.Metadata.Type)
+	assert.Equal(t, uint32(0), temperature.Metadata.HelpRef)
+	assert.Equal(t, "Cel", symbols.Symbols()[temperature.Metadata.UnitRef])
+
+	latency := series["latency/"]
+	require.NotNil(t, latency)
+	assert.Empty(t, latency.Samples)
+	require.Len(t, latency.Histograms, 1)
+	assert.Equal(t, writev2.Histogram{
+		Count:          &writev2.Histogram_CountInt{CountInt: 4},
+		Sum:            10,
+		ZeroThreshold:  defaultZeroThreshold,
+		ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+		PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}},
+		PositiveDeltas: []int64{1, 1},
+		Timestamp:      2000,
+	}, latency.Histograms[0])
+	assert.Equal(t, int64(1000), latency.CreatedTimestamp)
+	assert.Equal(t, writev2.Metadata_METRIC_TYPE_HISTOGRAM, latency.Metadata.Type)
+
+	targetInfo := series["target_info/"]
+	require.NotNil(t, targetInfo)
+	assert.Equal(t, []writev2.Sample{{Value: 1, Timestamp: 2000}}, targetInfo.Samples)
+}
+
+func TestFromMetricsV2Errors(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetName("empty")
+	empty := metrics.AppendEmpty()
+	empty.SetName("no_data_points")
+	empty.SetEmptyGauge()
+
+	tsMap, _, err := FromMetricsV2(md, Settings{})
+	assert.Error(t, err)
+	assert.Empty(t, tsMap)
+}
+
+func desymbolizeLabels(t *testing.T, symbols []string, refs []uint32) map[string]string {
+	require.Equal(t, 0, len(refs)%2)
+	labels := map[string]string{}
+	for i := 0; i < len(refs); i += 2 {
+		name, err := writev2.Desymbolize(symbols, refs[i])
+		require.NoError(t, err)
+		value, err := writev2.Desymbolize(symbols, refs[i+1])
+		require.NoError(t, err)
+		labels[name] = value
+	}
+	return labels
+}
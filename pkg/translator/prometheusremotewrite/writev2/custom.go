@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package writev2 holds the messages of the Prometheus Remote Write 2.0 protocol, `io.prometheus.write.v2.Request`,
+// generated from types.proto with `make genproto`. The generator is protoc-gen-gogofast, which is built on
+// protoc-gen-gogo and names it in the header of types.pb.go. See https://prometheus.io/docs/specs/remote_write_spec_2_0/.
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+const (
+	// ProtoMsg is the fully qualified name of the Remote Write 2.0 request message.
+	ProtoMsg = "io.prometheus.write.v2.Request"
+	// ContentType is the content type of the Remote Write 2.0 requests.
+	ContentType = "application/x-protobuf;proto=" + ProtoMsg
+	// Version is the value of the X-Prometheus-Remote-Write-Version header of the Remote Write 2.0 requests.
+	Version = "2.0.0"
+)
+
+// IsFloatHistogram returns true if the histogram has float counts.
+func (m Histogram) IsFloatHistogram() bool {
+	_, ok := m.GetCount().(*Histogram_CountFloat)
+	return ok
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import "fmt"
+
+// SymbolsTable interns the strings of a request, so that each of them is sent once.
+type SymbolsTable struct {
+	symbols []string
+	index   map[string]uint32
+}
+
+// NewSymbolsTable returns a table holding the empty string only.
+func NewSymbolsTable() SymbolsTable {
+	return SymbolsTable{
+		symbols: []string{""},
+		index:   map[string]uint32{"": 0},
+	}
+}
+
+// Symbolize returns the reference of the string, adding it to the table if needed.
+func (t *SymbolsTable) Symbolize(s string) uint32 {
+	if ref, ok := t.index[s]; ok {
+		return ref
+	}
+	ref := uint32(len(t.symbols))
+	t.symbols = append(t.symbols, s)
+	t.index[s] = ref
+	return ref
+}
+
+// SymbolizeLabels appends the references of the name and value of each label pair to refs.
+func (t *SymbolsTable) SymbolizeLabels(refs []uint32, nameValues ...string) []uint32 {
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		refs = append(refs, t.Symbolize(nameValues[i]), t.Symbolize(nameValues[i+1]))
+	}
+	return refs
+}
+
+// Symbols returns the strings of the table, indexed by their reference.
+func (t *SymbolsTable) Symbols() []string {
+	return t.symbols
+}
+
+// Len returns the number of strings of the table.
+func (t *SymbolsTable) Len() int {
+	return len(t.symbols)
+}
+
+// Desymbolize returns the string of the reference.
+func Desymbolize(symbols []string, ref uint32) (string, error) {
+	if int(ref) >= len(symbols) {
+		return "", fmt.Errorf("symbol reference %d is out of range, the request has %d symbols", ref, len(symbols))
+	}
+	return symbols[ref], nil
+}
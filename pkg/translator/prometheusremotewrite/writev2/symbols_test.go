@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolsTable(t *testing.T) {
+	table := NewSymbolsTable()
+	assert.Equal(t, []string{""}, table.Symbols())
+
+	assert.Equal(t, uint32(0), table.Symbolize(""))
+	assert.Equal(t, uint32(1), table.Symbolize("__name__"))
+	assert.Equal(t, uint32(2), table.Symbolize("up"))
+	assert.Equal(t, uint32(1), table.Symbolize("__name__"))
+
+	refs := table.SymbolizeLabels(nil, "__name__", "up", "job", "up", "unpaired")
+	assert.Equal(t, []uint32{1, 2, 3, 2}, refs)
+	assert.Equal(t, []string{"", "__name__", "up", "job"}, table.Symbols())
+	assert.Equal(t, 4, table.Len())
+
+	s, err := Desymbolize(table.Symbols(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "job", s)
+
+	_, err = Desymbolize(table.Symbols(), 4)
+	assert.EqualError(t, err, "symbol reference 4 is out of range, the request has 4 symbols")
+}
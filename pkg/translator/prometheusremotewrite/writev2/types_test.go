@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		req  Request
+	}{
+		{
+			name: "empty",
+			req:  Request{},
+		},
+		{
+			name: "samples",
+			req: Request{
+				Symbols: []string{"", "__name__", "http_requests_total", "job", "api", "Total requests", "1", "trace_id", "abc"},
+				Timeseries: []TimeSeries{
+					{
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Samples:    []Sample{{Value: 1.5, Timestamp: 1000}, {Value: 0, Timestamp: 2000}, {Value: -3, Timestamp: -1}},
+						Exemplars:  []Exemplar{{LabelsRefs: []uint32{7, 8}, Value: 1, Timestamp: 1000}},
+						Metadata: Metadata{
+							Type:    Metadata_METRIC_TYPE_COUNTER,
+							HelpRef: 5,
+							UnitRef: 6,
+						},
+						CreatedTimestamp: 500,
+					},
+					{
+						LabelsRefs: []uint32{1, 2},
+						Samples:    []Sample{{Value: math.Inf(1), Timestamp: 1000}},
+					},
+				},
+			},
+		},
+		{
+			name: "integer histogram",
+			req: Request{
+				Symbols: []string{"", "__name__", "latency"},
+				Timeseries: []TimeSeries{
+					{
+						LabelsRefs: []uint32{1, 2},
+						Histograms: []Histogram{
+							{
+								Count:          &Histogram_CountInt{CountInt: 10},
+								Sum:            12.5,
+								Schema:         -2,
+								ZeroThreshold:  1e-128,
+								ZeroCount:      &Histogram_ZeroCountInt{ZeroCountInt: 1},
+								NegativeSpans:  []BucketSpan{{Offset: -3, Length: 1}},
+								NegativeDeltas: []int64{2},
+								PositiveSpans:  []BucketSpan{{Offset: 0, Length: 2}, {Offset: 3, Length: 1}},
+								PositiveDeltas: []int64{3, -1, 2},
+								ResetHint:      Histogram_RESET_HINT_NO,
+								Timestamp:      1000,
+							},
+							{
+								Timestamp: 2000,
+							},
+						},
+						Metadata: Metadata{Type: Metadata_METRIC_TYPE_HISTOGRAM},
+					},
+				},
+			},
+		},
+		{
+			name: "float histogram",
+			req: Request{
+				Symbols: []string{"", "__name__", "latency"},
+				Timeseries: []TimeSeries{
+					{
+						LabelsRefs: []uint32{1, 2},
+						Histograms: []Histogram{
+							{
+								Count:          &Histogram_CountFloat{CountFloat: 4.5},
+								Sum:            3,
+								ZeroCount:      &Histogram_ZeroCountFloat{ZeroCountFloat: 0.5},
+								PositiveSpans:  []BucketSpan{{Offset: 1, Length: 2}},
+								PositiveCounts: []float64{1.5, 2.5},
+								CustomValues:   []float64{0.1, 1},
+								ResetHint:      Histogram_RESET_HINT_GAUGE,
+								Timestamp:      1000,
+							},
+						},
+						Metadata: Metadata{Type: Metadata_METRIC_TYPE_GAUGEHISTOGRAM},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.req.Marshal()
+			require.NoError(t, err)
+			assert.Len(t, data, tt.req.Size())
+
+			var got Request
+			require.NoError(t, got.Unmarshal(data))
+			assert.Equal(t, tt.req, got)
+		})
+	}
+}
+
+func TestSampleEncoding(t *testing.T) {
+	req := Request{
+		Symbols: []string{""},
+		Timeseries: []TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 300},
+				Samples:    []Sample{{Value: 1, Timestamp: 2}},
+			},
+		},
+	}
+
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x22, 0x00, // symbols: ""
+		0x2a, 0x14, // timeseries
+		0x0a, 0x03, 0x01, 0xac, 0x02, // labels_refs: [1, 300]
+		0x12, 0x0b, 0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x10, 0x02, // samples: {value: 1, timestamp: 2}
+		0x2a, 0x00, // metadata: {}
+	}, data)
+}
+
+func TestIsFloatHistogram(t *testing.T) {
+	assert.False(t, Histogram{}.IsFloatHistogram())
+	assert.False(t, Histogram{Count: &Histogram_CountInt{CountInt: 1}}.IsFloatHistogram())
+	assert.True(t, Histogram{Count: &Histogram_CountFloat{CountFloat: 1}}.IsFloatHistogram())
+}
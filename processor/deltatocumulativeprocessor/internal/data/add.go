@@ -3,7 +3,14 @@
 
 package data // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
 
-import "go.opentelemetry.io/collector/pdata/pmetric"
+import (
+	"math"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/expo"
+)
 
 func (dp Number) Add(in Number) Number {
 	switch in.ValueType() {
@@ -18,12 +25,90 @@ func (dp Number) Add(in Number) Number {
 	return dp
 }
 
-// nolint
 func (dp Histogram) Add(in Histogram) Histogram {
-	panic("todo")
+	// bounds different: no way to merge, so reset observation to new boundaries
+	if !slices.Equal(dp.ExplicitBounds().AsRaw(), in.ExplicitBounds().AsRaw()) {
+		in.CopyTo(dp)
+		return dp
+	}
+
+	// spec requires len(BucketCounts) == len(ExplicitBounds)+1.
+	// given we have limited error handling at this stage (and already verified boundaries are correct),
+	// doing a best-effort add of whatever we have appears reasonable.
+	n := min(dp.BucketCounts().Len(), in.BucketCounts().Len())
+	for i := 0; i < n; i++ {
+		sum := dp.BucketCounts().At(i) + in.BucketCounts().At(i)
+		dp.BucketCounts().SetAt(i, sum)
+	}
+
+	dp.SetTimestamp(in.Timestamp())
+	dp.SetCount(dp.Count() + in.Count())
+
+	if dp.HasSum() && in.HasSum() {
+		dp.SetSum(dp.Sum() + in.Sum())
+	} else {
+		dp.RemoveSum()
+	}
+
+	if dp.HasMin() && in.HasMin() {
+		dp.SetMin(math.Min(dp.Min(), in.Min()))
+	} else {
+		dp.RemoveMin()
+	}
+
+	if dp.HasMax() && in.HasMax() {
+		dp.SetMax(math.Max(dp.Max(), in.Max()))
+	} else {
+		dp.RemoveMax()
+	}
+
+	return dp
 }
 
-// nolint
 func (dp ExpHistogram) Add(in ExpHistogram) ExpHistogram {
-	panic("todo")
+	type H = ExpHistogram
+
+	// buckets of different scales are brought to the lower (coarser) one
+	if dp.Scale() != in.Scale() {
+		hi, lo := expo.HiLo(dp, in, H.Scale)
+		from, to := expo.Scale(hi.Scale()), expo.Scale(lo.Scale())
+		expo.Downscale(hi.Positive(), from, to)
+		expo.Downscale(hi.Negative(), from, to)
+		hi.SetScale(lo.Scale())
+	}
+
+	// zero buckets of different widths are brought to the wider one
+	if dp.ZeroThreshold() != in.ZeroThreshold() {
+		hi, lo := expo.HiLo(dp, in, H.ZeroThreshold)
+		expo.WidenZero(lo.ExponentialHistogramDataPoint, hi.ZeroThreshold())
+		// the zero threshold may end up on the upper bound of the bucket it falls into
+		expo.WidenZero(hi.ExponentialHistogramDataPoint, lo.ZeroThreshold())
+	}
+
+	expo.Merge(dp.Positive(), in.Positive())
+	expo.Merge(dp.Negative(), in.Negative())
+
+	dp.SetTimestamp(in.Timestamp())
+	dp.SetCount(dp.Count() + in.Count())
+	dp.SetZeroCount(dp.ZeroCount() + in.ZeroCount())
+
+	if dp.HasSum() && in.HasSum() {
+		dp.SetSum(dp.Sum() + in.Sum())
+	} else {
+		dp.RemoveSum()
+	}
+
+	if dp.HasMin() && in.HasMin() {
+		dp.SetMin(math.Min(dp.Min(), in.Min()))
+	} else {
+		dp.RemoveMin()
+	}
+
+	if dp.HasMax() && in.HasMax() {
+		dp.SetMax(math.Max(dp.Max(), in.Max()))
+	} else {
+		dp.RemoveMax()
+	}
+
+	return dp
 }
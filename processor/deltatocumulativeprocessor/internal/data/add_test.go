@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package data_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
+)
+
+func TestHistogramAdd(t *testing.T) {
+	hist := func(ts int, bounds []float64, counts []uint64, sum, min, max float64) data.Histogram {
+		dp := pmetric.NewHistogramDataPoint()
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.ExplicitBounds().FromRaw(bounds)
+		dp.BucketCounts().FromRaw(counts)
+		var count uint64
+		for _, c := range counts {
+			count += c
+		}
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.SetMin(min)
+		dp.SetMax(max)
+		return data.Histogram{HistogramDataPoint: dp}
+	}
+
+	t.Run("same-bounds", func(t *testing.T) {
+		dp := hist(1, []float64{1, 10}, []uint64{1, 2, 3}, 20, 0.5, 12)
+		in := hist(2, []float64{1, 10}, []uint64{4, 0, 1}, 15, 0.1, 11)
+
+		got := dp.Add(in)
+		require.Equal(t, pcommon.Timestamp(2), got.Timestamp())
+		require.Equal(t, []uint64{5, 2, 4}, got.BucketCounts().AsRaw())
+		require.Equal(t, uint64(11), got.Count())
+		require.Equal(t, 35.0, got.Sum())
+		require.Equal(t, 0.1, got.Min())
+		require.Equal(t, 12.0, got.Max())
+	})
+
+	t.Run("different-bounds", func(t *testing.T) {
+		dp := hist(1, []float64{1, 10}, []uint64{1, 2, 3}, 20, 0.5, 12)
+		in := hist(2, []float64{5}, []uint64{4, 1}, 15, 0.1, 11)
+
+		// no way to merge, the histogram restarts from the new observation
+		got := dp.Add(in)
+		require.Equal(t, []float64{5}, got.ExplicitBounds().AsRaw())
+		require.Equal(t, []uint64{4, 1}, got.BucketCounts().AsRaw())
+		require.Equal(t, uint64(5), got.Count())
+		require.Equal(t, 15.0, got.Sum())
+	})
+
+	t.Run("missing-sum-min-max", func(t *testing.T) {
+		dp := hist(1, []float64{1}, []uint64{1, 2}, 20, 0.5, 12)
+		in := hist(2, []float64{1}, []uint64{1, 1}, 15, 0.1, 11)
+		in.RemoveSum()
+		in.RemoveMin()
+		in.RemoveMax()
+
+		got := dp.Add(in)
+		require.Equal(t, uint64(5), got.Count())
+		require.False(t, got.HasSum())
+		require.False(t, got.HasMin())
+		require.False(t, got.HasMax())
+	})
+}
+
+func TestExpHistogramAdd(t *testing.T) {
+	type bins struct {
+		Offset int32
+		Counts []uint64
+	}
+	expo := func(ts int, scale int32, zero float64, zeroCount uint64, pos bins) data.ExpHistogram {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.SetScale(scale)
+		dp.SetZeroThreshold(zero)
+		dp.SetZeroCount(zeroCount)
+		dp.Positive().SetOffset(pos.Offset)
+		dp.Positive().BucketCounts().FromRaw(pos.Counts)
+		count := zeroCount
+		for _, c := range pos.Counts {
+			count += c
+		}
+		dp.SetCount(count)
+		dp.SetSum(float64(count))
+		return data.ExpHistogram{ExponentialHistogramDataPoint: dp}
+	}
+	binsOf := func(bs pmetric.ExponentialHistogramDataPointBuckets) bins {
+		return bins{Offset: bs.Offset(), Counts: bs.BucketCounts().AsRaw()}
+	}
+
+	t.Run("same-scale", func(t *testing.T) {
+		dp := expo(1, 2, 0, 1, bins{Offset: 0, Counts: []uint64{1, 2}})
+		in := expo(2, 2, 0, 2, bins{Offset: 1, Counts: []uint64{1, 1}})
+
+		got := dp.Add(in)
+		require.Equal(t, pcommon.Timestamp(2), got.Timestamp())
+		require.Equal(t, int32(2), got.Scale())
+		require.Equal(t, bins{Offset: 0, Counts: []uint64{1, 3, 1}}, binsOf(got.Positive()))
+		require.Equal(t, uint64(3), got.ZeroCount())
+		require.Equal(t, uint64(9), got.Count())
+		require.Equal(t, 9.0, got.Sum())
+	})
+
+	t.Run("different-scale", func(t *testing.T) {
+		dp := expo(1, 1, 0, 0, bins{Offset: 0, Counts: []uint64{1, 1, 1, 1}})
+		in := expo(2, 0, 0, 1, bins{Offset: 0, Counts: []uint64{2}})
+
+		// dp is downscaled to the scale of in
+		got := dp.Add(in)
+		require.Equal(t, int32(0), got.Scale())
+		require.Equal(t, bins{Offset: 0, Counts: []uint64{4, 2}}, binsOf(got.Positive()))
+		require.Equal(t, uint64(1), got.ZeroCount())
+		require.Equal(t, uint64(7), got.Count())
+	})
+
+	t.Run("different-zero-threshold", func(t *testing.T) {
+		// (1,2], (2,4]
+		dp := expo(1, 0, 0, 0, bins{Offset: 0, Counts: []uint64{1, 1}})
+		in := expo(2, 0, 1.5, 2, bins{Offset: 1, Counts: []uint64{3}})
+
+		// the zero bucket is widened to the upper bound of the bucket of 1.5
+		got := dp.Add(in)
+		require.Equal(t, 2.0, got.ZeroThreshold())
+		require.Equal(t, uint64(1+2), got.ZeroCount())
+		require.Equal(t, bins{Offset: 1, Counts: []uint64{4}}, binsOf(got.Positive()))
+		require.Equal(t, uint64(7), got.Count())
+	})
+}
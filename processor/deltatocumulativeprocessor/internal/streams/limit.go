@@ -17,18 +17,26 @@ func Limit[T any](m Map[T], max int) LimitMap[T] {
 
 type LimitMap[T any] struct {
 	Max int
+	// Total counts the streams held against Max, allowing several maps to share
+	// the same limit. Only the streams of Map are counted if nil.
+	Total func() int
 
 	Evictor streams.Evictor
 	streams.Map[T]
 }
 
 func (m LimitMap[T]) Store(id identity.Stream, v T) error {
-	if m.Map.Len() < m.Max {
+	total := m.Map.Len()
+	if m.Total != nil {
+		total = m.Total()
+	}
+	if total < m.Max {
 		return m.Map.Store(id, v)
 	}
 
 	errl := ErrLimit(m.Max)
-	if m.Evictor != nil {
+	// only the streams of Map can be evicted to make room for the new one
+	if m.Evictor != nil && m.Map.Len() > 0 {
 		gone := m.Evictor.Evict()
 		if err := m.Map.Store(id, v); err != nil {
 			return err
@@ -49,3 +49,27 @@ func TestLimit(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestLimitShared(t *testing.T) {
+	sum := random.Sum()
+
+	a := make(exp.HashMap[data.Number])
+	b := make(exp.HashMap[data.Number])
+	total := func() int { return a.Len() + b.Len() }
+	limA := streams.Limit(a, 10)
+	limA.Total = total
+	limB := streams.Limit(b, 10)
+	limB.Total = total
+
+	for i := 0; i < 5; i++ {
+		id, dp := sum.Stream()
+		require.NoError(t, limA.Store(id, dp))
+		id, dp = sum.Stream()
+		require.NoError(t, limB.Store(id, dp))
+	}
+
+	// the limit applies to the streams of both maps
+	id, dp := sum.Stream()
+	require.True(t, streams.AtLimit(limA.Store(id, dp)))
+	require.True(t, streams.AtLimit(limB.Store(id, dp)))
+}
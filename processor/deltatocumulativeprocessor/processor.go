@@ -31,8 +31,9 @@ type Processor struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	aggr  streams.Aggregator[data.Number]
-	stale *staleness.Staleness[data.Number]
+	sums Pipeline[data.Number]
+	hist Pipeline[data.Histogram]
+	expo Pipeline[data.ExpHistogram]
 
 	mtx sync.Mutex
 }
@@ -40,35 +41,59 @@ type Processor struct {
 func newProcessor(cfg *Config, log *zap.Logger, next consumer.Metrics) *Processor {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	proc := Processor{
+	proc := &Processor{
 		log:    log,
 		ctx:    ctx,
 		cancel: cancel,
 		next:   next,
 	}
+	proc.sums = pipeline[data.Number](cfg, proc.numStreams)
+	proc.hist = pipeline[data.Histogram](cfg, proc.numStreams)
+	proc.expo = pipeline[data.ExpHistogram](cfg, proc.numStreams)
 
-	var dps streams.Map[data.Number]
-	dps = delta.New[data.Number]()
+	return proc
+}
+
+// numStreams returns the number of streams tracked by all pipelines, which share the max_streams limit
+func (p *Processor) numStreams() int {
+	return p.sums.dps.Len() + p.hist.dps.Len() + p.expo.dps.Len()
+}
+
+// Pipeline accumulates the streams of one type of data point
+type Pipeline[D data.Point[D]] struct {
+	aggr  streams.Aggregator[D]
+	stale *staleness.Staleness[D]
+	// dps holds the streams of the pipeline
+	dps streams.Map[D]
+}
+
+func pipeline[D data.Point[D]](cfg *Config, total func() int) Pipeline[D] {
+	var pipe Pipeline[D]
+
+	var dps streams.Map[D]
+	dps = delta.New[D]()
 
 	if cfg.MaxStale > 0 {
 		stale := staleness.NewStaleness(cfg.MaxStale, dps)
-		proc.stale = stale
+		pipe.stale = stale
 		dps = stale
 	}
+	pipe.dps = dps
 	if cfg.MaxStreams > 0 {
 		lim := streams.Limit(dps, cfg.MaxStreams)
-		if proc.stale != nil {
-			lim.Evictor = proc.stale
+		lim.Total = total
+		if pipe.stale != nil {
+			lim.Evictor = pipe.stale
 		}
 		dps = lim
 	}
 
-	proc.aggr = streams.IntoAggregator(dps)
-	return &proc
+	pipe.aggr = streams.IntoAggregator(dps)
+	return pipe
 }
 
 func (p *Processor) Start(_ context.Context, _ component.Host) error {
-	if p.stale == nil {
+	if p.sums.stale == nil {
 		return nil
 	}
 
@@ -80,7 +105,9 @@ func (p *Processor) Start(_ context.Context, _ component.Host) error {
 				return
 			case <-tick.C:
 				p.mtx.Lock()
-				p.stale.ExpireOldEntries()
+				p.sums.stale.ExpireOldEntries()
+				p.hist.stale.ExpireOldEntries()
+				p.expo.stale.ExpireOldEntries()
 				p.mtx.Unlock()
 			}
 		}
@@ -108,14 +135,24 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 		case pmetric.MetricTypeSum:
 			sum := m.Sum()
 			if sum.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
-				err := streams.Aggregate[data.Number](metrics.Sum(m), p.aggr)
+				err := streams.Aggregate[data.Number](metrics.Sum(m), p.sums.aggr)
 				errs = errors.Join(errs, err)
 				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 			}
 		case pmetric.MetricTypeHistogram:
-			// TODO
+			hist := m.Histogram()
+			if hist.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
+				err := streams.Aggregate[data.Histogram](metrics.Histogram(m), p.hist.aggr)
+				errs = errors.Join(errs, err)
+				hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			}
 		case pmetric.MetricTypeExponentialHistogram:
-			// TODO
+			expo := m.ExponentialHistogram()
+			if expo.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
+				err := streams.Aggregate[data.ExpHistogram](metrics.ExpHistogram(m), p.expo.aggr)
+				errs = errors.Join(errs, err)
+				expo.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			}
 		}
 	})
 
@@ -4,9 +4,14 @@
 package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
 
 import (
+	"errors"
 	"time"
+
+	"go.opentelemetry.io/collector/component"
 )
 
+var errStorageNotSet = errors.New("option 'store_on_disk' requires the 'storage' extension to be set")
+
 // Config is the configuration for the processor.
 type Config struct {
 
@@ -30,7 +35,21 @@ type Config struct {
 
 	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
 	// Useful when the duration to wait for traces to complete is high.
+	// Requires StorageID to be set.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the ID of the storage extension, such as filestorage or dbstorage, holding the trace spans
+	// when StoreOnDisk is enabled. The in-flight traces found in the storage are restored at start-up.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.StoreOnDisk && cfg.StorageID == nil {
+		return errStorageNotSet
+	}
+	return nil
 }
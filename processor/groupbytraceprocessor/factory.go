@@ -25,7 +25,6 @@ const (
 )
 
 var (
-	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release")
 	errDiscardOrphansNotSupported = fmt.Errorf("option 'discard orphans' not supported in this release")
 )
 
@@ -49,7 +48,8 @@ func createDefaultConfig() component.Config {
 
 		// not supported for now
 		DiscardOrphans: defaultDiscardOrphans,
-		StoreOnDisk:    defaultStoreOnDisk,
+
+		StoreOnDisk: defaultStoreOnDisk,
 	}
 }
 
@@ -62,16 +62,19 @@ func createTracesProcessor(
 
 	oCfg := cfg.(*Config)
 
-	var st storage
-	if oCfg.StoreOnDisk {
-		return nil, errDiskStorageNotSupported
-	}
 	if oCfg.DiscardOrphans {
 		return nil, errDiscardOrphansNotSupported
 	}
 
-	// the only supported storage for now
-	st = newMemoryStorage()
+	var st storage
+	if oCfg.StoreOnDisk {
+		if oCfg.StorageID == nil {
+			return nil, errStorageNotSet
+		}
+		st = newPersistentStorage(*oCfg.StorageID, params.ID)
+	} else {
+		st = newMemoryStorage()
+	}
 
 	return newGroupByTraceProcessor(params.Logger, st, nextConsumer, *oCfg), nil
 }
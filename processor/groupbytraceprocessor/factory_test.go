@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/processor/processortest"
 )
 
@@ -36,6 +37,34 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, p)
 }
 
+func TestCreateTestProcessorWithPersistentStorage(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	storageID := component.MustNewID("file_storage")
+	c.StoreOnDisk = true
+	c.StorageID = &storageID
+
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), c, next)
+
+	// verify
+	assert.NoError(t, err)
+	assert.IsType(t, &persistentStorage{}, p.(*groupByTraceProcessor).st)
+}
+
+func TestValidateConfig(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	assert.NoError(t, c.Validate())
+
+	c.StoreOnDisk = true
+	assert.ErrorIs(t, c.Validate(), errStorageNotSet)
+
+	storageID := component.MustNewID("file_storage")
+	c.StorageID = &storageID
+	assert.NoError(t, c.Validate())
+}
+
 func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 	// prepare
 	f := NewFactory()
@@ -56,7 +85,7 @@ func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 			&Config{
 				StoreOnDisk: true,
 			},
-			errDiskStorageNotSupported,
+			errStorageNotSet,
 		},
 	} {
 		p, err := f.CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), tt.config, next)
@@ -3,12 +3,14 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/processor/group
 go 1.21
 
 require (
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage v0.96.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal v0.96.0
 	github.com/stretchr/testify v1.9.0
 	go.opencensus.io v0.24.0
 	go.opentelemetry.io/collector/component v0.96.1-0.20240322165517-15201f1e5967
 	go.opentelemetry.io/collector/confmap v0.96.1-0.20240322165517-15201f1e5967
 	go.opentelemetry.io/collector/consumer v0.96.1-0.20240322165517-15201f1e5967
+	go.opentelemetry.io/collector/extension v0.96.1-0.20240322165517-15201f1e5967
 	go.opentelemetry.io/collector/pdata v1.3.1-0.20240322165517-15201f1e5967
 	go.opentelemetry.io/collector/processor v0.96.1-0.20240322165517-15201f1e5967
 	go.opentelemetry.io/otel/metric v1.24.0
@@ -62,3 +64,5 @@ retract (
 	v0.76.1
 	v0.65.0
 )
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage => ../../extension/storage
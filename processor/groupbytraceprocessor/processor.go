@@ -83,14 +83,55 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
 	// start these metrics, as it might take a while for them to receive their first event
 	stats.Record(context.Background(), mTracesEvicted.M(0))
 	stats.Record(context.Background(), mIncompleteReleases.M(0))
 	stats.Record(context.Background(), mNumTracesConf.M(int64(sp.config.NumTraces)))
 
+	if err := sp.st.start(ctx, host); err != nil {
+		return err
+	}
+	if err := sp.restoreTraces(); err != nil {
+		return err
+	}
+
 	sp.eventMachine.startInBackground()
-	return sp.st.start()
+	return nil
+}
+
+// restoreTraces places the traces found in the storage back in the ring buffers, scheduling
+// their release as if they had just been received. It must be called before the event machine
+// starts, as it accesses the workers' buffers directly.
+func (sp *groupByTraceProcessor) restoreTraces() error {
+	traceIDs, err := sp.st.traceIDs()
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve the traces from the storage: %w", err)
+	}
+	if len(traceIDs) == 0 {
+		return nil
+	}
+
+	sp.logger.Info("restoring traces from the storage", zap.Int("traces", len(traceIDs)))
+	for _, traceID := range traceIDs {
+		var bucket uint64
+		if len(sp.eventMachine.workers) != 1 {
+			bucket = workerIndexForTraceID(traceID, len(sp.eventMachine.workers))
+		}
+		worker := sp.eventMachine.workers[bucket]
+
+		if evicted := worker.buffer.put(traceID); !evicted.IsEmpty() {
+			// the storage holds more traces than the buffer can, as it happens when num_traces
+			// is reduced between restarts: keep the most recent ones
+			if _, err = sp.st.delete(evicted); err != nil {
+				return fmt.Errorf("couldn't delete trace %q from the storage: %w", evicted, err)
+			}
+			stats.Record(context.Background(), mTracesEvicted.M(1))
+		}
+
+		sp.scheduleExpiration(traceID, worker)
+	}
+	return nil
 }
 
 // Shutdown is invoked during service shutdown.
@@ -136,6 +177,11 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
 	}
 
+	sp.scheduleExpiration(traceID, worker)
+	return nil
+}
+
+func (sp *groupByTraceProcessor) scheduleExpiration(traceID pcommon.TraceID, worker *eventMachineWorker) {
 	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
 
 	time.AfterFunc(sp.config.WaitDuration, func() {
@@ -145,7 +191,6 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 			payload: traceID,
 		})
 	})
-	return nil
 }
 
 func (sp *groupByTraceProcessor) onTraceExpired(traceID pcommon.TraceID, worker *eventMachineWorker) error {
@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 )
 
@@ -134,6 +135,69 @@ func TestInternalCacheLimit(t *testing.T) {
 	assert.NotContains(t, receivedTraceIDs, traceIDs[0])
 }
 
+func TestTracesRestoredFromPersistentStorage(t *testing.T) {
+	// prepare
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	processorID := component.MustNewID("groupbytrace")
+	ctx := context.Background()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	// the first instance is stopped before the traces are released
+	st := newPersistentStorage(ext.ID, processorID)
+	p := newGroupByTraceProcessor(zap.NewNop(), st, &mockProcessor{}, Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+		NumWorkers:   2,
+	})
+	require.NoError(t, p.Start(ctx, host))
+	for _, traceID := range traceIDs {
+		require.NoError(t, p.ConsumeTraces(ctx, simpleTracesWithID(traceID)))
+	}
+	require.Eventually(t, func() bool {
+		return st.count() == len(traceIDs)
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, p.Shutdown(ctx))
+
+	mu := sync.Mutex{}
+	var receivedTraceIDs []pcommon.TraceID
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, received ptrace.Traces) error {
+			mu.Lock()
+			defer mu.Unlock()
+			receivedTraceIDs = append(receivedTraceIDs, received.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+			return nil
+		},
+	}
+
+	// test
+	st = newPersistentStorage(ext.ID, processorID)
+	p = newGroupByTraceProcessor(zap.NewNop(), st, next, Config{
+		WaitDuration: 10 * time.Millisecond,
+		NumTraces:    10,
+		NumWorkers:   2,
+	})
+	require.NoError(t, p.Start(ctx, host))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// verify
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(receivedTraceIDs) == len(traceIDs)
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, traceIDs, receivedTraceIDs)
+	require.Eventually(t, func() bool {
+		return st.count() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestProcessorCapabilities(t *testing.T) {
 	// prepare
 	config := Config{
@@ -630,7 +694,10 @@ func (st *mockStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans,
 	}
 	return nil, nil
 }
-func (st *mockStorage) start() error {
+func (st *mockStorage) traceIDs() ([]pcommon.TraceID, error) {
+	return nil, nil
+}
+func (st *mockStorage) start(context.Context, component.Host) error {
 	if st.onStart != nil {
 		return st.onStart()
 	}
@@ -4,6 +4,9 @@
 package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
 
 import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
@@ -24,8 +27,13 @@ type storage interface {
 	// or nil in case a trace cannot be found
 	delete(pcommon.TraceID) ([]ptrace.ResourceSpans, error)
 
+	// traceIDs returns the IDs of the traces currently in the storage, ordered by their arrival
+	// when the storage is able to tell it. This is used to restore the in-flight traces
+	// from a storage that survives restarts.
+	traceIDs() ([]pcommon.TraceID, error)
+
 	// start gives the storage the opportunity to initialize any resources or procedures
-	start() error
+	start(ctx context.Context, host component.Host) error
 
 	// shutdown signals the storage that the processor is shutting down
 	shutdown() error
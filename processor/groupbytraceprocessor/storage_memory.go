@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
@@ -74,7 +75,18 @@ func (st *memoryStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans
 	return st.content[traceID], nil
 }
 
-func (st *memoryStorage) start() error {
+func (st *memoryStorage) traceIDs() ([]pcommon.TraceID, error) {
+	st.RLock()
+	defer st.RUnlock()
+
+	ids := make([]pcommon.TraceID, 0, len(st.content))
+	for traceID := range st.content {
+		ids = append(ids, traceID)
+	}
+	return ids, nil
+}
+
+func (st *memoryStorage) start(context.Context, component.Host) error {
 	go st.periodicMetrics()
 	return nil
 }
@@ -0,0 +1,317 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// firstSeqKey holds the lowest sequence number that might still be in use in the index
+	firstSeqKey = "first_seq"
+	// nextSeqKey holds the sequence number to be assigned to the next trace
+	nextSeqKey = "next_seq"
+
+	// indexEntrySize is the size of an index entry: the trace ID followed by its number of batches
+	indexEntrySize = 16 + 8
+)
+
+// persistentStorage keeps the traces in a storage extension, such as the filestorage or dbstorage.
+// Each batch of a trace is serialized under its own key, so that appending a batch doesn't require
+// reading the whole trace back. An index of the trace IDs ordered by arrival, holding the number of
+// batches of each trace, is kept next to them, so that the in-flight traces can be restored when the
+// processor is restarted.
+type persistentStorage struct {
+	sync.Mutex
+	client    storage.Client
+	storageID component.ID
+	processor component.ID
+
+	// the index entry of each in-flight trace, and the trace at each sequence number of the index
+	traces    map[pcommon.TraceID]*indexEntry
+	seqTraces map[uint64]pcommon.TraceID
+	firstSeq  uint64
+	nextSeq   uint64
+
+	marshaler   ptrace.ProtoMarshaler
+	unmarshaler ptrace.ProtoUnmarshaler
+
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+// indexEntry locates the batches of a trace in the storage
+type indexEntry struct {
+	seq     uint64
+	batches uint64
+}
+
+var _ storage = (*persistentStorage)(nil)
+
+func newPersistentStorage(storageID component.ID, processor component.ID) *persistentStorage {
+	return &persistentStorage{
+		storageID:                 storageID,
+		processor:                 processor,
+		traces:                    make(map[pcommon.TraceID]*indexEntry),
+		seqTraces:                 make(map[uint64]pcommon.TraceID),
+		metricsCollectionInterval: time.Second,
+	}
+}
+
+func (st *persistentStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	st.Lock()
+	defer st.Unlock()
+
+	buf, err := st.marshaler.MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("couldn't serialize trace %q: %w", traceID, err)
+	}
+
+	entry, known := st.traces[traceID]
+	if !known {
+		// a new trace, register it at the end of the index
+		entry = &indexEntry{seq: st.nextSeq}
+	}
+
+	ops := []storage.Operation{
+		storage.SetOperation(batchKey(traceID, entry.batches), buf),
+		storage.SetOperation(indexKey(entry.seq), encodeIndexEntry(traceID, entry.batches+1)),
+	}
+	if !known {
+		ops = append(ops, storage.SetOperation(nextSeqKey, encodeSeq(entry.seq+1)))
+	}
+	if err = st.client.Batch(context.Background(), ops...); err != nil {
+		return err
+	}
+
+	entry.batches++
+	if !known {
+		st.traces[traceID] = entry
+		st.seqTraces[entry.seq] = traceID
+		st.nextSeq = entry.seq + 1
+	}
+	return nil
+}
+
+func (st *persistentStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	entry, known := st.traces[traceID]
+	if !known {
+		return nil, nil
+	}
+	return st.read(context.Background(), traceID, entry.batches)
+}
+
+// delete will return the trace that was removed from the storage, which is deserialized from the storage
+// and therefore not shared with any other caller.
+func (st *persistentStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	entry, known := st.traces[traceID]
+	if !known {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	resourceSpans, err := st.read(ctx, traceID, entry.batches)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]storage.Operation, 0, entry.batches+2)
+	for batch := uint64(0); batch < entry.batches; batch++ {
+		ops = append(ops, storage.DeleteOperation(batchKey(traceID, batch)))
+	}
+	ops = append(ops, storage.DeleteOperation(indexKey(entry.seq)))
+	delete(st.traces, traceID)
+	delete(st.seqTraces, entry.seq)
+
+	// traces are typically removed in the same order they arrived, move the start of
+	// the index forward so that a restart doesn't need to scan the removed entries
+	if entry.seq == st.firstSeq {
+		for st.firstSeq < st.nextSeq {
+			if _, ok := st.seqTraces[st.firstSeq]; ok {
+				break
+			}
+			st.firstSeq++
+		}
+		ops = append(ops, storage.SetOperation(firstSeqKey, encodeSeq(st.firstSeq)))
+	}
+	if err = st.client.Batch(ctx, ops...); err != nil {
+		return nil, err
+	}
+
+	return resourceSpans, nil
+}
+
+// traceIDs returns the IDs of the traces found in the storage, ordered by their arrival.
+func (st *persistentStorage) traceIDs() ([]pcommon.TraceID, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	ids := make([]pcommon.TraceID, 0, len(st.seqTraces))
+	for seq := st.firstSeq; seq < st.nextSeq; seq++ {
+		if traceID, ok := st.seqTraces[seq]; ok {
+			ids = append(ids, traceID)
+		}
+	}
+	return ids, nil
+}
+
+func (st *persistentStorage) start(ctx context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[st.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension %q not found", st.storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension %q found", st.storageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, st.processor, "")
+	if err != nil {
+		return fmt.Errorf("couldn't get a client from storage extension %q: %w", st.storageID, err)
+	}
+	st.client = client
+
+	if err = st.loadIndex(ctx); err != nil {
+		return err
+	}
+
+	go st.periodicMetrics()
+	return nil
+}
+
+// loadIndex rebuilds the in-memory view of the index from the storage.
+func (st *persistentStorage) loadIndex(ctx context.Context) error {
+	st.Lock()
+	defer st.Unlock()
+
+	var err error
+	if st.firstSeq, err = st.readSeq(ctx, firstSeqKey); err != nil {
+		return err
+	}
+	if st.nextSeq, err = st.readSeq(ctx, nextSeqKey); err != nil {
+		return err
+	}
+
+	for seq := st.firstSeq; seq < st.nextSeq; seq++ {
+		buf, err := st.client.Get(ctx, indexKey(seq))
+		if err != nil {
+			return fmt.Errorf("couldn't read the index entry %d: %w", seq, err)
+		}
+		if len(buf) != indexEntrySize {
+			// the trace has been removed already
+			continue
+		}
+		traceID := pcommon.TraceID(buf[:len(pcommon.TraceID{})])
+		st.traces[traceID] = &indexEntry{seq: seq, batches: binary.BigEndian.Uint64(buf[len(traceID):])}
+		st.seqTraces[seq] = traceID
+	}
+
+	return nil
+}
+
+func (st *persistentStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	if st.client == nil {
+		return nil
+	}
+	return st.client.Close(context.Background())
+}
+
+func (st *persistentStorage) periodicMetrics() {
+	numTraces := st.count()
+	stats.Record(context.Background(), mNumTracesInMemory.M(int64(numTraces)))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *persistentStorage) count() int {
+	st.Lock()
+	defer st.Unlock()
+	return len(st.traces)
+}
+
+// read retrieves and deserializes the given number of batches of the trace with the given ID.
+// The caller is expected to hold the lock.
+func (st *persistentStorage) read(ctx context.Context, traceID pcommon.TraceID, batches uint64) ([]ptrace.ResourceSpans, error) {
+	var resourceSpans []ptrace.ResourceSpans
+	for batch := uint64(0); batch < batches; batch++ {
+		buf, err := st.client.Get(ctx, batchKey(traceID, batch))
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil {
+			continue
+		}
+
+		trace, err := st.unmarshaler.UnmarshalTraces(buf)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't deserialize trace %q: %w", traceID, err)
+		}
+		for i := 0; i < trace.ResourceSpans().Len(); i++ {
+			resourceSpans = append(resourceSpans, trace.ResourceSpans().At(i))
+		}
+	}
+	return resourceSpans, nil
+}
+
+func (st *persistentStorage) readSeq(ctx context.Context, key string) (uint64, error) {
+	buf, err := st.client.Get(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't read %q: %w", key, err)
+	}
+	if len(buf) != 8 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+func batchKey(traceID pcommon.TraceID, batch uint64) string {
+	return fmt.Sprintf("trace_%s_%d", traceID, batch)
+}
+
+func indexKey(seq uint64) string {
+	return fmt.Sprintf("index_%d", seq)
+}
+
+// encodeIndexEntry encodes the trace ID followed by its number of batches
+func encodeIndexEntry(traceID pcommon.TraceID, batches uint64) []byte {
+	buf := make([]byte, indexEntrySize)
+	copy(buf, traceID[:])
+	binary.BigEndian.PutUint64(buf[len(traceID):], batches)
+	return buf
+}
+
+func encodeSeq(seq uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, seq)
+	return buf
+}
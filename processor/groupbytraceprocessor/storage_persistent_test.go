@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newStartedPersistentStorage(t *testing.T, host component.Host, storageID component.ID) *persistentStorage {
+	st := newPersistentStorage(storageID, component.MustNewID("groupbytrace"))
+	require.NoError(t, st.start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, st.shutdown())
+	})
+	return st
+}
+
+func TestPersistentCreateAndGetTrace(t *testing.T) {
+	// prepare
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	st := newStartedPersistentStorage(t, storagetest.NewStorageHost().WithExtension(ext.ID, ext), ext.ID)
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	baseTrace := ptrace.NewTraces()
+	span := baseTrace.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+
+	// test
+	for _, traceID := range traceIDs {
+		span.SetTraceID(traceID)
+		assert.NoError(t, st.createOrAppend(traceID, baseTrace))
+	}
+
+	// verify
+	assert.Equal(t, 2, st.count())
+	for _, traceID := range traceIDs {
+		expected := []ptrace.ResourceSpans{baseTrace.ResourceSpans().At(0)}
+		expected[0].ScopeSpans().At(0).Spans().At(0).SetTraceID(traceID)
+
+		retrieved, err := st.get(traceID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved)
+	}
+
+	ids, err := st.traceIDs()
+	require.NoError(t, err)
+	assert.Equal(t, traceIDs, ids)
+}
+
+func TestPersistentAppendAndDeleteTrace(t *testing.T) {
+	// prepare
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	st := newStartedPersistentStorage(t, storagetest.NewStorageHost().WithExtension(ext.ID, ext), ext.ID)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	first := simpleTracesWithID(traceID)
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("second-name")
+
+	require.NoError(t, st.createOrAppend(traceID, first))
+	require.NoError(t, st.createOrAppend(traceID, second))
+
+	// test
+	deleted, err := st.delete(traceID)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, deleted, 2)
+	assert.Equal(t, "second-name", deleted[1].ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, 0, st.count())
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestPersistentIndexSurvivesRestart(t *testing.T) {
+	// prepare
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+		pcommon.TraceID([16]byte{3, 4, 5, 6}),
+	}
+
+	st := newPersistentStorage(ext.ID, component.MustNewID("groupbytrace"))
+	require.NoError(t, st.start(context.Background(), host))
+	for _, traceID := range traceIDs {
+		require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+	require.NoError(t, st.createOrAppend(traceIDs[2], simpleTracesWithID(traceIDs[2])))
+	_, err := st.delete(traceIDs[0])
+	require.NoError(t, err)
+	require.NoError(t, st.shutdown())
+
+	// test
+	restored := newStartedPersistentStorage(t, host, ext.ID)
+
+	// verify
+	ids, err := restored.traceIDs()
+	require.NoError(t, err)
+	assert.Equal(t, traceIDs[1:], ids)
+	assert.Equal(t, uint64(1), restored.firstSeq)
+	assert.Equal(t, uint64(3), restored.nextSeq)
+
+	retrieved, err := restored.get(traceIDs[2])
+	require.NoError(t, err)
+	require.Len(t, retrieved, 2)
+	for _, rs := range retrieved {
+		assert.Equal(t, traceIDs[2], rs.ScopeSpans().At(0).Spans().At(0).TraceID())
+	}
+}
+
+func TestPersistentStorageMissingExtension(t *testing.T) {
+	st := newPersistentStorage(component.MustNewID("file_storage"), component.MustNewID("groupbytrace"))
+	assert.Error(t, st.start(context.Background(), storagetest.NewStorageHost()))
+	assert.NoError(t, st.shutdown())
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var _ component.ConfigValidator = (*Config)(nil)
+
+// MatchType describes how the name of a metric is matched.
+type MatchType string
+
+const (
+	// MatchTypeStrict matches the metric name exactly.
+	MatchTypeStrict MatchType = "strict"
+	// MatchTypeRegexp matches the metric name against a regular expression.
+	MatchTypeRegexp MatchType = "regexp"
+)
+
+// AggregationType is the function used to aggregate the values of gauges.
+type AggregationType string
+
+const (
+	AggregationTypeSum  AggregationType = "sum"
+	AggregationTypeMean AggregationType = "mean"
+	AggregationTypeMin  AggregationType = "min"
+	AggregationTypeMax  AggregationType = "max"
+)
+
+// Config defines the configuration for the processor.
+type Config struct {
+	// Interval is the period at which the aggregated metrics are emitted.
+	Interval time.Duration `mapstructure:"interval"`
+	// MaxStaleness is the time after which an input stream which has not been seen anymore
+	// stops contributing to the aggregated metrics. Set to 0 to retain state indefinitely.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+	// Aggregations selects the metrics to aggregate and how to aggregate them.
+	Aggregations []AggregationConfig `mapstructure:"aggregations"`
+}
+
+// AggregationConfig describes the aggregation of the metrics matching a name.
+type AggregationConfig struct {
+	// Include is the name of the metrics to aggregate, or a regular expression matching it.
+	Include string `mapstructure:"include"`
+	// MatchType is either strict or regexp. Defaults to strict.
+	MatchType MatchType `mapstructure:"match_type"`
+	// NewName is the name of the aggregated metric. Defaults to the name of the input metric.
+	NewName string `mapstructure:"new_name"`
+	// KeepAttributes are the data point attributes the aggregated metric keeps, all others are aggregated away.
+	KeepAttributes []string `mapstructure:"keep_attributes"`
+	// DropAttributes are the data point attributes aggregated away, all others are kept.
+	DropAttributes []string `mapstructure:"drop_attributes"`
+	// GaugeAggregation is the function aggregating the values of gauges. Defaults to sum.
+	GaugeAggregation AggregationType `mapstructure:"gauge_aggregation"`
+}
+
+func (c *Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be a positive duration (got %s)", c.Interval)
+	}
+	if c.MaxStaleness < 0 {
+		return fmt.Errorf("max_staleness must not be negative (got %s)", c.MaxStaleness)
+	}
+	if len(c.Aggregations) == 0 {
+		return errors.New("at least one aggregation must be configured")
+	}
+
+	var errs error
+	for i, a := range c.Aggregations {
+		if err := a.validate(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("aggregations[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+func (a *AggregationConfig) validate() error {
+	if a.Include == "" {
+		return errors.New("include must not be empty")
+	}
+
+	switch a.MatchType {
+	case "", MatchTypeStrict:
+	case MatchTypeRegexp:
+		if _, err := regexp.Compile(a.Include); err != nil {
+			return fmt.Errorf("include is not a valid regular expression: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported match_type %q, must be one of %q or %q", a.MatchType, MatchTypeStrict, MatchTypeRegexp)
+	}
+
+	if len(a.KeepAttributes) > 0 && len(a.DropAttributes) > 0 {
+		return errors.New("keep_attributes and drop_attributes cannot be used together")
+	}
+
+	switch a.GaugeAggregation {
+	case "", AggregationTypeSum, AggregationTypeMean, AggregationTypeMin, AggregationTypeMax:
+	default:
+		return fmt.Errorf("unsupported gauge_aggregation %q, must be one of %q, %q, %q or %q",
+			a.GaugeAggregation, AggregationTypeSum, AggregationTypeMean, AggregationTypeMin, AggregationTypeMax)
+	}
+	return nil
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Interval:     time.Minute,
+		MaxStaleness: 5 * time.Minute,
+	}
+}
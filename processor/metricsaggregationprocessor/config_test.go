@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor"
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: &Config{
+				Interval:     time.Minute,
+				MaxStaleness: 5 * time.Minute,
+				Aggregations: []AggregationConfig{
+					{
+						Include:        "http.server.request.duration",
+						KeepAttributes: []string{"http.route", "http.request.method"},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all"),
+			expected: &Config{
+				Interval:     30 * time.Second,
+				MaxStaleness: 10 * time.Minute,
+				Aggregations: []AggregationConfig{
+					{
+						Include:        `^k8s\.pod\..*`,
+						MatchType:      MatchTypeRegexp,
+						DropAttributes: []string{"k8s.pod.name"},
+					},
+					{
+						Include:          "queue.size",
+						NewName:          "queue.size.max",
+						KeepAttributes:   []string{"queue.name"},
+						GaugeAggregation: AggregationTypeMax,
+					},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "missing-aggregations"),
+			errorMessage: "at least one aggregation must be configured",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid-interval"),
+			errorMessage: "interval must be a positive duration (got 0s)",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid-regexp"),
+			errorMessage: "aggregations[0]: include is not a valid regular expression: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid-match-type"),
+			errorMessage: `aggregations[0]: unsupported match_type "glob", must be one of "strict" or "regexp"`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "keep-and-drop"),
+			errorMessage: "aggregations[0]: keep_attributes and drop_attributes cannot be used together",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid-gauge-aggregation"),
+			errorMessage: `aggregations[0]: unsupported gauge_aggregation "median", must be one of "sum", "mean", "min" or "max"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+			if tt.errorMessage != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.errorMessage)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+func TestNumber(t *testing.T) {
+	a, b := pmetric.NewNumberDataPoint(), pmetric.NewNumberDataPoint()
+	a.SetIntValue(3)
+	b.SetIntValue(4)
+
+	AddNumber(a, b)
+	assert.Equal(t, pmetric.NumberDataPointValueTypeInt, a.ValueType())
+	assert.Equal(t, int64(7), a.IntValue())
+
+	b.SetDoubleValue(0.5)
+	AddNumber(a, b)
+	assert.Equal(t, 7.5, a.DoubleValue())
+
+	dst := pmetric.NewNumberDataPoint()
+	require.True(t, SubNumber(dst, a, b))
+	assert.Equal(t, 7.0, dst.DoubleValue())
+	assert.False(t, SubNumber(dst, b, a))
+}
+
+func newHistogram(count uint64, buckets []uint64, sum, min, max float64) pmetric.HistogramDataPoint {
+	dp := pmetric.NewHistogramDataPoint()
+	dp.ExplicitBounds().FromRaw([]float64{1, 10})
+	dp.BucketCounts().FromRaw(buckets)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetMin(min)
+	dp.SetMax(max)
+	return dp
+}
+
+func TestHistogram(t *testing.T) {
+	a := newHistogram(3, []uint64{1, 1, 1}, 20, 0.5, 15)
+	b := newHistogram(2, []uint64{0, 2, 0}, 8, 3, 5)
+
+	require.NoError(t, AddHistogram(a, b))
+	assert.Equal(t, uint64(5), a.Count())
+	assert.Equal(t, []uint64{1, 3, 1}, a.BucketCounts().AsRaw())
+	assert.Equal(t, 28.0, a.Sum())
+	assert.Equal(t, 0.5, a.Min())
+	assert.Equal(t, 15.0, a.Max())
+
+	b.RemoveMin()
+	require.NoError(t, AddHistogram(a, b))
+	assert.False(t, a.HasMin())
+	assert.True(t, a.HasMax())
+
+	dst := pmetric.NewHistogramDataPoint()
+	require.True(t, SubHistogram(dst, a, b))
+	assert.Equal(t, uint64(5), dst.Count())
+	assert.Equal(t, []uint64{1, 3, 1}, dst.BucketCounts().AsRaw())
+	assert.Equal(t, []float64{1, 10}, dst.ExplicitBounds().AsRaw())
+	assert.Equal(t, 28.0, dst.Sum())
+	assert.False(t, dst.HasMin())
+	assert.False(t, dst.HasMax())
+	assert.False(t, SubHistogram(dst, b, a))
+
+	b.ExplicitBounds().FromRaw([]float64{1, 5})
+	assert.ErrorIs(t, AddHistogram(a, b), ErrBoundsMismatch)
+	assert.False(t, SubHistogram(dst, a, b))
+}
+
+func TestExponentialHistogram(t *testing.T) {
+	a := pmetric.NewExponentialHistogramDataPoint()
+	a.SetScale(2)
+	a.Positive().SetOffset(-2)
+	a.Positive().BucketCounts().FromRaw([]uint64{1, 1, 1, 1, 1, 1})
+	a.Negative().SetOffset(4)
+	a.Negative().BucketCounts().FromRaw([]uint64{2})
+	a.SetZeroCount(1)
+	a.SetZeroThreshold(0.001)
+	a.SetCount(9)
+	a.SetSum(10)
+
+	b := pmetric.NewExponentialHistogramDataPoint()
+	b.SetScale(1)
+	b.Positive().SetOffset(3)
+	b.Positive().BucketCounts().FromRaw([]uint64{4})
+	b.SetZeroThreshold(0.01)
+	b.SetCount(4)
+	b.SetSum(30)
+
+	AddExponentialHistogram(a, b)
+	assert.Equal(t, int32(1), a.Scale())
+	// indexes -2..3 at scale 2 are -1..1 at scale 1
+	assert.Equal(t, int32(-1), a.Positive().Offset())
+	assert.Equal(t, []uint64{2, 2, 2, 0, 4}, a.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, int32(2), a.Negative().Offset())
+	assert.Equal(t, []uint64{2}, a.Negative().BucketCounts().AsRaw())
+	assert.Equal(t, uint64(13), a.Count())
+	assert.Equal(t, uint64(1), a.ZeroCount())
+	// the zero bucket is widened to the upper bound of the bucket 0.01 falls into
+	_, threshold := expo.Scale(1).Bounds(expo.Scale(1).Idx(0.01))
+	assert.Equal(t, threshold, a.ZeroThreshold())
+	assert.Equal(t, 40.0, a.Sum())
+
+	dst := pmetric.NewExponentialHistogramDataPoint()
+	require.True(t, SubExponentialHistogram(dst, a, b))
+	assert.Equal(t, int32(1), dst.Scale())
+	assert.Equal(t, int32(-1), dst.Positive().Offset())
+	assert.Equal(t, []uint64{2, 2, 2, 0, 0}, dst.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, uint64(9), dst.Count())
+	assert.Equal(t, 10.0, dst.Sum())
+	assert.False(t, SubExponentialHistogram(dst, b, a))
+}
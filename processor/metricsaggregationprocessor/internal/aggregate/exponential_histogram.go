@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregate // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/aggregate"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+// AddExponentialHistogram adds the counts, the sum and the buckets of src to dst, and merges their min and max.
+// Both data points are brought to the lowest of their scales and to the widest of their zero buckets first,
+// src is left untouched. The sum, min and max are removed from dst unless both data points have them.
+func AddExponentialHistogram(dst, src pmetric.ExponentialHistogramDataPoint) {
+	src = align(dst, src)
+	expo.Merge(dst.Positive(), src.Positive())
+	expo.Merge(dst.Negative(), src.Negative())
+	dst.SetCount(dst.Count() + src.Count())
+	dst.SetZeroCount(dst.ZeroCount() + src.ZeroCount())
+
+	if dst.HasSum() && src.HasSum() {
+		dst.SetSum(dst.Sum() + src.Sum())
+	} else {
+		dst.RemoveSum()
+	}
+	if dst.HasMin() && src.HasMin() {
+		dst.SetMin(min(dst.Min(), src.Min()))
+	} else {
+		dst.RemoveMin()
+	}
+	if dst.HasMax() && src.HasMax() {
+		dst.SetMax(max(dst.Max(), src.Max()))
+	} else {
+		dst.RemoveMax()
+	}
+}
+
+// SubExponentialHistogram sets dst to the increase of the cumulative histogram from prev to cur, at the lowest of their
+// scales and the widest of their zero buckets. It returns false if any count decreased, meaning the stream was reset,
+// in which case dst must not be used. The min and max of the increase are unknown, they are not set.
+func SubExponentialHistogram(dst, cur, prev pmetric.ExponentialHistogramDataPoint) bool {
+	if cur.Count() < prev.Count() {
+		return false
+	}
+
+	cur.CopyTo(dst)
+	prev = align(dst, prev)
+	if dst.ZeroCount() < prev.ZeroCount() ||
+		!expo.Diff(dst.Positive(), prev.Positive()) ||
+		!expo.Diff(dst.Negative(), prev.Negative()) {
+		return false
+	}
+
+	dst.SetCount(cur.Count() - prev.Count())
+	dst.SetZeroCount(dst.ZeroCount() - prev.ZeroCount())
+	if cur.HasSum() && prev.HasSum() {
+		dst.SetSum(cur.Sum() - prev.Sum())
+	} else {
+		dst.RemoveSum()
+	}
+	dst.RemoveMin()
+	dst.RemoveMax()
+	return true
+}
+
+// align brings dst and src to the lowest of their scales and to the widest of their zero buckets.
+// It returns the aligned src, which is a copy of src whenever it needs to be changed.
+func align(dst, src pmetric.ExponentialHistogramDataPoint) pmetric.ExponentialHistogramDataPoint {
+	type H = pmetric.ExponentialHistogramDataPoint
+
+	if src.Scale() > dst.Scale() || src.ZeroThreshold() != dst.ZeroThreshold() {
+		cp := pmetric.NewExponentialHistogramDataPoint()
+		src.CopyTo(cp)
+		src = cp
+	}
+
+	// buckets of different scales are brought to the lower (coarser) one
+	if dst.Scale() != src.Scale() {
+		hi, lo := expo.HiLo(dst, src, H.Scale)
+		from, to := expo.Scale(hi.Scale()), expo.Scale(lo.Scale())
+		expo.Downscale(hi.Positive(), from, to)
+		expo.Downscale(hi.Negative(), from, to)
+		hi.SetScale(lo.Scale())
+	}
+
+	// zero buckets of different widths are brought to the wider one
+	if dst.ZeroThreshold() != src.ZeroThreshold() {
+		hi, lo := expo.HiLo(dst, src, H.ZeroThreshold)
+		expo.WidenZero(lo, hi.ZeroThreshold())
+		// the zero threshold may end up on the upper bound of the bucket it falls into
+		expo.WidenZero(hi, lo.ZeroThreshold())
+	}
+	return src
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregate // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/aggregate"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// ErrBoundsMismatch is returned when adding histograms which do not have the same explicit bounds.
+var ErrBoundsMismatch = errors.New("histograms with different explicit bounds cannot be aggregated")
+
+// AddHistogram adds the counts, the sum and the buckets of src to dst, and merges their min and max.
+// The sum, min and max are removed from dst unless both data points have them.
+func AddHistogram(dst, src pmetric.HistogramDataPoint) error {
+	if !equalBounds(dst.ExplicitBounds(), src.ExplicitBounds()) || dst.BucketCounts().Len() != src.BucketCounts().Len() {
+		return ErrBoundsMismatch
+	}
+
+	dst.SetCount(dst.Count() + src.Count())
+	for i := 0; i < dst.BucketCounts().Len(); i++ {
+		dst.BucketCounts().SetAt(i, dst.BucketCounts().At(i)+src.BucketCounts().At(i))
+	}
+
+	if dst.HasSum() && src.HasSum() {
+		dst.SetSum(dst.Sum() + src.Sum())
+	} else {
+		dst.RemoveSum()
+	}
+	if dst.HasMin() && src.HasMin() {
+		dst.SetMin(min(dst.Min(), src.Min()))
+	} else {
+		dst.RemoveMin()
+	}
+	if dst.HasMax() && src.HasMax() {
+		dst.SetMax(max(dst.Max(), src.Max()))
+	} else {
+		dst.RemoveMax()
+	}
+	return nil
+}
+
+// SubHistogram sets dst to the increase of the cumulative histogram from prev to cur.
+// It returns false, leaving dst untouched, if any count decreased or the bounds changed, meaning the stream was reset.
+// The min and max of the increase are unknown, they are not set.
+func SubHistogram(dst, cur, prev pmetric.HistogramDataPoint) bool {
+	if !equalBounds(cur.ExplicitBounds(), prev.ExplicitBounds()) || cur.BucketCounts().Len() != prev.BucketCounts().Len() {
+		return false
+	}
+	if cur.Count() < prev.Count() {
+		return false
+	}
+
+	counts := make([]uint64, cur.BucketCounts().Len())
+	for i := range counts {
+		c, p := cur.BucketCounts().At(i), prev.BucketCounts().At(i)
+		if c < p {
+			return false
+		}
+		counts[i] = c - p
+	}
+
+	dst.SetCount(cur.Count() - prev.Count())
+	dst.BucketCounts().FromRaw(counts)
+	cur.ExplicitBounds().CopyTo(dst.ExplicitBounds())
+	if cur.HasSum() && prev.HasSum() {
+		dst.SetSum(cur.Sum() - prev.Sum())
+	} else {
+		dst.RemoveSum()
+	}
+	dst.RemoveMin()
+	dst.RemoveMax()
+	return true
+}
+
+func equalBounds(a, b pcommon.Float64Slice) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i := 0; i < a.Len(); i++ {
+		if a.At(i) != b.At(i) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package aggregate implements the arithmetic on data points used to
+// aggregate several streams into one.
+package aggregate // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/aggregate"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Float returns the value of the data point as a float64, regardless of its type.
+func Float(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+// AddNumber adds the value of src to dst. The value of dst stays an int only if both values are ints.
+func AddNumber(dst, src pmetric.NumberDataPoint) {
+	if dst.ValueType() == pmetric.NumberDataPointValueTypeInt && src.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		dst.SetIntValue(dst.IntValue() + src.IntValue())
+		return
+	}
+	dst.SetDoubleValue(Float(dst) + Float(src))
+}
+
+// SubNumber sets dst to the increase of the cumulative value from prev to cur.
+// It returns false, leaving dst untouched, if the value decreased, meaning the stream was reset.
+func SubNumber(dst, cur, prev pmetric.NumberDataPoint) bool {
+	if cur.ValueType() == pmetric.NumberDataPointValueTypeInt && prev.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		if cur.IntValue() < prev.IntValue() {
+			return false
+		}
+		dst.SetIntValue(cur.IntValue() - prev.IntValue())
+		return true
+	}
+	if Float(cur) < Float(prev) {
+		return false
+	}
+	dst.SetDoubleValue(Float(cur) - Float(prev))
+	return true
+}
@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package expo implements various operations on exponential histograms and their bucket counts
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+
+import (
+	"cmp"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type (
+	DataPoint = pmetric.ExponentialHistogramDataPoint
+	Buckets   = pmetric.ExponentialHistogramDataPointBuckets
+)
+
+// HiLo returns the greater of a and b by comparing the result of applying fn to each.
+// If equal, returns operands as passed
+func HiLo[T any, N cmp.Ordered](a, b T, fn func(T) N) (hi, lo T) {
+	an, bn := fn(a), fn(b)
+	if cmp.Less(an, bn) {
+		return b, a
+	}
+	return a, b
+}
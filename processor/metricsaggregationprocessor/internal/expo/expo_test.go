@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo_test
+
+import (
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+// bkt returns buckets starting at offset with the given counts
+func bkt(offset int32, counts ...uint64) expo.Buckets {
+	bs := pmetric.NewExponentialHistogramDataPointBuckets()
+	bs.SetOffset(offset)
+	bs.BucketCounts().FromRaw(counts)
+	return bs
+}
+
+type bins struct {
+	Offset int32
+	Counts []uint64
+}
+
+func binsOf(bs expo.Buckets) bins {
+	b := bins{Offset: bs.Offset()}
+	if bs.BucketCounts().Len() > 0 {
+		b.Counts = bs.BucketCounts().AsRaw()
+	}
+	return b
+}
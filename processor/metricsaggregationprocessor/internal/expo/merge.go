@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+
+// Merge combines the counts of buckets a and b into a.
+// Both buckets MUST be of same scale
+func Merge(arel, brel Buckets) {
+	if brel.BucketCounts().Len() == 0 {
+		return
+	}
+	if arel.BucketCounts().Len() == 0 {
+		brel.CopyTo(arel)
+		return
+	}
+
+	a, b := arel.BucketCounts(), brel.BucketCounts()
+
+	lo := min(arel.Offset(), brel.Offset())
+	hi := max(arel.Offset()+int32(a.Len()), brel.Offset()+int32(b.Len()))
+
+	out := make([]uint64, hi-lo)
+	for i := 0; i < a.Len(); i++ {
+		out[arel.Offset()-lo+int32(i)] += a.At(i)
+	}
+	for i := 0; i < b.Len(); i++ {
+		out[brel.Offset()-lo+int32(i)] += b.At(i)
+	}
+
+	arel.SetOffset(lo)
+	a.FromRaw(out)
+}
+
+// Diff subtracts the counts of buckets b from a.
+// Both buckets MUST be of same scale. It returns false, leaving a untouched,
+// if any count of b is greater than the count of the same bucket in a.
+func Diff(arel, brel Buckets) bool {
+	a, b := arel.BucketCounts(), brel.BucketCounts()
+	at := func(i int) int { return int(brel.Offset()-arel.Offset()) + i }
+
+	for i := 0; i < b.Len(); i++ {
+		if b.At(i) == 0 {
+			continue
+		}
+		if j := at(i); j < 0 || j >= a.Len() || a.At(j) < b.At(i) {
+			return false
+		}
+	}
+	for i := 0; i < b.Len(); i++ {
+		if b.At(i) != 0 {
+			a.SetAt(at(i), a.At(at(i))-b.At(i))
+		}
+	}
+	return true
+}
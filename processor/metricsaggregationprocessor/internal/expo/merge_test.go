@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b bins
+		want bins
+	}{
+		{
+			name: "overlap",
+			a:    bins{Offset: 0, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 1, Counts: []uint64{1, 1, 1}},
+			want: bins{Offset: 0, Counts: []uint64{1, 3, 1, 1}},
+		},
+		{
+			name: "gap",
+			a:    bins{Offset: 0, Counts: []uint64{1}},
+			b:    bins{Offset: -2, Counts: []uint64{5}},
+			want: bins{Offset: -2, Counts: []uint64{5, 0, 1}},
+		},
+		{
+			name: "empty-a",
+			a:    bins{Offset: 0},
+			b:    bins{Offset: 3, Counts: []uint64{1, 2}},
+			want: bins{Offset: 3, Counts: []uint64{1, 2}},
+		},
+		{
+			name: "empty-b",
+			a:    bins{Offset: 3, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 0},
+			want: bins{Offset: 3, Counts: []uint64{1, 2}},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			a, b := bkt(cs.a.Offset, cs.a.Counts...), bkt(cs.b.Offset, cs.b.Counts...)
+			expo.Merge(a, b)
+			require.Equal(t, cs.want, binsOf(a))
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b bins
+		want bins
+		ok   bool
+	}{
+		{
+			name: "overlap",
+			a:    bins{Offset: 0, Counts: []uint64{1, 3, 1, 1}},
+			b:    bins{Offset: 1, Counts: []uint64{1, 1, 1}},
+			want: bins{Offset: 0, Counts: []uint64{1, 2, 0, 0}},
+			ok:   true,
+		},
+		{
+			name: "empty-b",
+			a:    bins{Offset: 3, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 0},
+			want: bins{Offset: 3, Counts: []uint64{1, 2}},
+			ok:   true,
+		},
+		{
+			name: "zero-outside",
+			a:    bins{Offset: 3, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 0, Counts: []uint64{0, 0, 0, 1}},
+			want: bins{Offset: 3, Counts: []uint64{0, 2}},
+			ok:   true,
+		},
+		{
+			name: "negative",
+			a:    bins{Offset: 0, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 0, Counts: []uint64{1, 3}},
+			want: bins{Offset: 0, Counts: []uint64{1, 2}},
+		},
+		{
+			name: "outside",
+			a:    bins{Offset: 0, Counts: []uint64{1, 2}},
+			b:    bins{Offset: 2, Counts: []uint64{1}},
+			want: bins{Offset: 0, Counts: []uint64{1, 2}},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			a, b := bkt(cs.a.Offset, cs.a.Counts...), bkt(cs.b.Offset, cs.b.Counts...)
+			require.Equal(t, cs.ok, expo.Diff(a, b))
+			require.Equal(t, cs.want, binsOf(a))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+func TestIdx(t *testing.T) {
+	cases := []struct {
+		scale expo.Scale
+		value float64
+		idx   int32
+	}{
+		// base 2: (0.5,1], (1,2], (2,4]
+		{scale: 0, value: 1, idx: -1},
+		{scale: 0, value: 2, idx: 0},
+		{scale: 0, value: 3, idx: 1},
+		{scale: 0, value: 4, idx: 1},
+		// base √2: (1,√2], (√2,2]
+		{scale: 1, value: 1.2, idx: 0},
+		{scale: 1, value: 2, idx: 1},
+		// base 4: (1,4], (4,16]
+		{scale: -1, value: 4, idx: 0},
+		{scale: -1, value: 5, idx: 1},
+	}
+
+	for _, cs := range cases {
+		t.Run(fmt.Sprintf("%d/%g", cs.scale, cs.value), func(t *testing.T) {
+			idx := cs.scale.Idx(cs.value)
+			require.Equal(t, cs.idx, idx)
+
+			lo, hi := cs.scale.Bounds(idx)
+			require.Less(t, lo, cs.value)
+			require.LessOrEqual(t, cs.value, hi)
+		})
+	}
+}
+
+func TestDownscale(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to expo.Scale
+		in       bins
+		want     bins
+	}{
+		{
+			name: "pairs",
+			from: 1, to: 0,
+			in:   bins{Offset: 0, Counts: []uint64{1, 2, 3, 4}},
+			want: bins{Offset: 0, Counts: []uint64{3, 7}},
+		},
+		{
+			name: "odd-offset",
+			from: 1, to: 0,
+			in:   bins{Offset: 1, Counts: []uint64{1, 2, 3}},
+			want: bins{Offset: 0, Counts: []uint64{1, 5}},
+		},
+		{
+			name: "negative-offset",
+			from: 2, to: 1,
+			in:   bins{Offset: -3, Counts: []uint64{1, 1, 1, 1}},
+			want: bins{Offset: -2, Counts: []uint64{1, 2, 1}},
+		},
+		{
+			name: "multiple-steps",
+			from: 3, to: 1,
+			in:   bins{Offset: 1, Counts: []uint64{1, 2, 3, 4}},
+			want: bins{Offset: 0, Counts: []uint64{6, 4}},
+		},
+		{
+			name: "empty",
+			from: 1, to: 0,
+			in:   bins{Offset: 5},
+			want: bins{Offset: 2},
+		},
+		{
+			name: "same-scale",
+			from: 2, to: 2,
+			in:   bins{Offset: 3, Counts: []uint64{1, 2}},
+			want: bins{Offset: 3, Counts: []uint64{1, 2}},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			bs := bkt(cs.in.Offset, cs.in.Counts...)
+			expo.Downscale(bs, cs.from, cs.to)
+			require.Equal(t, cs.want, binsOf(bs))
+		})
+	}
+
+	t.Run("upscale", func(t *testing.T) {
+		require.Panics(t, func() {
+			expo.Downscale(bkt(0, 1), 0, 1)
+		})
+	})
+}
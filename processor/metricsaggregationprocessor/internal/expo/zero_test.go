@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/expo"
+)
+
+func TestWidenZero(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(0)
+	dp.SetZeroCount(1)
+	// (0.5,1], (1,2], (2,4]
+	bkt(-1, 1, 2, 3).CopyTo(dp.Positive())
+	// [-2,-1)
+	bkt(0, 4).CopyTo(dp.Negative())
+
+	// 1.5 falls into (1,2], so the zero bucket is widened to [-2,2]
+	expo.WidenZero(dp, 1.5)
+
+	require.Equal(t, 2.0, dp.ZeroThreshold())
+	require.Equal(t, uint64(1+1+2+4), dp.ZeroCount())
+	require.Equal(t, bins{Offset: 1, Counts: []uint64{3}}, binsOf(dp.Positive()))
+	require.Equal(t, bins{Offset: 1}, binsOf(dp.Negative()))
+
+	// widening to the current threshold is a no-op
+	expo.WidenZero(dp, 2)
+	require.Equal(t, uint64(8), dp.ZeroCount())
+
+	require.Panics(t, func() {
+		expo.WidenZero(dp, 1)
+	})
+}
@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+)
+
+// We override how now is returned, so we can have deterministic tests
+var nowFunc = time.Now
+
+var _ processor.Metrics = (*Processor)(nil)
+
+type Processor struct {
+	next consumer.Metrics
+
+	log    *zap.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	interval     time.Duration
+	maxStaleness time.Duration
+	rules        []rule
+
+	metrics map[identity.Metric]aggregator
+	mtx     sync.Mutex
+}
+
+func newProcessor(cfg *Config, log *zap.Logger, next consumer.Metrics) *Processor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rules := make([]rule, 0, len(cfg.Aggregations))
+	for _, a := range cfg.Aggregations {
+		rules = append(rules, newRule(a))
+	}
+
+	return &Processor{
+		next:         next,
+		log:          log,
+		ctx:          ctx,
+		cancel:       cancel,
+		interval:     cfg.Interval,
+		maxStaleness: cfg.MaxStaleness,
+		rules:        rules,
+		metrics:      make(map[identity.Metric]aggregator),
+	}
+}
+
+func (p *Processor) Start(_ context.Context, _ component.Host) error {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		tick := time.NewTicker(p.interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-tick.C:
+				if err := p.export(p.ctx); err != nil {
+					p.log.Error("failed to export the aggregated metrics", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the periodic export and exports what was aggregated since the last one.
+func (p *Processor) Shutdown(ctx context.Context) error {
+	p.cancel()
+	p.wg.Wait()
+	return p.export(ctx)
+}
+
+func (p *Processor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+// ConsumeMetrics aggregates the metrics matching a rule, and forwards the others.
+func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	p.mtx.Lock()
+	now := nowFunc()
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				r, ok := p.match(m)
+				if !ok {
+					return false
+				}
+				p.aggregate(rm.Resource(), sm.Scope(), m, r, now)
+				return true
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	p.mtx.Unlock()
+
+	if md.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	return p.next.ConsumeMetrics(ctx, md)
+}
+
+// match returns the first rule matching the metric. Summaries cannot be aggregated, they never match.
+func (p *Processor) match(m pmetric.Metric) (rule, bool) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum, pmetric.MetricTypeHistogram, pmetric.MetricTypeExponentialHistogram:
+	default:
+		return rule{}, false
+	}
+
+	for _, r := range p.rules {
+		if r.matches(m.Name()) {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+func (p *Processor) aggregate(res pcommon.Resource, scope pcommon.InstrumentationScope, m pmetric.Metric, r rule, now time.Time) {
+	out := pmetric.NewMetric()
+	out.SetName(r.metricName(m.Name()))
+	out.SetDescription(m.Description())
+	out.SetUnit(m.Unit())
+
+	var errs error
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		out.SetEmptyGauge()
+		state := loadState(p, res, scope, out, r, numberOps, combineNumbers(r.gaugeAggregation))
+		errs = observeAll(state, m.Gauge().DataPoints(), now)
+	case pmetric.MetricTypeSum:
+		sum := out.SetEmptySum()
+		sum.SetIsMonotonic(m.Sum().IsMonotonic())
+		sum.SetAggregationTemporality(m.Sum().AggregationTemporality())
+		var combine func(pmetric.NumberDataPoint, []pmetric.NumberDataPoint)
+		if sum.AggregationTemporality() == pmetric.AggregationTemporalityCumulative && !sum.IsMonotonic() {
+			combine = combineNumbers(AggregationTypeSum)
+		}
+		state := loadState(p, res, scope, out, r, numberOps, combine)
+		errs = observeAll(state, m.Sum().DataPoints(), now)
+	case pmetric.MetricTypeHistogram:
+		out.SetEmptyHistogram().SetAggregationTemporality(m.Histogram().AggregationTemporality())
+		state := loadState(p, res, scope, out, r, histogramOps, nil)
+		errs = observeAll(state, m.Histogram().DataPoints(), now)
+	case pmetric.MetricTypeExponentialHistogram:
+		out.SetEmptyExponentialHistogram().SetAggregationTemporality(m.ExponentialHistogram().AggregationTemporality())
+		state := loadState(p, res, scope, out, r, exponentialHistogramOps, nil)
+		errs = observeAll(state, m.ExponentialHistogram().DataPoints(), now)
+	}
+
+	if errs != nil {
+		p.log.Warn("dropped data points which cannot be aggregated", zap.String("metric", m.Name()), zap.Error(errs))
+	}
+}
+
+// loadState returns the state of the aggregated metric, creating it if needed.
+// The aggregated values are computed from the last values of the input streams with combine, unless it is nil.
+func loadState[P point[P]](p *Processor, res pcommon.Resource, scope pcommon.InstrumentationScope, out pmetric.Metric, r rule, o ops[P], combine func(P, []P)) *metricState[P] {
+	id := identity.OfResourceMetric(res, scope, out)
+	if state, ok := p.metrics[id]; ok {
+		return state.(*metricState[P])
+	}
+
+	state := &metricState[P]{
+		id:         id,
+		resource:   pcommon.NewResource(),
+		scope:      pcommon.NewInstrumentationScope(),
+		metric:     out,
+		rule:       r,
+		ops:        o,
+		delta:      temporality(out) == pmetric.AggregationTemporalityDelta,
+		lastValues: combine != nil,
+		combine:    combine,
+		series:     make(map[identity.Stream]*series[P]),
+	}
+	res.CopyTo(state.resource)
+	scope.CopyTo(state.scope)
+	p.metrics[id] = state
+	return state
+}
+
+func observeAll[P point[P], S interface {
+	Len() int
+	At(int) P
+}](state *metricState[P], dps S, now time.Time) error {
+	var errs error
+	for i := 0; i < dps.Len(); i++ {
+		errs = errors.Join(errs, state.observe(dps.At(i), now))
+	}
+	return errs
+}
+
+func temporality(m pmetric.Metric) pmetric.AggregationTemporality {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().AggregationTemporality()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().AggregationTemporality()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().AggregationTemporality()
+	}
+	return pmetric.AggregationTemporalityUnspecified
+}
+
+// export sends the aggregated metrics to the next consumer.
+func (p *Processor) export(ctx context.Context) error {
+	md := p.flush(nowFunc())
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	return p.next.ConsumeMetrics(ctx, md)
+}
+
+// flush forgets the input streams which became stale, then returns the aggregated metrics.
+func (p *Processor) flush(now time.Time) pmetric.Metrics {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	md := pmetric.NewMetrics()
+	scopes := make(map[identity.Scope]pmetric.ScopeMetrics)
+	resources := make(map[identity.Resource]pmetric.ResourceMetrics)
+
+	for id, state := range p.metrics {
+		if p.maxStaleness > 0 && state.expire(now.Add(-p.maxStaleness)) {
+			delete(p.metrics, id)
+			continue
+		}
+
+		sm, ok := scopes[id.Scope()]
+		if !ok {
+			rm, ok := resources[id.Scope().Resource()]
+			if !ok {
+				rm = md.ResourceMetrics().AppendEmpty()
+				res, _ := state.source()
+				res.CopyTo(rm.Resource())
+				resources[id.Scope().Resource()] = rm
+			}
+			sm = rm.ScopeMetrics().AppendEmpty()
+			_, scope := state.source()
+			scope.CopyTo(sm.Scope())
+			scopes[id.Scope()] = sm
+		}
+		state.flush(sm.Metrics().AppendEmpty(), now)
+	}
+
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				return dataPointCount(m) == 0
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return md
+}
+
+func dataPointCount(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	}
+	return 0
+}
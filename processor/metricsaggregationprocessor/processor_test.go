@@ -0,0 +1,341 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+var start = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestProcessor(t *testing.T, next *consumertest.MetricsSink, aggregations ...AggregationConfig) *Processor {
+	cfg := &Config{
+		Interval:     time.Minute,
+		MaxStaleness: 5 * time.Minute,
+		Aggregations: aggregations,
+	}
+	require.NoError(t, cfg.Validate())
+	return newProcessor(cfg, zap.NewNop(), next)
+}
+
+// setNow makes the processor observe the data points at the given time.
+func setNow(t *testing.T, now time.Time) {
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = time.Now })
+}
+
+func newMetrics(fn func(m pmetric.Metric)) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("test")
+	fn(sm.Metrics().AppendEmpty())
+	return md
+}
+
+func cumulativeCounter(points map[string]int64, starts map[string]time.Time, ts time.Time) pmetric.Metrics {
+	return newMetrics(func(m pmetric.Metric) {
+		m.SetName("requests")
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for pod, v := range points {
+			dp := sum.DataPoints().AppendEmpty()
+			dp.Attributes().PutStr("pod", pod)
+			dp.Attributes().PutStr("route", "/cart")
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(starts[pod]))
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+			dp.SetIntValue(v)
+		}
+	})
+}
+
+// single returns the only metric of md.
+func single(t *testing.T, md pmetric.Metrics) pmetric.Metric {
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	require.Equal(t, 1, rm.ScopeMetrics().Len())
+	sm := rm.ScopeMetrics().At(0)
+	require.Equal(t, 1, sm.Metrics().Len())
+	return sm.Metrics().At(0)
+}
+
+func TestAggregateCumulativeSum(t *testing.T) {
+	p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{Include: "requests", KeepAttributes: []string{"route"}})
+	ctx := context.Background()
+	before := start.Add(-time.Hour)
+	starts := map[string]time.Time{"a": before, "b": before}
+
+	// the streams started before they were aggregated, they are the baseline of the aggregated stream
+	setNow(t, start)
+	require.NoError(t, p.ConsumeMetrics(ctx, cumulativeCounter(map[string]int64{"a": 10, "b": 20}, starts, start)))
+	m := single(t, p.flush(start.Add(time.Minute)))
+	assert.Equal(t, "requests", m.Name())
+	assert.True(t, m.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, m.Sum().AggregationTemporality())
+	require.Equal(t, 1, m.Sum().DataPoints().Len())
+	dp := m.Sum().DataPoints().At(0)
+	assert.Equal(t, map[string]any{"route": "/cart"}, dp.Attributes().AsRaw())
+	assert.Equal(t, pcommon.NewTimestampFromTime(start), dp.StartTimestamp())
+	assert.Equal(t, pcommon.NewTimestampFromTime(start.Add(time.Minute)), dp.Timestamp())
+	assert.Equal(t, int64(0), dp.IntValue())
+
+	setNow(t, start.Add(time.Minute))
+	require.NoError(t, p.ConsumeMetrics(ctx, cumulativeCounter(map[string]int64{"a": 15, "b": 26}, starts, start.Add(time.Minute))))
+	dp = single(t, p.flush(start.Add(2*time.Minute))).Sum().DataPoints().At(0)
+	assert.Equal(t, int64(11), dp.IntValue())
+
+	// a restarted, and c started after the aggregated stream: their whole values are increases
+	starts["a"] = start.Add(90 * time.Second)
+	starts["c"] = start.Add(90 * time.Second)
+	setNow(t, start.Add(2*time.Minute))
+	require.NoError(t, p.ConsumeMetrics(ctx, cumulativeCounter(map[string]int64{"a": 3, "b": 26, "c": 5}, starts, start.Add(2*time.Minute))))
+	dp = single(t, p.flush(start.Add(3*time.Minute))).Sum().DataPoints().At(0)
+	assert.Equal(t, int64(19), dp.IntValue())
+	assert.Equal(t, pcommon.NewTimestampFromTime(start), dp.StartTimestamp())
+}
+
+func TestAggregateDeltaSum(t *testing.T) {
+	p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{Include: "requests", DropAttributes: []string{"pod"}})
+	setNow(t, start)
+
+	md := newMetrics(func(m pmetric.Metric) {
+		m.SetName("requests")
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for i, pod := range []string{"a", "b", "a"} {
+			dp := sum.DataPoints().AppendEmpty()
+			dp.Attributes().PutStr("pod", pod)
+			dp.Attributes().PutStr("route", "/cart")
+			dp.SetDoubleValue(float64(i + 1))
+		}
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	m := single(t, p.flush(start.Add(time.Minute)))
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+	require.Equal(t, 1, m.Sum().DataPoints().Len())
+	dp := m.Sum().DataPoints().At(0)
+	assert.Equal(t, map[string]any{"route": "/cart"}, dp.Attributes().AsRaw())
+	assert.Equal(t, 6.0, dp.DoubleValue())
+	assert.Equal(t, pcommon.NewTimestampFromTime(start), dp.StartTimestamp())
+
+	// nothing was received since the last export
+	assert.Equal(t, 0, p.flush(start.Add(2*time.Minute)).DataPointCount())
+}
+
+func TestAggregateGauge(t *testing.T) {
+	tests := []struct {
+		aggregation AggregationType
+		expected    func(t *testing.T, dp pmetric.NumberDataPoint)
+	}{
+		{
+			aggregation: AggregationTypeSum,
+			expected: func(t *testing.T, dp pmetric.NumberDataPoint) {
+				assert.Equal(t, int64(9), dp.IntValue())
+			},
+		},
+		{
+			aggregation: AggregationTypeMean,
+			expected: func(t *testing.T, dp pmetric.NumberDataPoint) {
+				assert.Equal(t, 3.0, dp.DoubleValue())
+			},
+		},
+		{
+			aggregation: AggregationTypeMin,
+			expected: func(t *testing.T, dp pmetric.NumberDataPoint) {
+				assert.Equal(t, int64(1), dp.IntValue())
+			},
+		},
+		{
+			aggregation: AggregationTypeMax,
+			expected: func(t *testing.T, dp pmetric.NumberDataPoint) {
+				assert.Equal(t, int64(6), dp.IntValue())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{
+				Include:          "queue.size",
+				NewName:          "queue.size.total",
+				KeepAttributes:   []string{"queue"},
+				GaugeAggregation: tt.aggregation,
+			})
+			setNow(t, start)
+
+			// only the last value of each input stream is aggregated
+			for _, values := range [][]int64{{4, 2, 1}, {6, 2}} {
+				md := newMetrics(func(m pmetric.Metric) {
+					m.SetName("queue.size")
+					gauge := m.SetEmptyGauge()
+					for i, v := range values {
+						dp := gauge.DataPoints().AppendEmpty()
+						dp.Attributes().PutStr("queue", "orders")
+						dp.Attributes().PutInt("worker", int64(i))
+						dp.SetTimestamp(pcommon.NewTimestampFromTime(start))
+						dp.SetIntValue(v)
+					}
+				})
+				require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+			}
+
+			m := single(t, p.flush(start.Add(time.Minute)))
+			assert.Equal(t, "queue.size.total", m.Name())
+			require.Equal(t, 1, m.Gauge().DataPoints().Len())
+			dp := m.Gauge().DataPoints().At(0)
+			assert.Equal(t, map[string]any{"queue": "orders"}, dp.Attributes().AsRaw())
+			assert.Equal(t, pcommon.Timestamp(0), dp.StartTimestamp())
+			tt.expected(t, dp)
+		})
+	}
+}
+
+func TestAggregateHistogram(t *testing.T) {
+	p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{Include: "duration"})
+	setNow(t, start)
+
+	md := newMetrics(func(m pmetric.Metric) {
+		m.SetName("duration")
+		hist := m.SetEmptyHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for i, pod := range []string{"a", "b"} {
+			dp := hist.DataPoints().AppendEmpty()
+			dp.Attributes().PutStr("pod", pod)
+			dp.ExplicitBounds().FromRaw([]float64{10, 100})
+			dp.BucketCounts().FromRaw([]uint64{1, uint64(i), 2})
+			dp.SetCount(3 + uint64(i))
+			dp.SetSum(float64(300 + i*50))
+			dp.SetMin(float64(1 + i))
+			dp.SetMax(float64(200 + i*100))
+		}
+		// different bounds cannot be aggregated, the data point is dropped
+		dp := hist.DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("pod", "c")
+		dp.ExplicitBounds().FromRaw([]float64{50})
+		dp.BucketCounts().FromRaw([]uint64{1, 1})
+		dp.SetCount(2)
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	m := single(t, p.flush(start.Add(time.Minute)))
+	require.Equal(t, 1, m.Histogram().DataPoints().Len())
+	dp := m.Histogram().DataPoints().At(0)
+	assert.Equal(t, map[string]any{}, dp.Attributes().AsRaw())
+	assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{2, 1, 4}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(7), dp.Count())
+	assert.Equal(t, 650.0, dp.Sum())
+	assert.Equal(t, 1.0, dp.Min())
+	assert.Equal(t, 300.0, dp.Max())
+}
+
+func TestAggregateExponentialHistogram(t *testing.T) {
+	p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{Include: "duration", KeepAttributes: []string{"route"}})
+	setNow(t, start)
+
+	md := newMetrics(func(m pmetric.Metric) {
+		m.SetName("duration")
+		hist := m.SetEmptyExponentialHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+		dp := hist.DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("pod", "a")
+		dp.SetScale(1)
+		dp.Positive().SetOffset(0)
+		dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 3, 4})
+		dp.SetZeroCount(1)
+		dp.SetCount(11)
+		dp.SetSum(100)
+
+		dp = hist.DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("pod", "b")
+		dp.SetScale(0)
+		dp.Positive().SetOffset(1)
+		dp.Positive().BucketCounts().FromRaw([]uint64{5})
+		dp.SetCount(5)
+		dp.SetSum(20)
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	dp := single(t, p.flush(start.Add(time.Minute))).ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(0), dp.Scale())
+	assert.Equal(t, int32(0), dp.Positive().Offset())
+	assert.Equal(t, []uint64{3, 12}, dp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+	assert.Equal(t, uint64(16), dp.Count())
+	assert.Equal(t, 120.0, dp.Sum())
+}
+
+func TestForwardNotAggregated(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	p := newTestProcessor(t, sink, AggregationConfig{Include: "^queue\\.", MatchType: MatchTypeRegexp})
+	setNow(t, start)
+
+	md := newMetrics(func(m pmetric.Metric) {
+		m.SetName("queue.size")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	assert.Empty(t, sink.AllMetrics())
+
+	md = newMetrics(func(m pmetric.Metric) {
+		m.SetName("latency")
+		m.SetEmptySummary().DataPoints().AppendEmpty().SetCount(1)
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, "latency", single(t, sink.AllMetrics()[0]).Name())
+}
+
+func TestExpireStaleStreams(t *testing.T) {
+	p := newTestProcessor(t, new(consumertest.MetricsSink), AggregationConfig{Include: "queue.size"})
+
+	for i, now := range []time.Time{start, start.Add(3 * time.Minute)} {
+		setNow(t, now)
+		md := newMetrics(func(m pmetric.Metric) {
+			m.SetName("queue.size")
+			dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+			dp.Attributes().PutInt("worker", int64(i))
+			dp.SetIntValue(int64(i + 1))
+		})
+		require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	}
+
+	dp := single(t, p.flush(start.Add(4*time.Minute))).Gauge().DataPoints().At(0)
+	assert.Equal(t, int64(3), dp.IntValue())
+
+	// the first stream is stale
+	dp = single(t, p.flush(start.Add(6*time.Minute))).Gauge().DataPoints().At(0)
+	assert.Equal(t, int64(2), dp.IntValue())
+
+	assert.Equal(t, 0, p.flush(start.Add(9*time.Minute)).DataPointCount())
+	assert.Empty(t, p.metrics)
+}
+
+func TestShutdownExportsAggregatedMetrics(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	p := newTestProcessor(t, sink, AggregationConfig{Include: "queue.size"})
+
+	md := newMetrics(func(m pmetric.Metric) {
+		m.SetName("queue.size")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 1, sink.DataPointCount())
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor"
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// rule is the compiled form of an AggregationConfig.
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+	newName string
+
+	// attributes are the attributes kept, or dropped when drop is set
+	attributes map[string]struct{}
+	drop       bool
+
+	gaugeAggregation AggregationType
+}
+
+func newRule(cfg AggregationConfig) rule {
+	r := rule{
+		name:             cfg.Include,
+		newName:          cfg.NewName,
+		gaugeAggregation: cfg.GaugeAggregation,
+	}
+	if cfg.MatchType == MatchTypeRegexp {
+		r.pattern = regexp.MustCompile(cfg.Include)
+	}
+	if r.gaugeAggregation == "" {
+		r.gaugeAggregation = AggregationTypeSum
+	}
+
+	names := cfg.KeepAttributes
+	if len(cfg.DropAttributes) > 0 {
+		names = cfg.DropAttributes
+		r.drop = true
+	}
+	r.attributes = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		r.attributes[name] = struct{}{}
+	}
+	return r
+}
+
+func (r rule) matches(name string) bool {
+	if r.pattern != nil {
+		return r.pattern.MatchString(name)
+	}
+	return r.name == name
+}
+
+// metricName returns the name of the aggregated metric.
+func (r rule) metricName(name string) string {
+	if r.newName != "" {
+		return r.newName
+	}
+	return name
+}
+
+// filterAttributes copies the attributes of src the aggregated streams keep to dst.
+func (r rule) filterAttributes(src, dst pcommon.Map) {
+	src.Range(func(k string, v pcommon.Value) bool {
+		if _, ok := r.attributes[k]; ok != r.drop {
+			v.CopyTo(dst.PutEmpty(k))
+		}
+		return true
+	})
+}
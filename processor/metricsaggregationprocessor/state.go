@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricsaggregationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsaggregationprocessor/internal/aggregate"
+)
+
+// point is implemented by the data points of all aggregated metric types.
+type point[Self any] interface {
+	Attributes() pcommon.Map
+	StartTimestamp() pcommon.Timestamp
+	SetStartTimestamp(pcommon.Timestamp)
+	Timestamp() pcommon.Timestamp
+	SetTimestamp(pcommon.Timestamp)
+	CopyTo(Self)
+}
+
+// ops are the operations on the data points of a metric type.
+type ops[P point[P]] struct {
+	new func() P
+	// add adds src to dst
+	add func(dst, src P) error
+	// sub sets dst to the increase from prev to cur, it returns false if the stream was reset
+	sub func(dst, cur, prev P) bool
+	// appendTo appends an empty data point to the metric
+	appendTo func(m pmetric.Metric) P
+}
+
+var (
+	numberOps = ops[pmetric.NumberDataPoint]{
+		new: pmetric.NewNumberDataPoint,
+		add: func(dst, src pmetric.NumberDataPoint) error {
+			aggregate.AddNumber(dst, src)
+			return nil
+		},
+		sub: aggregate.SubNumber,
+		appendTo: func(m pmetric.Metric) pmetric.NumberDataPoint {
+			if m.Type() == pmetric.MetricTypeGauge {
+				return m.Gauge().DataPoints().AppendEmpty()
+			}
+			return m.Sum().DataPoints().AppendEmpty()
+		},
+	}
+	histogramOps = ops[pmetric.HistogramDataPoint]{
+		new:      pmetric.NewHistogramDataPoint,
+		add:      aggregate.AddHistogram,
+		sub:      aggregate.SubHistogram,
+		appendTo: func(m pmetric.Metric) pmetric.HistogramDataPoint { return m.Histogram().DataPoints().AppendEmpty() },
+	}
+	exponentialHistogramOps = ops[pmetric.ExponentialHistogramDataPoint]{
+		new: pmetric.NewExponentialHistogramDataPoint,
+		add: func(dst, src pmetric.ExponentialHistogramDataPoint) error {
+			aggregate.AddExponentialHistogram(dst, src)
+			return nil
+		},
+		sub: aggregate.SubExponentialHistogram,
+		appendTo: func(m pmetric.Metric) pmetric.ExponentialHistogramDataPoint {
+			return m.ExponentialHistogram().DataPoints().AppendEmpty()
+		},
+	}
+)
+
+// aggregator holds the state of an aggregated metric.
+type aggregator interface {
+	// flush appends the aggregated data points to dst.
+	flush(dst pmetric.Metric, now time.Time)
+	// expire removes the input streams not seen since the deadline, and returns true if nothing is left to export.
+	expire(deadline time.Time) bool
+	// source returns the resource and the scope of the aggregated metric.
+	source() (pcommon.Resource, pcommon.InstrumentationScope)
+}
+
+var (
+	_ aggregator = (*metricState[pmetric.NumberDataPoint])(nil)
+	_ aggregator = (*metricState[pmetric.HistogramDataPoint])(nil)
+	_ aggregator = (*metricState[pmetric.ExponentialHistogramDataPoint])(nil)
+)
+
+type metricState[P point[P]] struct {
+	id       identity.Metric
+	resource pcommon.Resource
+	scope    pcommon.InstrumentationScope
+	// metric is the aggregated metric, without data points
+	metric pmetric.Metric
+	rule   rule
+	ops    ops[P]
+
+	delta bool
+	// lastValues is set when the aggregated value is computed from the last values of the input streams,
+	// which is the case of gauges and of non-monotonic cumulative sums, using combine.
+	lastValues bool
+	combine    func(dst P, values []P)
+
+	series map[identity.Stream]*series[P]
+}
+
+// series is an aggregated stream, made of all the input streams having the same kept attributes.
+type series[P point[P]] struct {
+	attrs pcommon.Map
+	start pcommon.Timestamp
+
+	// acc is the accumulated value of the series, unless it is computed from the last values of its inputs.
+	acc    P
+	hasAcc bool
+
+	inputs map[identity.Stream]*input[P]
+}
+
+type input[P point[P]] struct {
+	// last is the last data point of the stream, unless it is a delta stream.
+	last P
+	seen time.Time
+}
+
+// streamAttributes allows to compute the identity of an aggregated stream from its attributes.
+type streamAttributes struct {
+	attrs pcommon.Map
+}
+
+func (a streamAttributes) Attributes() pcommon.Map {
+	return a.attrs
+}
+
+// observe aggregates the data point of an input stream.
+func (m *metricState[P]) observe(dp P, now time.Time) error {
+	attrs := pcommon.NewMap()
+	m.rule.filterAttributes(dp.Attributes(), attrs)
+	key := identity.OfStream(m.id, streamAttributes{attrs: attrs})
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &series[P]{
+			attrs:  attrs,
+			start:  pcommon.NewTimestampFromTime(now),
+			inputs: make(map[identity.Stream]*input[P]),
+		}
+		m.series[key] = s
+	}
+
+	inKey := identity.OfStream(m.id, dp)
+	in, known := s.inputs[inKey]
+	if !known {
+		in = &input[P]{}
+		s.inputs[inKey] = in
+	}
+	in.seen = now
+
+	switch {
+	case m.delta:
+		return m.accumulate(s, dp)
+	case !known:
+		in.last = m.ops.new()
+		dp.CopyTo(in.last)
+		if m.lastValues {
+			return nil
+		}
+
+		increase := dp
+		if dp.StartTimestamp() == 0 || dp.StartTimestamp() < s.start {
+			// the stream started before the aggregated one, only its increase from now on is accounted for
+			increase = m.ops.new()
+			m.ops.sub(increase, dp, dp)
+		}
+		return m.accumulate(s, increase)
+	case dp.Timestamp() < in.last.Timestamp():
+		// out of order
+		return nil
+	case m.lastValues:
+		dp.CopyTo(in.last)
+		return nil
+	default:
+		increase := m.ops.new()
+		if dp.StartTimestamp() != in.last.StartTimestamp() || !m.ops.sub(increase, dp, in.last) {
+			// the stream was reset, its whole value is an increase
+			increase = dp
+		}
+		dp.CopyTo(in.last)
+		return m.accumulate(s, increase)
+	}
+}
+
+func (m *metricState[P]) accumulate(s *series[P], dp P) error {
+	if !s.hasAcc {
+		s.acc = m.ops.new()
+		dp.CopyTo(s.acc)
+		s.hasAcc = true
+		return nil
+	}
+	return m.ops.add(s.acc, dp)
+}
+
+func (m *metricState[P]) flush(dst pmetric.Metric, now time.Time) {
+	m.metric.CopyTo(dst)
+	ts := pcommon.NewTimestampFromTime(now)
+
+	for _, s := range m.series {
+		var dp P
+		switch {
+		case m.lastValues:
+			values := make([]P, 0, len(s.inputs))
+			for _, in := range s.inputs {
+				values = append(values, in.last)
+			}
+			dp = m.ops.appendTo(dst)
+			m.combine(dp, values)
+		case s.hasAcc:
+			dp = m.ops.appendTo(dst)
+			s.acc.CopyTo(dp)
+		default:
+			// no delta was received during the interval
+			continue
+		}
+
+		s.attrs.CopyTo(dp.Attributes())
+		if m.metric.Type() != pmetric.MetricTypeGauge {
+			dp.SetStartTimestamp(s.start)
+		}
+		dp.SetTimestamp(ts)
+
+		if m.delta {
+			var zero P
+			s.acc, s.hasAcc = zero, false
+			s.start = ts
+		}
+	}
+}
+
+func (m *metricState[P]) expire(deadline time.Time) bool {
+	for key, s := range m.series {
+		for inKey, in := range s.inputs {
+			if in.seen.Before(deadline) {
+				delete(s.inputs, inKey)
+			}
+		}
+		// the sum of the deltas received during the interval is still exported
+		if len(s.inputs) == 0 && (!m.delta || !s.hasAcc) {
+			delete(m.series, key)
+		}
+	}
+	return len(m.series) == 0
+}
+
+func (m *metricState[P]) source() (pcommon.Resource, pcommon.InstrumentationScope) {
+	return m.resource, m.scope
+}
+
+// combineNumbers sets dst to the aggregation of the values.
+func combineNumbers(aggregation AggregationType) func(dst pmetric.NumberDataPoint, values []pmetric.NumberDataPoint) {
+	return func(dst pmetric.NumberDataPoint, values []pmetric.NumberDataPoint) {
+		allInts := true
+		for _, v := range values {
+			allInts = allInts && v.ValueType() == pmetric.NumberDataPointValueTypeInt
+		}
+
+		switch aggregation {
+		case AggregationTypeMean:
+			var sum float64
+			for _, v := range values {
+				sum += aggregate.Float(v)
+			}
+			dst.SetDoubleValue(sum / float64(len(values)))
+		case AggregationTypeMin, AggregationTypeMax:
+			best := values[0]
+			for _, v := range values[1:] {
+				if (aggregation == AggregationTypeMin && aggregate.Float(v) < aggregate.Float(best)) ||
+					(aggregation == AggregationTypeMax && aggregate.Float(v) > aggregate.Float(best)) {
+					best = v
+				}
+			}
+			if allInts {
+				dst.SetIntValue(best.IntValue())
+			} else {
+				dst.SetDoubleValue(aggregate.Float(best))
+			}
+		default:
+			if allInts {
+				dst.SetIntValue(0)
+			} else {
+				dst.SetDoubleValue(0)
+			}
+			for _, v := range values {
+				aggregate.AddNumber(dst, v)
+			}
+		}
+	}
+}
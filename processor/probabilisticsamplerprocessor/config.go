@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 type AttributeSource string
@@ -23,6 +25,35 @@ var validAttributeSource = map[AttributeSource]bool{
 	recordAttributeSource:  true,
 }
 
+// SamplerMode determines how the sampling decision is made.
+type SamplerMode string
+
+const (
+	// hashSeedMode is the original sampler, hashing the trace ID
+	// or the log record attribute with the configured hash seed.
+	hashSeedMode = SamplerMode("hash_seed")
+	// equalizingMode uses the consistent probability sampling
+	// information (OTEP 235), applying the configured probability
+	// as an absolute threshold that equalizes the incoming probabilities.
+	equalizingMode = SamplerMode("equalizing")
+	// proportionalMode uses the consistent probability sampling
+	// information (OTEP 235), multiplying the incoming probabilities
+	// by the configured probability.
+	proportionalMode = SamplerMode("proportional")
+
+	defaultMode = hashSeedMode
+
+	// defaultSamplingPrecision is the number of hex digits
+	// used to encode the sampling threshold.
+	defaultSamplingPrecision = 4
+)
+
+var validMode = map[SamplerMode]bool{
+	hashSeedMode:     true,
+	equalizingMode:   true,
+	proportionalMode: true,
+}
+
 // Config has the configuration guiding the sampler processor.
 type Config struct {
 
@@ -35,6 +66,17 @@ type Config struct {
 	// different sampling rates, configuring different seeds avoids that.
 	HashSeed uint32 `mapstructure:"hash_seed"`
 
+	// Mode selects how the sampling decision is made, either `hash_seed`, `proportional` or `equalizing`.
+	// The `proportional` and `equalizing` modes follow the OpenTelemetry consistent probability sampling
+	// specification, using the randomness of the trace ID (or the `rv` value of the tracestate) and recording the
+	// sampling threshold on the sampled items so that the probability of sampling multiple times is known.
+	// Defaults to `hash_seed`.
+	Mode SamplerMode `mapstructure:"mode"`
+
+	// SamplingPrecision is the number of hex digits used to encode the sampling threshold,
+	// between 1 and 14. It only applies to the `proportional` and `equalizing` modes.
+	SamplingPrecision int `mapstructure:"sampling_precision"`
+
 	// AttributeSource (logs only) defines where to look for the attribute in from_attribute. The allowed values are
 	// `traceID` or `record`. Default is `traceID`.
 	AttributeSource `mapstructure:"attribute_source"`
@@ -57,5 +99,16 @@ func (cfg *Config) Validate() error {
 	if cfg.AttributeSource != "" && !validAttributeSource[cfg.AttributeSource] {
 		return fmt.Errorf("invalid attribute source: %v. Expected: %v or %v", cfg.AttributeSource, traceIDAttributeSource, recordAttributeSource)
 	}
+	if cfg.Mode != "" && !validMode[cfg.Mode] {
+		return fmt.Errorf("invalid mode: %v. Expected: %v, %v or %v", cfg.Mode, hashSeedMode, proportionalMode, equalizingMode)
+	}
+	if cfg.Mode == proportionalMode || cfg.Mode == equalizingMode {
+		if cfg.SamplingPrecision < 1 || cfg.SamplingPrecision > sampling.NumHexDigits {
+			return fmt.Errorf("invalid sampling precision: %d. Expected a value between 1 and %d", cfg.SamplingPrecision, sampling.NumHexDigits)
+		}
+		if ratio := float64(cfg.SamplingPercentage) / 100; ratio > 0 && ratio < sampling.MinSamplingProbability {
+			return fmt.Errorf("sampling rate is too small: %g", cfg.SamplingPercentage)
+		}
+	}
 	return nil
 }
@@ -28,6 +28,8 @@ func TestLoadConfig(t *testing.T) {
 				SamplingPercentage: 15.3,
 				HashSeed:           22,
 				AttributeSource:    "traceID",
+				Mode:               "hash_seed",
+				SamplingPrecision:  4,
 			},
 		},
 		{
@@ -38,6 +40,17 @@ func TestLoadConfig(t *testing.T) {
 				AttributeSource:    "record",
 				FromAttribute:      "foo",
 				SamplingPriority:   "bar",
+				Mode:               "hash_seed",
+				SamplingPrecision:  4,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "proportional"),
+			expected: &Config{
+				SamplingPercentage: 10,
+				AttributeSource:    "traceID",
+				Mode:               "proportional",
+				SamplingPrecision:  6,
 			},
 		},
 	}
@@ -72,3 +85,55 @@ func TestLoadInvalidConfig(t *testing.T) {
 	_, err = otelcoltest.LoadConfigAndValidate(filepath.Join("testdata", "invalid.yaml"), factories)
 	require.ErrorContains(t, err, "negative sampling rate: -15.30")
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *Config
+		errMsg string
+	}{
+		{
+			name: "invalid mode",
+			cfg: &Config{
+				SamplingPercentage: 10,
+				Mode:               "random",
+			},
+			errMsg: "invalid mode: random",
+		},
+		{
+			name: "invalid sampling precision",
+			cfg: &Config{
+				SamplingPercentage: 10,
+				Mode:               equalizingMode,
+				SamplingPrecision:  15,
+			},
+			errMsg: "invalid sampling precision: 15",
+		},
+		{
+			name: "sampling rate too small",
+			cfg: &Config{
+				SamplingPercentage: 1e-15,
+				Mode:               proportionalMode,
+				SamplingPrecision:  4,
+			},
+			errMsg: "sampling rate is too small",
+		},
+		{
+			name: "sampling precision ignored by hash_seed",
+			cfg: &Config{
+				SamplingPercentage: 10,
+				Mode:               hashSeedMode,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -6,6 +6,10 @@ package probabilisticsamplerprocessor // import "github.com/open-telemetry/opent
 import (
 	"encoding/binary"
 	"hash/fnv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 // computeHash creates a hash using the FNV-1a algorithm
@@ -23,3 +27,14 @@ func i32tob(val uint32) []byte {
 	binary.LittleEndian.PutUint32(r, val)
 	return r
 }
+
+// randomnessFromBytes derives the randomness used by the consistent probability sampling modes
+// from a FNV-1a hash of the given bytes, for items which don't have a trace ID to derive it from.
+// The hash isn't seeded so that every sampling stage derives the same randomness.
+func randomnessFromBytes(b []byte) sampling.Randomness {
+	hash := fnv.New64a()
+	_, _ = hash.Write(b)
+	var tid pcommon.TraceID
+	binary.BigEndian.PutUint64(tid[8:], hash.Sum64())
+	return sampling.TraceIDToRandomness(tid)
+}
@@ -15,15 +15,24 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
+// thresholdAttribute is the log record attribute recording the sampling threshold
+// of sampled log records, following the encoding of the tracestate `th` value.
+const thresholdAttribute = "sampling.threshold"
+
 type logSamplerProcessor struct {
 	scaledSamplingRate uint32
 	hashSeed           uint32
 	traceIDEnabled     bool
 	samplingSource     string
 	samplingPriority   string
-	logger             *zap.Logger
+	// consistent is set when sampling with one of
+	// the consistent probability sampling modes.
+	consistent *consistentSampler
+	logger     *zap.Logger
 }
 
 // newLogsProcessor returns a processor.LogsProcessor that will perform head sampling according to the given
@@ -38,6 +47,13 @@ func newLogsProcessor(ctx context.Context, set processor.CreateSettings, nextCon
 		samplingSource:     cfg.FromAttribute,
 		logger:             set.Logger,
 	}
+	if cfg.Mode == proportionalMode || cfg.Mode == equalizingMode {
+		cs, err := newConsistentSampler(cfg.Mode, cfg.SamplingPercentage, cfg.SamplingPrecision)
+		if err != nil {
+			return nil, err
+		}
+		lsp.consistent = cs
+	}
 
 	return processorhelper.NewLogsProcessor(
 		ctx,
@@ -56,7 +72,8 @@ func (lsp *logSamplerProcessor) processLogs(ctx context.Context, ld plog.Logs) (
 				tagPolicyValue := "always_sampling"
 				// pick the sampling source.
 				var lidBytes []byte
-				if lsp.traceIDEnabled && !l.TraceID().IsEmpty() {
+				traceIDSource := lsp.traceIDEnabled && !l.TraceID().IsEmpty()
+				if traceIDSource {
 					value := l.TraceID()
 					tagPolicyValue = "trace_id_hash"
 					lidBytes = value[:]
@@ -67,19 +84,24 @@ func (lsp *logSamplerProcessor) processLogs(ctx context.Context, ld plog.Logs) (
 						lidBytes = getBytesFromValue(value)
 					}
 				}
-				priority := lsp.scaledSamplingRate
-				if lsp.samplingPriority != "" {
-					if localPriority, ok := l.Attributes().Get(lsp.samplingPriority); ok {
-						switch localPriority.Type() {
-						case pcommon.ValueTypeDouble:
-							priority = uint32(localPriority.Double() * percentageScaleFactor)
-						case pcommon.ValueTypeInt:
-							priority = uint32(float64(localPriority.Int()) * percentageScaleFactor)
-						}
+
+				var sampled bool
+				if lsp.consistent != nil {
+					var rnd sampling.Randomness
+					if traceIDSource {
+						tagPolicyValue = "trace_id_randomness"
+						rnd = sampling.TraceIDToRandomness(l.TraceID())
+					} else {
+						rnd = randomnessFromBytes(lidBytes)
+					}
+					sampled = lsp.decideConsistent(l, rnd)
+				} else {
+					priority := lsp.scaledSamplingRate
+					if percentage, ok := lsp.priorityPercentage(l); ok {
+						priority = uint32(percentage * percentageScaleFactor)
 					}
+					sampled = computeHash(lidBytes, lsp.hashSeed)&bitMaskHashBuckets < priority
 				}
-
-				sampled := computeHash(lidBytes, lsp.hashSeed)&bitMaskHashBuckets < priority
 				var err error = stats.RecordWithTags(
 					ctx,
 					[]tag.Mutator{tag.Upsert(tagPolicyKey, tagPolicyValue), tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))},
@@ -103,6 +125,57 @@ func (lsp *logSamplerProcessor) processLogs(ctx context.Context, ld plog.Logs) (
 	return ld, nil
 }
 
+// priorityPercentage returns the sampling percentage set by
+// the sampling priority attribute of the log record, if any.
+func (lsp *logSamplerProcessor) priorityPercentage(l plog.LogRecord) (float64, bool) {
+	if lsp.samplingPriority == "" {
+		return 0, false
+	}
+	if localPriority, ok := l.Attributes().Get(lsp.samplingPriority); ok {
+		switch localPriority.Type() {
+		case pcommon.ValueTypeDouble:
+			return localPriority.Double(), true
+		case pcommon.ValueTypeInt:
+			return float64(localPriority.Int()), true
+		}
+	}
+	return 0, false
+}
+
+// decideConsistent makes the sampling decision of the log record using the given randomness,
+// the threshold of a previous sampling stage is read from the sampling threshold attribute
+// which is updated on sampled log records.
+func (lsp *logSamplerProcessor) decideConsistent(l plog.LogRecord, rnd sampling.Randomness) bool {
+	cs := lsp.consistent
+	if percentage, ok := lsp.priorityPercentage(l); ok {
+		prioritySampler, err := newConsistentSampler(cs.mode, float32(percentage), int(cs.precision))
+		if err != nil {
+			lsp.logger.Debug("Invalid sampling priority, using the configured sampling percentage", zap.Error(err))
+		} else {
+			cs = prioritySampler
+		}
+	}
+
+	var (
+		arriving    sampling.Threshold
+		hasArriving bool
+	)
+	if value, ok := l.Attributes().Get(thresholdAttribute); ok {
+		th, err := sampling.TValueToThreshold(value.AsString())
+		// The arriving threshold is ignored when it would not
+		// have sampled this log record, it can't be relied on to count it.
+		if err == nil && th.ShouldSample(rnd) {
+			arriving, hasArriving = th, true
+		}
+	}
+
+	th, sampled := cs.decide(rnd, arriving, hasArriving)
+	if sampled {
+		l.Attributes().PutStr(thresholdAttribute, th.TValue())
+	}
+	return sampled
+}
+
 func getBytesFromValue(value pcommon.Value) []byte {
 	if value.Type() == pcommon.ValueTypeBytes {
 		return value.Bytes().AsRaw()
@@ -136,6 +136,28 @@ func TestLogsSampling(t *testing.T) {
 			},
 			received: 25,
 		},
+		{
+			name: "equalizing",
+			cfg: &Config{
+				SamplingPercentage: 75,
+				AttributeSource:    traceIDAttributeSource,
+				Mode:               equalizingMode,
+				SamplingPrecision:  defaultSamplingPrecision,
+			},
+			// sampled when the randomness of the trace ID is above 0x40000000000000
+			received: 36,
+		},
+		{
+			name: "equalizing sampling_priority",
+			cfg: &Config{
+				SamplingPercentage: 0,
+				AttributeSource:    traceIDAttributeSource,
+				SamplingPriority:   "priority",
+				Mode:               equalizingMode,
+				SamplingPrecision:  defaultSamplingPrecision,
+			},
+			received: 25,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -173,3 +195,77 @@ func TestLogsSampling(t *testing.T) {
 		})
 	}
 }
+
+func TestLogsSamplingThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     SamplerMode
+		arriving string
+		sampled  bool
+		expected string
+	}{
+		{
+			name:     "equalizing",
+			mode:     equalizingMode,
+			sampled:  true,
+			expected: "8",
+		},
+		{
+			name:     "equalizing_arriving_threshold",
+			mode:     equalizingMode,
+			arriving: "c",
+			sampled:  true,
+			expected: "c",
+		},
+		{
+			name:     "proportional_arriving_threshold",
+			mode:     proportionalMode,
+			arriving: "8",
+			sampled:  true,
+			expected: "c",
+		},
+		{
+			name:     "proportional_not_sampled",
+			mode:     proportionalMode,
+			arriving: "d",
+		},
+		{
+			name:     "invalid_arriving_threshold",
+			mode:     proportionalMode,
+			arriving: "invalid",
+			sampled:  true,
+			expected: "8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			cfg := &Config{
+				SamplingPercentage: 50,
+				AttributeSource:    traceIDAttributeSource,
+				Mode:               tt.mode,
+				SamplingPrecision:  defaultSamplingPrecision,
+			}
+			processor, err := newLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), sink, cfg)
+			require.NoError(t, err)
+
+			logs := plog.NewLogs()
+			record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			record.SetTraceID([16]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0xd0, 0, 0, 0, 0, 0, 0})
+			if tt.arriving != "" {
+				record.Attributes().PutStr(thresholdAttribute, tt.arriving)
+			}
+			require.NoError(t, processor.ConsumeLogs(context.Background(), logs))
+
+			if !tt.sampled {
+				assert.Equal(t, 0, sink.LogRecordCount())
+				return
+			}
+			require.Equal(t, 1, sink.LogRecordCount())
+			sampled := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			th, ok := sampled.Attributes().Get(thresholdAttribute)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, th.Str())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+// consistentSampler makes sampling decisions following the consistent
+// probability sampling specification, comparing the randomness of an item
+// against a threshold derived from the configured sampling probability.
+type consistentSampler struct {
+	mode      SamplerMode
+	precision uint8
+	// probability is the configured sampling probability, zero
+	// meaning that no item is sampled.
+	probability float64
+	// threshold is the encoding of probability with the configured precision.
+	threshold sampling.Threshold
+}
+
+// newConsistentSampler returns a consistentSampler for the given mode
+// and sampling percentage.
+func newConsistentSampler(mode SamplerMode, percentage float32, precision int) (*consistentSampler, error) {
+	cs := &consistentSampler{
+		mode:        mode,
+		precision:   uint8(precision),
+		probability: min(float64(percentage)/100, 1),
+	}
+	if cs.probability <= 0 {
+		return cs, nil
+	}
+	th, err := probabilityToThreshold(cs.probability, cs.precision)
+	if err != nil {
+		return nil, err
+	}
+	cs.threshold = th
+	return cs, nil
+}
+
+// decide returns the sampling threshold applied to an item with the given randomness
+// and whether it is sampled. The arriving threshold, if any, is the threshold that
+// was recorded on the item by a previous sampling stage.
+func (cs *consistentSampler) decide(rnd sampling.Randomness, arriving sampling.Threshold, hasArriving bool) (sampling.Threshold, bool) {
+	if cs.probability <= 0 {
+		return sampling.AlwaysSampleThreshold, false
+	}
+	th := cs.threshold
+	if hasArriving {
+		if cs.mode == proportionalMode {
+			prob := arriving.Probability() * cs.probability
+			if prob < sampling.MinSamplingProbability {
+				// The resulting probability can't be represented.
+				return th, false
+			}
+			var err error
+			if th, err = probabilityToThreshold(prob, cs.precision); err != nil {
+				return th, false
+			}
+		}
+		// A sampling stage can only lower the probability of an item,
+		// which also prevents the precision from raising it in proportional mode.
+		if sampling.ThresholdGreater(arriving, th) {
+			th = arriving
+		}
+	}
+	return th, th.ShouldSample(rnd)
+}
+
+// probabilityToThreshold encodes the probability with the given precision,
+// using the full precision for probabilities too close to one to be encoded.
+func probabilityToThreshold(prob float64, precision uint8) (sampling.Threshold, error) {
+	th, err := sampling.ProbabilityToThresholdWithPrecision(prob, precision)
+	if errors.Is(err, sampling.ErrPrecisionUnderflow) {
+		return sampling.ProbabilityToThreshold(prob)
+	}
+	return th, err
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probabilisticsamplerprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+func TestConsistentSamplerDecide(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       SamplerMode
+		percentage float32
+		rvalue     string
+		arriving   string
+		sampled    bool
+		threshold  string
+	}{
+		{
+			name:       "equalizing_sampled",
+			mode:       equalizingMode,
+			percentage: 50,
+			rvalue:     "80000000000000",
+			sampled:    true,
+			threshold:  "8",
+		},
+		{
+			name:       "equalizing_not_sampled",
+			mode:       equalizingMode,
+			percentage: 50,
+			rvalue:     "7fffffffffffff",
+		},
+		{
+			name:       "equalizing_keeps_lower_arriving_probability",
+			mode:       equalizingMode,
+			percentage: 50,
+			rvalue:     "c0000000000000",
+			arriving:   "c",
+			sampled:    true,
+			threshold:  "c",
+		},
+		{
+			name:       "equalizing_lowers_arriving_probability",
+			mode:       equalizingMode,
+			percentage: 25,
+			rvalue:     "c0000000000000",
+			arriving:   "8",
+			sampled:    true,
+			threshold:  "c",
+		},
+		{
+			name:       "proportional_sampled",
+			mode:       proportionalMode,
+			percentage: 50,
+			rvalue:     "c0000000000000",
+			arriving:   "8",
+			sampled:    true,
+			threshold:  "c",
+		},
+		{
+			name:       "proportional_not_sampled",
+			mode:       proportionalMode,
+			percentage: 50,
+			rvalue:     "bfffffffffffff",
+			arriving:   "8",
+		},
+		{
+			name:       "proportional_without_arriving_threshold",
+			mode:       proportionalMode,
+			percentage: 25,
+			rvalue:     "c0000000000000",
+			sampled:    true,
+			threshold:  "c",
+		},
+		{
+			name:       "proportional_underflow",
+			mode:       proportionalMode,
+			percentage: 1,
+			rvalue:     "ffffffffffffff",
+			arriving:   "fffffffffffff",
+		},
+		{
+			name:       "always_sample",
+			mode:       proportionalMode,
+			percentage: 100,
+			rvalue:     "00000000000000",
+			sampled:    true,
+			threshold:  "0",
+		},
+		{
+			name:       "never_sample",
+			mode:       equalizingMode,
+			percentage: 0,
+			rvalue:     "ffffffffffffff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := newConsistentSampler(tt.mode, tt.percentage, defaultSamplingPrecision)
+			require.NoError(t, err)
+
+			rnd, err := sampling.RValueToRandomness(tt.rvalue)
+			require.NoError(t, err)
+
+			var arriving sampling.Threshold
+			if tt.arriving != "" {
+				arriving, err = sampling.TValueToThreshold(tt.arriving)
+				require.NoError(t, err)
+			}
+
+			th, sampled := cs.decide(rnd, arriving, tt.arriving != "")
+			assert.Equal(t, tt.sampled, sampled)
+			if tt.sampled {
+				assert.Equal(t, tt.threshold, th.TValue())
+			}
+		})
+	}
+}
+
+func TestConsistentSamplerPrecision(t *testing.T) {
+	cs, err := newConsistentSampler(equalizingMode, 100.0/3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", cs.threshold.TValue())
+
+	cs, err = newConsistentSampler(equalizingMode, 99.99, 14)
+	require.NoError(t, err, "Must fall back to the full precision")
+	assert.True(t, sampling.ThresholdGreater(cs.threshold, sampling.AlwaysSampleThreshold))
+}
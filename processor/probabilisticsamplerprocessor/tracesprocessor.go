@@ -6,6 +6,7 @@ package probabilisticsamplerprocessor // import "github.com/open-telemetry/opent
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -15,6 +16,8 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 // samplingPriority has the semantic result of parsing the "sampling.priority"
@@ -44,7 +47,10 @@ const (
 type traceSamplerProcessor struct {
 	scaledSamplingRate uint32
 	hashSeed           uint32
-	logger             *zap.Logger
+	// consistent is set when sampling with one of
+	// the consistent probability sampling modes.
+	consistent *consistentSampler
+	logger     *zap.Logger
 }
 
 // newTracesProcessor returns a processor.TracesProcessor that will perform head sampling according to the given
@@ -56,6 +62,13 @@ func newTracesProcessor(ctx context.Context, set processor.CreateSettings, cfg *
 		hashSeed:           cfg.HashSeed,
 		logger:             set.Logger,
 	}
+	if cfg.Mode == proportionalMode || cfg.Mode == equalizingMode {
+		cs, err := newConsistentSampler(cfg.Mode, cfg.SamplingPercentage, cfg.SamplingPrecision)
+		if err != nil {
+			return nil, err
+		}
+		tsp.consistent = cs
+	}
 
 	return processorhelper.NewTracesProcessor(
 		ctx,
@@ -89,16 +102,24 @@ func (tsp *traceSamplerProcessor) processTraces(ctx context.Context, td ptrace.T
 					statCountTracesSampled.M(int64(1)),
 				)
 
-				// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
-				// with various different criteria to generate trace id and perhaps were already sampled without hashing.
-				// Hashing here prevents bias due to such systems.
-				tidBytes := s.TraceID()
-				sampled := sp == mustSampleSpan ||
-					computeHash(tidBytes[:], tsp.hashSeed)&bitMaskHashBuckets < tsp.scaledSamplingRate
+				var sampled bool
+				policy := "trace_id_hash"
+				switch {
+				case sp == mustSampleSpan:
+					sampled = true
+				case tsp.consistent != nil:
+					policy, sampled = tsp.decideConsistent(s)
+				default:
+					// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
+					// with various different criteria to generate trace id and perhaps were already sampled without hashing.
+					// Hashing here prevents bias due to such systems.
+					tidBytes := s.TraceID()
+					sampled = computeHash(tidBytes[:], tsp.hashSeed)&bitMaskHashBuckets < tsp.scaledSamplingRate
+				}
 
 				_ = stats.RecordWithTags(
 					ctx,
-					[]tag.Mutator{tag.Upsert(tagPolicyKey, "trace_id_hash"), tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))},
+					[]tag.Mutator{tag.Upsert(tagPolicyKey, policy), tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))},
 					statCountTracesSampled.M(int64(1)),
 				)
 				return !sampled
@@ -115,6 +136,50 @@ func (tsp *traceSamplerProcessor) processTraces(ctx context.Context, td ptrace.T
 	return td, nil
 }
 
+// decideConsistent makes the sampling decision of the span using the randomness
+// of its trace ID, or the `rv` value of its tracestate when present, and records the
+// sampling threshold as the `th` value of the tracestate of sampled spans.
+// It returns the source of randomness used and whether the span is sampled.
+func (tsp *traceSamplerProcessor) decideConsistent(s ptrace.Span) (string, bool) {
+	rnd := sampling.TraceIDToRandomness(s.TraceID())
+	w3c, err := sampling.NewW3CTraceState(s.TraceState().AsRaw())
+	if err != nil {
+		// The tracestate is left untouched since it can't be updated.
+		tsp.logger.Debug("Invalid tracestate, sampling using the trace ID", zap.Error(err))
+		_, sampled := tsp.consistent.decide(rnd, sampling.AlwaysSampleThreshold, false)
+		return "trace_id_randomness", sampled
+	}
+
+	otts := w3c.OTelValue()
+	policy := "trace_id_randomness"
+	if rv, ok := otts.RValueRandomness(); ok {
+		policy, rnd = "tracestate_rvalue", rv
+	}
+	arriving, hasArriving := otts.TValueThreshold()
+	if hasArriving && !arriving.ShouldSample(rnd) {
+		// The arriving threshold would not have sampled this span,
+		// so it can't be relied on to count it.
+		otts.ClearTValue()
+		hasArriving = false
+	}
+
+	th, sampled := tsp.consistent.decide(rnd, arriving, hasArriving)
+	if !sampled {
+		return policy, false
+	}
+	if err = otts.UpdateTValueWithSampling(th, th.TValue()); err != nil {
+		tsp.logger.Debug("Unable to update the sampling threshold", zap.Error(err))
+		return policy, true
+	}
+	var ts strings.Builder
+	if err = w3c.Serialize(&ts); err != nil {
+		tsp.logger.Debug("Unable to encode the tracestate", zap.Error(err))
+		return policy, true
+	}
+	s.TraceState().FromRaw(ts.String())
+	return policy, true
+}
+
 // parseSpanSamplingPriority checks if the span has the "sampling.priority" tag to
 // decide if the span should be sampled or not. The usage of the tag follows the
 // OpenTracing semantic tags:
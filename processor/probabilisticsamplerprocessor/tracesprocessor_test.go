@@ -305,6 +305,134 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 
 // Test_parseSpanSamplingPriority ensures that the function parsing the attributes is taking "sampling.priority"
 // attribute correctly.
+func Test_tracesamplerprocessor_ConsistentSampling(t *testing.T) {
+	// The randomness of the trace ID is taken from its least significant 56 bits.
+	highRandomness := pcommon.TraceID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	midRandomness := pcommon.TraceID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 0}
+	tests := []struct {
+		name       string
+		cfg        *Config
+		traceID    pcommon.TraceID
+		tracestate string
+		priority   int64
+		sampled    bool
+		expected   string
+	}{
+		{
+			name: "equalizing_sampled",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               equalizingMode,
+			},
+			traceID:  highRandomness,
+			sampled:  true,
+			expected: "ot=th:8",
+		},
+		{
+			name: "equalizing_keeps_other_values",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               equalizingMode,
+			},
+			traceID:    highRandomness,
+			tracestate: "ot=th:c;x:y,vendor=value",
+			sampled:    true,
+			expected:   "ot=th:c;x:y,vendor=value",
+		},
+		{
+			name: "equalizing_rvalue",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               equalizingMode,
+			},
+			traceID:    pcommon.TraceID{},
+			tracestate: "ot=rv:90000000000000",
+			sampled:    true,
+			expected:   "ot=rv:90000000000000;th:8",
+		},
+		{
+			name: "equalizing_inconsistent_threshold",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               equalizingMode,
+			},
+			traceID:    midRandomness,
+			tracestate: "ot=th:c",
+			sampled:    true,
+			expected:   "ot=th:8",
+		},
+		{
+			name: "proportional_sampled",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               proportionalMode,
+			},
+			traceID:    highRandomness,
+			tracestate: "ot=th:8",
+			sampled:    true,
+			expected:   "ot=th:c",
+		},
+		{
+			name: "proportional_not_sampled",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               proportionalMode,
+			},
+			traceID:    midRandomness,
+			tracestate: "ot=th:8",
+		},
+		{
+			name: "invalid_tracestate",
+			cfg: &Config{
+				SamplingPercentage: 50,
+				Mode:               proportionalMode,
+			},
+			traceID:    highRandomness,
+			tracestate: "ot=th:invalid",
+			sampled:    true,
+			expected:   "ot=th:invalid",
+		},
+		{
+			name: "sampling_priority",
+			cfg: &Config{
+				SamplingPercentage: 0,
+				Mode:               equalizingMode,
+			},
+			traceID:    highRandomness,
+			tracestate: "ot=th:8",
+			priority:   1,
+			sampled:    true,
+			expected:   "ot=th:8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.SamplingPrecision = defaultSamplingPrecision
+			sink := new(consumertest.TracesSink)
+			tsp, err := newTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), tt.cfg, sink)
+			require.NoError(t, err)
+
+			td := ptrace.NewTraces()
+			span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			span.SetTraceID(tt.traceID)
+			span.TraceState().FromRaw(tt.tracestate)
+			if tt.priority != 0 {
+				span.Attributes().PutInt("sampling.priority", tt.priority)
+			}
+
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+			if !tt.sampled {
+				assert.Equal(t, 0, sink.SpanCount())
+				return
+			}
+			require.Equal(t, 1, sink.SpanCount())
+			sampled := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			assert.Equal(t, tt.expected, sampled.TraceState().AsRaw())
+		})
+	}
+}
+
 func Test_parseSpanSamplingPriority(t *testing.T) {
 	tests := []struct {
 		name string
@@ -3,6 +3,69 @@
 
 package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
 
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+// Action is the pseudonymization applied to the values matched by a
+// PseudonymizationRule.
+type Action string
+
+const (
+	// maskAction replaces the value with a fixed length of asterisks,
+	// like the values matching BlockedValues.
+	maskAction = Action("mask")
+	// hmacAction replaces the value with its hex encoded HMAC-SHA256.
+	hmacAction = Action("hmac")
+	// truncateAction keeps the first Length characters of the value.
+	truncateAction = Action("truncate")
+	// partialMaskAction replaces all but the last Length characters
+	// of the value with asterisks.
+	partialMaskAction = Action("partial_mask")
+	// tokenizeAction replaces each letter and digit of the value with another
+	// one derived from the HMAC-SHA256 of the value, preserving its format.
+	tokenizeAction = Action("tokenize")
+
+	// defaultPartialMaskLength is the number of characters
+	// kept by the partial_mask action by default.
+	defaultPartialMaskLength = 4
+)
+
+var validActions = map[Action]bool{
+	maskAction:        true,
+	hmacAction:        true,
+	truncateAction:    true,
+	partialMaskAction: true,
+	tokenizeAction:    true,
+}
+
+var errMissingHMACKey = errors.New("hmac_key must be set for the hmac and tokenize actions")
+
+// PseudonymizationRule replaces sensitive values with a pseudonym, which
+// unlike masking keeps the values joinable when the action is deterministic.
+type PseudonymizationRule struct {
+	// Key is the attribute key whose whole value is pseudonymized. Keys
+	// with a rule are allowed even if they aren't part of AllowedKeys.
+	Key string `mapstructure:"key"`
+
+	// Pattern is a regular expression matching the parts of the values
+	// of allowed attributes that are pseudonymized. Only one of Key or
+	// Pattern can be set.
+	Pattern string `mapstructure:"pattern"`
+
+	// Action is the pseudonymization applied to the value, one of `mask`,
+	// `hmac`, `truncate`, `partial_mask` or `tokenize`.
+	Action Action `mapstructure:"action"`
+
+	// Length is the number of characters kept by the `truncate` action,
+	// or left visible at the end of the value by the `partial_mask` action.
+	// Defaults to 4 for the `partial_mask` action.
+	Length int `mapstructure:"length"`
+}
+
 type Config struct {
 
 	// AllowAllKeys is a flag to allow all span attribute keys. Setting this
@@ -30,4 +93,38 @@ type Config struct {
 	// information, while it is valuable when integrating and testing a new
 	// configuration. Possible values are `debug`, `info`, and `silent`.
 	Summary string `mapstructure:"summary"`
+
+	// PseudonymizationRules is a list of rules replacing the values of
+	// attribute keys, or the values matching a pattern, with a pseudonym.
+	PseudonymizationRules []PseudonymizationRule `mapstructure:"pseudonymization_rules"`
+
+	// HMACKey is the secret key used by the `hmac` and `tokenize` actions.
+	// It can be read from the environment using the `${env:VAR}` syntax.
+	HMACKey configopaque.String `mapstructure:"hmac_key"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	for i, rule := range cfg.PseudonymizationRules {
+		if (rule.Key == "") == (rule.Pattern == "") {
+			return fmt.Errorf("pseudonymization rule %d: exactly one of key or pattern must be set", i)
+		}
+		if !validActions[rule.Action] {
+			return fmt.Errorf("pseudonymization rule %d: invalid action %q", i, rule.Action)
+		}
+		if rule.Length < 0 {
+			return fmt.Errorf("pseudonymization rule %d: length must not be negative", i)
+		}
+		switch rule.Action {
+		case hmacAction, tokenizeAction:
+			if cfg.HMACKey == "" {
+				return fmt.Errorf("pseudonymization rule %d: %w", i, errMissingHMACKey)
+			}
+		case truncateAction:
+			if rule.Length == 0 {
+				return fmt.Errorf("pseudonymization rule %d: length must be set for the truncate action", i)
+			}
+		}
+	}
+	return nil
 }
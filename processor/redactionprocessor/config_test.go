@@ -36,6 +36,18 @@ func TestLoadConfig(t *testing.T) {
 			id:       component.NewIDWithName(metadata.Type, "empty"),
 			expected: createDefaultConfig(),
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "pseudonymization"),
+			expected: &Config{
+				AllowedKeys: []string{"description"},
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: hmacAction},
+					{Key: "user.phone", Action: partialMaskAction, Length: 2},
+					{Pattern: "[a-z]+@example\\.com", Action: tokenizeAction},
+				},
+				HMACKey: "secret",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,3 +67,86 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *Config
+		errMsg string
+	}{
+		{
+			name: "valid rules",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: hmacAction},
+					{Pattern: "[0-9]+", Action: partialMaskAction},
+				},
+				HMACKey: "secret",
+			},
+		},
+		{
+			name: "key and pattern",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Pattern: "[0-9]+", Action: maskAction},
+				},
+			},
+			errMsg: "exactly one of key or pattern must be set",
+		},
+		{
+			name: "neither key nor pattern",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Action: maskAction},
+				},
+			},
+			errMsg: "exactly one of key or pattern must be set",
+		},
+		{
+			name: "invalid action",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: "encrypt"},
+				},
+			},
+			errMsg: `invalid action "encrypt"`,
+		},
+		{
+			name: "missing hmac key",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: tokenizeAction},
+				},
+			},
+			errMsg: errMissingHMACKey.Error(),
+		},
+		{
+			name: "missing truncate length",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: truncateAction},
+				},
+			},
+			errMsg: "length must be set for the truncate action",
+		},
+		{
+			name: "negative length",
+			cfg: &Config{
+				PseudonymizationRules: []PseudonymizationRule{
+					{Key: "user.id", Action: partialMaskAction, Length: -1},
+				},
+			},
+			errMsg: "length must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -13,7 +13,9 @@ var (
 )
 
 const (
-	TracesStability = component.StabilityLevelBeta
+	LogsStability    = component.StabilityLevelAlpha
+	MetricsStability = component.StabilityLevelAlpha
+	TracesStability  = component.StabilityLevelBeta
 )
 
 func Meter(settings component.TelemetrySettings) metric.Meter {
@@ -7,10 +7,13 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 )
@@ -24,6 +27,10 @@ type redaction struct {
 	ignoreList map[string]string
 	// Attribute values blocked in a span
 	blockRegexList map[string]*regexp.Regexp
+	// Pseudonymization of the whole value of attribute keys
+	keyRules map[string]pseudonymizer
+	// Pseudonymization of the attribute values matching a pattern
+	patternRules []patternRule
 	// Redaction processor configuration
 	config *Config
 	// Logger
@@ -39,11 +46,17 @@ func newRedaction(ctx context.Context, config *Config, logger *zap.Logger) (*red
 		// TODO: Placeholder for an error metric in the next PR
 		return nil, fmt.Errorf("failed to process block list: %w", err)
 	}
+	keyRules, patternRules, err := makePseudonymizationRules(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process pseudonymization rules: %w", err)
+	}
 
 	return &redaction{
 		allowList:      allowList,
 		ignoreList:     ignoreList,
 		blockRegexList: blockRegexList,
+		keyRules:       keyRules,
+		patternRules:   patternRules,
 		config:         config,
 		logger:         logger,
 	}, nil
@@ -75,15 +88,268 @@ func (s *redaction) processResourceSpan(ctx context.Context, rs ptrace.ResourceS
 
 			// Attributes can also be part of span
 			s.processAttrs(ctx, spanAttrs)
+
+			// The span name can contain blocked values
+			s.processSpanName(ctx, span)
+
+			// Attributes can also be part of span events
+			for l := 0; l < span.Events().Len(); l++ {
+				s.processAttrs(ctx, span.Events().At(l).Attributes())
+			}
 		}
 	}
 }
 
-// processAttrs redacts the attributes of a resource span or a span
+// processSpanName masks the blocked values of a span name
+func (s *redaction) processSpanName(_ context.Context, span ptrace.Span) {
+	maskedName, masked, pseudonymized := s.maskString(span.Name())
+	if masked {
+		s.addMetaAttrs([]string{spanNameKey}, span.Attributes(), maskedValues, maskedValueCount)
+	}
+	if pseudonymized {
+		s.addMetaAttrs([]string{spanNameKey}, span.Attributes(), pseudonymizedValues, pseudonymizedValueCount)
+	}
+	span.SetName(maskedName)
+}
+
+// processLogs implements ProcessLogsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processLogs(ctx context.Context, logs plog.Logs) (plog.Logs, error) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		s.processAttrs(ctx, rl.Resource().Attributes())
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				log := sl.LogRecords().At(k)
+				s.processAttrs(ctx, log.Attributes())
+				s.processLogBody(ctx, log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+// processLogBody redacts the body of a log record. String bodies are masked,
+// while the keys of map bodies are redacted like attributes, using the dotted
+// path of the key from the root of the body, e.g. `user.email` for the
+// `email` key of the `user` map. The elements of slices share the path of
+// the slice.
+func (s *redaction) processLogBody(_ context.Context, log plog.LogRecord) {
+	var summary bodySummary
+	body := log.Body()
+	switch body.Type() {
+	case pcommon.ValueTypeStr:
+		maskedBody, masked, pseudonymized := s.maskString(body.Str())
+		if masked {
+			summary.masked = append(summary.masked, bodyKey)
+		}
+		if pseudonymized {
+			summary.pseudonymized = append(summary.pseudonymized, bodyKey)
+		}
+		if masked || pseudonymized {
+			body.SetStr(maskedBody)
+		}
+	case pcommon.ValueTypeMap:
+		s.processBodyMap(body.Map(), "", false, &summary)
+	case pcommon.ValueTypeSlice:
+		s.processBodySlice(body.Slice(), "", false, &summary)
+	}
+
+	// Add diagnostic information to the log record, the keys of maps
+	// nested in slices can be reported several times
+	attributes := log.Attributes()
+	s.addMetaAttrs(uniqueKeys(summary.redacted), attributes, redactedKeys, redactedKeyCount)
+	s.addMetaAttrs(uniqueKeys(summary.masked), attributes, maskedValues, maskedValueCount)
+	s.addMetaAttrs(uniqueKeys(summary.pseudonymized), attributes, pseudonymizedValues, pseudonymizedValueCount)
+	s.addMetaAttrs(uniqueKeys(summary.ignored), attributes, "", ignoredKeyCount)
+}
+
+// bodySummary lists the paths of the log body keys
+// that were redacted, masked, pseudonymized or ignored
+type bodySummary struct {
+	redacted      []string
+	masked        []string
+	pseudonymized []string
+	ignored       []string
+}
+
+// processBodyMap redacts the keys of a map in the log body, the keys of a map
+// whose path is allowed are all allowed
+func (s *redaction) processBodyMap(m pcommon.Map, path string, allowed bool, summary *bodySummary) {
+	m.RemoveIf(func(k string, value pcommon.Value) bool {
+		keyPath := k
+		if path != "" {
+			keyPath = path + "." + k
+		}
+
+		// don't delete or redact the key if it should be ignored
+		if _, ignored := s.ignoreList[keyPath]; ignored {
+			summary.ignored = append(summary.ignored, bodyPath(keyPath))
+			return false
+		}
+
+		// Pseudonymize the whole value of the keys with a rule
+		if pseudonymize, ok := s.keyRules[keyPath]; ok {
+			value.SetStr(pseudonymize(value.AsString()))
+			summary.pseudonymized = append(summary.pseudonymized, bodyPath(keyPath))
+			return false
+		}
+
+		keyAllowed := allowed || s.config.AllowAllKeys
+		if !keyAllowed {
+			_, keyAllowed = s.allowList[keyPath]
+		}
+		switch value.Type() {
+		case pcommon.ValueTypeMap:
+			s.processBodyMap(value.Map(), keyPath, keyAllowed, summary)
+			return redactEmpty(value, keyPath, keyAllowed, summary)
+		case pcommon.ValueTypeSlice:
+			s.processBodySlice(value.Slice(), keyPath, keyAllowed, summary)
+			return redactEmpty(value, keyPath, keyAllowed, summary)
+		}
+
+		// Delete the values of the keys that aren't allowed
+		if !keyAllowed {
+			summary.redacted = append(summary.redacted, bodyPath(keyPath))
+			return true
+		}
+
+		// Mask any blocked values for the other keys
+		maskedVal, masked, pseudonymized := s.maskString(value.Str())
+		if masked {
+			summary.masked = append(summary.masked, bodyPath(keyPath))
+		}
+		if pseudonymized {
+			summary.pseudonymized = append(summary.pseudonymized, bodyPath(keyPath))
+		}
+		if masked || pseudonymized {
+			value.SetStr(maskedVal)
+		}
+		return false
+	})
+}
+
+// processBodySlice redacts the elements of a slice in the log body,
+// each path is reported once in the summary for the whole slice
+func (s *redaction) processBodySlice(sl pcommon.Slice, path string, allowed bool, summary *bodySummary) {
+	var redacted, masked, pseudonymized bool
+	sl.RemoveIf(func(value pcommon.Value) bool {
+		switch value.Type() {
+		case pcommon.ValueTypeMap:
+			s.processBodyMap(value.Map(), path, allowed, summary)
+			return redactEmpty(value, path, allowed || s.config.AllowAllKeys, summary)
+		case pcommon.ValueTypeSlice:
+			s.processBodySlice(value.Slice(), path, allowed, summary)
+			return redactEmpty(value, path, allowed || s.config.AllowAllKeys, summary)
+		}
+
+		if !allowed && !s.config.AllowAllKeys {
+			redacted = true
+			return true
+		}
+		maskedVal, valueMasked, valuePseudonymized := s.maskString(value.Str())
+		if valueMasked || valuePseudonymized {
+			value.SetStr(maskedVal)
+		}
+		masked = masked || valueMasked
+		pseudonymized = pseudonymized || valuePseudonymized
+		return false
+	})
+	if redacted {
+		summary.redacted = append(summary.redacted, bodyPath(path))
+	}
+	if masked {
+		summary.masked = append(summary.masked, bodyPath(path))
+	}
+	if pseudonymized {
+		summary.pseudonymized = append(summary.pseudonymized, bodyPath(path))
+	}
+}
+
+// redactEmpty reports whether the map or slice of a path that isn't allowed
+// was left empty by the redaction of its content, so that it is removed too
+func redactEmpty(value pcommon.Value, path string, allowed bool, summary *bodySummary) bool {
+	if allowed {
+		return false
+	}
+	if (value.Type() == pcommon.ValueTypeMap && value.Map().Len() > 0) ||
+		(value.Type() == pcommon.ValueTypeSlice && value.Slice().Len() > 0) {
+		return false
+	}
+	summary.redacted = append(summary.redacted, bodyPath(path))
+	return true
+}
+
+// uniqueKeys sorts the keys and removes the duplicates
+func uniqueKeys(keys []string) []string {
+	sort.Strings(keys)
+	return slices.Compact(keys)
+}
+
+// bodyPath returns the key used in the summary for a path in the log body
+func bodyPath(path string) string {
+	if path == "" {
+		return bodyKey
+	}
+	return bodyKey + "." + path
+}
+
+// processMetrics implements ProcessMetricsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processMetrics(ctx context.Context, metrics pmetric.Metrics) (pmetric.Metrics, error) {
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		rm := metrics.ResourceMetrics().At(i)
+		s.processAttrs(ctx, rm.Resource().Attributes())
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				s.processMetricAttrs(ctx, sm.Metrics().At(k))
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// processMetricAttrs redacts the attributes of the data points of a metric
+func (s *redaction) processMetricAttrs(ctx context.Context, metric pmetric.Metric) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	}
+}
+
+// processAttrs redacts the attributes of a resource, a span, a log record or a data point
 func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 	// TODO: Use the context for recording metrics
 	var toDelete []string
 	var toBlock []string
+	var toPseudonymize []string
 	var ignoring []string
 
 	// Identify attributes to redact and mask in the following sequence
@@ -102,6 +368,16 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 			return true
 		}
 
+		// Pseudonymize the whole value of the keys with a rule, these
+		// keys are allowed and the blocked values don't apply to them.
+		// The pseudonym is a string, whatever the type of the value.
+		if pseudonymize, ok := s.keyRules[k]; ok {
+			value.SetStr(pseudonymize(value.AsString()))
+			toPseudonymize = append(toPseudonymize, k)
+			// Skip to the next attribute
+			return true
+		}
+
 		// Make a list of attribute keys to redact
 		if !s.config.AllowAllKeys {
 			if _, allowed := s.allowList[k]; !allowed {
@@ -112,20 +388,15 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 		}
 
 		// Mask any blocked values for the other attributes
-		strVal := value.Str()
-		var matched bool
-		for _, compiledRE := range s.blockRegexList {
-			match := compiledRE.MatchString(strVal)
-			if match {
-				if !matched {
-					matched = true
-					toBlock = append(toBlock, k)
-				}
-
-				maskedValue := compiledRE.ReplaceAllString(strVal, "****")
-				value.SetStr(maskedValue)
-				strVal = maskedValue
-			}
+		maskedVal, masked, pseudonymized := s.maskString(value.Str())
+		if masked {
+			toBlock = append(toBlock, k)
+		}
+		if pseudonymized {
+			toPseudonymize = append(toPseudonymize, k)
+		}
+		if masked || pseudonymized {
+			value.SetStr(maskedVal)
 		}
 		return true
 	})
@@ -137,9 +408,30 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 	// Add diagnostic information to the span
 	s.addMetaAttrs(toDelete, attributes, redactedKeys, redactedKeyCount)
 	s.addMetaAttrs(toBlock, attributes, maskedValues, maskedValueCount)
+	s.addMetaAttrs(toPseudonymize, attributes, pseudonymizedValues, pseudonymizedValueCount)
 	s.addMetaAttrs(ignoring, attributes, "", ignoredKeyCount)
 }
 
+// maskString masks the parts of the value matching a blocked value and
+// pseudonymizes the parts matching the pattern of a pseudonymization rule,
+// it reports whether any part was masked and whether any was pseudonymized
+func (s *redaction) maskString(strVal string) (string, bool, bool) {
+	var masked, pseudonymized bool
+	for _, compiledRE := range s.blockRegexList {
+		if compiledRE.MatchString(strVal) {
+			masked = true
+			strVal = compiledRE.ReplaceAllString(strVal, maskedValue)
+		}
+	}
+	for _, rule := range s.patternRules {
+		if rule.re.MatchString(strVal) {
+			pseudonymized = true
+			strVal = rule.re.ReplaceAllStringFunc(strVal, rule.pseudonymize)
+		}
+	}
+	return strVal, masked, pseudonymized
+}
+
 // addMetaAttrs adds diagnostic information about redacted or masked attribute keys
 func (s *redaction) addMetaAttrs(redactedAttrs []string, attributes pcommon.Map, valuesAttr, countAttr string) {
 	redactedCount := int64(len(redactedAttrs))
@@ -170,7 +462,15 @@ const (
 	redactedKeyCount = "redaction.redacted.count"
 	maskedValues     = "redaction.masked.keys"
 	maskedValueCount = "redaction.masked.count"
-	ignoredKeyCount  = "redaction.ignored.count"
+	// pseudonymizedValues and pseudonymizedValueCount summarize the keys
+	// whose values were pseudonymized, whole or in part
+	pseudonymizedValues     = "redaction.pseudonymized.keys"
+	pseudonymizedValueCount = "redaction.pseudonymized.count"
+	ignoredKeyCount         = "redaction.ignored.count"
+	// bodyKey prefixes the paths of the log body keys reported in the summary
+	bodyKey = "body"
+	// spanNameKey is reported in the summary when a span name is masked
+	spanNameKey = "span.name"
 )
 
 // makeAllowList sets up a lookup table of allowed span attribute keys
@@ -185,7 +485,8 @@ func makeAllowList(c *Config) map[string]string {
 	// span attributes (e.g. `notes`, `description`), then it will those
 	// attribute keys in `redaction.masked.keys` and set the
 	// `redaction.masked.count` to 2
-	redactionKeys := []string{redactedKeys, redactedKeyCount, maskedValues, maskedValueCount, ignoredKeyCount}
+	redactionKeys := []string{redactedKeys, redactedKeyCount, maskedValues, maskedValueCount,
+		pseudonymizedValues, pseudonymizedValueCount, ignoredKeyCount}
 	// allowList consists of the keys explicitly allowed by the configuration
 	// as well as of the new span attributes that the processor creates to
 	// summarize its changes
@@ -220,3 +521,29 @@ func makeBlockRegexList(_ context.Context, config *Config) (map[string]*regexp.R
 	}
 	return blockRegexList, nil
 }
+
+// patternRule pseudonymizes the parts of values matching a pattern
+type patternRule struct {
+	re           *regexp.Regexp
+	pseudonymize pseudonymizer
+}
+
+// makePseudonymizationRules precompiles the pseudonymization rules, split
+// between the rules applying to attribute keys and to value patterns
+func makePseudonymizationRules(config *Config) (map[string]pseudonymizer, []patternRule, error) {
+	key := []byte(config.HMACKey)
+	keyRules := make(map[string]pseudonymizer)
+	var patternRules []patternRule
+	for _, rule := range config.PseudonymizationRules {
+		if rule.Key != "" {
+			keyRules[rule.Key] = newPseudonymizer(rule, key)
+			continue
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error compiling regex in pseudonymization rules: %w", err)
+		}
+		patternRules = append(patternRules, patternRule{re: re, pseudonymize: newPseudonymizer(rule, key)})
+	}
+	return keyRules, patternRules, nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap/zaptest"
 )
@@ -103,7 +105,286 @@ func TestAllowAllKeysMaskValues(t *testing.T) {
 	assert.Equal(t, "placeholder ****", value.Str())
 }
 
-// TODO: Test redaction with metric tags in a metrics PR
+// TestPseudonymizationRules validates that the processor pseudonymizes the
+// values of the keys with a rule and the values matching a rule pattern
+func TestPseudonymizationRules(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"notes", "card"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		PseudonymizationRules: []PseudonymizationRule{
+			{Key: "user.id", Action: hmacAction},
+			{Key: "user.phone", Action: partialMaskAction},
+			{Pattern: "[a-z]+@example\\.com", Action: tokenizeAction},
+		},
+		HMACKey: "secret",
+		Summary: "debug",
+	}
+	allowed := map[string]pcommon.Value{
+		"notes": pcommon.NewValueStr("no sensitive data"),
+	}
+	masked := map[string]pcommon.Value{
+		"user.id":    pcommon.NewValueStr("4111-1111-1111-1111"),
+		"user.phone": pcommon.NewValueStr("555-0100-1234"),
+		"card":       pcommon.NewValueStr("contact john@example.com about 4111111111111111"),
+	}
+
+	outTraces := runTest(t, allowed, nil, masked, nil, config)
+
+	attr := outTraces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	assert.Equal(t, map[string]any{
+		"notes":                 "no sensitive data",
+		"user.id":               "993004b64c142acae983b4361a8492d2f18aec4175a0fa8ef81743e0e7d8104e",
+		"user.phone":            "*********1234",
+		"card":                  "contact " + tokenize("john@example.com", []byte("secret")) + " about ****",
+		maskedValues:            "card",
+		maskedValueCount:        int64(1),
+		pseudonymizedValues:     "card,user.id,user.phone",
+		pseudonymizedValueCount: int64(3),
+	}, attr.AsRaw())
+}
+
+// TestRedactLogs validates that the processor redacts
+// and pseudonymizes the attributes of log records
+func TestRedactLogs(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"id"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		PseudonymizationRules: []PseudonymizationRule{
+			{Key: "user.id", Action: hmacAction},
+		},
+		HMACKey: "secret",
+		Summary: "debug",
+	}
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host.name", "localhost")
+	log := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	log.Attributes().PutInt("id", 5)
+	log.Attributes().PutStr("user.id", "4111-1111-1111-1111")
+	log.Attributes().PutStr("credit_card", "4111111111111111")
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	rl = out.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{
+		redactedKeys:     "host.name",
+		redactedKeyCount: int64(1),
+	}, rl.Resource().Attributes().AsRaw())
+	log = rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, map[string]any{
+		"id":                    int64(5),
+		"user.id":               "993004b64c142acae983b4361a8492d2f18aec4175a0fa8ef81743e0e7d8104e",
+		redactedKeys:            "credit_card",
+		redactedKeyCount:        int64(1),
+		pseudonymizedValues:     "user.id",
+		pseudonymizedValueCount: int64(1),
+	}, log.Attributes().AsRaw())
+}
+
+// TestRedactLogStringBody validates that the processor masks and
+// pseudonymizes the parts of string log bodies
+func TestRedactLogStringBody(t *testing.T) {
+	config := &Config{
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		PseudonymizationRules: []PseudonymizationRule{
+			{Pattern: "[a-z]+@example\\.com", Action: partialMaskAction},
+		},
+		Summary: "debug",
+	}
+	logs := plog.NewLogs()
+	log := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	log.Body().SetStr("john@example.com paid with 4111111111111111")
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	log = out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "************.com paid with ****", log.Body().Str())
+	assert.Equal(t, map[string]any{
+		maskedValues:            bodyKey,
+		maskedValueCount:        int64(1),
+		pseudonymizedValues:     bodyKey,
+		pseudonymizedValueCount: int64(1),
+	}, log.Attributes().AsRaw())
+}
+
+// TestRedactLogMapBody validates that the processor redacts the keys of
+// map bodies using their dotted path, recursively through maps and slices
+func TestRedactLogMapBody(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"message", "user.name", "request", "users.name"},
+		IgnoredKeys:   []string{"trace.card"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		PseudonymizationRules: []PseudonymizationRule{
+			{Key: "user.email", Action: partialMaskAction},
+		},
+		Summary: "debug",
+	}
+	logs := plog.NewLogs()
+	log := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	require.NoError(t, log.Body().SetEmptyMap().FromRaw(map[string]any{
+		"message": "paid with 4111111111111111",
+		"user": map[string]any{
+			"name":     "John",
+			"email":    "john@example.com",
+			"password": "hunter2",
+		},
+		"request": map[string]any{
+			"method": "POST",
+			"card":   "4111111111111111",
+		},
+		"users": []any{
+			map[string]any{"name": "Jane", "password": "hunter3"},
+			map[string]any{"name": "Joe", "password": "hunter4"},
+		},
+		"tags":    []any{"private", "secret"},
+		"session": map[string]any{"token": "abc"},
+		"trace":   map[string]any{"card": "4111111111111111"},
+	}))
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	log = out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, map[string]any{
+		"message": "paid with ****",
+		"user": map[string]any{
+			"name":  "John",
+			"email": "************.com",
+		},
+		"request": map[string]any{
+			"method": "POST",
+			"card":   "****",
+		},
+		"users": []any{
+			map[string]any{"name": "Jane"},
+			map[string]any{"name": "Joe"},
+		},
+		"trace": map[string]any{"card": "4111111111111111"},
+	}, log.Body().Map().AsRaw())
+	assert.Equal(t, map[string]any{
+		redactedKeys:            "body.session,body.session.token,body.tags,body.user.password,body.users.password",
+		redactedKeyCount:        int64(5),
+		maskedValues:            "body.message,body.request.card",
+		maskedValueCount:        int64(2),
+		pseudonymizedValues:     "body.user.email",
+		pseudonymizedValueCount: int64(1),
+		ignoredKeyCount:         int64(1),
+	}, log.Attributes().AsRaw())
+}
+
+// TestRedactLogSliceBody validates that the processor redacts
+// the elements of slice bodies
+func TestRedactLogSliceBody(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Summary:       "info",
+	}
+	logs := plog.NewLogs()
+	log := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	require.NoError(t, log.Body().SetEmptySlice().FromRaw([]any{
+		"paid with 4111111111111111",
+		map[string]any{"card": "4111111111111111"},
+	}))
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	log = out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, []any{
+		"paid with ****",
+		map[string]any{"card": "****"},
+	}, log.Body().Slice().AsRaw())
+	assert.Equal(t, map[string]any{
+		maskedValueCount: int64(2),
+	}, log.Attributes().AsRaw())
+}
+
+// TestRedactSpanEventsAndName validates that the processor redacts the
+// attributes of span events and masks the blocked values of span names
+func TestRedactSpanEventsAndName(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"message"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Summary:       "debug",
+	}
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /cards/4111111111111111")
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.Attributes().PutStr("message", "invalid card 4111111111111111")
+	event.Attributes().PutStr("password", "hunter2")
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processTraces(context.Background(), traces)
+	require.NoError(t, err)
+
+	span = out.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "GET /cards/****", span.Name())
+	assert.Equal(t, map[string]any{
+		maskedValues:     spanNameKey,
+		maskedValueCount: int64(1),
+	}, span.Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"message":        "invalid card ****",
+		redactedKeys:     "password",
+		redactedKeyCount: int64(1),
+		maskedValues:     "message",
+		maskedValueCount: int64(1),
+	}, span.Events().At(0).Attributes().AsRaw())
+}
+
+// TestRedactMetrics validates that the processor redacts
+// the attributes of the data points of every metric type
+func TestRedactMetrics(t *testing.T) {
+	config := &Config{
+		AllowedKeys: []string{"id"},
+		PseudonymizationRules: []PseudonymizationRule{
+			{Key: "user.id", Action: truncateAction, Length: 4},
+		},
+	}
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "localhost")
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+	attrs := []pcommon.Map{
+		ms.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes(),
+		ms.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes(),
+		ms.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes(),
+		ms.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes(),
+		ms.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes(),
+	}
+	for _, attr := range attrs {
+		attr.PutInt("id", 5)
+		attr.PutStr("user.id", "4111-1111-1111-1111")
+		attr.PutStr("credit_card", "4111111111111111")
+	}
+
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	out, err := processor.processMetrics(context.Background(), metrics)
+	require.NoError(t, err)
+
+	assert.Empty(t, out.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	for i, attr := range attrs {
+		assert.Equal(t, map[string]any{
+			"id":      int64(5),
+			"user.id": "4111",
+		}, attr.AsRaw(), "metric %d", i)
+	}
+}
 
 // TestRedactSummaryDebug validates that the processor writes a verbose summary
 // of any attributes it deleted to the new redaction.redacted.keys and
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+const maskedValue = "****"
+
+// pseudonymizer replaces a sensitive value with its pseudonym
+type pseudonymizer func(value string) string
+
+// newPseudonymizer returns the pseudonymizer applying the action of the rule,
+// the key is used by the actions relying on HMAC-SHA256.
+func newPseudonymizer(rule PseudonymizationRule, key []byte) pseudonymizer {
+	switch rule.Action {
+	case hmacAction:
+		return func(value string) string {
+			mac := hmac.New(sha256.New, key)
+			_, _ = mac.Write([]byte(value))
+			return hex.EncodeToString(mac.Sum(nil))
+		}
+	case truncateAction:
+		return func(value string) string {
+			return truncate(value, rule.Length)
+		}
+	case partialMaskAction:
+		length := rule.Length
+		if length == 0 {
+			length = defaultPartialMaskLength
+		}
+		return func(value string) string {
+			return partialMask(value, length)
+		}
+	case tokenizeAction:
+		return func(value string) string {
+			return tokenize(value, key)
+		}
+	default:
+		return func(string) string {
+			return maskedValue
+		}
+	}
+}
+
+// truncate keeps the first length characters of the value
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:length])
+}
+
+// partialMask replaces all but the last length characters of the value with
+// asterisks, so that the length of the value is preserved.
+func partialMask(value string, length int) string {
+	runes := []rune(value)
+	masked := max(len(runes)-length, 0)
+	return strings.Repeat("*", masked) + string(runes[masked:])
+}
+
+// tokenize replaces each letter and digit of the value with another one of
+// the same kind, keeping the length, the case and the separators of the value.
+// The replacements are derived from the HMAC-SHA256 of the whole value so that
+// the same value always has the same token.
+func tokenize(value string, key []byte) string {
+	var (
+		stream  []byte
+		counter uint32
+	)
+	next := func() int {
+		if len(stream) == 0 {
+			mac := hmac.New(sha256.New, key)
+			_ = binary.Write(mac, binary.BigEndian, counter)
+			_, _ = mac.Write([]byte(value))
+			stream = mac.Sum(nil)
+			counter++
+		}
+		b := stream[0]
+		stream = stream[1:]
+		return int(b)
+	}
+
+	var token strings.Builder
+	token.Grow(len(value))
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			token.WriteByte(byte('0' + next()%10))
+		case r >= 'a' && r <= 'z':
+			token.WriteByte(byte('a' + next()%26))
+		case r >= 'A' && r <= 'Z':
+			token.WriteByte(byte('A' + next()%26))
+		default:
+			token.WriteRune(r)
+		}
+	}
+	return token.String()
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redactionprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPseudonymizer(t *testing.T) {
+	key := []byte("secret")
+	tests := []struct {
+		name     string
+		rule     PseudonymizationRule
+		value    string
+		expected string
+	}{
+		{
+			name:     "mask",
+			rule:     PseudonymizationRule{Action: maskAction},
+			value:    "4111-1111-1111-1111",
+			expected: "****",
+		},
+		{
+			name:     "hmac",
+			rule:     PseudonymizationRule{Action: hmacAction},
+			value:    "4111-1111-1111-1111",
+			expected: "993004b64c142acae983b4361a8492d2f18aec4175a0fa8ef81743e0e7d8104e",
+		},
+		{
+			name:     "truncate",
+			rule:     PseudonymizationRule{Action: truncateAction, Length: 3},
+			value:    "héllo wörld",
+			expected: "hél",
+		},
+		{
+			name:     "truncate_short_value",
+			rule:     PseudonymizationRule{Action: truncateAction, Length: 3},
+			value:    "ab",
+			expected: "ab",
+		},
+		{
+			name:     "partial_mask",
+			rule:     PseudonymizationRule{Action: partialMaskAction},
+			value:    "4111-1111-1111-1111",
+			expected: "***************1111",
+		},
+		{
+			name:     "partial_mask_length",
+			rule:     PseudonymizationRule{Action: partialMaskAction, Length: 5},
+			value:    "héllo wörld",
+			expected: "******wörld",
+		},
+		{
+			name:     "partial_mask_short_value",
+			rule:     PseudonymizationRule{Action: partialMaskAction},
+			value:    "ab",
+			expected: "ab",
+		},
+		{
+			name:     "tokenize",
+			rule:     PseudonymizationRule{Action: tokenizeAction},
+			value:    "4111-1111-1111-1111",
+			expected: "1411-5538-2253-4202",
+		},
+		{
+			name:     "tokenize_letters",
+			rule:     PseudonymizationRule{Action: tokenizeAction},
+			value:    "John.Doe@example.com",
+			expected: "Zzjf.Pgk@ulgnydh.gck",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pseudonymize := newPseudonymizer(tt.rule, key)
+			assert.Equal(t, tt.expected, pseudonymize(tt.value))
+			assert.Equal(t, tt.expected, pseudonymize(tt.value), "Must be deterministic")
+		})
+	}
+}
+
+func TestTokenizeKey(t *testing.T) {
+	value := "John.Doe@example.com"
+	assert.NotEqual(t, tokenize(value, []byte("secret")), tokenize(value, []byte("other secret")))
+	assert.NotEqual(t, tokenize(value, []byte("secret")), tokenize("Jane.Doe@example.com", []byte("secret")))
+}
+
+func TestTokenizeLongValue(t *testing.T) {
+	// Values longer than a HMAC-SHA256 digest extend the stream of replacements.
+	value := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	token := tokenize(value, []byte("secret"))
+	assert.Len(t, token, len(value))
+	assert.Regexp(t, "^[a-z]{26}[A-Z]{26}[0-9]{10}$", token)
+	assert.NotEqual(t, value, token)
+}
@@ -15,6 +15,7 @@ import (
 var (
 	errRequiresTargets  = errors.New("requires schema targets")
 	errDuplicateTargets = errors.New("duplicate targets detected")
+	errEmptySchemaFile  = errors.New("empty schema file path")
 )
 
 // Config defines the user provided values for the Schema Processor
@@ -31,6 +32,12 @@ type Config struct {
 	// translated to, allowing older and newer formats
 	// to conform to the target schema identifier.
 	Targets []string `mapstructure:"targets"`
+
+	// SchemaFiles maps schema URLs to local schema translation
+	// files that are read instead of fetching the schema URL,
+	// allowing the processor to be used where the schema URLs
+	// can not be reached. (Optional field)
+	SchemaFiles map[string]string `mapstructure:"schema_files"`
 }
 
 func (c *Config) Validate() error {
@@ -40,6 +47,14 @@ func (c *Config) Validate() error {
 			return err
 		}
 	}
+	for schemaURL, path := range c.SchemaFiles {
+		if _, _, err := translation.GetFamilyAndVersion(schemaURL); err != nil {
+			return err
+		}
+		if path == "" {
+			return fmt.Errorf("schema url %q: %w", schemaURL, errEmptySchemaFile)
+		}
+	}
 	// Not strictly needed since it would just pass on
 	// any data that doesn't match targets, however defining
 	// this processor with no targets is wasteful.
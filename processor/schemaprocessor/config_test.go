@@ -39,6 +39,9 @@ func TestLoadConfig(t *testing.T) {
 			"https://opentelemetry.io/schemas/1.4.2",
 			"https://example.com/otel/schemas/1.2.0",
 		},
+		SchemaFiles: map[string]string{
+			"https://opentelemetry.io/schemas/1.9.0": "./schemas/opentelemetry/1.9.0.yaml",
+		},
 	}, cfg)
 }
 
@@ -48,6 +51,7 @@ func TestConfigurationValidation(t *testing.T) {
 	tests := []struct {
 		scenario    string
 		target      []string
+		schemaFiles map[string]string
 		expectError error
 	}{
 		{scenario: "No targets", target: nil, expectError: errRequiresTargets},
@@ -76,11 +80,30 @@ func TestConfigurationValidation(t *testing.T) {
 			},
 			expectError: errDuplicateTargets,
 		},
+		{
+			scenario:    "Schema file with invalid schema url",
+			target:      []string{"https://opentelemetry.io/schemas/1.9.0"},
+			schemaFiles: map[string]string{"https://opentelemetry.io/schemas/": "1.0.0.yaml"},
+			expectError: translation.ErrInvalidVersion,
+		},
+		{
+			scenario:    "Schema file without a path",
+			target:      []string{"https://opentelemetry.io/schemas/1.9.0"},
+			schemaFiles: map[string]string{"https://opentelemetry.io/schemas/1.0.0": ""},
+			expectError: errEmptySchemaFile,
+		},
+		{
+			scenario:    "Valid schema file",
+			target:      []string{"https://opentelemetry.io/schemas/1.9.0"},
+			schemaFiles: map[string]string{"https://opentelemetry.io/schemas/1.0.0": "1.0.0.yaml"},
+			expectError: nil,
+		},
 	}
 
 	for _, tc := range tests {
 		cfg := &Config{
-			Targets: tc.target,
+			Targets:     tc.target,
+			SchemaFiles: tc.schemaFiles,
 		}
 
 		assert.ErrorIs(t, component.ValidateConfig(cfg), tc.expectError, tc.scenario)
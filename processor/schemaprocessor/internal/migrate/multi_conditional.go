@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/schema/v1.0/ast"
+	"go.uber.org/multierr"
+)
+
+// MultiConditionalAttributeSet is similar to `ConditionalAttributeSet`
+// however it checks several named fields at once, all of which must match
+// for the changes to be applied.
+// A field that has no values to match against is ignored.
+type MultiConditionalAttributeSet struct {
+	on    map[string]map[string]struct{}
+	attrs *AttributeChangeSet
+}
+
+type MultiConditionalAttributeSetSlice []*MultiConditionalAttributeSet
+
+func NewMultiConditionalAttributeSet(mappings ast.AttributeMap, matches map[string][]string) *MultiConditionalAttributeSet {
+	on := make(map[string]map[string]struct{}, len(matches))
+	for field, values := range matches {
+		on[field] = make(map[string]struct{}, len(values))
+		for _, v := range values {
+			on[field][v] = struct{}{}
+		}
+	}
+	return &MultiConditionalAttributeSet{
+		on:    on,
+		attrs: NewAttributeChangeSet(mappings),
+	}
+}
+
+func (mca *MultiConditionalAttributeSet) Apply(attrs pcommon.Map, values map[string]string) (errs error) {
+	if mca.check(values) {
+		errs = mca.attrs.Apply(attrs)
+	}
+	return errs
+}
+
+func (mca *MultiConditionalAttributeSet) Rollback(attrs pcommon.Map, values map[string]string) (errs error) {
+	if mca.check(values) {
+		errs = mca.attrs.Rollback(attrs)
+	}
+	return errs
+}
+
+func (mca *MultiConditionalAttributeSet) check(values map[string]string) bool {
+	for field, matches := range mca.on {
+		if len(matches) == 0 {
+			continue
+		}
+		if _, ok := matches[values[field]]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func NewMultiConditionalAttributeSetSlice(conditions ...*MultiConditionalAttributeSet) *MultiConditionalAttributeSetSlice {
+	values := new(MultiConditionalAttributeSetSlice)
+	for _, c := range conditions {
+		(*values) = append((*values), c)
+	}
+	return values
+}
+
+func (slice *MultiConditionalAttributeSetSlice) Apply(attrs pcommon.Map, values map[string]string) error {
+	return slice.do(StateSelectorApply, attrs, values)
+}
+
+func (slice *MultiConditionalAttributeSetSlice) Rollback(attrs pcommon.Map, values map[string]string) error {
+	return slice.do(StateSelectorRollback, attrs, values)
+}
+
+func (slice *MultiConditionalAttributeSetSlice) do(ss StateSelector, attrs pcommon.Map, values map[string]string) (errs error) {
+	for i := 0; i < len((*slice)); i++ {
+		switch ss {
+		case StateSelectorApply:
+			errs = multierr.Append(errs, (*slice)[i].Apply(attrs, values))
+		case StateSelectorRollback:
+			errs = multierr.Append(errs, (*slice)[len((*slice))-i-1].Rollback(attrs, values))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestMultiConditionalAttributeSetApply(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		cond   *MultiConditionalAttributeSet
+		check  map[string]string
+		attr   pcommon.Map
+		expect pcommon.Map
+	}{
+		{
+			name: "No conditions set, applies to all",
+			cond: NewMultiConditionalAttributeSet(
+				map[string]string{"service.version": "application.version"},
+				map[string][]string{},
+			),
+			check: map[string]string{"span.name": "application start"},
+			attr: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("service.version", "v0.0.0")
+			}),
+			expect: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("application.version", "v0.0.0")
+			}),
+		},
+		{
+			name: "All conditions matched",
+			cond: NewMultiConditionalAttributeSet(
+				map[string]string{"service.version": "application.version"},
+				map[string][]string{
+					"span.name":  {"application start"},
+					"event.name": {"started", "stopped"},
+				},
+			),
+			check: map[string]string{"span.name": "application start", "event.name": "stopped"},
+			attr: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("service.version", "v0.0.0")
+			}),
+			expect: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("application.version", "v0.0.0")
+			}),
+		},
+		{
+			name: "One condition not matched",
+			cond: NewMultiConditionalAttributeSet(
+				map[string]string{"service.version": "application.version"},
+				map[string][]string{
+					"span.name":  {"application start"},
+					"event.name": {"started"},
+				},
+			),
+			check: map[string]string{"span.name": "application start", "event.name": "stopped"},
+			attr: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("service.version", "v0.0.0")
+			}),
+			expect: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("service.version", "v0.0.0")
+			}),
+		},
+		{
+			name: "Empty condition is ignored",
+			cond: NewMultiConditionalAttributeSet(
+				map[string]string{"service.version": "application.version"},
+				map[string][]string{
+					"span.name":  {},
+					"event.name": {"started"},
+				},
+			),
+			check: map[string]string{"event.name": "started"},
+			attr: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("service.version", "v0.0.0")
+			}),
+			expect: testHelperBuildMap(func(m pcommon.Map) {
+				m.PutStr("application.version", "v0.0.0")
+			}),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.NoError(t, tc.cond.Apply(tc.attr, tc.check))
+			assert.Equal(t, tc.expect.AsRaw(), tc.attr.AsRaw(), "Must match the expected value")
+		})
+	}
+}
+
+func TestMultiConditionalAttributeSetSliceRollback(t *testing.T) {
+	t.Parallel()
+
+	slice := NewMultiConditionalAttributeSetSlice(
+		NewMultiConditionalAttributeSet(
+			map[string]string{"service_version": "service.version"},
+			map[string][]string{"event.name": {"started"}},
+		),
+		NewMultiConditionalAttributeSet(
+			map[string]string{"service.version": "application.version"},
+			map[string][]string{"event.name": {"started"}},
+		),
+	)
+
+	attrs := testHelperBuildMap(func(m pcommon.Map) {
+		m.PutStr("application.version", "v0.0.0")
+	})
+	assert.NoError(t, slice.Rollback(attrs, map[string]string{"event.name": "started"}))
+	assert.Equal(t, map[string]any{"service_version": "v0.0.0"}, attrs.AsRaw(), "Must rollback in reverse order")
+
+	assert.NoError(t, slice.Apply(attrs, map[string]string{"event.name": "started"}))
+	assert.Equal(t, map[string]any{"application.version": "v0.0.0"}, attrs.AsRaw(), "Must apply in order")
+}
@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// MetricSplit represents the `split` change of a schema, it replaces
+// a metric with several metrics, one for each of the values of an attribute
+// which is then removed from the new metrics.
+//
+// Rolling back the change merges the new metrics back into the original
+// metric and restores the attribute.
+type MetricSplit struct {
+	metric    string
+	attribute string
+	names     []string
+	values    map[string]pcommon.Value
+}
+
+type MetricSplitSlice []*MetricSplit
+
+// NewMetricSplit creates a `MetricSplit` of the named metric by the attribute,
+// the mappings are the names of the new metrics and the attribute value used
+// to select the data points of each new metric.
+func NewMetricSplit[Name SignalType, Attribute SignalType, Value any](metric Name, attribute Attribute, mappings map[Name]Value) *MetricSplit {
+	split := &MetricSplit{
+		metric:    string(metric),
+		attribute: string(attribute),
+		names:     make([]string, 0, len(mappings)),
+		values:    make(map[string]pcommon.Value, len(mappings)),
+	}
+	for name, v := range mappings {
+		value := pcommon.NewValueEmpty()
+		// Values that can't be represented as an attribute
+		// are kept empty so that no data point is matched.
+		_ = value.FromRaw(v)
+		split.names = append(split.names, string(name))
+		split.values[string(name)] = value
+	}
+	// Sorting the names ensures the new metrics are
+	// always created in the same order.
+	sort.Strings(split.names)
+	return split
+}
+
+func (ms *MetricSplit) Apply(metrics pmetric.MetricSlice) {
+	for i, n := 0, metrics.Len(); i < n; i++ {
+		m := metrics.At(i)
+		if m.Name() != ms.metric {
+			continue
+		}
+		for _, name := range ms.names {
+			split := pmetric.NewMetric()
+			m.CopyTo(split)
+			split.SetName(name)
+			removeDataPointsIf(split, func(attrs pcommon.Map) bool {
+				return !ms.matches(attrs, name)
+			})
+			if dataPointCount(split) == 0 {
+				continue
+			}
+			rangeDataPointAttributes(split, func(attrs pcommon.Map) {
+				attrs.Remove(ms.attribute)
+			})
+			split.MoveTo(metrics.AppendEmpty())
+		}
+		// Data points with an attribute value that isn't part of
+		// the split remain part of the original metric.
+		removeDataPointsIf(m, func(attrs pcommon.Map) bool {
+			for _, name := range ms.names {
+				if ms.matches(attrs, name) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	metrics.RemoveIf(func(m pmetric.Metric) bool {
+		return m.Name() == ms.metric && dataPointCount(m) == 0
+	})
+}
+
+func (ms *MetricSplit) Rollback(metrics pmetric.MetricSlice) {
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		value, ok := ms.values[m.Name()]
+		if !ok {
+			continue
+		}
+		merged, found := ms.find(metrics, m.Type())
+		if !found {
+			merged = metrics.AppendEmpty()
+			m.CopyTo(merged)
+			merged.SetName(ms.metric)
+			removeDataPointsIf(merged, func(pcommon.Map) bool { return true })
+		}
+		rangeDataPointAttributes(m, func(attrs pcommon.Map) {
+			value.CopyTo(attrs.PutEmpty(ms.attribute))
+		})
+		moveDataPoints(m, merged)
+	}
+	metrics.RemoveIf(func(m pmetric.Metric) bool {
+		_, split := ms.values[m.Name()]
+		return split && dataPointCount(m) == 0
+	})
+}
+
+func (ms *MetricSplit) matches(attrs pcommon.Map, name string) bool {
+	v, ok := attrs.Get(ms.attribute)
+	if !ok {
+		return false
+	}
+	expect := ms.values[name]
+	return v.Type() != pcommon.ValueTypeEmpty &&
+		expect.Type() != pcommon.ValueTypeEmpty &&
+		v.AsString() == expect.AsString()
+}
+
+func (ms *MetricSplit) find(metrics pmetric.MetricSlice, mt pmetric.MetricType) (pmetric.Metric, bool) {
+	for i := 0; i < metrics.Len(); i++ {
+		if m := metrics.At(i); m.Name() == ms.metric && m.Type() == mt {
+			return m, true
+		}
+	}
+	return pmetric.Metric{}, false
+}
+
+func NewMetricSplitSlice(splits ...*MetricSplit) *MetricSplitSlice {
+	values := new(MetricSplitSlice)
+	for _, s := range splits {
+		(*values) = append((*values), s)
+	}
+	return values
+}
+
+func (slice *MetricSplitSlice) Apply(metrics pmetric.MetricSlice) {
+	slice.do(StateSelectorApply, metrics)
+}
+
+func (slice *MetricSplitSlice) Rollback(metrics pmetric.MetricSlice) {
+	slice.do(StateSelectorRollback, metrics)
+}
+
+func (slice *MetricSplitSlice) do(ss StateSelector, metrics pmetric.MetricSlice) {
+	for i := 0; i < len((*slice)); i++ {
+		switch ss {
+		case StateSelectorApply:
+			(*slice)[i].Apply(metrics)
+		case StateSelectorRollback:
+			(*slice)[len((*slice))-i-1].Rollback(metrics)
+		}
+	}
+}
+
+func rangeDataPointAttributes(m pmetric.Metric, fn func(attrs pcommon.Map)) {
+	removeDataPointsIf(m, func(attrs pcommon.Map) bool {
+		fn(attrs)
+		return false
+	})
+}
+
+func removeDataPointsIf(m pmetric.Metric, fn func(attrs pcommon.Map) bool) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			return fn(dp.Attributes())
+		})
+	case pmetric.MetricTypeSum:
+		m.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			return fn(dp.Attributes())
+		})
+	case pmetric.MetricTypeHistogram:
+		m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			return fn(dp.Attributes())
+		})
+	case pmetric.MetricTypeExponentialHistogram:
+		m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			return fn(dp.Attributes())
+		})
+	case pmetric.MetricTypeSummary:
+		m.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool {
+			return fn(dp.Attributes())
+		})
+	}
+}
+
+func dataPointCount(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+// moveDataPoints moves all the data points of src into dst,
+// both metrics are expected to be of the same type.
+func moveDataPoints(src, dst pmetric.Metric) {
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		src.Gauge().DataPoints().MoveAndAppendTo(dst.Gauge().DataPoints())
+	case pmetric.MetricTypeSum:
+		src.Sum().DataPoints().MoveAndAppendTo(dst.Sum().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		src.Histogram().DataPoints().MoveAndAppendTo(dst.Histogram().DataPoints())
+	case pmetric.MetricTypeExponentialHistogram:
+		src.ExponentialHistogram().DataPoints().MoveAndAppendTo(dst.ExponentialHistogram().DataPoints())
+	case pmetric.MetricTypeSummary:
+		src.Summary().DataPoints().MoveAndAppendTo(dst.Summary().DataPoints())
+	}
+}
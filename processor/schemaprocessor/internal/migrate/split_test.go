@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func testHelperBuildSum(metrics pmetric.MetricSlice, name string, points map[string]float64) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit("{packet}")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	for state, v := range points {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetDoubleValue(v)
+		if state != "" {
+			dp.Attributes().PutStr("state", state)
+		}
+	}
+}
+
+func testHelperSumValues(t *testing.T, metrics pmetric.MetricSlice) map[string]map[string]float64 {
+	values := make(map[string]map[string]float64)
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		require.Equal(t, pmetric.MetricTypeSum, m.Type(), "Must keep the metric type")
+		assert.Equal(t, "{packet}", m.Unit(), "Must keep the metric unit")
+		points := make(map[string]float64)
+		for j := 0; j < m.Sum().DataPoints().Len(); j++ {
+			dp := m.Sum().DataPoints().At(j)
+			state, _ := dp.Attributes().Get("state")
+			points[state.AsString()] = dp.DoubleValue()
+		}
+		values[m.Name()] = points
+	}
+	return values
+}
+
+func TestMetricSplitApply(t *testing.T) {
+	t.Parallel()
+
+	split := NewMetricSplit("system.paging.operations", "state", map[string]any{
+		"system.paging.operations.in":  "in",
+		"system.paging.operations.out": "out",
+	})
+
+	t.Run("All data points split", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := pmetric.NewMetricSlice()
+		testHelperBuildSum(metrics, "system.paging.operations", map[string]float64{"in": 1, "out": 2})
+		testHelperBuildSum(metrics, "system.cpu.time", map[string]float64{"": 3})
+
+		split.Apply(metrics)
+		assert.Equal(t, map[string]map[string]float64{
+			"system.cpu.time":              {"": 3},
+			"system.paging.operations.in":  {"": 1},
+			"system.paging.operations.out": {"": 2},
+		}, testHelperSumValues(t, metrics))
+	})
+
+	t.Run("Unmatched data points are kept", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := pmetric.NewMetricSlice()
+		testHelperBuildSum(metrics, "system.paging.operations", map[string]float64{"in": 1, "unknown": 2})
+
+		split.Apply(metrics)
+		assert.Equal(t, map[string]map[string]float64{
+			"system.paging.operations":    {"unknown": 2},
+			"system.paging.operations.in": {"": 1},
+		}, testHelperSumValues(t, metrics))
+	})
+}
+
+func TestMetricSplitRollback(t *testing.T) {
+	t.Parallel()
+
+	split := NewMetricSplit("system.paging.operations", "state", map[string]any{
+		"system.paging.operations.in":  "in",
+		"system.paging.operations.out": "out",
+	})
+
+	t.Run("Merged into a new metric", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := pmetric.NewMetricSlice()
+		testHelperBuildSum(metrics, "system.paging.operations.in", map[string]float64{"": 1})
+		testHelperBuildSum(metrics, "system.paging.operations.out", map[string]float64{"": 2})
+
+		split.Rollback(metrics)
+		assert.Equal(t, map[string]map[string]float64{
+			"system.paging.operations": {"in": 1, "out": 2},
+		}, testHelperSumValues(t, metrics))
+		assert.True(t, metrics.At(0).Sum().IsMonotonic(), "Must keep the sum properties")
+	})
+
+	t.Run("Merged into the existing metric", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := pmetric.NewMetricSlice()
+		testHelperBuildSum(metrics, "system.paging.operations", map[string]float64{"unknown": 3})
+		testHelperBuildSum(metrics, "system.paging.operations.out", map[string]float64{"": 2})
+
+		split.Rollback(metrics)
+		assert.Equal(t, map[string]map[string]float64{
+			"system.paging.operations": {"unknown": 3, "out": 2},
+		}, testHelperSumValues(t, metrics))
+	})
+}
+
+func TestMetricSplitSliceRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	slice := NewMetricSplitSlice(
+		NewMetricSplit("system.paging.operations", "state", map[string]any{
+			"system.paging.operations.in":  "in",
+			"system.paging.operations.out": "out",
+		}),
+	)
+
+	metrics := pmetric.NewMetricSlice()
+	testHelperBuildSum(metrics, "system.paging.operations", map[string]float64{"in": 1, "out": 2})
+	expect := testHelperSumValues(t, metrics)
+
+	slice.Apply(metrics)
+	assert.Equal(t, 2, metrics.Len(), "Must have split the metric")
+
+	slice.Rollback(metrics)
+	assert.Equal(t, expect, testHelperSumValues(t, metrics), "Must restore the original metric")
+}
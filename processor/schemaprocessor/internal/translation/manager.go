@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+var (
+	errNilProviders = errors.New("no providers defined")
+	errNotTarget    = errors.New("schema family is not part of the targets")
+	errBackoff      = errors.New("schema file failed to be retrieved, not retrying yet")
+)
+
+const (
+	// initialRetryInterval and maxRetryInterval bound the time a schema file
+	// that failed to be retrieved is not requested again, the interval doubles
+	// with each consecutive failure.
+	initialRetryInterval = time.Second
+	maxRetryInterval     = 5 * time.Minute
+)
+
+// Manager is responsible for ensuring that schemas are kept up to date
+// with the most recent version that are requested.
+type Manager interface {
+	// RequestTranslation will provide either the defined Translation
+	// if it is a known target, or, return a noop variation.
+	// In the event that the schema file of a matched Translation isn't cached yet,
+	// the call blocks until it has been retrieved by one of the providers.
+	// Otherwise, the translation allows concurrent reads.
+	RequestTranslation(ctx context.Context, schemaURL string) Translation
+
+	// Prefetch retrieves and caches the schema file that the signals
+	// published with the schema URL are translated with,
+	// including when the schema URL is the one of a target.
+	Prefetch(ctx context.Context, schemaURL string) error
+
+	// SetProviders will update the list of providers used by the manager
+	// to retrieve the schema files, each provider is tried in order
+	// until one is able to retrieve the schema file.
+	SetProviders(providers ...Provider) error
+}
+
+// failure records a schema file that failed to be retrieved,
+// it isn't requested again until retryAt.
+type failure struct {
+	err      error
+	interval time.Duration
+	retryAt  time.Time
+}
+
+type manager struct {
+	log *zap.Logger
+	now func() time.Time
+
+	rw           sync.RWMutex
+	providers    []Provider
+	targets      map[string]string
+	match        map[string]*Version
+	translations map[string]*translator
+	failures     map[string]*failure
+}
+
+var _ Manager = (*manager)(nil)
+
+// NewManager creates a manager that will allow for management
+// of schema, the targets define the schema URL, of each schema family,
+// that signals are translated to.
+func NewManager(targets []string, log *zap.Logger) (Manager, error) {
+	m := &manager{
+		log:          log,
+		now:          time.Now,
+		targets:      make(map[string]string, len(targets)),
+		match:        make(map[string]*Version, len(targets)),
+		translations: make(map[string]*translator),
+		failures:     make(map[string]*failure),
+	}
+	for _, target := range targets {
+		family, version, err := GetFamilyAndVersion(target)
+		if err != nil {
+			return nil, err
+		}
+		m.targets[family] = target
+		m.match[family] = version
+	}
+	return m, nil
+}
+
+func (m *manager) RequestTranslation(ctx context.Context, schemaURL string) Translation {
+	family, version, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		m.log.Debug("No valid schema url was provided, using no-op schema",
+			zap.String("schema-url", schemaURL),
+			zap.Error(err),
+		)
+		return nopTranslation{}
+	}
+
+	target, match := m.match[family]
+	if !match || target.Equal(version) {
+		return nopTranslation{}
+	}
+
+	fileURL := m.schemaFileURL(family, version, schemaURL)
+	t, err := m.translation(ctx, fileURL, m.targets[family])
+	if err != nil {
+		logFailure := m.log.Error
+		if errors.Is(err, errBackoff) {
+			logFailure = m.log.Debug
+		}
+		logFailure("Failed to retrieve translation",
+			zap.String("schema-url", fileURL),
+			zap.Error(err),
+		)
+		return nopTranslation{}
+	}
+
+	if !t.SupportedVersion(version) {
+		m.log.Debug("Schema version is not part of the translation, using no-op schema",
+			zap.String("schema-url", schemaURL),
+		)
+		return nopTranslation{}
+	}
+	return t
+}
+
+func (m *manager) Prefetch(ctx context.Context, schemaURL string) error {
+	family, version, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		return err
+	}
+	if _, match := m.match[family]; !match {
+		return fmt.Errorf("%w: %s", errNotTarget, family)
+	}
+	_, err = m.translation(ctx, m.schemaFileURL(family, version, schemaURL), m.targets[family])
+	return err
+}
+
+// schemaFileURL returns the URL of the schema file translating the signals
+// published with the schema URL, of the given family and version, to the target.
+// A schema file lists all the versions up to its own, so upgrading
+// requires the schema file of the target while downgrading requires
+// the schema file of the incoming signal.
+func (m *manager) schemaFileURL(family string, version *Version, schemaURL string) string {
+	if version.GreaterThan(m.match[family]) {
+		return schemaURL
+	}
+	return m.targets[family]
+}
+
+// translation returns the cached translation of the schema file,
+// retrieving it if needed. A schema file that failed to be retrieved
+// is not requested again until its retry interval has passed.
+func (m *manager) translation(ctx context.Context, fileURL, targetSchemaURL string) (*translator, error) {
+	m.rw.RLock()
+	t, exist := m.translations[fileURL]
+	f, failed := m.failures[fileURL]
+	m.rw.RUnlock()
+	if exist {
+		return t, nil
+	}
+	if failed && m.now().Before(f.retryAt) {
+		return nil, fmt.Errorf("%w: %w", errBackoff, f.err)
+	}
+
+	t, err := m.retrieve(ctx, fileURL, targetSchemaURL)
+
+	m.rw.Lock()
+	defer m.rw.Unlock()
+	if err != nil {
+		interval := initialRetryInterval
+		if last, ok := m.failures[fileURL]; ok {
+			interval = min(2*last.interval, maxRetryInterval)
+		}
+		m.failures[fileURL] = &failure{err: err, interval: interval, retryAt: m.now().Add(interval)}
+		return nil, err
+	}
+	delete(m.failures, fileURL)
+	m.translations[fileURL] = t
+	return t, nil
+}
+
+func (m *manager) retrieve(ctx context.Context, fileURL, targetSchemaURL string) (*translator, error) {
+	m.rw.RLock()
+	providers := m.providers
+	m.rw.RUnlock()
+
+	if len(providers) == 0 {
+		return nil, errNilProviders
+	}
+
+	var errs error
+	for _, p := range providers {
+		content, err := p.Retrieve(ctx, fileURL)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		return newTranslatorFromReader(targetSchemaURL, bytes.NewReader(content))
+	}
+	return nil, errs
+}
+
+func (m *manager) SetProviders(providers ...Provider) error {
+	if len(providers) == 0 {
+		return errNilProviders
+	}
+	m.rw.Lock()
+	m.providers = append(m.providers[:0], providers...)
+	m.rw.Unlock()
+	return nil
+}
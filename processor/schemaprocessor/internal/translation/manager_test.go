@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/fixture"
+)
+
+// testProvider serves the test schema for every schema URL
+// of the test schema family and counts the requests made.
+type testProvider struct {
+	mu       sync.Mutex
+	requests map[string]int
+	content  []byte
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	return &testProvider{requests: make(map[string]int), content: content}
+}
+
+func (tp *testProvider) Retrieve(_ context.Context, schemaURL string) ([]byte, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	tp.requests[schemaURL]++
+	family, _, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	if family+"/" != testSchemaFamily {
+		return nil, ErrSchemaNotFound
+	}
+	return tp.content, nil
+}
+
+func TestManagerRequestTranslation(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManager([]string{testSchemaFamily + "1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.SetProviders(), errNilProviders)
+	assert.IsType(t, nopTranslation{}, m.RequestTranslation(context.Background(), testSchemaFamily+"1.0.0"), "Must not translate without providers")
+
+	p := newTestProvider(t)
+	require.NoError(t, m.SetProviders(p))
+
+	for _, tc := range []struct {
+		name      string
+		schemaURL string
+		expectNop bool
+	}{
+		{name: "invalid schema url", schemaURL: "opentelemetry.io/schemas", expectNop: true},
+		{name: "not a target family", schemaURL: "https://opentelemetry.io/schemas/1.0.0", expectNop: true},
+		{name: "target version", schemaURL: testSchemaFamily + "1.1.0", expectNop: true},
+		{name: "unknown version", schemaURL: testSchemaFamily + "0.1.0", expectNop: true},
+		{name: "upgrade", schemaURL: testSchemaFamily + "1.0.0"},
+		{name: "downgrade", schemaURL: testSchemaFamily + "1.3.0"},
+	} {
+		tn := m.RequestTranslation(context.Background(), tc.schemaURL)
+		if tc.expectNop {
+			assert.IsType(t, nopTranslation{}, tn, tc.name)
+		} else {
+			assert.IsType(t, (*translator)(nil), tn, tc.name)
+		}
+	}
+
+	// Upgrades use the schema file of the target while
+	// downgrades use the schema file of the incoming signal,
+	// each of them is retrieved once and then cached.
+	m.RequestTranslation(context.Background(), testSchemaFamily+"1.0.0")
+	m.RequestTranslation(context.Background(), testSchemaFamily+"1.3.0")
+	assert.Equal(t, map[string]int{
+		testSchemaFamily + "1.1.0": 1,
+		testSchemaFamily + "1.3.0": 1,
+	}, p.requests)
+}
+
+func TestManagerRequestTranslationConcurrent(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManager([]string{testSchemaFamily + "1.3.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.NoError(t, m.SetProviders(newTestProvider(t)))
+
+	fixture.ParallelRaceCompute(t, 10, func() error {
+		for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+			if !m.RequestTranslation(context.Background(), testSchemaFamily+v).SupportedVersion(&Version{1, 0, 0}) {
+				return ErrSchemaNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func TestManagerPrefetch(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManager([]string{testSchemaFamily + "1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	p := newTestProvider(t)
+	require.NoError(t, m.SetProviders(p))
+
+	assert.Error(t, m.Prefetch(context.Background(), "opentelemetry.io/schemas"))
+	assert.ErrorIs(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.0.0"), errNotTarget)
+
+	// The schema file of the target is the one upgrades are made with.
+	require.NoError(t, m.Prefetch(context.Background(), testSchemaFamily+"1.1.0"))
+	assert.Equal(t, map[string]int{testSchemaFamily + "1.1.0": 1}, p.requests)
+	assert.IsType(t, (*translator)(nil), m.RequestTranslation(context.Background(), testSchemaFamily+"1.0.0"))
+	assert.Equal(t, map[string]int{testSchemaFamily + "1.1.0": 1}, p.requests)
+}
+
+// failingProvider fails to retrieve any schema file
+// and counts the requests made.
+type failingProvider struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (fp *failingProvider) Retrieve(_ context.Context, _ string) ([]byte, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	fp.requests++
+	return nil, ErrSchemaNotFound
+}
+
+func TestManagerRetrievalBackoff(t *testing.T) {
+	t.Parallel()
+
+	tm, err := NewManager([]string{testSchemaFamily + "1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	m := tm.(*manager)
+	now := time.Unix(0, 0)
+	m.now = func() time.Time { return now }
+	p := &failingProvider{}
+	require.NoError(t, m.SetProviders(p))
+
+	request := func() {
+		assert.IsType(t, nopTranslation{}, m.RequestTranslation(context.Background(), testSchemaFamily+"1.0.0"))
+	}
+
+	request()
+	request()
+	assert.Equal(t, 1, p.requests, "Must not retry before the retry interval")
+	assert.ErrorIs(t, m.Prefetch(context.Background(), testSchemaFamily+"1.1.0"), errBackoff)
+
+	now = now.Add(initialRetryInterval)
+	request()
+	assert.Equal(t, 2, p.requests)
+
+	// The retry interval doubles with each consecutive failure.
+	now = now.Add(initialRetryInterval)
+	request()
+	assert.Equal(t, 2, p.requests)
+	now = now.Add(initialRetryInterval)
+	request()
+	assert.Equal(t, 3, p.requests)
+
+	// A successful retrieval clears the failure.
+	require.NoError(t, m.SetProviders(newTestProvider(t)))
+	now = now.Add(maxRetryInterval)
+	assert.IsType(t, (*translator)(nil), m.RequestTranslation(context.Background(), testSchemaFamily+"1.0.0"))
+	assert.Empty(t, m.failures)
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ErrSchemaNotFound is returned by a Provider that
+// isn't able to provide the requested schema file.
+var ErrSchemaNotFound = errors.New("schema file not found")
+
+// Provider allows for the schema files to be read from
+// different locations, such as the network or the local file system.
+type Provider interface {
+	// Retrieve returns the content of the schema file
+	// that is identified by the schema URL.
+	Retrieve(ctx context.Context, schemaURL string) ([]byte, error)
+}
+
+type httpProvider struct {
+	client *http.Client
+}
+
+var _ Provider = (*httpProvider)(nil)
+
+// NewHTTPProvider returns a Provider that downloads
+// the schema file from the schema URL.
+func NewHTTPProvider(client *http.Client) Provider {
+	return &httpProvider{client: client}
+}
+
+func (hp *httpProvider) Retrieve(ctx context.Context, schemaURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%q: %w", schemaURL, ErrSchemaNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("invalid status code returned for %q: %d", schemaURL, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+type fileProvider struct {
+	files map[string]string
+}
+
+var _ Provider = (*fileProvider)(nil)
+
+// NewFileProvider returns a Provider that reads the schema files
+// from the local file system, the files are keyed by the schema URL
+// they are used for.
+func NewFileProvider(files map[string]string) Provider {
+	return &fileProvider{files: files}
+}
+
+func (fp *fileProvider) Retrieve(_ context.Context, schemaURL string) ([]byte, error) {
+	path, ok := fp.files[schemaURL]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", schemaURL, ErrSchemaNotFound)
+	}
+	return os.ReadFile(path)
+}
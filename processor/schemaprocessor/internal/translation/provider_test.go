@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProviderRetrieve(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/1.3.0":
+			_, _ = w.Write(content)
+		case "/schemas/1.4.0":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	p := NewHTTPProvider(s.Client())
+
+	data, err := p.Retrieve(context.Background(), s.URL+"/schemas/1.3.0")
+	assert.NoError(t, err, "Must not error when retrieving the schema")
+	assert.Equal(t, content, data, "Must match the served schema")
+
+	_, err = p.Retrieve(context.Background(), s.URL+"/schemas/0.1.0")
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	_, err = p.Retrieve(context.Background(), s.URL+"/schemas/1.4.0")
+	assert.Error(t, err, "Must error on an unexpected status code")
+}
+
+func TestFileProviderRetrieve(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	p := NewFileProvider(map[string]string{
+		testSchemaFamily + "1.3.0": filepath.Join("testdata", "schema.yaml"),
+		testSchemaFamily + "1.4.0": filepath.Join("testdata", "missing.yaml"),
+	})
+
+	data, err := p.Retrieve(context.Background(), testSchemaFamily+"1.3.0")
+	assert.NoError(t, err, "Must not error when reading the schema")
+	assert.Equal(t, content, data, "Must match the schema file")
+
+	_, err = p.Retrieve(context.Background(), testSchemaFamily+"1.2.0")
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+
+	_, err = p.Retrieve(context.Background(), testSchemaFamily+"1.4.0")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
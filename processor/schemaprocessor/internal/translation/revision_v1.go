@@ -5,6 +5,7 @@ package translation // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"go.opentelemetry.io/otel/schema/v1.0/ast"
+	ast11 "go.opentelemetry.io/otel/schema/v1.1/ast"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
 )
@@ -12,36 +13,52 @@ import (
 // RevisionV1 represents all changes that are to be
 // applied to a signal at a given version.
 type RevisionV1 struct {
-	ver              *Version
-	all              *migrate.AttributeChangeSetSlice
-	resource         *migrate.AttributeChangeSetSlice
-	spans            *migrate.ConditionalAttributeSetSlice
-	eventNames       *migrate.SignalNameChangeSlice
-	eventAttrsOnSpan *migrate.ConditionalAttributeSetSlice
-	eventAttrsOnName *migrate.ConditionalAttributeSetSlice
-	metricsAttrs     *migrate.ConditionalAttributeSetSlice
-	metricNames      *migrate.SignalNameChangeSlice
+	ver          *Version
+	all          *migrate.AttributeChangeSetSlice
+	resource     *migrate.AttributeChangeSetSlice
+	spans        *migrate.ConditionalAttributeSetSlice
+	eventNames   *migrate.SignalNameChangeSlice
+	eventAttrs   *migrate.MultiConditionalAttributeSetSlice
+	logs         *migrate.AttributeChangeSetSlice
+	metricsAttrs *migrate.ConditionalAttributeSetSlice
+	metricNames  *migrate.SignalNameChangeSlice
+	metricSplits *migrate.MetricSplitSlice
 }
 
+const (
+	// fieldSpanName and fieldEventName are the fields that
+	// span event attribute changes can be conditioned on.
+	fieldSpanName  = "span.name"
+	fieldEventName = "event.name"
+)
+
 // NewRevision processes the VersionDef and assigns the version to this revision
 // to allow sorting within a slice.
 // Since VersionDef uses custom types for various definitions, it isn't possible
 // to cast those values into the primitives so each has to be processed together.
 // Generics would be handy here.
-func NewRevision(ver *Version, def ast.VersionDef) *RevisionV1 {
+// The version definition of the 1.1 file format is used since it
+// is a superset of the 1.0 file format, only adding metric splits.
+func NewRevision(ver *Version, def ast11.VersionDef) *RevisionV1 {
 	return &RevisionV1{
-		ver:              ver,
-		all:              newAttributeChangeSetSliceFromChanges(def.All),
-		resource:         newAttributeChangeSetSliceFromChanges(def.Resources),
-		spans:            newSpanConditionalAttributeSlice(def.Spans),
-		eventNames:       newSpanEventSignalSlice(def.SpanEvents),
-		eventAttrsOnSpan: newSpanEventConditionalSpans(def.SpanEvents),
-		eventAttrsOnName: newSpanEventConditionalNames(def.SpanEvents),
-		metricsAttrs:     newMetricConditionalSlice(def.Metrics),
-		metricNames:      newMetricNameSignalSlice(def.Metrics),
+		ver:          ver,
+		all:          newAttributeChangeSetSliceFromChanges(def.All),
+		resource:     newAttributeChangeSetSliceFromChanges(def.Resources),
+		spans:        newSpanConditionalAttributeSlice(def.Spans),
+		eventNames:   newSpanEventSignalSlice(def.SpanEvents),
+		eventAttrs:   newSpanEventConditionalAttributeSlice(def.SpanEvents),
+		logs:         newLogsAttributeChangeSetSlice(def.Logs),
+		metricsAttrs: newMetricConditionalSlice(def.Metrics),
+		metricNames:  newMetricNameSignalSlice(def.Metrics),
+		metricSplits: newMetricSplitSlice(def.Metrics),
 	}
 }
 
+// Version returns the version that the changes of the revision belong to.
+func (r *RevisionV1) Version() *Version {
+	return r.ver
+}
+
 func newAttributeChangeSetSliceFromChanges(attrs ast.Attributes) *migrate.AttributeChangeSetSlice {
 	values := make([]*migrate.AttributeChangeSet, 0, 10)
 	for _, at := range attrs.Changes {
@@ -75,27 +92,37 @@ func newSpanEventSignalSlice(events ast.SpanEvents) *migrate.SignalNameChangeSli
 	return migrate.NewSignalNameChangeSlice(values...)
 }
 
-func newSpanEventConditionalSpans(events ast.SpanEvents) *migrate.ConditionalAttributeSetSlice {
-	values := make([]*migrate.ConditionalAttributeSet, 0, 10)
+func newSpanEventConditionalAttributeSlice(events ast.SpanEvents) *migrate.MultiConditionalAttributeSetSlice {
+	values := make([]*migrate.MultiConditionalAttributeSet, 0, 10)
 	for _, ch := range events.Changes {
 		if rename := ch.RenameAttributes; rename != nil {
-			values = append(values, migrate.NewConditionalAttributeSet(rename.AttributeMap, rename.ApplyToSpans...))
+			matches := map[string][]string{
+				fieldSpanName:  make([]string, 0, len(rename.ApplyToSpans)),
+				fieldEventName: make([]string, 0, len(rename.ApplyToEvents)),
+			}
+			for _, name := range rename.ApplyToSpans {
+				matches[fieldSpanName] = append(matches[fieldSpanName], string(name))
+			}
+			for _, name := range rename.ApplyToEvents {
+				matches[fieldEventName] = append(matches[fieldEventName], string(name))
+			}
+			values = append(values, migrate.NewMultiConditionalAttributeSet(rename.AttributeMap, matches))
 		}
 	}
-	return migrate.NewConditionalAttributeSetSlice(values...)
+	return migrate.NewMultiConditionalAttributeSetSlice(values...)
 }
 
-func newSpanEventConditionalNames(events ast.SpanEvents) *migrate.ConditionalAttributeSetSlice {
-	values := make([]*migrate.ConditionalAttributeSet, 0, 10)
-	for _, ch := range events.Changes {
-		if rename := ch.RenameAttributes; rename != nil {
-			values = append(values, migrate.NewConditionalAttributeSet(rename.AttributeMap, rename.ApplyToEvents...))
+func newLogsAttributeChangeSetSlice(logs ast.Logs) *migrate.AttributeChangeSetSlice {
+	values := make([]*migrate.AttributeChangeSet, 0, 10)
+	for _, ch := range logs.Changes {
+		if renamed := ch.RenameAttributes; renamed != nil {
+			values = append(values, migrate.NewAttributeChangeSet(renamed.AttributeMap))
 		}
 	}
-	return migrate.NewConditionalAttributeSetSlice(values...)
+	return migrate.NewAttributeChangeSetSlice(values...)
 }
 
-func newMetricConditionalSlice(metrics ast.Metrics) *migrate.ConditionalAttributeSetSlice {
+func newMetricConditionalSlice(metrics ast11.Metrics) *migrate.ConditionalAttributeSetSlice {
 	values := make([]*migrate.ConditionalAttributeSet, 0, 10)
 	for _, ch := range metrics.Changes {
 		if rename := ch.RenameAttributes; rename != nil {
@@ -105,10 +132,22 @@ func newMetricConditionalSlice(metrics ast.Metrics) *migrate.ConditionalAttribut
 	return migrate.NewConditionalAttributeSetSlice(values...)
 }
 
-func newMetricNameSignalSlice(metrics ast.Metrics) *migrate.SignalNameChangeSlice {
+func newMetricNameSignalSlice(metrics ast11.Metrics) *migrate.SignalNameChangeSlice {
 	values := make([]*migrate.SignalNameChange, 0, 10)
 	for _, ch := range metrics.Changes {
-		values = append(values, migrate.NewSignalNameChange(ch.RenameMetrics))
+		if ch.RenameMetrics != nil {
+			values = append(values, migrate.NewSignalNameChange(ch.RenameMetrics))
+		}
 	}
 	return migrate.NewSignalNameChangeSlice(values...)
 }
+
+func newMetricSplitSlice(metrics ast11.Metrics) *migrate.MetricSplitSlice {
+	values := make([]*migrate.MetricSplit, 0, 10)
+	for _, ch := range metrics.Changes {
+		if split := ch.Split; split != nil {
+			values = append(values, migrate.NewMetricSplit(split.ApplyToMetric, split.ByAttribute, split.MetricsFromAttributes))
+		}
+	}
+	return migrate.NewMetricSplitSlice(values...)
+}
@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	schema "go.opentelemetry.io/otel/schema/v1.1"
+	ast11 "go.opentelemetry.io/otel/schema/v1.1/ast"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/alias"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
+)
+
+var errMismatchedFamily = errors.New("schema file belongs to a different schema family")
+
+// Translation defines the complete abstraction of schema translation file
+// that is defined as part of the https://opentelemetry.io/docs/specs/otel/schemas/file_format_v1.1.0/
+// Each instance of Translation is "Target Aware", meaning that given a schemaURL as an input
+// it will convert from the given input, to the configured target.
+type Translation interface {
+	// SupportedVersion checks to see if the provided version is defined as part
+	// of this translation since it is useful to know if the translation is missing
+	// updates.
+	SupportedVersion(v *Version) bool
+
+	// ApplyAllResourceChanges will modify the resource part of the incoming signals
+	// and update the schema URL of the resource to the target.
+	ApplyAllResourceChanges(in alias.Resource, inSchemaURL string) error
+
+	// ApplyScopeSpanChanges will modify all spans and span events within the incoming scope
+	// and update the schema URL of the scope, if one is set, to the target.
+	ApplyScopeSpanChanges(in ptrace.ScopeSpans, inSchemaURL string) error
+
+	// ApplyScopeLogChanges will modify all logs within the incoming scope
+	// and update the schema URL of the scope, if one is set, to the target.
+	ApplyScopeLogChanges(in plog.ScopeLogs, inSchemaURL string) error
+
+	// ApplyScopeMetricChanges will update all metrics, including renaming and splitting them,
+	// within the incoming scope and update the schema URL of the scope, if one is set, to the target.
+	ApplyScopeMetricChanges(in pmetric.ScopeMetrics, inSchemaURL string) error
+}
+
+// translator applies the revisions of a schema file to
+// convert signals to the target version of the schema family.
+type translator struct {
+	targetSchemaURL string
+	target          *Version
+	// revisions is sorted in ascending order of versions
+	revisions []*RevisionV1
+}
+
+var _ Translation = (*translator)(nil)
+
+func newTranslatorFromReader(targetSchemaURL string, content io.Reader) (*translator, error) {
+	def, err := schema.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return newTranslator(targetSchemaURL, def)
+}
+
+func newTranslator(targetSchemaURL string, def *ast11.Schema) (*translator, error) {
+	family, target, err := GetFamilyAndVersion(targetSchemaURL)
+	if err != nil {
+		return nil, err
+	}
+	defFamily, _, err := GetFamilyAndVersion(def.SchemaURL)
+	if err != nil {
+		return nil, err
+	}
+	if family != defFamily {
+		return nil, fmt.Errorf("expected %q but got %q: %w", family, defFamily, errMismatchedFamily)
+	}
+
+	t := &translator{
+		targetSchemaURL: targetSchemaURL,
+		target:          target,
+		revisions:       make([]*RevisionV1, 0, len(def.Versions)),
+	}
+	for v, changes := range def.Versions {
+		ver, err := NewVersion(string(v))
+		if err != nil {
+			return nil, err
+		}
+		t.revisions = append(t.revisions, NewRevision(ver, changes))
+	}
+	sort.Slice(t.revisions, func(i, j int) bool {
+		return t.revisions[i].ver.LessThan(t.revisions[j].ver)
+	})
+	return t, nil
+}
+
+func (t *translator) SupportedVersion(v *Version) bool {
+	i := sort.Search(len(t.revisions), func(i int) bool {
+		return !t.revisions[i].ver.LessThan(v)
+	})
+	return i < len(t.revisions) && t.revisions[i].ver.Equal(v)
+}
+
+// iterator returns the revisions that are required to move signals
+// from the provided version to the target in the order they need to be processed,
+// and whether the revisions are applied (upgrading) or rolled back (downgrading).
+func (t *translator) iterator(from *Version) ([]*RevisionV1, migrate.StateSelector) {
+	var revisions []*RevisionV1
+	switch {
+	case from.LessThan(t.target):
+		for _, rev := range t.revisions {
+			if rev.ver.GreaterThan(from) && !rev.ver.GreaterThan(t.target) {
+				revisions = append(revisions, rev)
+			}
+		}
+		return revisions, migrate.StateSelectorApply
+	case from.GreaterThan(t.target):
+		for i := len(t.revisions) - 1; i >= 0; i-- {
+			if rev := t.revisions[i]; rev.ver.GreaterThan(t.target) && !rev.ver.GreaterThan(from) {
+				revisions = append(revisions, rev)
+			}
+		}
+		return revisions, migrate.StateSelectorRollback
+	}
+	return nil, migrate.StateSelectorApply
+}
+
+func (t *translator) revisionsFor(inSchemaURL string) ([]*RevisionV1, migrate.StateSelector, error) {
+	_, ver, err := GetFamilyAndVersion(inSchemaURL)
+	if err != nil {
+		return nil, 0, err
+	}
+	revisions, ss := t.iterator(ver)
+	return revisions, ss, nil
+}
+
+func (t *translator) ApplyAllResourceChanges(in alias.Resource, inSchemaURL string) error {
+	revisions, ss, err := t.revisionsFor(inSchemaURL)
+	if err != nil {
+		return err
+	}
+	var errs error
+	attrs := in.Resource().Attributes()
+	for _, rev := range revisions {
+		switch ss {
+		case migrate.StateSelectorApply:
+			errs = multierr.Append(errs, rev.all.Apply(attrs))
+			errs = multierr.Append(errs, rev.resource.Apply(attrs))
+		case migrate.StateSelectorRollback:
+			errs = multierr.Append(errs, rev.resource.Rollback(attrs))
+			errs = multierr.Append(errs, rev.all.Rollback(attrs))
+		}
+	}
+	in.SetSchemaUrl(t.targetSchemaURL)
+	return errs
+}
+
+func (t *translator) ApplyScopeSpanChanges(in ptrace.ScopeSpans, inSchemaURL string) error {
+	revisions, ss, err := t.revisionsFor(inSchemaURL)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, rev := range revisions {
+		for i := 0; i < in.Spans().Len(); i++ {
+			span := in.Spans().At(i)
+			switch ss {
+			case migrate.StateSelectorApply:
+				errs = multierr.Append(errs, rev.all.Apply(span.Attributes()))
+				errs = multierr.Append(errs, rev.spans.Apply(span.Attributes(), span.Name()))
+			case migrate.StateSelectorRollback:
+				errs = multierr.Append(errs, rev.spans.Rollback(span.Attributes(), span.Name()))
+				errs = multierr.Append(errs, rev.all.Rollback(span.Attributes()))
+			}
+			for j := 0; j < span.Events().Len(); j++ {
+				errs = multierr.Append(errs, t.applySpanEventChanges(rev, ss, span.Name(), span.Events().At(j)))
+			}
+		}
+	}
+	if in.SchemaUrl() != "" {
+		in.SetSchemaUrl(t.targetSchemaURL)
+	}
+	return errs
+}
+
+func (t *translator) applySpanEventChanges(rev *RevisionV1, ss migrate.StateSelector, spanName string, event ptrace.SpanEvent) (errs error) {
+	switch ss {
+	case migrate.StateSelectorApply:
+		errs = multierr.Append(errs, rev.all.Apply(event.Attributes()))
+		errs = multierr.Append(errs, rev.eventAttrs.Apply(event.Attributes(), map[string]string{
+			fieldSpanName:  spanName,
+			fieldEventName: event.Name(),
+		}))
+		rev.eventNames.Apply(event)
+	case migrate.StateSelectorRollback:
+		rev.eventNames.Rollback(event)
+		errs = multierr.Append(errs, rev.eventAttrs.Rollback(event.Attributes(), map[string]string{
+			fieldSpanName:  spanName,
+			fieldEventName: event.Name(),
+		}))
+		errs = multierr.Append(errs, rev.all.Rollback(event.Attributes()))
+	}
+	return errs
+}
+
+func (t *translator) ApplyScopeLogChanges(in plog.ScopeLogs, inSchemaURL string) error {
+	revisions, ss, err := t.revisionsFor(inSchemaURL)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, rev := range revisions {
+		for i := 0; i < in.LogRecords().Len(); i++ {
+			attrs := in.LogRecords().At(i).Attributes()
+			switch ss {
+			case migrate.StateSelectorApply:
+				errs = multierr.Append(errs, rev.all.Apply(attrs))
+				errs = multierr.Append(errs, rev.logs.Apply(attrs))
+			case migrate.StateSelectorRollback:
+				errs = multierr.Append(errs, rev.logs.Rollback(attrs))
+				errs = multierr.Append(errs, rev.all.Rollback(attrs))
+			}
+		}
+	}
+	if in.SchemaUrl() != "" {
+		in.SetSchemaUrl(t.targetSchemaURL)
+	}
+	return errs
+}
+
+func (t *translator) ApplyScopeMetricChanges(in pmetric.ScopeMetrics, inSchemaURL string) error {
+	revisions, ss, err := t.revisionsFor(inSchemaURL)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, rev := range revisions {
+		if ss == migrate.StateSelectorRollback {
+			rev.metricSplits.Rollback(in.Metrics())
+		}
+		for i := 0; i < in.Metrics().Len(); i++ {
+			metric := in.Metrics().At(i)
+			switch ss {
+			case migrate.StateSelectorApply:
+				rangeDataPointAttributes(metric, func(attrs pcommon.Map) {
+					errs = multierr.Append(errs, rev.all.Apply(attrs))
+					errs = multierr.Append(errs, rev.metricsAttrs.Apply(attrs, metric.Name()))
+				})
+				rev.metricNames.Apply(metric)
+			case migrate.StateSelectorRollback:
+				rev.metricNames.Rollback(metric)
+				rangeDataPointAttributes(metric, func(attrs pcommon.Map) {
+					errs = multierr.Append(errs, rev.metricsAttrs.Rollback(attrs, metric.Name()))
+					errs = multierr.Append(errs, rev.all.Rollback(attrs))
+				})
+			}
+		}
+		if ss == migrate.StateSelectorApply {
+			rev.metricSplits.Apply(in.Metrics())
+		}
+	}
+	if in.SchemaUrl() != "" {
+		in.SetSchemaUrl(t.targetSchemaURL)
+	}
+	return errs
+}
+
+func rangeDataPointAttributes(m pmetric.Metric, fn func(attrs pcommon.Map)) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+			fn(m.Gauge().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+			fn(m.Sum().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+			fn(m.Histogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < m.ExponentialHistogram().DataPoints().Len(); i++ {
+			fn(m.ExponentialHistogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < m.Summary().DataPoints().Len(); i++ {
+			fn(m.Summary().DataPoints().At(i).Attributes())
+		}
+	}
+}
+
+// nopTranslation is used when the signals don't belong
+// to any of the targeted schema families, or when no translation
+// could be resolved for them, and leaves the signals untouched.
+type nopTranslation struct{}
+
+var _ Translation = (*nopTranslation)(nil)
+
+func (nopTranslation) SupportedVersion(_ *Version) bool {
+	return false
+}
+
+func (nopTranslation) ApplyAllResourceChanges(_ alias.Resource, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeSpanChanges(_ ptrace.ScopeSpans, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeLogChanges(_ plog.ScopeLogs, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeMetricChanges(_ pmetric.ScopeMetrics, _ string) error {
+	return nil
+}
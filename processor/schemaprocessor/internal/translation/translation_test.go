@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
+)
+
+const testSchemaFamily = "https://example.com/schemas/"
+
+func newTestTranslator(t *testing.T, target string) *translator {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err, "Must be able to open the test schema")
+	t.Cleanup(func() { assert.NoError(t, f.Close()) })
+
+	tn, err := newTranslatorFromReader(testSchemaFamily+target, f)
+	require.NoError(t, err, "Must be able to parse the test schema")
+	return tn
+}
+
+func TestTranslatorFromReaderErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTranslatorFromReader("https://opentelemetry.io/schemas/1.0.0", mustOpen(t, "schema.yaml"))
+	assert.ErrorIs(t, err, errMismatchedFamily)
+
+	_, err = newTranslatorFromReader(testSchemaFamily+"1.0.0", mustOpen(t, "invalid.yaml"))
+	assert.Error(t, err, "Must error on an invalid schema file")
+}
+
+func mustOpen(t *testing.T, name string) *os.File {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, f.Close()) })
+	return f
+}
+
+func TestTranslatorSupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "1.3.0")
+	for _, v := range []*Version{{1, 0, 0}, {1, 1, 0}, {1, 2, 0}, {1, 3, 0}} {
+		assert.True(t, tn.SupportedVersion(v), "Must support version %s", v)
+	}
+	for _, v := range []*Version{{0, 9, 0}, {1, 1, 1}, {1, 4, 0}} {
+		assert.False(t, tn.SupportedVersion(v), "Must not support version %s", v)
+	}
+}
+
+func TestTranslatorIterator(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		target   string
+		from     *Version
+		expect   []*Version
+		selector migrate.StateSelector
+	}{
+		{
+			name:     "upgrade",
+			target:   "1.2.0",
+			from:     &Version{1, 0, 0},
+			expect:   []*Version{{1, 1, 0}, {1, 2, 0}},
+			selector: migrate.StateSelectorApply,
+		},
+		{
+			name:     "downgrade",
+			target:   "1.1.0",
+			from:     &Version{1, 3, 0},
+			expect:   []*Version{{1, 3, 0}, {1, 2, 0}},
+			selector: migrate.StateSelectorRollback,
+		},
+		{
+			name:     "same version",
+			target:   "1.1.0",
+			from:     &Version{1, 1, 0},
+			expect:   nil,
+			selector: migrate.StateSelectorApply,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			revisions, ss := newTestTranslator(t, tc.target).iterator(tc.from)
+			var versions []*Version
+			for _, rev := range revisions {
+				versions = append(versions, rev.Version())
+			}
+			assert.Equal(t, tc.expect, versions)
+			assert.Equal(t, tc.selector, ss)
+		})
+	}
+}
+
+func TestTranslatorResource(t *testing.T) {
+	t.Parallel()
+
+	in := plog.NewResourceLogs()
+	in.Resource().Attributes().PutStr("deployment.environment", "production")
+	in.Resource().Attributes().PutStr("http.method", "GET")
+	in.SetSchemaUrl(testSchemaFamily + "1.0.0")
+
+	require.NoError(t, newTestTranslator(t, "1.3.0").ApplyAllResourceChanges(in, in.SchemaUrl()))
+	assert.Equal(t, testSchemaFamily+"1.3.0", in.SchemaUrl())
+	assert.Equal(t, map[string]any{
+		"deployment.environment.name": "production",
+		"http.request.method":         "GET",
+	}, in.Resource().Attributes().AsRaw())
+
+	require.NoError(t, newTestTranslator(t, "1.0.0").ApplyAllResourceChanges(in, in.SchemaUrl()))
+	assert.Equal(t, testSchemaFamily+"1.0.0", in.SchemaUrl())
+	assert.Equal(t, map[string]any{
+		"deployment.environment": "production",
+		"http.method":            "GET",
+	}, in.Resource().Attributes().AsRaw())
+}
+
+func TestTranslatorSpans(t *testing.T) {
+	t.Parallel()
+
+	in := ptrace.NewScopeSpans()
+	in.SetSchemaUrl(testSchemaFamily + "1.0.0")
+	checkout := in.Spans().AppendEmpty()
+	checkout.SetName("checkout")
+	checkout.Attributes().PutStr("db.cassandra.keyspace", "orders")
+	event := checkout.Events().AppendEmpty()
+	event.SetName("exception")
+	event.Attributes().PutStr("exception.message", "timed out")
+	event.Attributes().PutStr("http.method", "POST")
+	other := in.Spans().AppendEmpty()
+	other.SetName("payment")
+	event = other.Events().AppendEmpty()
+	event.SetName("exception")
+	event.Attributes().PutStr("exception.message", "declined")
+
+	original := ptrace.NewScopeSpans()
+	in.CopyTo(original)
+
+	require.NoError(t, newTestTranslator(t, "1.2.0").ApplyScopeSpanChanges(in, in.SchemaUrl()))
+	assert.Equal(t, testSchemaFamily+"1.2.0", in.SchemaUrl())
+	assert.Equal(t, map[string]any{"db.name": "orders"}, in.Spans().At(0).Attributes().AsRaw())
+	assert.Equal(t, "error", in.Spans().At(0).Events().At(0).Name())
+	assert.Equal(t, map[string]any{
+		"error.message":       "timed out",
+		"http.request.method": "POST",
+	}, in.Spans().At(0).Events().At(0).Attributes().AsRaw())
+	assert.Equal(t, "error", in.Spans().At(1).Events().At(0).Name())
+	assert.Equal(t, map[string]any{
+		"exception.message": "declined",
+	}, in.Spans().At(1).Events().At(0).Attributes().AsRaw(), "Must only rename attributes of matched spans")
+
+	require.NoError(t, newTestTranslator(t, "1.0.0").ApplyScopeSpanChanges(in, in.SchemaUrl()))
+	assert.Equal(t, original, in, "Must restore the original spans")
+}
+
+func TestTranslatorLogs(t *testing.T) {
+	t.Parallel()
+
+	in := plog.NewScopeLogs()
+	log := in.LogRecords().AppendEmpty()
+	log.Attributes().PutStr("process.executable", "otelcol")
+	log.Attributes().PutStr("http.method", "GET")
+
+	require.NoError(t, newTestTranslator(t, "1.3.0").ApplyScopeLogChanges(in, testSchemaFamily+"1.0.0"))
+	assert.Empty(t, in.SchemaUrl(), "Must not set the schema url of the scope when it wasn't set")
+	assert.Equal(t, map[string]any{
+		"process.executable.name": "otelcol",
+		"http.request.method":     "GET",
+	}, log.Attributes().AsRaw())
+
+	require.NoError(t, newTestTranslator(t, "1.1.0").ApplyScopeLogChanges(in, testSchemaFamily+"1.3.0"))
+	assert.Equal(t, map[string]any{
+		"process.executable.name": "otelcol",
+		"http.method":             "GET",
+	}, log.Attributes().AsRaw())
+}
+
+func TestTranslatorMetrics(t *testing.T) {
+	t.Parallel()
+
+	in := pmetric.NewScopeMetrics()
+	in.SetSchemaUrl(testSchemaFamily + "1.0.0")
+	m := in.Metrics().AppendEmpty()
+	m.SetName("container.cpu.usage.total")
+	m.SetEmptySum().DataPoints().AppendEmpty().SetDoubleValue(1)
+	m = in.Metrics().AppendEmpty()
+	m.SetName("system.cpu.time")
+	dp := m.SetEmptySum().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(2)
+	dp.Attributes().PutStr("cpu", "0")
+	m = in.Metrics().AppendEmpty()
+	m.SetName("system.paging.operations")
+	m.SetEmptySum()
+	for _, direction := range []string{"in", "out"} {
+		dp = m.Sum().DataPoints().AppendEmpty()
+		dp.SetIntValue(3)
+		dp.Attributes().PutStr("direction", direction)
+		dp.Attributes().PutStr("http.method", "GET")
+	}
+
+	original := testHelperMetricAttributes(in)
+
+	require.NoError(t, newTestTranslator(t, "1.3.0").ApplyScopeMetricChanges(in, in.SchemaUrl()))
+	assert.Equal(t, testSchemaFamily+"1.3.0", in.SchemaUrl())
+	names := make([]string, 0, in.Metrics().Len())
+	for i := 0; i < in.Metrics().Len(); i++ {
+		names = append(names, in.Metrics().At(i).Name())
+	}
+	assert.Equal(t, []string{
+		"container.cpu.time",
+		"system.cpu.time",
+		"system.paging.operations.in",
+		"system.paging.operations.out",
+	}, names)
+	assert.Equal(t, map[string]any{"cpu.id": "0"}, in.Metrics().At(1).Sum().DataPoints().At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"http.request.method": "GET"}, in.Metrics().At(2).Sum().DataPoints().At(0).Attributes().AsRaw())
+
+	require.NoError(t, newTestTranslator(t, "1.0.0").ApplyScopeMetricChanges(in, in.SchemaUrl()))
+	assert.Equal(t, testSchemaFamily+"1.0.0", in.SchemaUrl())
+	assert.Equal(t, original, testHelperMetricAttributes(in), "Must restore the original metrics")
+}
+
+// testHelperMetricAttributes returns the data point attributes of each metric,
+// since the order of attributes isn't preserved when metrics are split and merged.
+func testHelperMetricAttributes(in pmetric.ScopeMetrics) map[string][]map[string]any {
+	values := make(map[string][]map[string]any, in.Metrics().Len())
+	for i := 0; i < in.Metrics().Len(); i++ {
+		m := in.Metrics().At(i)
+		for j := 0; j < m.Sum().DataPoints().Len(); j++ {
+			values[m.Name()] = append(values[m.Name()], m.Sum().DataPoints().At(j).Attributes().AsRaw())
+		}
+	}
+	return values
+}
@@ -8,16 +8,24 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
 )
 
 type transformer struct {
-	targets []string
-	log     *zap.Logger
+	targets     []string
+	prefetch    []string
+	schemaFiles map[string]string
+	client      confighttp.ClientConfig
+	telemetry   component.TelemetrySettings
+	log         *zap.Logger
+	manager     translation.Manager
 }
 
 func newTransformer(
@@ -29,29 +37,143 @@ func newTransformer(
 	if !ok {
 		return nil, errors.New("invalid configuration provided")
 	}
+	m, err := translation.NewManager(cfg.Targets, set.Logger)
+	if err != nil {
+		return nil, err
+	}
 	return &transformer{
-		log:     set.Logger,
-		targets: cfg.Targets,
+		log:         set.Logger,
+		targets:     cfg.Targets,
+		prefetch:    cfg.Prefetch,
+		schemaFiles: cfg.SchemaFiles,
+		client:      cfg.ClientConfig,
+		telemetry:   set.TelemetrySettings,
+		manager:     m,
 	}, nil
 }
 
-func (t transformer) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+func (t transformer) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	for rl := 0; rl < ld.ResourceLogs().Len(); rl++ {
+		rLog := ld.ResourceLogs().At(rl)
+		resourceSchemaURL := rLog.SchemaUrl()
+		if resourceSchemaURL != "" {
+			t.report(resourceSchemaURL, t.manager.
+				RequestTranslation(ctx, resourceSchemaURL).
+				ApplyAllResourceChanges(rLog, resourceSchemaURL),
+			)
+		}
+		for sl := 0; sl < rLog.ScopeLogs().Len(); sl++ {
+			log := rLog.ScopeLogs().At(sl)
+			schemaURL := log.SchemaUrl()
+			if schemaURL == "" {
+				schemaURL = resourceSchemaURL
+			}
+			if schemaURL == "" {
+				continue
+			}
+			t.report(schemaURL, t.manager.
+				RequestTranslation(ctx, schemaURL).
+				ApplyScopeLogChanges(log, schemaURL),
+			)
+		}
+	}
 	return ld, nil
 }
 
-func (t transformer) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+func (t transformer) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	for rm := 0; rm < md.ResourceMetrics().Len(); rm++ {
+		rMetric := md.ResourceMetrics().At(rm)
+		resourceSchemaURL := rMetric.SchemaUrl()
+		if resourceSchemaURL != "" {
+			t.report(resourceSchemaURL, t.manager.
+				RequestTranslation(ctx, resourceSchemaURL).
+				ApplyAllResourceChanges(rMetric, resourceSchemaURL),
+			)
+		}
+		for sm := 0; sm < rMetric.ScopeMetrics().Len(); sm++ {
+			metric := rMetric.ScopeMetrics().At(sm)
+			schemaURL := metric.SchemaUrl()
+			if schemaURL == "" {
+				schemaURL = resourceSchemaURL
+			}
+			if schemaURL == "" {
+				continue
+			}
+			t.report(schemaURL, t.manager.
+				RequestTranslation(ctx, schemaURL).
+				ApplyScopeMetricChanges(metric, schemaURL),
+			)
+		}
+	}
 	return md, nil
 }
 
-func (t transformer) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+func (t transformer) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	for rt := 0; rt < td.ResourceSpans().Len(); rt++ {
+		rTrace := td.ResourceSpans().At(rt)
+		resourceSchemaURL := rTrace.SchemaUrl()
+		if resourceSchemaURL != "" {
+			t.report(resourceSchemaURL, t.manager.
+				RequestTranslation(ctx, resourceSchemaURL).
+				ApplyAllResourceChanges(rTrace, resourceSchemaURL),
+			)
+		}
+		for ss := 0; ss < rTrace.ScopeSpans().Len(); ss++ {
+			span := rTrace.ScopeSpans().At(ss)
+			schemaURL := span.SchemaUrl()
+			if schemaURL == "" {
+				schemaURL = resourceSchemaURL
+			}
+			if schemaURL == "" {
+				continue
+			}
+			t.report(schemaURL, t.manager.
+				RequestTranslation(ctx, schemaURL).
+				ApplyScopeSpanChanges(span, schemaURL),
+			)
+		}
+	}
 	return td, nil
 }
 
+// report logs the errors that happened while translating signals,
+// the signals are still forwarded since the errors are caused by
+// conflicting attribute names which don't prevent the translation.
+func (t transformer) report(schemaURL string, err error) {
+	if err != nil {
+		t.log.Debug("Issues translating signals",
+			zap.String("schema-url", schemaURL),
+			zap.Error(err),
+		)
+	}
+}
+
 // start will load the remote file definition if it isn't already cached
 // and resolve the schema translation file
-func (t *transformer) start(_ context.Context, _ component.Host) error {
-	for _, target := range t.targets {
-		t.log.Info("Fetching remote schema url", zap.String("schema-url", target))
+func (t *transformer) start(ctx context.Context, host component.Host) error {
+	var providers []translation.Provider
+	// Local schema files take priority so that the processor
+	// is able to work without access to the schema URLs.
+	if len(t.schemaFiles) > 0 {
+		providers = append(providers, translation.NewFileProvider(t.schemaFiles))
+	}
+	client, err := t.client.ToClient(host, t.telemetry)
+	if err != nil {
+		return err
+	}
+	providers = append(providers, translation.NewHTTPProvider(client))
+	if err := t.manager.SetProviders(providers...); err != nil {
+		return err
+	}
+
+	for _, schemaURL := range t.prefetch {
+		t.log.Info("Fetching remote schema url", zap.String("schema-url", schemaURL))
+		if err := t.manager.Prefetch(ctx, schemaURL); err != nil {
+			t.log.Warn("Failed to prefetch schema url",
+				zap.String("schema-url", schemaURL),
+				zap.Error(err),
+			)
+		}
 	}
 	return nil
 }
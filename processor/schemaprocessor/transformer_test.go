@@ -6,25 +6,43 @@ package schemaprocessor
 import (
 	"context"
 	_ "embed"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
 	"go.uber.org/zap/zaptest"
 )
 
+func newTestTransformerWithConfig(t *testing.T, cfg component.Config) *transformer {
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zaptest.NewLogger(t)
+	trans, err := newTransformer(context.Background(), cfg, set)
+	require.NoError(t, err, "Must not error when creating transformer")
+	return trans
+}
+
 func newTestTransformer(t *testing.T) *transformer {
-	trans, err := newTransformer(context.Background(), newDefaultConfiguration(), processor.CreateSettings{
-		TelemetrySettings: component.TelemetrySettings{
-			Logger: zaptest.NewLogger(t),
-		},
-	})
-	require.NoError(t, err, "Must not error when creating default transformer")
+	return newTestTransformerWithConfig(t, newDefaultConfiguration())
+}
+
+// newTestTranslatingTransformer returns a transformer that translates
+// signals of the test schema family to the provided version.
+func newTestTranslatingTransformer(t *testing.T, version string) *transformer {
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Targets = []string{"https://example.com/schemas/" + version}
+	cfg.SchemaFiles = make(map[string]string)
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		cfg.SchemaFiles["https://example.com/schemas/"+v] = filepath.Join("internal", "translation", "testdata", "schema.yaml")
+	}
+	trans := newTestTransformerWithConfig(t, cfg)
+	require.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()))
 	return trans
 }
 
@@ -32,7 +50,7 @@ func TestTransformerStart(t *testing.T) {
 	t.Parallel()
 
 	trans := newTestTransformer(t)
-	assert.NoError(t, trans.start(context.Background(), nil))
+	assert.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()))
 }
 
 func TestTransformerProcessing(t *testing.T) {
@@ -86,3 +104,56 @@ func TestTransformerProcessing(t *testing.T) {
 		assert.Equal(t, in, out, "Must return the same data (subject to change)")
 	})
 }
+
+func TestTransformerTranslation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("metrics", func(t *testing.T) {
+		in := pmetric.NewMetrics()
+		rm := in.ResourceMetrics().AppendEmpty()
+		rm.SetSchemaUrl("https://example.com/schemas/1.0.0")
+		rm.Resource().Attributes().PutStr("deployment.environment", "production")
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("container.cpu.usage.total")
+		m.SetEmptySum().DataPoints().AppendEmpty().SetDoubleValue(1)
+
+		out, err := newTestTranslatingTransformer(t, "1.3.0").processMetrics(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing metrics")
+		rm = out.ResourceMetrics().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.3.0", rm.SchemaUrl())
+		assert.Equal(t, map[string]any{"deployment.environment.name": "production"}, rm.Resource().Attributes().AsRaw())
+		assert.Equal(t, "container.cpu.time", rm.ScopeMetrics().At(0).Metrics().At(0).Name())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		in := ptrace.NewTraces()
+		rs := in.ResourceSpans().AppendEmpty()
+		rs.SetSchemaUrl("https://example.com/schemas/1.3.0")
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.SetSchemaUrl("https://example.com/schemas/1.2.0")
+		span := ss.Spans().AppendEmpty()
+		span.SetName("checkout")
+		span.Attributes().PutStr("db.name", "orders")
+
+		out, err := newTestTranslatingTransformer(t, "1.0.0").processTraces(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing traces")
+		rs = out.ResourceSpans().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.0.0", rs.SchemaUrl())
+		assert.Equal(t, "https://example.com/schemas/1.0.0", rs.ScopeSpans().At(0).SchemaUrl())
+		assert.Equal(t, map[string]any{"db.cassandra.keyspace": "orders"}, rs.ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl("https://example.com/schemas/1.0.0")
+		log := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		log.Attributes().PutStr("process.executable", "otelcol")
+
+		out, err := newTestTranslatingTransformer(t, "1.1.0").processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		rl = out.ResourceLogs().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.1.0", rl.SchemaUrl())
+		assert.Equal(t, map[string]any{"process.executable.name": "otelcol"}, rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+	})
+}
@@ -6,6 +6,8 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 import (
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -43,6 +45,9 @@ const (
 	// OTTLCondition sample traces which match user provided OpenTelemetry Transformation Language
 	// conditions.
 	OTTLCondition PolicyType = "ottl_condition"
+	// Adaptive samples traces at a target rate, computing a probability per key over a sliding
+	// window so that rare keys are always sampled.
+	Adaptive PolicyType = "adaptive"
 )
 
 // sharedPolicyCfg holds the common configuration to all policies that are used in derivative policy configurations
@@ -72,6 +77,8 @@ type sharedPolicyCfg struct {
 	BooleanAttributeCfg BooleanAttributeCfg `mapstructure:"boolean_attribute"`
 	// Configs for OTTL condition filter sampling policy evaluator
 	OTTLConditionCfg OTTLConditionCfg `mapstructure:"ottl_condition"`
+	// Configs for adaptive sampling policy evaluator.
+	AdaptiveCfg AdaptiveCfg `mapstructure:"adaptive"`
 }
 
 // CompositeSubPolicyCfg holds the common configuration to all policies under composite policy.
@@ -220,6 +227,35 @@ type OTTLConditionCfg struct {
 	SpanEventConditions []string       `mapstructure:"spanevent"`
 }
 
+// AdaptiveCfg holds the configurable settings to create an adaptive sampling
+// policy evaluator.
+type AdaptiveCfg struct {
+	// TracesPerSecond is the overall number of traces per second the policy aims to sample.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+	// KeyAttributes are the attributes which, together with the name of the root span, identify
+	// the key a trace is accounted to. They are looked up on the root span and then on its resource.
+	// Defaults to service.name.
+	KeyAttributes []string `mapstructure:"key_attributes"`
+	// Window is the duration of the sliding window over which the rate of each key is measured.
+	// Defaults to 30s.
+	Window time.Duration `mapstructure:"window"`
+}
+
+// DecisionCacheConfig holds the configurable settings of the cache of sampling
+// decisions, used to apply the original decision to spans arriving after their
+// trace has been released from memory.
+type DecisionCacheConfig struct {
+	// Size is the maximum number of decisions kept in the cache. Defaults to zero,
+	// i.e.: the cache is disabled and late spans are evaluated as a new trace.
+	Size int `mapstructure:"size"`
+	// StorageID is the optional storage extension used to persist the cached decisions
+	// across restarts of the collector.
+	StorageID *component.ID `mapstructure:"storage"`
+	// PersistInterval is the interval at which the cached decisions are saved to the
+	// storage extension, they are also saved on shutdown. Defaults to 1m.
+	PersistInterval time.Duration `mapstructure:"persist_interval"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	// DecisionWait is the desired wait time from the arrival of the first span of
@@ -234,4 +270,6 @@ type Config struct {
 	// PolicyCfgs sets the tail-based sampling policy which makes a sampling decision
 	// for a given trace when requested.
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
+	// DecisionCache holds the settings of the cache of sampling decisions.
+	DecisionCache DecisionCacheConfig `mapstructure:"decision_cache"`
 }
@@ -30,12 +30,18 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, component.UnmarshalConfig(sub, cfg))
 
+	fileStorageID := component.MustNewID("file_storage")
+
 	assert.Equal(t,
 		cfg,
 		&Config{
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
+			DecisionCache: DecisionCacheConfig{
+				Size:      1000,
+				StorageID: &fileStorageID,
+			},
 			PolicyCfgs: []PolicyCfg{
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
@@ -117,6 +123,17 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-12",
+						Type: Adaptive,
+						AdaptiveCfg: AdaptiveCfg{
+							TracesPerSecond: 100,
+							KeyAttributes:   []string{"service.name", "deployment.environment"},
+							Window:          time.Minute,
+						},
+					},
+				},
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
 						Name: "and-policy-1",
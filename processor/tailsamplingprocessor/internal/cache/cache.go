@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cache implements a bounded cache of sampling decisions keyed by
+// trace ID, optionally persisted through a storage extension client.
+package cache // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// storageKey is the key under which the snapshot of the cache is persisted.
+const storageKey = "decision_cache"
+
+// entrySize is the size of a single encoded entry: the trace ID followed by
+// one byte holding the decision.
+const entrySize = len(pcommon.TraceID{}) + 1
+
+var (
+	// ErrInvalidSize occurs when an invalid cache size is specified.
+	ErrInvalidSize = errors.New("invalid cache size, it must be greater than zero")
+	// errCorruptedSnapshot occurs when the persisted snapshot cannot be decoded.
+	errCorruptedSnapshot = errors.New("corrupted decision cache snapshot")
+)
+
+type entry struct {
+	id      pcommon.TraceID
+	sampled bool
+}
+
+// DecisionCache is a fixed size, least recently used cache of sampling decisions.
+// It is safe for concurrent use.
+type DecisionCache struct {
+	mu        sync.Mutex
+	size      int
+	ll        *list.List
+	items     map[pcommon.TraceID]*list.Element
+	onEvicted func(id pcommon.TraceID)
+}
+
+// New creates a DecisionCache holding at most size decisions. The optional onEvicted
+// function is called, with the cache lock held, every time a decision is evicted to
+// make room for a new one.
+func New(size int, onEvicted func(id pcommon.TraceID)) (*DecisionCache, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return &DecisionCache{
+		size:      size,
+		ll:        list.New(),
+		items:     make(map[pcommon.TraceID]*list.Element, size),
+		onEvicted: onEvicted,
+	}, nil
+}
+
+// Get returns the cached decision for the given trace ID. The second return
+// value reports whether a decision was found.
+func (c *DecisionCache) Get(id pcommon.TraceID) (sampled bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[id]
+	if !ok {
+		return false, false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*entry).sampled, true
+}
+
+// Put records the decision for the given trace ID, evicting the least recently
+// used decision if the cache is full.
+func (c *DecisionCache) Put(id pcommon.TraceID, sampled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(id, sampled)
+}
+
+func (c *DecisionCache) put(id pcommon.TraceID, sampled bool) {
+	if elem, ok := c.items[id]; ok {
+		elem.Value.(*entry).sampled = sampled
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	c.items[id] = c.ll.PushFront(&entry{id: id, sampled: sampled})
+	if c.ll.Len() <= c.size {
+		return
+	}
+
+	oldest := c.ll.Back()
+	c.ll.Remove(oldest)
+	evicted := oldest.Value.(*entry)
+	delete(c.items, evicted.id)
+	if c.onEvicted != nil {
+		c.onEvicted(evicted.id)
+	}
+}
+
+// Len returns the number of decisions currently held by the cache.
+func (c *DecisionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Load restores the decisions previously persisted with Save. Decisions already
+// present in the cache are kept and take precedence over the persisted ones.
+func (c *DecisionCache) Load(ctx context.Context, client storage.Client) error {
+	buf, err := client.Get(ctx, storageKey)
+	if err != nil {
+		return fmt.Errorf("failed to read decision cache from storage: %w", err)
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	if len(buf)%entrySize != 0 {
+		return errCorruptedSnapshot
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The snapshot is ordered from the least to the most recently used entry,
+	// replay it in that order so that the recency is preserved.
+	for off := 0; off < len(buf); off += entrySize {
+		var id pcommon.TraceID
+		copy(id[:], buf[off:off+len(id)])
+		if _, ok := c.items[id]; ok {
+			continue
+		}
+		c.put(id, buf[off+len(id)] == 1)
+	}
+	return nil
+}
+
+// Save persists all the decisions held by the cache, replacing any previously
+// persisted snapshot.
+func (c *DecisionCache) Save(ctx context.Context, client storage.Client) error {
+	c.mu.Lock()
+	buf := make([]byte, 0, c.ll.Len()*entrySize)
+	for elem := c.ll.Back(); elem != nil; elem = elem.Prev() {
+		e := elem.Value.(*entry)
+		buf = append(buf, e.id[:]...)
+		if e.sampled {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	}
+	c.mu.Unlock()
+
+	if err := client.Set(ctx, storageKey, buf); err != nil {
+		return fmt.Errorf("failed to write decision cache to storage: %w", err)
+	}
+	return nil
+}
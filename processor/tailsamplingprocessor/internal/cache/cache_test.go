@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestNewInvalidSize(t *testing.T) {
+	_, err := New(0, nil)
+	require.ErrorIs(t, err, ErrInvalidSize)
+}
+
+func TestGetPut(t *testing.T) {
+	c, err := New(2, nil)
+	require.NoError(t, err)
+
+	id1 := pcommon.TraceID([16]byte{1})
+	id2 := pcommon.TraceID([16]byte{2})
+
+	_, ok := c.Get(id1)
+	assert.False(t, ok)
+
+	c.Put(id1, true)
+	c.Put(id2, false)
+
+	sampled, ok := c.Get(id1)
+	assert.True(t, ok)
+	assert.True(t, sampled)
+
+	sampled, ok = c.Get(id2)
+	assert.True(t, ok)
+	assert.False(t, sampled)
+
+	// overriding an existing decision must not grow the cache
+	c.Put(id2, true)
+	sampled, ok = c.Get(id2)
+	assert.True(t, ok)
+	assert.True(t, sampled)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestEviction(t *testing.T) {
+	var evicted []pcommon.TraceID
+	c, err := New(2, func(id pcommon.TraceID) {
+		evicted = append(evicted, id)
+	})
+	require.NoError(t, err)
+
+	id1 := pcommon.TraceID([16]byte{1})
+	id2 := pcommon.TraceID([16]byte{2})
+	id3 := pcommon.TraceID([16]byte{3})
+
+	c.Put(id1, true)
+	c.Put(id2, true)
+	// touching id1 makes id2 the least recently used entry
+	_, _ = c.Get(id1)
+	c.Put(id3, true)
+
+	assert.Equal(t, []pcommon.TraceID{id2}, evicted)
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get(id2)
+	assert.False(t, ok)
+	_, ok = c.Get(id1)
+	assert.True(t, ok)
+	_, ok = c.Get(id3)
+	assert.True(t, ok)
+}
+
+func TestSaveLoad(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "")
+
+	id1 := pcommon.TraceID([16]byte{1})
+	id2 := pcommon.TraceID([16]byte{2})
+	id3 := pcommon.TraceID([16]byte{3})
+
+	c, err := New(3, nil)
+	require.NoError(t, err)
+	c.Put(id1, true)
+	c.Put(id2, false)
+	c.Put(id3, true)
+	require.NoError(t, c.Save(ctx, client))
+
+	restored, err := New(2, nil)
+	require.NoError(t, err)
+	require.NoError(t, restored.Load(ctx, client))
+
+	// the least recently used entry doesn't fit in the smaller cache
+	assert.Equal(t, 2, restored.Len())
+	_, ok := restored.Get(id1)
+	assert.False(t, ok)
+
+	sampled, ok := restored.Get(id2)
+	assert.True(t, ok)
+	assert.False(t, sampled)
+
+	sampled, ok = restored.Get(id3)
+	assert.True(t, ok)
+	assert.True(t, sampled)
+}
+
+func TestLoadEmptyStorage(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "")
+
+	c, err := New(1, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.Load(context.Background(), client))
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLoadCorruptedSnapshot(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "")
+	require.NoError(t, client.Set(ctx, storageKey, []byte{1, 2, 3}))
+
+	c, err := New(1, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, c.Load(ctx, client), errCorruptedSnapshot)
+}
@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+const (
+	// adaptiveWindowBuckets is the number of buckets the sliding window is divided into.
+	adaptiveWindowBuckets = 10
+	// adaptiveDefaultWindow is the sliding window used when none is configured.
+	adaptiveDefaultWindow = 30 * time.Second
+	// adaptiveThresholdPrecision is the number of hex digits used to encode the threshold in the trace state.
+	adaptiveThresholdPrecision = 4
+	// adaptiveKeySeparator separates the values forming a key.
+	adaptiveKeySeparator = "\x00"
+)
+
+var (
+	errAdaptiveTracesPerSecond = errors.New("traces_per_second must be greater than zero")
+	errAdaptiveWindow          = errors.New("window must not be negative")
+)
+
+// adaptiveKeyStats holds the number of traces seen for a key in each bucket of the sliding
+// window, and the threshold currently applied to the key.
+type adaptiveKeyStats struct {
+	counts    [adaptiveWindowBuckets]int64
+	total     int64
+	threshold otelsampling.Threshold
+}
+
+type adaptive struct {
+	tracesPerSecond float64
+	keyAttributes   []string
+	window          time.Duration
+	bucketDuration  time.Duration
+	logger          *zap.Logger
+	now             func() time.Time
+
+	// mu guards the sliding window, evaluations may run concurrently
+	mu            sync.Mutex
+	started       time.Time
+	currentBucket int64
+	keys          map[string]*adaptiveKeyStats
+}
+
+var (
+	_ PolicyEvaluator = (*adaptive)(nil)
+	_ ThresholdWriter = (*adaptive)(nil)
+)
+
+// NewAdaptive creates a policy evaluator sampling traces at the given overall rate. The rate of
+// each key, identified by the values of keyAttributes and the name of the root span, is measured
+// over a sliding window. Keys seen less often than their fair share of the rate are always sampled,
+// the others are sampled with the probability bringing them down to that share. The threshold
+// matching this probability is written into the trace state of the spans of the traces sampled
+// by this policy alone, see ThresholdWriter.
+func NewAdaptive(settings component.TelemetrySettings, tracesPerSecond float64, keyAttributes []string, window time.Duration) (PolicyEvaluator, error) {
+	if tracesPerSecond <= 0 {
+		return nil, errAdaptiveTracesPerSecond
+	}
+	if window < 0 {
+		return nil, errAdaptiveWindow
+	}
+	if window == 0 {
+		window = adaptiveDefaultWindow
+	}
+	if len(keyAttributes) == 0 {
+		keyAttributes = []string{"service.name"}
+	}
+
+	return &adaptive{
+		tracesPerSecond: tracesPerSecond,
+		keyAttributes:   keyAttributes,
+		window:          window,
+		bucketDuration:  window / adaptiveWindowBuckets,
+		logger:          settings.Logger,
+		now:             time.Now,
+		keys:            make(map[string]*adaptiveKeyStats),
+	}, nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (a *adaptive) Evaluate(_ context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error) {
+	a.logger.Debug("Evaluating spans in adaptive filter")
+
+	trace.Lock()
+	defer trace.Unlock()
+	key, rnd := a.keyAndRandomness(traceID, trace.ReceivedBatches)
+
+	threshold := a.count(key)
+	if !threshold.ShouldSample(rnd) {
+		return NotSampled, nil
+	}
+
+	trace.adaptiveThreshold = threshold
+	return Sampled, nil
+}
+
+// WriteThreshold records the threshold the trace was sampled with in the trace state of its spans.
+func (a *adaptive) WriteThreshold(trace *TraceData) {
+	trace.Lock()
+	defer trace.Unlock()
+	a.writeThreshold(trace.ReceivedBatches, trace.adaptiveThreshold)
+}
+
+// count accounts a trace to the key and returns the threshold currently applied to the key.
+func (a *adaptive) count(key string) otelsampling.Threshold {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.advance(a.now())
+
+	stats, ok := a.keys[key]
+	if !ok {
+		// keys seen for the first time are rare by definition
+		stats = &adaptiveKeyStats{threshold: otelsampling.AlwaysSampleThreshold}
+		a.keys[key] = stats
+	}
+	stats.counts[a.currentBucket%adaptiveWindowBuckets]++
+	stats.total++
+	return stats.threshold
+}
+
+// advance moves the sliding window to the bucket of now, recomputing the thresholds of all keys
+// whenever a bucket is left. The caller must hold mu.
+func (a *adaptive) advance(now time.Time) {
+	bucket := now.UnixNano() / int64(a.bucketDuration)
+	if a.started.IsZero() {
+		a.started = now
+		a.currentBucket = bucket
+		return
+	}
+	if bucket <= a.currentBucket {
+		return
+	}
+
+	for key, stats := range a.keys {
+		for b := a.currentBucket + 1; b <= bucket && b <= a.currentBucket+adaptiveWindowBuckets; b++ {
+			i := b % adaptiveWindowBuckets
+			stats.total -= stats.counts[i]
+			stats.counts[i] = 0
+		}
+		if stats.total == 0 {
+			delete(a.keys, key)
+		}
+	}
+	a.currentBucket = bucket
+
+	a.updateThresholds(now)
+}
+
+// updateThresholds computes the fair share of the target rate each key is entitled to, such that
+// keys below the share keep all their traces and the sum of all shares is the target rate.
+func (a *adaptive) updateThresholds(now time.Time) {
+	// the window isn't filled yet right after start, rates are measured over the elapsed time instead
+	measured := now.Sub(a.started)
+	if measured > a.window {
+		measured = a.window
+	}
+	if measured < a.bucketDuration {
+		measured = a.bucketDuration
+	}
+
+	rates := make([]float64, 0, len(a.keys))
+	for _, stats := range a.keys {
+		rates = append(rates, float64(stats.total)/measured.Seconds())
+	}
+	sort.Float64s(rates)
+
+	share := -1.0
+	remaining := a.tracesPerSecond
+	for i, rate := range rates {
+		fair := remaining / float64(len(rates)-i)
+		if rate > fair {
+			share = fair
+			break
+		}
+		remaining -= rate
+	}
+
+	for _, stats := range a.keys {
+		stats.threshold = otelsampling.AlwaysSampleThreshold
+		if share < 0 {
+			continue
+		}
+		rate := float64(stats.total) / measured.Seconds()
+		if rate <= share {
+			continue
+		}
+		probability := share / rate
+		if probability < otelsampling.MinSamplingProbability {
+			probability = otelsampling.MinSamplingProbability
+		}
+		threshold, err := otelsampling.ProbabilityToThresholdWithPrecision(probability, adaptiveThresholdPrecision)
+		if err != nil {
+			a.logger.Debug("Failed to compute sampling threshold", zap.Float64("probability", probability), zap.Error(err))
+			continue
+		}
+		stats.threshold = threshold
+	}
+}
+
+// keyAndRandomness returns the key the trace is accounted to and the randomness used for the
+// consistent sampling decision, taken from the root span or from the first span if the root span
+// hasn't been received.
+func (a *adaptive) keyAndRandomness(traceID pcommon.TraceID, batches ptrace.Traces) (string, otelsampling.Randomness) {
+	var (
+		span     ptrace.Span
+		resource pcommon.Resource
+		found    bool
+	)
+	for i := 0; i < batches.ResourceSpans().Len(); i++ {
+		rs := batches.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if !found || spans.At(k).ParentSpanID().IsEmpty() {
+					span, resource, found = spans.At(k), rs.Resource(), true
+				}
+				if span.ParentSpanID().IsEmpty() {
+					return a.key(span, resource), randomness(traceID, span)
+				}
+			}
+		}
+	}
+	if !found {
+		return "", otelsampling.TraceIDToRandomness(traceID)
+	}
+	return a.key(span, resource), randomness(traceID, span)
+}
+
+func (a *adaptive) key(span ptrace.Span, resource pcommon.Resource) string {
+	values := make([]string, 0, len(a.keyAttributes)+1)
+	for _, attr := range a.keyAttributes {
+		v, ok := span.Attributes().Get(attr)
+		if !ok {
+			v, ok = resource.Attributes().Get(attr)
+		}
+		if ok {
+			values = append(values, v.AsString())
+		} else {
+			values = append(values, "")
+		}
+	}
+	values = append(values, span.Name())
+	return strings.Join(values, adaptiveKeySeparator)
+}
+
+// randomness returns the explicit randomness of the trace state of the span, if any, or the
+// randomness of the trace ID.
+func randomness(traceID pcommon.TraceID, span ptrace.Span) otelsampling.Randomness {
+	if ts, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw()); err == nil {
+		if rnd, ok := ts.OTelValue().RValueRandomness(); ok {
+			return rnd
+		}
+	}
+	return otelsampling.TraceIDToRandomness(traceID)
+}
+
+// writeThreshold records the threshold the spans were sampled with in their trace state, unless
+// they were already sampled with a lower probability.
+func (a *adaptive) writeThreshold(batches ptrace.Traces, threshold otelsampling.Threshold) {
+	tvalue := threshold.TValue()
+	for i := 0; i < batches.ResourceSpans().Len(); i++ {
+		rs := batches.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				ts, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw())
+				if err != nil {
+					a.logger.Debug("Failed to parse trace state", zap.Error(err))
+					continue
+				}
+				if err = ts.OTelValue().UpdateTValueWithSampling(threshold, tvalue); err != nil {
+					// the span was sampled upstream with a lower probability, which is still effective
+					continue
+				}
+				var sb strings.Builder
+				if err = ts.Serialize(&sb); err != nil {
+					a.logger.Debug("Failed to serialize trace state", zap.Error(err))
+					continue
+				}
+				span.TraceState().FromRaw(sb.String())
+			}
+		}
+	}
+}
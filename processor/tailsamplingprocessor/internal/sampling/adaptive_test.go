@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+func newAdaptiveTrace(traceID pcommon.TraceID, service, rootName, traceState string) *TraceData {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	child := spans.AppendEmpty()
+	child.SetTraceID(traceID)
+	child.SetSpanID([8]byte{2})
+	child.SetParentSpanID([8]byte{1})
+	child.SetName("child")
+	child.TraceState().FromRaw(traceState)
+
+	root := spans.AppendEmpty()
+	root.SetTraceID(traceID)
+	root.SetSpanID([8]byte{1})
+	root.SetName(rootName)
+	root.TraceState().FromRaw(traceState)
+
+	spanCount := &atomic.Int64{}
+	spanCount.Store(2)
+	return &TraceData{
+		ReceivedBatches: traces,
+		SpanCount:       spanCount,
+	}
+}
+
+func newTestAdaptive(t *testing.T, tracesPerSecond float64, now *time.Time) *adaptive {
+	evaluator, err := NewAdaptive(componenttest.NewNopTelemetrySettings(), tracesPerSecond, nil, 10*time.Second)
+	require.NoError(t, err)
+	a := evaluator.(*adaptive)
+	a.now = func() time.Time { return *now }
+	return a
+}
+
+func TestNewAdaptiveInvalid(t *testing.T) {
+	_, err := NewAdaptive(componenttest.NewNopTelemetrySettings(), 0, nil, time.Second)
+	assert.ErrorIs(t, err, errAdaptiveTracesPerSecond)
+
+	_, err = NewAdaptive(componenttest.NewNopTelemetrySettings(), 10, nil, -time.Second)
+	assert.ErrorIs(t, err, errAdaptiveWindow)
+}
+
+func TestAdaptiveSamplesAllBelowTarget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	a := newTestAdaptive(t, 100, &now)
+
+	for i, traceID := range genRandomTraceIDs(50) {
+		now = now.Add(100 * time.Millisecond)
+		trace := newAdaptiveTrace(traceID, "frontend", "GET /", "")
+		decision, err := a.Evaluate(context.Background(), traceID, trace)
+		require.NoError(t, err)
+		assert.Equal(t, Sampled, decision, "trace %d", i)
+	}
+}
+
+func TestAdaptiveKeepsRareKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	a := newTestAdaptive(t, 10, &now)
+	traceIDs := genRandomTraceIDs(3000)
+
+	// warm up the first bucket: 1000 frequent traces and 5 rare ones per second
+	for _, traceID := range traceIDs[:1000] {
+		_, err := a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "frontend", "GET /", ""))
+		require.NoError(t, err)
+	}
+	for _, traceID := range traceIDs[1000:1005] {
+		_, err := a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "backend", "reindex", ""))
+		require.NoError(t, err)
+	}
+
+	now = now.Add(time.Second)
+
+	sampled := 0
+	for _, traceID := range traceIDs[1005:2005] {
+		trace := newAdaptiveTrace(traceID, "frontend", "GET /", "")
+		decision, err := a.Evaluate(context.Background(), traceID, trace)
+		require.NoError(t, err)
+		if decision != Sampled {
+			continue
+		}
+		sampled++
+
+		// the effective probability is recorded in the trace state of all spans
+		a.WriteThreshold(trace)
+		spans := trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			ts, err := otelsampling.NewW3CTraceState(spans.At(i).TraceState().AsRaw())
+			require.NoError(t, err)
+			th, ok := ts.OTelValue().TValueThreshold()
+			require.True(t, ok)
+			assert.InDelta(t, 0.005, th.Probability(), 0.0001)
+		}
+	}
+	// the frequent key gets the share left over by the rare key: 5 traces per second
+	assert.Greater(t, sampled, 0)
+	assert.Less(t, sampled, 15)
+
+	for _, traceID := range traceIDs[2005:2025] {
+		decision, err := a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "backend", "reindex", ""))
+		require.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+	}
+}
+
+func TestAdaptiveTraceState(t *testing.T) {
+	now := time.Unix(1000, 0)
+	a := newTestAdaptive(t, 1, &now)
+	traceIDs := genRandomTraceIDs(1000)
+	for _, traceID := range traceIDs {
+		_, err := a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "frontend", "GET /", ""))
+		require.NoError(t, err)
+	}
+	now = now.Add(time.Second)
+
+	// explicit randomness in the trace state takes precedence over the trace ID
+	trace := newAdaptiveTrace(traceIDs[0], "frontend", "GET /", "ot=rv:ffffffffffffff,vendor=value")
+	decision, err := a.Evaluate(context.Background(), traceIDs[0], trace)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the trace state is left untouched until the decision of the policy is applied
+	root := trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1)
+	assert.Equal(t, "ot=rv:ffffffffffffff,vendor=value", root.TraceState().AsRaw())
+
+	a.WriteThreshold(trace)
+	ts, err := otelsampling.NewW3CTraceState(root.TraceState().AsRaw())
+	require.NoError(t, err)
+	assert.Equal(t, "ffffffffffffff", ts.OTelValue().RValue())
+	assert.NotEmpty(t, ts.OTelValue().TValue())
+	assert.Equal(t, []otelsampling.KV{{Key: "vendor", Value: "value"}}, ts.ExtraValues())
+}
+
+func TestAdaptiveKeepsLowerUpstreamProbability(t *testing.T) {
+	now := time.Unix(1000, 0)
+	a := newTestAdaptive(t, 100, &now)
+	traceID := genRandomTraceIDs(1)[0]
+
+	// sampled upstream with a probability of 1/16, lower than the one of this policy
+	trace := newAdaptiveTrace(traceID, "frontend", "GET /", "ot=th:f;rv:ffffffffffffff")
+	decision, err := a.Evaluate(context.Background(), traceID, trace)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	a.WriteThreshold(trace)
+
+	root := trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1)
+	assert.Equal(t, "ot=th:f;rv:ffffffffffffff", root.TraceState().AsRaw())
+}
+
+func TestAdaptiveExpiresKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	a := newTestAdaptive(t, 10, &now)
+	traceID := genRandomTraceIDs(1)[0]
+
+	_, err := a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "frontend", "GET /", ""))
+	require.NoError(t, err)
+	assert.Len(t, a.keys, 1)
+
+	now = now.Add(time.Minute)
+	_, err = a.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "backend", "reindex", ""))
+	require.NoError(t, err)
+	assert.Len(t, a.keys, 1)
+	assert.Contains(t, a.keys, "backend\x00reindex")
+}
+
+func TestAdaptiveConcurrentEvaluation(t *testing.T) {
+	evaluator, err := NewAdaptive(componenttest.NewNopTelemetrySettings(), 10, nil, time.Second)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, traceID := range genRandomTraceIDs(100) {
+		wg.Add(1)
+		go func(traceID pcommon.TraceID) {
+			defer wg.Done()
+			_, err := evaluator.Evaluate(context.Background(), traceID, newAdaptiveTrace(traceID, "frontend", "GET /", ""))
+			assert.NoError(t, err)
+		}(traceID)
+	}
+	wg.Wait()
+}
@@ -11,6 +11,8 @@ import (
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 // TraceData stores the sampling related trace data.
@@ -28,6 +30,8 @@ type TraceData struct {
 	ReceivedBatches ptrace.Traces
 	// FinalDecision.
 	FinalDecision Decision
+	// adaptiveThreshold is the threshold the adaptive policy sampled the trace with.
+	adaptiveThreshold otelsampling.Threshold
 }
 
 // Decision gives the status of sampling decision.
@@ -63,3 +67,11 @@ type PolicyEvaluator interface {
 	// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 	Evaluate(ctx context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error)
 }
+
+// ThresholdWriter is implemented by the policy evaluators sampling traces with a probability that
+// is recorded in the trace state of their spans. The probability is only effective when the trace
+// is sampled because of this policy alone, so WriteThreshold must only be called in that case.
+type ThresholdWriter interface {
+	// WriteThreshold records the threshold the trace was sampled with in the trace state of its spans.
+	WriteThreshold(trace *TraceData)
+}
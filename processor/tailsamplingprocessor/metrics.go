@@ -24,6 +24,7 @@ var (
 
 	statTraceRemovalAgeSec           = stats.Int64("sampling_trace_removal_age", "Time (in seconds) from arrival of a new trace until its removal from memory", "s")
 	statLateSpanArrivalAfterDecision = stats.Int64("sampling_late_span_age", "Time (in seconds) from the sampling decision was taken and the arrival of a late span", "s")
+	statLateSpanDroppedCount         = stats.Int64("sampling_late_span_dropped", "Count of late spans dropped because their trace was not sampled", stats.UnitDimensionless)
 
 	statPolicyEvaluationErrorCount = stats.Int64("sampling_policy_evaluation_error", "Count of sampling policy evaluation errors", stats.UnitDimensionless)
 
@@ -34,6 +35,10 @@ var (
 	statDroppedTooEarlyCount    = stats.Int64("sampling_trace_dropped_too_early", "Count of traces that needed to be dropped before the configured wait time", stats.UnitDimensionless)
 	statNewTraceIDReceivedCount = stats.Int64("new_trace_id_received", "Counts the arrival of new traces", stats.UnitDimensionless)
 	statTracesOnMemoryGauge     = stats.Int64("sampling_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+
+	statDecisionCacheHitCount      = stats.Int64("sampling_decision_cache_hit", "Count of decision cache lookups that found the sampling decision of a released trace", stats.UnitDimensionless)
+	statDecisionCacheMissCount     = stats.Int64("sampling_decision_cache_miss", "Count of decision cache lookups that did not find a sampling decision", stats.UnitDimensionless)
+	statDecisionCacheEvictionCount = stats.Int64("sampling_decision_cache_eviction", "Count of sampling decisions evicted from the decision cache", stats.UnitDimensionless)
 )
 
 // samplingProcessorMetricViews return the metrics views according to given telemetry level.
@@ -75,6 +80,12 @@ func samplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 			Description: statLateSpanArrivalAfterDecision.Description(),
 			Aggregation: ageDistributionAggregation,
 		},
+		&view.View{
+			Name:        processorhelper.BuildCustomMetricName(metadata.Type.String(), statLateSpanDroppedCount.Name()),
+			Measure:     statLateSpanDroppedCount,
+			Description: statLateSpanDroppedCount.Description(),
+			Aggregation: view.Sum(),
+		},
 		&view.View{
 			Name:        processorhelper.BuildCustomMetricName(metadata.Type.String(), statPolicyEvaluationErrorCount.Name()),
 			Measure:     statPolicyEvaluationErrorCount,
@@ -112,6 +123,24 @@ func samplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 			Measure:     statTracesOnMemoryGauge,
 			Description: statTracesOnMemoryGauge.Description(),
 			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Name:        processorhelper.BuildCustomMetricName(metadata.Type.String(), statDecisionCacheHitCount.Name()),
+			Measure:     statDecisionCacheHitCount,
+			Description: statDecisionCacheHitCount.Description(),
+			Aggregation: view.Sum(),
+		},
+		&view.View{
+			Name:        processorhelper.BuildCustomMetricName(metadata.Type.String(), statDecisionCacheMissCount.Name()),
+			Measure:     statDecisionCacheMissCount,
+			Description: statDecisionCacheMissCount.Description(),
+			Aggregation: view.Sum(),
+		},
+		&view.View{
+			Name:        processorhelper.BuildCustomMetricName(metadata.Type.String(), statDecisionCacheEvictionCount.Name()),
+			Measure:     statDecisionCacheEvictionCount,
+			Description: statDecisionCacheEvictionCount.Description(),
+			Aggregation: view.Sum(),
 		})
 
 	if isMetricStatCountSpansSampledEnabled() {
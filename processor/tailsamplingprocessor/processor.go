@@ -5,6 +5,7 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"runtime"
@@ -16,12 +17,14 @@ import (
 	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -57,6 +60,18 @@ type tailSamplingSpanProcessor struct {
 	deleteChan      chan pcommon.TraceID
 	numTracesOnMap  *atomic.Uint64
 
+	// decisionCache keeps the decisions of released traces, so that late spans
+	// follow the original decision. It is nil when the cache is disabled.
+	decisionCache *cache.DecisionCache
+	componentID   component.ID
+	storageID     *component.ID
+	storageClient storage.Client
+	// persistInterval is the interval at which the decision cache is saved
+	// to the storage client, on top of the save done at shutdown.
+	persistInterval time.Duration
+	persistDone     chan struct{}
+	persistWG       sync.WaitGroup
+
 	// This is for reusing the slice by each call of `makeDecision`. This
 	// was previously identified to be a bottleneck using profiling.
 	mutatorsBuf []tag.Mutator
@@ -72,11 +87,26 @@ type spanAndScope struct {
 
 const (
 	sourceFormat = "tail_sampling"
+
+	// defaultDecisionCachePersistInterval is the interval at which the decision
+	// cache is persisted when no interval is configured.
+	defaultDecisionCachePersistInterval = time.Minute
 )
 
+// option configures optional settings of the tail sampling processor.
+type option func(*tailSamplingSpanProcessor)
+
+// withComponentID sets the ID of the processor instance, used to obtain a client
+// from the storage extension.
+func withComponentID(id component.ID) option {
+	return func(tsp *tailSamplingSpanProcessor) {
+		tsp.componentID = id
+	}
+}
+
 // newTracesProcessor returns a processor.TracesProcessor that will perform tail sampling according to the given
 // configuration.
-func newTracesProcessor(ctx context.Context, settings component.TelemetrySettings, nextConsumer consumer.Traces, cfg Config) (processor.Traces, error) {
+func newTracesProcessor(ctx context.Context, settings component.TelemetrySettings, nextConsumer consumer.Traces, cfg Config, opts ...option) (processor.Traces, error) {
 	policyNames := map[string]bool{}
 	policies := make([]*policy, len(cfg.PolicyCfgs))
 	for i := range cfg.PolicyCfgs {
@@ -129,6 +159,25 @@ func newTracesProcessor(ctx context.Context, settings component.TelemetrySetting
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 	tsp.deleteChan = make(chan pcommon.TraceID, cfg.NumTraces)
 
+	if cfg.DecisionCache.Size > 0 {
+		tsp.decisionCache, err = cache.New(cfg.DecisionCache.Size, func(pcommon.TraceID) {
+			stats.Record(tsp.ctx, statDecisionCacheEvictionCount.M(1))
+		})
+		if err != nil {
+			inBatcher.Stop()
+			return nil, err
+		}
+		tsp.storageID = cfg.DecisionCache.StorageID
+		tsp.persistInterval = cfg.DecisionCache.PersistInterval
+		if tsp.persistInterval <= 0 {
+			tsp.persistInterval = defaultDecisionCachePersistInterval
+		}
+	}
+
+	for _, opt := range opts {
+		opt(tsp)
+	}
+
 	return tsp, nil
 }
 
@@ -179,6 +228,9 @@ func getSharedPolicyEvaluator(settings component.TelemetrySettings, cfg *sharedP
 	case OTTLCondition:
 		ottlfCfg := cfg.OTTLConditionCfg
 		return sampling.NewOTTLConditionFilter(settings, ottlfCfg.SpanConditions, ottlfCfg.SpanEventConditions, ottlfCfg.ErrorMode)
+	case Adaptive:
+		aCfg := cfg.AdaptiveCfg
+		return sampling.NewAdaptive(settings, aCfg.TracesPerSecond, aCfg.KeyAttributes, aCfg.Window)
 
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
@@ -214,6 +266,10 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace.ReceivedBatches = ptrace.NewTraces()
 		trace.Unlock()
 
+		if tsp.decisionCache != nil {
+			tsp.decisionCache.Put(id, decision == sampling.Sampled)
+		}
+
 		if decision == sampling.Sampled {
 			_ = tsp.nextConsumer.ConsumeTraces(policy.ctx, allSpans)
 		}
@@ -288,6 +344,10 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		finalDecision = sampling.Sampled
 	}
 
+	if finalDecision == sampling.Sampled {
+		tsp.writeThreshold(trace)
+	}
+
 	mutators := tsp.mutatorsBuf
 	for i, p := range tsp.policies {
 		switch trace.Decisions[i] {
@@ -351,6 +411,26 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 	return finalDecision, matchingPolicy
 }
 
+// writeThreshold lets the policy sampling the trace record its sampling threshold in the trace
+// state of the spans. The threshold is only written if no other policy samples the trace, as the
+// trace is kept regardless of the probability of the policy otherwise.
+func (tsp *tailSamplingSpanProcessor) writeThreshold(trace *sampling.TraceData) {
+	var writer sampling.ThresholdWriter
+	for i, p := range tsp.policies {
+		if trace.Decisions[i] != sampling.Sampled {
+			continue
+		}
+		w, ok := p.evaluator.(sampling.ThresholdWriter)
+		if !ok || writer != nil {
+			return
+		}
+		writer = w
+	}
+	if writer != nil {
+		writer.WriteThreshold(trace)
+	}
+}
+
 // ConsumeTraces is required by the processor.Traces interface.
 func (tsp *tailSamplingSpanProcessor) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
 	resourceSpans := td.ResourceSpans()
@@ -383,7 +463,7 @@ func (tsp *tailSamplingSpanProcessor) groupSpansByTraceKey(resourceSpans ptrace.
 func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.ResourceSpans) {
 	// Group spans per their traceId to minimize contention on idToTrace
 	idToSpansAndScope := tsp.groupSpansByTraceKey(resourceSpans)
-	var newTraceIDs int64
+	var newTraceIDs, decisionCacheHits, decisionCacheMisses int64
 	for id, spans := range idToSpansAndScope {
 		lenSpans := int64(len(spans))
 		lenPolicies := len(tsp.policies)
@@ -392,6 +472,20 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 			initialDecisions[i] = sampling.Pending
 		}
 		d, loaded := tsp.idToTrace.Load(id)
+		if !loaded && tsp.decisionCache != nil {
+			// The trace may have been released from memory after its decision was
+			// taken, in which case the late spans follow the original decision.
+			if sampled, ok := tsp.decisionCache.Get(id); ok {
+				decisionCacheHits++
+				if sampled {
+					tsp.forwardLateSpans(resourceSpans, spans)
+				} else {
+					stats.Record(tsp.ctx, statLateSpanDroppedCount.M(lenSpans))
+				}
+				continue
+			}
+			decisionCacheMisses++
+		}
 		if !loaded {
 			spanCount := &atomic.Int64{}
 			spanCount.Store(lenSpans)
@@ -435,16 +529,11 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 
 			switch finalDecision {
 			case sampling.Sampled:
-				// Forward the spans to the policy destinations
-				traceTd := ptrace.NewTraces()
-				appendToTraces(traceTd, resourceSpans, spans)
-				if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, traceTd); err != nil {
-					tsp.logger.Warn(
-						"Error sending late arrived spans to destination",
-						zap.Error(err))
-				}
+				tsp.forwardLateSpans(resourceSpans, spans)
 			case sampling.NotSampled:
-				stats.Record(tsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(actualData.DecisionTime)/time.Second)))
+				stats.Record(tsp.ctx,
+					statLateSpanArrivalAfterDecision.M(int64(time.Since(actualData.DecisionTime)/time.Second)),
+					statLateSpanDroppedCount.M(lenSpans))
 			default:
 				tsp.logger.Warn("Encountered unexpected sampling decision",
 					zap.Int("decision", int(finalDecision)))
@@ -453,6 +542,22 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	}
 
 	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
+	if tsp.decisionCache != nil {
+		stats.Record(tsp.ctx,
+			statDecisionCacheHitCount.M(decisionCacheHits),
+			statDecisionCacheMissCount.M(decisionCacheMisses))
+	}
+}
+
+// forwardLateSpans forwards spans arriving after their trace was sampled to the policy destinations.
+func (tsp *tailSamplingSpanProcessor) forwardLateSpans(resourceSpans ptrace.ResourceSpans, spans []spanAndScope) {
+	traceTd := ptrace.NewTraces()
+	appendToTraces(traceTd, resourceSpans, spans)
+	if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, traceTd); err != nil {
+		tsp.logger.Warn(
+			"Error sending late arrived spans to destination",
+			zap.Error(err))
+	}
 }
 
 func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
@@ -460,16 +565,71 @@ func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.decisionCache != nil && tsp.storageID != nil {
+		client, err := getStorageClient(ctx, host, *tsp.storageID, tsp.componentID)
+		if err != nil {
+			return err
+		}
+		tsp.storageClient = client
+		if err = tsp.decisionCache.Load(ctx, tsp.storageClient); err != nil {
+			// A decision cache that can't be restored isn't fatal, the processor
+			// behaves as if it was started with an empty cache.
+			tsp.logger.Warn("Failed to restore the decision cache", zap.Error(err))
+		}
+		tsp.persistDone = make(chan struct{})
+		tsp.persistWG.Add(1)
+		go tsp.persistDecisionCache()
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
-	return nil
+	if tsp.storageClient == nil {
+		return nil
+	}
+	close(tsp.persistDone)
+	tsp.persistWG.Wait()
+	return errors.Join(
+		tsp.decisionCache.Save(ctx, tsp.storageClient),
+		tsp.storageClient.Close(ctx),
+	)
+}
+
+// persistDecisionCache periodically saves the decision cache to the storage
+// client, so that the decisions survive a crash of the collector.
+func (tsp *tailSamplingSpanProcessor) persistDecisionCache() {
+	defer tsp.persistWG.Done()
+	ticker := time.NewTicker(tsp.persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tsp.persistDone:
+			return
+		case <-ticker.C:
+			if err := tsp.decisionCache.Save(tsp.ctx, tsp.storageClient); err != nil {
+				tsp.logger.Warn("Failed to persist the decision cache", zap.Error(err))
+			}
+		}
+	}
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %q not found", storageID)
+	}
+
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension %q found", storageID)
+	}
+
+	return storageExt.GetClient(ctx, component.KindProcessor, componentID, "")
 }
 
 func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletionTime time.Time) {
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -23,7 +24,9 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -546,6 +549,44 @@ func TestSamplingMultiplePolicies(t *testing.T) {
 	require.Equal(t, expectedNumWithLateSpan, msp.SpanCount(), "late span was not accounted for")
 }
 
+func TestThresholdWrittenOnlyWhenDecisive(t *testing.T) {
+	mtw := &mockThresholdWriter{}
+	mpe := &mockPolicyEvaluator{}
+	tsp := &tailSamplingSpanProcessor{
+		ctx:    context.Background(),
+		logger: zap.NewNop(),
+		policies: []*policy{
+			{name: "threshold-policy", evaluator: mtw, ctx: context.TODO()},
+			{name: "mock-policy", evaluator: mpe, ctx: context.TODO()},
+		},
+		mutatorsBuf: make([]tag.Mutator, 1),
+	}
+	newTrace := func() *sampling.TraceData {
+		return &sampling.TraceData{
+			Decisions:       make([]sampling.Decision, len(tsp.policies)),
+			SpanCount:       &atomic.Int64{},
+			ReceivedBatches: ptrace.NewTraces(),
+		}
+	}
+
+	mtw.NextDecision = sampling.Sampled
+	mpe.NextDecision = sampling.NotSampled
+	decision, _ := tsp.makeDecision(uInt64ToTraceID(1), newTrace(), &policyMetrics{})
+	assert.Equal(t, sampling.Sampled, decision)
+	assert.Equal(t, 1, mtw.WriteCount, "the threshold must be written when the policy alone samples the trace")
+
+	mpe.NextDecision = sampling.Sampled
+	decision, _ = tsp.makeDecision(uInt64ToTraceID(2), newTrace(), &policyMetrics{})
+	assert.Equal(t, sampling.Sampled, decision)
+	assert.Equal(t, 1, mtw.WriteCount, "the threshold must not be written when another policy samples the trace")
+
+	mtw.NextDecision = sampling.Sampled
+	mpe.NextDecision = sampling.InvertNotSampled
+	decision, _ = tsp.makeDecision(uInt64ToTraceID(3), newTrace(), &policyMetrics{})
+	assert.Equal(t, sampling.NotSampled, decision)
+	assert.Equal(t, 1, mtw.WriteCount, "the threshold must not be written when the trace is not sampled")
+}
+
 func TestSamplingPolicyDecisionNotSampled(t *testing.T) {
 	const maxSize = 100
 	const decisionWaitSeconds = 5
@@ -742,6 +783,146 @@ func TestLateArrivingSpansAssignedOriginalDecision(t *testing.T) {
 	require.EqualValues(t, 0, nextConsumer.SpanCount(), "original final decision not honored")
 }
 
+func TestLateArrivingSpansOfReleasedTraceAssignedCachedDecision(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		decision      sampling.Decision
+		wantSpanCount int
+	}{
+		{name: "sampled", decision: sampling.Sampled, wantSpanCount: 2},
+		{name: "not sampled", decision: sampling.NotSampled, wantSpanCount: 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			const maxSize = 100
+			nextConsumer := new(consumertest.TracesSink)
+			mpe := &mockPolicyEvaluator{NextDecision: tt.decision}
+			decisionCache, err := cache.New(maxSize, nil)
+			require.NoError(t, err)
+			tsp := &tailSamplingSpanProcessor{
+				ctx:             context.Background(),
+				nextConsumer:    nextConsumer,
+				maxNumTraces:    maxSize,
+				logger:          zap.NewNop(),
+				decisionBatcher: newSyncIDBatcher(1),
+				policies:        []*policy{{name: "mock-policy", evaluator: mpe, ctx: context.TODO()}},
+				deleteChan:      make(chan pcommon.TraceID, maxSize),
+				policyTicker:    &manualTTicker{},
+				tickerFrequency: 100 * time.Millisecond,
+				numTracesOnMap:  &atomic.Uint64{},
+				mutatorsBuf:     make([]tag.Mutator, 1),
+				decisionCache:   decisionCache,
+			}
+			require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, tsp.Shutdown(context.Background()))
+			}()
+
+			traceID := uInt64ToTraceID(1)
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+			tsp.samplingPolicyOnTick()
+			tsp.samplingPolicyOnTick()
+			require.EqualValues(t, 1, mpe.EvaluationCount)
+
+			// Release the trace from memory, as it happens once num_traces is exceeded.
+			tsp.dropTrace(traceID, time.Now())
+			_, ok := tsp.idToTrace.Load(traceID)
+			require.False(t, ok)
+
+			// The late span must follow the cached decision instead of starting a new trace.
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+			tsp.samplingPolicyOnTick()
+			tsp.samplingPolicyOnTick()
+			require.EqualValues(t, 1, mpe.EvaluationCount)
+			_, ok = tsp.idToTrace.Load(traceID)
+			require.False(t, ok)
+			require.Equal(t, tt.wantSpanCount, nextConsumer.SpanCount())
+		})
+	}
+}
+
+func TestDecisionCachePersistedAcrossRestarts(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    uint64(100),
+		PolicyCfgs:   testPolicy,
+		DecisionCache: DecisionCacheConfig{
+			Size:      100,
+			StorageID: &ext.ID,
+		},
+	}
+	traceID := uInt64ToTraceID(1)
+
+	sp, err := newTracesProcessor(context.Background(), componenttest.NewNopTelemetrySettings(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, sp.Start(context.Background(), host))
+	sp.(*tailSamplingSpanProcessor).decisionCache.Put(traceID, true)
+	require.NoError(t, sp.Shutdown(context.Background()))
+
+	nextConsumer := new(consumertest.TracesSink)
+	sp, err = newTracesProcessor(context.Background(), componenttest.NewNopTelemetrySettings(), nextConsumer, cfg)
+	require.NoError(t, err)
+	require.NoError(t, sp.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, sp.Shutdown(context.Background()))
+	}()
+
+	// The span of the trace sampled before the restart is forwarded right away.
+	require.NoError(t, sp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	require.Equal(t, 1, nextConsumer.SpanCount())
+}
+
+func TestDecisionCachePersistedPeriodically(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	sp, err := newTracesProcessor(context.Background(), componenttest.NewNopTelemetrySettings(), consumertest.NewNop(), Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    uint64(100),
+		PolicyCfgs:   testPolicy,
+		DecisionCache: DecisionCacheConfig{
+			Size:            100,
+			StorageID:       &ext.ID,
+			PersistInterval: 10 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, sp.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, sp.Shutdown(context.Background()))
+	}()
+
+	tsp := sp.(*tailSamplingSpanProcessor)
+	traceID := uInt64ToTraceID(1)
+	tsp.decisionCache.Put(traceID, true)
+
+	// The decision is persisted without waiting for the shutdown of the processor.
+	require.Eventually(t, func() bool {
+		persisted, err := cache.New(100, nil)
+		if err != nil || persisted.Load(context.Background(), tsp.storageClient) != nil {
+			return false
+		}
+		sampled, ok := persisted.Get(traceID)
+		return ok && sampled
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDecisionCacheUnknownStorage(t *testing.T) {
+	storageID := component.MustNewID("unknown")
+	sp, err := newTracesProcessor(context.Background(), componenttest.NewNopTelemetrySettings(), consumertest.NewNop(), Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    uint64(100),
+		PolicyCfgs:   testPolicy,
+		DecisionCache: DecisionCacheConfig{
+			Size:      100,
+			StorageID: &storageID,
+		},
+	})
+	require.NoError(t, err)
+	require.Error(t, sp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, sp.Shutdown(context.Background()))
+}
+
 func TestMultipleBatchesAreCombinedIntoOne(t *testing.T) {
 	const maxSize = 100
 	const decisionWaitSeconds = 1
@@ -969,6 +1150,17 @@ func (m *mockPolicyEvaluator) Evaluate(context.Context, pcommon.TraceID, *sampli
 	return m.NextDecision, m.NextError
 }
 
+type mockThresholdWriter struct {
+	mockPolicyEvaluator
+	WriteCount int
+}
+
+var _ sampling.ThresholdWriter = (*mockThresholdWriter)(nil)
+
+func (m *mockThresholdWriter) WriteThreshold(*sampling.TraceData) {
+	m.WriteCount++
+}
+
 type manualTTicker struct {
 	Started bool
 }
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines configuration for the Prometheus remote write receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Path is the path the remote write requests are sent to. Defaults to /api/v1/write.
+	Path string `mapstructure:"path"`
+
+	// TrimMetricSuffixes removes the type and unit suffixes added by the Prometheus naming conventions
+	// from the names of the received metrics.
+	TrimMetricSuffixes bool `mapstructure:"trim_metric_suffixes"`
+
+	// MaxDecompressedSize is the maximum size in bytes of the requests once decompressed. Defaults to 32 MiB.
+	MaxDecompressedSize int `mapstructure:"max_decompressed_size"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	if cfg.Path == "" || cfg.Path[0] != '/' {
+		return errors.New("path must start with a /")
+	}
+	if cfg.MaxDecompressedSize <= 0 {
+		return errors.New("max_decompressed_size must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const receiverFormat = "prometheus_remote_write"
+
+var errNilNextConsumer = errors.New("nil next consumer")
+
+type prometheusRemoteWriteReceiver struct {
+	settings     receiver.CreateSettings
+	config       *Config
+	nextConsumer consumer.Metrics
+	obsrecv      *receiverhelper.ObsReport
+
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+func newReceiver(set receiver.CreateSettings, cfg *Config, nextConsumer consumer.Metrics) (*prometheusRemoteWriteReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	transport := "http"
+	if cfg.TLSSetting != nil {
+		transport = "https"
+	}
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &prometheusRemoteWriteReceiver{
+		settings:     set,
+		config:       cfg,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+	}, nil
+}
+
+// Start starts the HTTP server receiving the remote write requests.
+func (r *prometheusRemoteWriteReceiver) Start(_ context.Context, host component.Host) error {
+	ln, err := r.config.ServerConfig.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(r.config.Path, r.handleWrite)
+	r.server, err = r.config.ServerConfig.ToServer(host, r.settings.TelemetrySettings, mux)
+	if err != nil {
+		return err
+	}
+
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+		if errHTTP := r.server.Serve(ln); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			r.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the HTTP server.
+func (r *prometheusRemoteWriteReceiver) Shutdown(context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	err := r.server.Close()
+	r.shutdownWG.Wait()
+	return err
+}
+
+// handleWrite handles a remote write request. Malformed requests are answered with a client error, which
+// remote write clients don't retry, while the errors of the next consumer that aren't permanent are
+// answered with a server error so that the request is retried.
+func (r *prometheusRemoteWriteReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	protoMsg, err := protoMsgFromContentType(req.Header.Get("Content-Type"))
+	if err != nil {
+		r.fail(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" && encoding != "snappy" {
+		r.fail(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content encoding %q", encoding))
+		return
+	}
+
+	ctx := r.obsrecv.StartMetricsOp(req.Context())
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.obsrecv.EndMetricsOp(ctx, receiverFormat, 0, err)
+		r.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	writeReq, err := decodeRequest(protoMsg, body, r.config.MaxDecompressedSize)
+	if err != nil {
+		r.obsrecv.EndMetricsOp(ctx, receiverFormat, 0, err)
+		status := http.StatusBadRequest
+		if errors.Is(err, errRequestTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		r.fail(w, status, err)
+		return
+	}
+	md, stats, err := translate(writeReq, r.config.TrimMetricSuffixes)
+	if err != nil {
+		r.obsrecv.EndMetricsOp(ctx, receiverFormat, 0, err)
+		r.fail(w, http.StatusBadRequest, err)
+		return
+	}
+
+	dataPoints := md.DataPointCount()
+	if dataPoints > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	r.obsrecv.EndMetricsOp(ctx, receiverFormat, dataPoints, err)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if consumererror.IsPermanent(err) {
+			status = http.StatusBadRequest
+		}
+		r.fail(w, status, err)
+		return
+	}
+
+	if protoMsg == writev2.ProtoMsg {
+		w.Header().Set(samplesWrittenHeader, strconv.Itoa(stats.samples))
+		w.Header().Set(histogramsWrittenHeader, strconv.Itoa(stats.histograms))
+		w.Header().Set(exemplarsWrittenHeader, strconv.Itoa(stats.exemplars))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *prometheusRemoteWriteReceiver) fail(w http.ResponseWriter, status int, err error) {
+	r.settings.Logger.Debug("Failed to handle a remote write request", zap.Int("status_code", status), zap.Error(err))
+	http.Error(w, err.Error(), status)
+}
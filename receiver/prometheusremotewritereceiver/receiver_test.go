@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestReceiveRemoteWrite(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+	r, err := newReceiver(receivertest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	data, err := (&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{v1Series("up", 1, "job", "api")},
+	}).Marshal()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/api/v1/write", cfg.Endpoint), bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Close = true
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metric := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "up", metric.Name())
+	assert.Equal(t, 1.0, metric.Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestHandleWrite(t *testing.T) {
+	symbols := writev2.NewSymbolsTable()
+	v2Data, err := (&writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: symbols.SymbolizeLabels(nil, "__name__", "up", "job", "api"),
+				Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}, {Value: 1, Timestamp: 2000}},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+			},
+		},
+		Symbols: symbols.Symbols(),
+	}).Marshal()
+	require.NoError(t, err)
+
+	v1Data, err := (&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{v1Series("up", 1, "job", "api")},
+	}).Marshal()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name            string
+		method          string
+		contentType     string
+		contentEncoding string
+		body            []byte
+		consumer        consumer.Metrics
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedPoints  int
+	}{
+		{
+			name:           "remote write 1.0",
+			contentType:    "application/x-protobuf",
+			body:           snappy.Encode(nil, v1Data),
+			expectedStatus: http.StatusNoContent,
+			expectedPoints: 1,
+		},
+		{
+			name:           "remote write 2.0",
+			contentType:    writev2.ContentType,
+			body:           snappy.Encode(nil, v2Data),
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				samplesWrittenHeader:    "2",
+				histogramsWrittenHeader: "0",
+				exemplarsWrittenHeader:  "0",
+			},
+			expectedPoints: 2,
+		},
+		{
+			name:           "method not allowed",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "application/json",
+			body:           []byte("{}"),
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:            "unsupported content encoding",
+			contentType:     "application/x-protobuf",
+			contentEncoding: "gzip",
+			body:            snappy.Encode(nil, v1Data),
+			expectedStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "not compressed",
+			contentType:    writev2.ContentType,
+			body:           v2Data,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "too large",
+			contentType: writev2.ContentType,
+			// the snappy block format starts with the decompressed length
+			body:           binary.AppendUvarint(nil, defaultMaxDecompressedSize+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "retryable consumer error",
+			contentType:    writev2.ContentType,
+			body:           snappy.Encode(nil, v2Data),
+			consumer:       consumertest.NewErr(fmt.Errorf("queue is full")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "permanent consumer error",
+			contentType:    writev2.ContentType,
+			body:           snappy.Encode(nil, v2Data),
+			consumer:       consumertest.NewErr(consumererror.NewPermanent(fmt.Errorf("invalid metrics"))),
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			next := tt.consumer
+			if next == nil {
+				next = sink
+			}
+			r, err := newReceiver(receivertest.NewNopCreateSettings(), createDefaultConfig().(*Config), next)
+			require.NoError(t, err)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/api/v1/write", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			encoding := tt.contentEncoding
+			if encoding == "" {
+				encoding = "snappy"
+			}
+			req.Header.Set("Content-Encoding", encoding)
+			w := httptest.NewRecorder()
+			r.handleWrite(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			for header, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(header))
+			}
+			assert.Equal(t, tt.expectedPoints, sink.DataPointCount())
+			if tt.expectedPoints > 0 {
+				md := sink.AllMetrics()[0]
+				assert.Equal(t, pmetric.MetricTypeGauge, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Type())
+			}
+		})
+	}
+}
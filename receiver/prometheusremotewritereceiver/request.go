@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const (
+	// protoMsgV1 is the protobuf message of the remote write 1.0 requests.
+	protoMsgV1 = "prometheus.WriteRequest"
+
+	samplesWrittenHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	histogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	exemplarsWrittenHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+// errRequestTooLarge is returned when a request is larger than the maximum size once decompressed.
+var errRequestTooLarge = errors.New("the decompressed request is too large")
+
+// protoMsgFromContentType returns the protobuf message of a request from its content type.
+// Requests without a content type, or without a proto parameter, are remote write 1.0 requests.
+func protoMsgFromContentType(contentType string) (string, error) {
+	if contentType == "" {
+		return protoMsgV1, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", err
+	}
+	if mediaType != "application/x-protobuf" {
+		return "", fmt.Errorf("unsupported media type %q", mediaType)
+	}
+	switch msg := params["proto"]; msg {
+	case "", protoMsgV1:
+		return protoMsgV1, nil
+	case writev2.ProtoMsg:
+		return msg, nil
+	default:
+		return "", fmt.Errorf("unsupported protobuf message %q", msg)
+	}
+}
+
+// decodeRequest decodes a snappy compressed request of the given protobuf message, which must not be larger than
+// maxSize bytes once decompressed. Remote write 1.0 requests are converted to remote write 2.0 requests, so that
+// both versions are translated the same way.
+func decodeRequest(protoMsg string, body []byte, maxSize int) (*writev2.Request, error) {
+	size, err := snappy.DecodedLen(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the request: %w", err)
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes, the maximum is %d bytes", errRequestTooLarge, size, maxSize)
+	}
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the request: %w", err)
+	}
+
+	if protoMsg == writev2.ProtoMsg {
+		req := &writev2.Request{}
+		if err = req.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("failed to decode the request: %w", err)
+		}
+		return req, nil
+	}
+
+	var req prompb.WriteRequest
+	if err = req.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("failed to decode the request: %w", err)
+	}
+	return requestFromV1(&req), nil
+}
+
+// requestFromV1 converts a remote write 1.0 request to a remote write 2.0 request. The metadata of the
+// request is attached to the series of its metric family.
+func requestFromV1(req *prompb.WriteRequest) *writev2.Request {
+	symbols := writev2.NewSymbolsTable()
+
+	metadata := make(map[string]writev2.Metadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		metadata[m.MetricFamilyName] = writev2.Metadata{
+			Type:    writev2.Metadata_MetricType(m.Type),
+			HelpRef: symbols.Symbolize(m.Help),
+			UnitRef: symbols.Symbolize(m.Unit),
+		}
+	}
+
+	timeseries := make([]writev2.TimeSeries, len(req.Timeseries))
+	for i, ts := range req.Timeseries {
+		v2 := &timeseries[i]
+		var name string
+		v2.LabelsRefs = make([]uint32, 0, 2*len(ts.Labels))
+		for _, l := range ts.Labels {
+			v2.LabelsRefs = append(v2.LabelsRefs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+			if l.Name == model.MetricNameLabel {
+				name = l.Value
+			}
+		}
+		v2.Metadata = lookupMetadata(metadata, name)
+
+		for _, s := range ts.Samples {
+			v2.Samples = append(v2.Samples, writev2.Sample{Value: s.Value, Timestamp: s.Timestamp})
+		}
+		for _, h := range ts.Histograms {
+			v2.Histograms = append(v2.Histograms, histogramFromV1(h))
+		}
+		for _, e := range ts.Exemplars {
+			exemplar := writev2.Exemplar{Value: e.Value, Timestamp: e.Timestamp}
+			for _, l := range e.Labels {
+				exemplar.LabelsRefs = append(exemplar.LabelsRefs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+			}
+			v2.Exemplars = append(v2.Exemplars, exemplar)
+		}
+	}
+
+	return &writev2.Request{
+		Symbols:    symbols.Symbols(),
+		Timeseries: timeseries,
+	}
+}
+
+// lookupMetadata returns the metadata of the metric family of a series. The metadata of remote write 1.0
+// requests is keyed by the metric family name, which doesn't have the suffixes of the series of histograms,
+// summaries and, depending on the exposition format, counters.
+func lookupMetadata(metadata map[string]writev2.Metadata, name string) writev2.Metadata {
+	if m, ok := metadata[name]; ok {
+		return m
+	}
+	for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix, totalSuffix} {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			if m, ok := metadata[family]; ok {
+				return m
+			}
+		}
+	}
+	return writev2.Metadata{}
+}
+
+func histogramFromV1(h prompb.Histogram) writev2.Histogram {
+	v2 := writev2.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  bucketSpansFromV1(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  bucketSpansFromV1(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      writev2.Histogram_ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	switch count := h.Count.(type) {
+	case *prompb.Histogram_CountInt:
+		v2.Count = &writev2.Histogram_CountInt{CountInt: count.CountInt}
+	case *prompb.Histogram_CountFloat:
+		v2.Count = &writev2.Histogram_CountFloat{CountFloat: count.CountFloat}
+	}
+	switch zeroCount := h.ZeroCount.(type) {
+	case *prompb.Histogram_ZeroCountInt:
+		v2.ZeroCount = &writev2.Histogram_ZeroCountInt{ZeroCountInt: zeroCount.ZeroCountInt}
+	case *prompb.Histogram_ZeroCountFloat:
+		v2.ZeroCount = &writev2.Histogram_ZeroCountFloat{ZeroCountFloat: zeroCount.ZeroCountFloat}
+	}
+	return v2
+}
+
+func bucketSpansFromV1(spans []prompb.BucketSpan) []writev2.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	v2 := make([]writev2.BucketSpan, len(spans))
+	for i, span := range spans {
+		v2[i] = writev2.BucketSpan{Offset: span.Offset, Length: span.Length}
+	}
+	return v2
+}
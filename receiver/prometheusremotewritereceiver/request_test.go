@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestProtoMsgFromContentType(t *testing.T) {
+	for _, tt := range []struct {
+		contentType string
+		expected    string
+		expectedErr string
+	}{
+		{contentType: "", expected: protoMsgV1},
+		{contentType: "application/x-protobuf", expected: protoMsgV1},
+		{contentType: "application/x-protobuf;proto=prometheus.WriteRequest", expected: protoMsgV1},
+		{contentType: "application/x-protobuf;proto=io.prometheus.write.v2.Request", expected: writev2.ProtoMsg},
+		{contentType: "application/json", expectedErr: `unsupported media type "application/json"`},
+		{contentType: "application/x-protobuf;proto=io.prometheus.write.v3.Request", expectedErr: `unsupported protobuf message "io.prometheus.write.v3.Request"`},
+	} {
+		t.Run(tt.contentType, func(t *testing.T) {
+			msg, err := protoMsgFromContentType(tt.contentType)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestDecodeRequest(t *testing.T) {
+	v1 := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}},
+				Exemplars: []prompb.Exemplar{
+					{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 1, Timestamp: 1000},
+				},
+			},
+			{
+				Labels: []prompb.Label{{Name: "__name__", Value: "latency"}},
+				Histograms: []prompb.Histogram{{
+					Count:          &prompb.Histogram_CountInt{CountInt: 2},
+					Sum:            3,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 1}},
+					PositiveDeltas: []int64{1},
+					Timestamp:      1000,
+				}},
+			},
+		},
+		Metadata: []prompb.MetricMetadata{
+			{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "up", Help: "Whether the target is up"},
+		},
+	}
+	data, err := v1.Marshal()
+	require.NoError(t, err)
+
+	req, err := decodeRequest(protoMsgV1, snappy.Encode(nil, data), defaultMaxDecompressedSize)
+	require.NoError(t, err)
+	assert.Equal(t, &writev2.Request{
+		Symbols: []string{"", "Whether the target is up", "__name__", "up", "job", "api", "trace_id", "abc", "latency"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{2, 3, 4, 5},
+				Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+				Exemplars:  []writev2.Exemplar{{LabelsRefs: []uint32{6, 7}, Value: 1, Timestamp: 1000}},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE, HelpRef: 1},
+			},
+			{
+				LabelsRefs: []uint32{2, 8},
+				Histograms: []writev2.Histogram{{
+					Count:          &writev2.Histogram_CountInt{CountInt: 2},
+					Sum:            3,
+					ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 1}},
+					PositiveDeltas: []int64{1},
+					Timestamp:      1000,
+				}},
+			},
+		},
+	}, req)
+
+	data, err = req.Marshal()
+	require.NoError(t, err)
+	v2, err := decodeRequest(writev2.ProtoMsg, snappy.Encode(nil, data), defaultMaxDecompressedSize)
+	require.NoError(t, err)
+	assert.Equal(t, req, v2)
+
+	_, err = decodeRequest(protoMsgV1, data, defaultMaxDecompressedSize)
+	assert.ErrorContains(t, err, "failed to decompress the request")
+	_, err = decodeRequest(writev2.ProtoMsg, snappy.Encode(nil, []byte{0x22, 0x05}), defaultMaxDecompressedSize)
+	assert.EqualError(t, err, "failed to decode the request: unexpected EOF")
+	_, err = decodeRequest(writev2.ProtoMsg, snappy.Encode(nil, data), len(data)-1)
+	assert.ErrorIs(t, err, errRequestTooLarge)
+}
+
+func TestLookupMetadata(t *testing.T) {
+	metadata := map[string]writev2.Metadata{
+		"latency":        {Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		"requests":       {Type: writev2.Metadata_METRIC_TYPE_COUNTER},
+		"requests_total": {Type: writev2.Metadata_METRIC_TYPE_COUNTER, HelpRef: 1},
+	}
+	assert.Equal(t, metadata["latency"], lookupMetadata(metadata, "latency_bucket"))
+	assert.Equal(t, metadata["latency"], lookupMetadata(metadata, "latency_count"))
+	assert.Equal(t, metadata["requests_total"], lookupMetadata(metadata, "requests_total"))
+	assert.Equal(t, metadata["requests"], lookupMetadata(metadata, "requests_sum"))
+	assert.Equal(t, writev2.Metadata{}, lookupMetadata(metadata, "unknown"))
+}
@@ -0,0 +1,692 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const (
+	scopeName            = "otelcol/prometheusremotewritereceiver"
+	targetInfoMetricName = "target_info"
+
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+	totalSuffix  = "_total"
+
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+var errMissingMetricName = errors.New("series without a metric name")
+
+// writeStats counts what was written from a request, for the response headers of remote write 2.0.
+type writeStats struct {
+	samples    int
+	histograms int
+	exemplars  int
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+type exemplar struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// series is a time series of a request, with its labels resolved from the symbols of the request.
+type series struct {
+	name     string
+	job      string
+	instance string
+	// labels are all the labels of the series but the metric name, sorted by name
+	labels []label
+	// attributes are the labels of the series that are attributes of its data points
+	attributes []label
+	// signature identifies the attributes
+	signature string
+	help      string
+	unit      string
+	exemplars []exemplar
+	ts        *writev2.TimeSeries
+}
+
+func (s *series) label(name string) (string, bool) {
+	i := sort.Search(len(s.labels), func(i int) bool { return s.labels[i].name >= name })
+	if i < len(s.labels) && s.labels[i].name == name {
+		return s.labels[i].value, true
+	}
+	return "", false
+}
+
+// isBucket returns whether the series is a bucket of a classic histogram.
+func (s *series) isBucket() bool {
+	switch s.ts.Metadata.Type {
+	case writev2.Metadata_METRIC_TYPE_UNSPECIFIED, writev2.Metadata_METRIC_TYPE_HISTOGRAM, writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+		_, ok := s.label(model.BucketLabel)
+		return ok && strings.HasSuffix(s.name, bucketSuffix)
+	}
+	return false
+}
+
+// isQuantile returns whether the series is a quantile of a summary.
+func (s *series) isQuantile() bool {
+	switch s.ts.Metadata.Type {
+	case writev2.Metadata_METRIC_TYPE_UNSPECIFIED, writev2.Metadata_METRIC_TYPE_SUMMARY:
+		_, ok := s.label(model.QuantileLabel)
+		return ok
+	}
+	return false
+}
+
+// translate converts a remote write request to metrics, following in reverse the naming conventions of
+// the Prometheus translator: the job and instance labels identify the resource of the series, and the
+// labels of the target_info series of a resource are its attributes.
+func translate(req *writev2.Request, trimSuffixes bool) (pmetric.Metrics, writeStats, error) {
+	var stats writeStats
+	allSeries := make([]series, 0, len(req.Timeseries))
+	// the families of the classic histograms and summaries, whose _sum and _count series don't have metadata
+	// in remote write 1.0 requests
+	histogramFamilies := make(map[string]bool)
+	summaryFamilies := make(map[string]bool)
+	for i := range req.Timeseries {
+		s, err := newSeries(req.Symbols, &req.Timeseries[i])
+		if err != nil {
+			return pmetric.Metrics{}, stats, err
+		}
+		if s.isBucket() {
+			histogramFamilies[strings.TrimSuffix(s.name, bucketSuffix)] = true
+		}
+		if s.isQuantile() {
+			summaryFamilies[s.name] = true
+		}
+		allSeries = append(allSeries, s)
+	}
+
+	b := &metricsBuilder{
+		trimSuffixes: trimSuffixes,
+		resources:    make(map[resourceKey]*resourceBuilder),
+	}
+	for i := range allSeries {
+		s := &allSeries[i]
+		if err := b.addSeries(s, histogramFamilies, summaryFamilies); err != nil {
+			return pmetric.Metrics{}, stats, err
+		}
+		stats.samples += len(s.ts.Samples)
+		stats.histograms += len(s.ts.Histograms)
+		stats.exemplars += len(s.ts.Exemplars)
+	}
+	return b.build(), stats, nil
+}
+
+func newSeries(symbols []string, ts *writev2.TimeSeries) (series, error) {
+	labels, err := desymbolizeLabels(symbols, ts.LabelsRefs)
+	if err != nil {
+		return series{}, err
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+
+	s := series{ts: ts}
+	var signature strings.Builder
+	for _, l := range labels {
+		switch l.name {
+		case model.MetricNameLabel:
+			s.name = l.value
+			continue
+		case model.JobLabel:
+			s.job = l.value
+		case model.InstanceLabel:
+			s.instance = l.value
+		case model.BucketLabel, model.QuantileLabel:
+		default:
+			s.attributes = append(s.attributes, l)
+			signature.WriteString(l.name)
+			signature.WriteByte(0xff)
+			signature.WriteString(l.value)
+			signature.WriteByte(0xff)
+		}
+		s.labels = append(s.labels, l)
+	}
+	if s.name == "" {
+		return series{}, errMissingMetricName
+	}
+	s.signature = signature.String()
+
+	if s.help, err = writev2.Desymbolize(symbols, ts.Metadata.HelpRef); err != nil {
+		return series{}, err
+	}
+	if s.unit, err = writev2.Desymbolize(symbols, ts.Metadata.UnitRef); err != nil {
+		return series{}, err
+	}
+
+	for _, e := range ts.Exemplars {
+		labels, err := desymbolizeLabels(symbols, e.LabelsRefs)
+		if err != nil {
+			return series{}, err
+		}
+		s.exemplars = append(s.exemplars, exemplar{labels: labels, value: e.Value, timestamp: e.Timestamp})
+	}
+	return s, nil
+}
+
+func desymbolizeLabels(symbols []string, refs []uint32) ([]label, error) {
+	if len(refs)%2 != 0 {
+		return nil, fmt.Errorf("odd number of label references: %d", len(refs))
+	}
+	labels := make([]label, 0, len(refs)/2)
+	for i := 0; i < len(refs); i += 2 {
+		name, err := writev2.Desymbolize(symbols, refs[i])
+		if err != nil {
+			return nil, err
+		}
+		val, err := writev2.Desymbolize(symbols, refs[i+1])
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label{name: name, value: val})
+	}
+	return labels, nil
+}
+
+// role is how the samples of a series contribute to the data points of its metric.
+type role int
+
+const (
+	roleValue role = iota
+	roleBucket
+	roleQuantile
+	roleSum
+	roleCount
+	roleNativeHistogram
+)
+
+// classify returns the metric family of a series, the type of the metric of the family and the role of the series.
+func classify(s *series, histogramFamilies, summaryFamilies map[string]bool) (string, pmetric.MetricType, role) {
+	typ := s.ts.Metadata.Type
+	switch {
+	case len(s.ts.Histograms) > 0:
+		return s.name, pmetric.MetricTypeExponentialHistogram, roleNativeHistogram
+	case s.isBucket():
+		return strings.TrimSuffix(s.name, bucketSuffix), pmetric.MetricTypeHistogram, roleBucket
+	case s.isQuantile():
+		return s.name, pmetric.MetricTypeSummary, roleQuantile
+	}
+
+	for suffix, r := range map[string]role{sumSuffix: roleSum, countSuffix: roleCount} {
+		family, ok := strings.CutSuffix(s.name, suffix)
+		if !ok {
+			continue
+		}
+		switch {
+		case histogramFamilies[family] || typ == writev2.Metadata_METRIC_TYPE_HISTOGRAM || typ == writev2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+			return family, pmetric.MetricTypeHistogram, r
+		case summaryFamilies[family] || typ == writev2.Metadata_METRIC_TYPE_SUMMARY:
+			return family, pmetric.MetricTypeSummary, r
+		}
+	}
+
+	if typ == writev2.Metadata_METRIC_TYPE_COUNTER || (typ == writev2.Metadata_METRIC_TYPE_UNSPECIFIED && strings.HasSuffix(s.name, totalSuffix)) {
+		return s.name, pmetric.MetricTypeSum, roleValue
+	}
+	return s.name, pmetric.MetricTypeGauge, roleValue
+}
+
+type resourceKey struct {
+	job      string
+	instance string
+}
+
+type familyKey struct {
+	name string
+	typ  pmetric.MetricType
+}
+
+type pointKey struct {
+	signature string
+	timestamp int64
+}
+
+type metricsBuilder struct {
+	trimSuffixes bool
+	resources    map[resourceKey]*resourceBuilder
+	// order keeps the resources in the order of the request
+	order []*resourceBuilder
+}
+
+type resourceBuilder struct {
+	key        resourceKey
+	targetInfo []label
+	families   map[familyKey]*family
+	order      []*family
+}
+
+type family struct {
+	name   string
+	typ    pmetric.MetricType
+	help   string
+	unit   string
+	points map[pointKey]*point
+	order  []*point
+}
+
+type bucket struct {
+	bound float64
+	count float64
+}
+
+type quantile struct {
+	quantile float64
+	value    float64
+}
+
+type point struct {
+	attributes []label
+	timestamp  int64
+	created    int64
+	stale      bool
+	value      float64
+	sum        float64
+	hasSum     bool
+	count      float64
+	hasCount   bool
+	buckets    []bucket
+	quantiles  []quantile
+	histogram  *writev2.Histogram
+	exemplars  []exemplar
+}
+
+func (b *metricsBuilder) resource(key resourceKey) *resourceBuilder {
+	rb, ok := b.resources[key]
+	if !ok {
+		rb = &resourceBuilder{key: key, families: make(map[familyKey]*family)}
+		b.resources[key] = rb
+		b.order = append(b.order, rb)
+	}
+	return rb
+}
+
+func (b *metricsBuilder) addSeries(s *series, histogramFamilies, summaryFamilies map[string]bool) error {
+	rb := b.resource(resourceKey{job: s.job, instance: s.instance})
+	if s.name == targetInfoMetricName {
+		rb.targetInfo = append(rb.targetInfo, s.attributes...)
+		return nil
+	}
+
+	name, typ, r := classify(s, histogramFamilies, summaryFamilies)
+	key := familyKey{name: name, typ: typ}
+	f, ok := rb.families[key]
+	if !ok {
+		f = &family{name: name, typ: typ, points: make(map[pointKey]*point)}
+		rb.families[key] = f
+		rb.order = append(rb.order, f)
+	}
+	if f.help == "" {
+		f.help = s.help
+	}
+	if f.unit == "" {
+		f.unit = s.unit
+	}
+
+	var last *point
+	if r == roleNativeHistogram {
+		for i := range s.ts.Histograms {
+			h := &s.ts.Histograms[i]
+			last = f.point(s, h.Timestamp)
+			last.histogram = h
+			last.stale = value.IsStaleNaN(h.Sum)
+		}
+	}
+	for _, sample := range s.ts.Samples {
+		last = f.point(s, sample.Timestamp)
+		if value.IsStaleNaN(sample.Value) {
+			last.stale = true
+		}
+		switch r {
+		case roleValue:
+			last.value = sample.Value
+		case roleBucket:
+			le, _ := s.label(model.BucketLabel)
+			bound, err := strconv.ParseFloat(le, 64)
+			if err != nil {
+				return fmt.Errorf("invalid bucket bound %q of %s: %w", le, s.name, err)
+			}
+			last.buckets = append(last.buckets, bucket{bound: bound, count: sample.Value})
+		case roleQuantile:
+			q, _ := s.label(model.QuantileLabel)
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				return fmt.Errorf("invalid quantile %q of %s: %w", q, s.name, err)
+			}
+			last.quantiles = append(last.quantiles, quantile{quantile: v, value: sample.Value})
+		case roleSum:
+			last.sum, last.hasSum = sample.Value, true
+		case roleCount:
+			last.count, last.hasCount = sample.Value, true
+		}
+	}
+
+	// the exemplars are attached to the data point of their timestamp, if any, or to the latest one.
+	for _, e := range s.exemplars {
+		p, ok := f.points[pointKey{signature: s.signature, timestamp: e.timestamp}]
+		if !ok {
+			p = last
+		}
+		if p != nil {
+			p.exemplars = append(p.exemplars, e)
+		}
+	}
+	return nil
+}
+
+func (f *family) point(s *series, timestamp int64) *point {
+	key := pointKey{signature: s.signature, timestamp: timestamp}
+	p, ok := f.points[key]
+	if !ok {
+		p = &point{attributes: s.attributes, timestamp: timestamp}
+		f.points[key] = p
+		f.order = append(f.order, p)
+	}
+	if s.ts.CreatedTimestamp != 0 {
+		p.created = s.ts.CreatedTimestamp
+	}
+	return p
+}
+
+func (b *metricsBuilder) build() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, rb := range b.order {
+		if len(rb.order) == 0 {
+			continue
+		}
+		rm := md.ResourceMetrics().AppendEmpty()
+		attrs := rm.Resource().Attributes()
+		for _, l := range rb.targetInfo {
+			attrs.PutStr(l.name, l.value)
+		}
+		if rb.key.job != "" {
+			// the job of a resource with a service namespace is <service.namespace>/<service.name>
+			if namespace, name, ok := strings.Cut(rb.key.job, "/"); ok {
+				attrs.PutStr(conventions.AttributeServiceNamespace, namespace)
+				attrs.PutStr(conventions.AttributeServiceName, name)
+			} else {
+				attrs.PutStr(conventions.AttributeServiceName, rb.key.job)
+			}
+		}
+		if rb.key.instance != "" {
+			attrs.PutStr(conventions.AttributeServiceInstanceID, rb.key.instance)
+		}
+
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+		for _, f := range rb.order {
+			f.appendMetric(sm.Metrics(), b.trimSuffixes)
+		}
+	}
+	return md
+}
+
+func (f *family) appendMetric(metrics pmetric.MetricSlice, trimSuffixes bool) {
+	metric := metrics.AppendEmpty()
+	name := f.name
+	if trimSuffixes {
+		name = prometheustranslator.TrimPromSuffixes(name, f.typ, f.unit)
+	}
+	metric.SetName(name)
+	metric.SetDescription(f.help)
+	metric.SetUnit(prometheustranslator.UnitWordToUCUM(f.unit))
+
+	switch f.typ {
+	case pmetric.MetricTypeGauge:
+		dataPoints := metric.SetEmptyGauge().DataPoints()
+		for _, p := range f.order {
+			p.toNumberDataPoint(dataPoints.AppendEmpty())
+		}
+	case pmetric.MetricTypeSum:
+		sum := metric.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.order {
+			p.toNumberDataPoint(sum.DataPoints().AppendEmpty())
+		}
+	case pmetric.MetricTypeHistogram:
+		histogram := metric.SetEmptyHistogram()
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.order {
+			p.toHistogramDataPoint(histogram.DataPoints().AppendEmpty())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		histogram := metric.SetEmptyExponentialHistogram()
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for _, p := range f.order {
+			p.toExponentialHistogramDataPoint(histogram.DataPoints().AppendEmpty())
+		}
+	case pmetric.MetricTypeSummary:
+		dataPoints := metric.SetEmptySummary().DataPoints()
+		for _, p := range f.order {
+			p.toSummaryDataPoint(dataPoints.AppendEmpty())
+		}
+	}
+}
+
+func (p *point) setAttributes(dest pcommon.Map) {
+	dest.EnsureCapacity(len(p.attributes))
+	for _, l := range p.attributes {
+		dest.PutStr(l.name, l.value)
+	}
+}
+
+// flags returns the flags of the data point, which has no recorded value if it is a staleness marker.
+func (p *point) flags() pmetric.DataPointFlags {
+	return pmetric.DefaultDataPointFlags.WithNoRecordedValue(p.stale)
+}
+
+func (p *point) toNumberDataPoint(dp pmetric.NumberDataPoint) {
+	p.setAttributes(dp.Attributes())
+	dp.SetStartTimestamp(timestampFromMs(p.created))
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	dp.SetFlags(p.flags())
+	if !p.stale {
+		dp.SetDoubleValue(p.value)
+	}
+	setExemplars(p.exemplars, dp.Exemplars())
+}
+
+func (p *point) toHistogramDataPoint(dp pmetric.HistogramDataPoint) {
+	p.setAttributes(dp.Attributes())
+	dp.SetStartTimestamp(timestampFromMs(p.created))
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	dp.SetFlags(p.flags())
+	setExemplars(p.exemplars, dp.Exemplars())
+
+	sort.Slice(p.buckets, func(i, j int) bool { return p.buckets[i].bound < p.buckets[j].bound })
+	count := p.count
+	if !p.hasCount && len(p.buckets) > 0 {
+		count = p.buckets[len(p.buckets)-1].count
+	}
+	// for OTLP the bounds don't include +Inf
+	buckets := p.buckets
+	if len(buckets) > 0 && math.IsInf(buckets[len(buckets)-1].bound, 1) {
+		buckets = buckets[:len(buckets)-1]
+	}
+	bounds := make([]float64, len(buckets))
+	bucketCounts := make([]uint64, len(buckets)+1)
+	var previous float64
+	for i, b := range buckets {
+		bounds[i] = b.bound
+		if !p.stale {
+			bucketCounts[i] = uint64(math.Max(b.count-previous, 0))
+		}
+		previous = b.count
+	}
+	if !p.stale {
+		bucketCounts[len(buckets)] = uint64(math.Max(count-previous, 0))
+		dp.SetCount(uint64(count))
+		if p.hasSum {
+			dp.SetSum(p.sum)
+		}
+	}
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(bucketCounts)
+}
+
+func (p *point) toSummaryDataPoint(dp pmetric.SummaryDataPoint) {
+	p.setAttributes(dp.Attributes())
+	dp.SetStartTimestamp(timestampFromMs(p.created))
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	dp.SetFlags(p.flags())
+	if !p.stale {
+		dp.SetCount(uint64(p.count))
+		dp.SetSum(p.sum)
+	}
+
+	sort.Slice(p.quantiles, func(i, j int) bool { return p.quantiles[i].quantile < p.quantiles[j].quantile })
+	quantileValues := dp.QuantileValues()
+	for _, q := range p.quantiles {
+		qv := quantileValues.AppendEmpty()
+		qv.SetQuantile(q.quantile)
+		// the values of a staleness marker are left unset, a staleness NaN isn't a value
+		if !p.stale {
+			qv.SetValue(q.value)
+		}
+	}
+}
+
+// toExponentialHistogramDataPoint converts a native histogram. The bucket of index i of a native histogram is
+// the bucket of index i-1 of an exponential histogram of the same scale.
+func (p *point) toExponentialHistogramDataPoint(dp pmetric.ExponentialHistogramDataPoint) {
+	h := p.histogram
+	p.setAttributes(dp.Attributes())
+	dp.SetStartTimestamp(timestampFromMs(p.created))
+	dp.SetTimestamp(timestampFromMs(p.timestamp))
+	dp.SetFlags(p.flags())
+	setExemplars(p.exemplars, dp.Exemplars())
+	dp.SetScale(h.Schema)
+	dp.SetZeroThreshold(h.ZeroThreshold)
+	if p.stale {
+		return
+	}
+
+	if h.IsFloatHistogram() {
+		dp.SetCount(roundCount(h.GetCountFloat()))
+		dp.SetZeroCount(roundCount(h.GetZeroCountFloat()))
+	} else {
+		dp.SetCount(h.GetCountInt())
+		dp.SetZeroCount(h.GetZeroCountInt())
+	}
+	dp.SetSum(h.Sum)
+	setBuckets(dp.Positive(), h.PositiveSpans, h.PositiveDeltas, h.PositiveCounts)
+	setBuckets(dp.Negative(), h.NegativeSpans, h.NegativeDeltas, h.NegativeCounts)
+}
+
+// setBuckets sets the dense buckets of an exponential histogram from the sparse buckets of a native
+// histogram, given as deltas for an integer histogram and as counts for a float histogram.
+func setBuckets(dest pmetric.ExponentialHistogramDataPointBuckets, spans []writev2.BucketSpan, deltas []int64, counts []float64) {
+	if len(spans) == 0 {
+		return
+	}
+
+	var (
+		index     int32
+		first     = true
+		offset    int32
+		dense     []uint64
+		i         int
+		cumulated int64
+	)
+	for _, span := range spans {
+		index += span.Offset
+		for j := uint32(0); j < span.Length; j++ {
+			var count uint64
+			switch {
+			case i < len(deltas):
+				cumulated += deltas[i]
+				count = uint64(max(cumulated, 0))
+			case i < len(counts):
+				count = roundCount(counts[i])
+			}
+			i++
+
+			if first {
+				offset, first = index-1, false
+			}
+			for int32(len(dense)) < index-1-offset {
+				dense = append(dense, 0)
+			}
+			dense = append(dense, count)
+			index++
+		}
+	}
+	dest.SetOffset(offset)
+	dest.BucketCounts().FromRaw(dense)
+}
+
+func roundCount(count float64) uint64 {
+	if count <= 0 || math.IsNaN(count) {
+		return 0
+	}
+	return uint64(math.Round(count))
+}
+
+func setExemplars(exemplars []exemplar, dest pmetric.ExemplarSlice) {
+	dest.EnsureCapacity(len(exemplars))
+	for _, e := range exemplars {
+		pe := dest.AppendEmpty()
+		pe.SetTimestamp(timestampFromMs(e.timestamp))
+		pe.SetDoubleValue(e.value)
+		for _, l := range e.labels {
+			switch l.name {
+			case traceIDKey:
+				var traceID pcommon.TraceID
+				if decodeID(traceID[:], l.value) {
+					pe.SetTraceID(traceID)
+					continue
+				}
+			case spanIDKey:
+				var spanID pcommon.SpanID
+				if decodeID(spanID[:], l.value) {
+					pe.SetSpanID(spanID)
+					continue
+				}
+			}
+			pe.FilteredAttributes().PutStr(l.name, l.value)
+		}
+	}
+}
+
+// decodeID decodes an hexadecimal trace or span ID, and returns whether it is valid.
+func decodeID(dst []byte, id string) bool {
+	if hex.DecodedLen(len(id)) != len(dst) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(id))
+	return err == nil
+}
+
+func timestampFromMs(ms int64) pcommon.Timestamp {
+	if ms <= 0 {
+		return 0
+	}
+	return pcommon.Timestamp(ms * int64(time.Millisecond))
+}
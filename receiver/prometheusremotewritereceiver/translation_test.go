@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestTranslate(t *testing.T) {
+	symbols := writev2.NewSymbolsTable()
+	labels := func(nameValues ...string) []uint32 {
+		return symbols.SymbolizeLabels(nil, append([]string{"job", "shop/api", "instance", "host-1:8080"}, nameValues...)...)
+	}
+	staleNaN := math.Float64frombits(value.StaleNaN)
+
+	timeseries := []writev2.TimeSeries{
+		{
+			LabelsRefs: labels("__name__", "http_requests_total", "method", "GET"),
+			Samples:    []writev2.Sample{{Value: 10, Timestamp: 2000}},
+			Exemplars: []writev2.Exemplar{{
+				LabelsRefs: symbols.SymbolizeLabels(nil, "trace_id", "0102030405060708090a0b0c0d0e0f10", "span_id", "0102030405060708", "user", "alice"),
+				Value:      1,
+				Timestamp:  1500,
+			}},
+			Metadata: writev2.Metadata{
+				Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+				HelpRef: symbols.Symbolize("Number of requests"),
+			},
+			CreatedTimestamp: 1000,
+		},
+		{
+			LabelsRefs: labels("__name__", "target_info", "host_name", "host-1"),
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+		},
+		{
+			LabelsRefs: labels("__name__", "temperature"),
+			Samples:    []writev2.Sample{{Value: 21.5, Timestamp: 2000}, {Value: staleNaN, Timestamp: 3000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE, UnitRef: symbols.Symbolize("celsius")},
+		},
+		{
+			LabelsRefs: labels("__name__", "latency_bucket", "le", "1"),
+			Samples:    []writev2.Sample{{Value: 3, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		},
+		{
+			LabelsRefs: labels("__name__", "latency_bucket", "le", "0.5"),
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		},
+		{
+			LabelsRefs: labels("__name__", "latency_bucket", "le", "+Inf"),
+			Samples:    []writev2.Sample{{Value: 4, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		},
+		{
+			LabelsRefs: labels("__name__", "latency_sum"),
+			Samples:    []writev2.Sample{{Value: 2.5, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		},
+		{
+			LabelsRefs: labels("__name__", "latency_count"),
+			Samples:    []writev2.Sample{{Value: 4, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+		},
+		{
+			LabelsRefs: labels("__name__", "rpc", "quantile", "0.9"),
+			Samples:    []writev2.Sample{{Value: 2, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+		},
+		{
+			LabelsRefs: labels("__name__", "rpc", "quantile", "0.5"),
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+		},
+		{
+			LabelsRefs: labels("__name__", "rpc_sum"),
+			Samples:    []writev2.Sample{{Value: 10, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+		},
+		{
+			LabelsRefs: labels("__name__", "rpc_count"),
+			Samples:    []writev2.Sample{{Value: 5, Timestamp: 2000}},
+			Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+		},
+		{
+			LabelsRefs: labels("__name__", "native"),
+			Histograms: []writev2.Histogram{{
+				Count:          &writev2.Histogram_CountInt{CountInt: 4},
+				Sum:            10,
+				ZeroThreshold:  1e-128,
+				ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+				PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 1}, {Offset: 1, Length: 1}},
+				PositiveDeltas: []int64{1, 1},
+				NegativeSpans:  []writev2.BucketSpan{{Offset: -1, Length: 1}},
+				NegativeDeltas: []int64{1},
+				Timestamp:      2000,
+			}},
+			Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+			CreatedTimestamp: 1000,
+		},
+	}
+
+	md, stats, err := translate(&writev2.Request{Symbols: symbols.Symbols(), Timeseries: timeseries}, false)
+	require.NoError(t, err)
+	assert.Equal(t, writeStats{samples: 13, histograms: 1, exemplars: 1}, stats)
+
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{
+		"service.namespace":   "shop",
+		"service.name":        "api",
+		"service.instance.id": "host-1:8080",
+		"host_name":           "host-1",
+	}, rm.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rm.ScopeMetrics().Len())
+	assert.Equal(t, "otelcol/prometheusremotewritereceiver", rm.ScopeMetrics().At(0).Scope().Name())
+	metrics := rm.ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 5, metrics.Len())
+
+	requests := metrics.At(0)
+	assert.Equal(t, "http_requests_total", requests.Name())
+	assert.Equal(t, "Number of requests", requests.Description())
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.True(t, requests.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	require.Equal(t, 1, requests.Sum().DataPoints().Len())
+	dp := requests.Sum().DataPoints().At(0)
+	assert.Equal(t, map[string]any{"method": "GET"}, dp.Attributes().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(1000e6), dp.StartTimestamp())
+	assert.Equal(t, pcommon.Timestamp(2000e6), dp.Timestamp())
+	assert.Equal(t, 10.0, dp.DoubleValue())
+	require.Equal(t, 1, dp.Exemplars().Len())
+	exemplar := dp.Exemplars().At(0)
+	assert.Equal(t, pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, exemplar.TraceID())
+	assert.Equal(t, pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8}, exemplar.SpanID())
+	assert.Equal(t, map[string]any{"user": "alice"}, exemplar.FilteredAttributes().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(1500e6), exemplar.Timestamp())
+
+	temperature := metrics.At(1)
+	assert.Equal(t, "temperature", temperature.Name())
+	assert.Equal(t, "Cel", temperature.Unit())
+	require.Equal(t, pmetric.MetricTypeGauge, temperature.Type())
+	require.Equal(t, 2, temperature.Gauge().DataPoints().Len())
+	assert.Equal(t, 21.5, temperature.Gauge().DataPoints().At(0).DoubleValue())
+	assert.False(t, temperature.Gauge().DataPoints().At(0).Flags().NoRecordedValue())
+	assert.True(t, temperature.Gauge().DataPoints().At(1).Flags().NoRecordedValue())
+	assert.Equal(t, pcommon.Timestamp(3000e6), temperature.Gauge().DataPoints().At(1).Timestamp())
+
+	latency := metrics.At(2)
+	assert.Equal(t, "latency", latency.Name())
+	require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+	require.Equal(t, 1, latency.Histogram().DataPoints().Len())
+	hdp := latency.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(4), hdp.Count())
+	assert.Equal(t, 2.5, hdp.Sum())
+	assert.Equal(t, []float64{0.5, 1}, hdp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 2, 1}, hdp.BucketCounts().AsRaw())
+	assert.Empty(t, hdp.Attributes().AsRaw())
+
+	rpc := metrics.At(3)
+	assert.Equal(t, "rpc", rpc.Name())
+	require.Equal(t, pmetric.MetricTypeSummary, rpc.Type())
+	require.Equal(t, 1, rpc.Summary().DataPoints().Len())
+	sdp := rpc.Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(5), sdp.Count())
+	assert.Equal(t, 10.0, sdp.Sum())
+	require.Equal(t, 2, sdp.QuantileValues().Len())
+	assert.Equal(t, 0.5, sdp.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 1.0, sdp.QuantileValues().At(0).Value())
+	assert.Equal(t, 0.9, sdp.QuantileValues().At(1).Quantile())
+	assert.Equal(t, 2.0, sdp.QuantileValues().At(1).Value())
+
+	native := metrics.At(4)
+	assert.Equal(t, "native", native.Name())
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, native.Type())
+	require.Equal(t, 1, native.ExponentialHistogram().DataPoints().Len())
+	edp := native.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(1000e6), edp.StartTimestamp())
+	assert.Equal(t, uint64(4), edp.Count())
+	assert.Equal(t, 10.0, edp.Sum())
+	assert.Equal(t, int32(0), edp.Scale())
+	assert.Equal(t, uint64(1), edp.ZeroCount())
+	assert.Equal(t, 1e-128, edp.ZeroThreshold())
+	assert.Equal(t, int32(0), edp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 0, 2}, edp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, int32(-2), edp.Negative().Offset())
+	assert.Equal(t, []uint64{1}, edp.Negative().BucketCounts().AsRaw())
+}
+
+func TestTranslateWithoutMetadata(t *testing.T) {
+	req := requestFromV1(&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series("jobs_total", 3, "job", "worker"),
+			v1Series("queue_size", 7, "job", "worker"),
+			v1Series("latency_bucket", 1, "job", "worker", "le", "0.1"),
+			v1Series("latency_bucket", 2, "job", "worker", "le", "+Inf"),
+			v1Series("latency_sum", 0.3, "job", "worker"),
+			v1Series("latency_count", 2, "job", "worker"),
+			v1Series("gc", 0.01, "job", "worker", "quantile", "0.5"),
+			v1Series("gc_sum", 0.2, "job", "worker"),
+			v1Series("gc_count", 8, "job", "worker"),
+			v1Series("queue_size", 9, "job", "other"),
+		},
+	})
+
+	md, _, err := translate(req, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+	assert.Equal(t, map[string]any{"service.name": "worker"}, md.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"service.name": "other"}, md.ResourceMetrics().At(1).Resource().Attributes().AsRaw())
+
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	types := map[string]pmetric.MetricType{}
+	for i := 0; i < metrics.Len(); i++ {
+		types[metrics.At(i).Name()] = metrics.At(i).Type()
+	}
+	assert.Equal(t, map[string]pmetric.MetricType{
+		"jobs_total": pmetric.MetricTypeSum,
+		"queue_size": pmetric.MetricTypeGauge,
+		"latency":    pmetric.MetricTypeHistogram,
+		"gc":         pmetric.MetricTypeSummary,
+	}, types)
+
+	latency := metrics.At(2).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(2), latency.Count())
+	assert.Equal(t, 0.3, latency.Sum())
+	assert.Equal(t, []float64{0.1}, latency.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 1}, latency.BucketCounts().AsRaw())
+}
+
+func TestTranslateTrimSuffixes(t *testing.T) {
+	req := requestFromV1(&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series("http_server_duration_seconds_total", 3),
+		},
+		Metadata: []prompb.MetricMetadata{
+			{
+				Type:             prompb.MetricMetadata_COUNTER,
+				MetricFamilyName: "http_server_duration_seconds",
+				Unit:             "seconds",
+			},
+		},
+	})
+
+	md, _, err := translate(req, true)
+	require.NoError(t, err)
+	metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "http_server_duration", metric.Name())
+	assert.Equal(t, "s", metric.Unit())
+	assert.Equal(t, pmetric.MetricTypeSum, metric.Type())
+}
+
+func TestTranslateErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		req         *writev2.Request
+		expectedErr string
+	}{
+		{
+			name: "missing metric name",
+			req: &writev2.Request{
+				Symbols:    []string{"", "job", "api"},
+				Timeseries: []writev2.TimeSeries{{LabelsRefs: []uint32{1, 2}}},
+			},
+			expectedErr: "series without a metric name",
+		},
+		{
+			name: "odd number of label references",
+			req: &writev2.Request{
+				Symbols:    []string{"", "__name__"},
+				Timeseries: []writev2.TimeSeries{{LabelsRefs: []uint32{1}}},
+			},
+			expectedErr: "odd number of label references: 1",
+		},
+		{
+			name: "out of range symbol",
+			req: &writev2.Request{
+				Symbols:    []string{"", "__name__"},
+				Timeseries: []writev2.TimeSeries{{LabelsRefs: []uint32{1, 2}}},
+			},
+			expectedErr: "symbol reference 2 is out of range, the request has 2 symbols",
+		},
+		{
+			name: "invalid bucket bound",
+			req: &writev2.Request{
+				Symbols: []string{"", "__name__", "latency_bucket", "le", "high"},
+				Timeseries: []writev2.TimeSeries{{
+					LabelsRefs: []uint32{1, 2, 3, 4},
+					Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+				}},
+			},
+			expectedErr: `invalid bucket bound "high" of latency_bucket: strconv.ParseFloat: parsing "high": invalid syntax`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := translate(tt.req, false)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+// v1Series returns a remote write 1.0 series with a sample of the given value.
+func v1Series(name string, v float64, nameValues ...string) prompb.TimeSeries {
+	ts := prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "__name__", Value: name}},
+		Samples: []prompb.Sample{{Value: v, Timestamp: 1000}},
+	}
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: nameValues[i], Value: nameValues[i+1]})
+	}
+	return ts
+}
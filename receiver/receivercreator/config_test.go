@@ -118,6 +118,15 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "discovery"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.WatchObservers = []component.ID{component.MustNewID("mock_observer")}
+				cfg.Discovery = DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"redis", "nginx"}}
+				return cfg
+			}(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receivercreator // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/receivercreator"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	// hintsPrefix is the prefix of the annotations and labels endpoints configure their receivers with.
+	hintsPrefix = "io.opentelemetry.discovery.metrics"
+	// receiverHint is the type of the receiver to create for an endpoint not matched by any receiver template.
+	receiverHint = "receiver"
+	// configHint is the receiver config, merged over the config of the receiver template.
+	configHint = "config"
+	// resourceAttributesHint are the resource attributes, merged over the ones of the receiver template.
+	resourceAttributesHint = "resource_attributes"
+
+	// discoveredReceiverName is the name of the receivers created from the receiver hint.
+	discoveredReceiverName = "discovery"
+)
+
+// DiscoveryConfig configures how endpoints configure their own receivers with annotations or labels.
+type DiscoveryConfig struct {
+	// Enabled allows the endpoints to configure their receivers.
+	Enabled bool `mapstructure:"enabled"`
+	// AllowedReceivers are the types of the receivers endpoints are allowed to configure.
+	AllowedReceivers []string `mapstructure:"allowed_receivers"`
+}
+
+func (d *DiscoveryConfig) validate() error {
+	if !d.Enabled {
+		return nil
+	}
+	if len(d.AllowedReceivers) == 0 {
+		return errors.New("discovery requires at least one of `allowed_receivers`")
+	}
+	for _, t := range d.AllowedReceivers {
+		if _, err := component.NewType(t); err != nil {
+			return fmt.Errorf("invalid allowed receiver type %q: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// allows returns whether endpoints can configure the receivers of the given type.
+func (d *DiscoveryConfig) allows(t component.Type) bool {
+	if !d.Enabled {
+		return false
+	}
+	for _, allowed := range d.AllowedReceivers {
+		if allowed == t.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// hints are the receiver configuration an endpoint carries in its annotations or labels.
+type hints struct {
+	// receiverType is the type of the receiver to create when no receiver template matches, if any.
+	receiverType string
+	// config is merged over the config of the receiver template.
+	config userConfigMap
+	// resourceAttributes are merged over the resource attributes of the receiver template.
+	resourceAttributes map[string]any
+}
+
+func (h hints) empty() bool {
+	return h.receiverType == "" && len(h.config) == 0 && len(h.resourceAttributes) == 0
+}
+
+// endpointHints reads the hints of the endpoint: the annotations of pods, ports, services, ingresses and nodes, and the labels
+// of containers. For ports, the hints specific to the port (e.g. io.opentelemetry.discovery.metrics.8080/config)
+// take precedence over the ones of the pod.
+func endpointHints(e observer.Endpoint) (hints, error) {
+	var annotations map[string]string
+	var port uint16
+	switch details := e.Details.(type) {
+	case *observer.Pod:
+		annotations = details.Annotations
+	case *observer.Port:
+		annotations = details.Pod.Annotations
+		port = details.Port
+	case *observer.K8sService:
+		annotations = details.Annotations
+	case *observer.K8sIngress:
+		annotations = details.Annotations
+	case *observer.K8sNode:
+		annotations = details.Annotations
+	case *observer.Container:
+		annotations = details.Labels
+		port = details.Port
+	}
+
+	var h hints
+	if len(annotations) == 0 {
+		return h, nil
+	}
+	lookup := func(name string) (string, bool) {
+		if port != 0 {
+			if v, ok := annotations[fmt.Sprintf("%s.%d/%s", hintsPrefix, port, name)]; ok {
+				return v, true
+			}
+		}
+		v, ok := annotations[hintsPrefix+"/"+name]
+		return v, ok
+	}
+
+	h.receiverType, _ = lookup(receiverHint)
+	if v, ok := lookup(configHint); ok {
+		// nested maps must be plain maps to be merged
+		var cfg map[string]any
+		if err := yaml.Unmarshal([]byte(v), &cfg); err != nil {
+			return hints{}, fmt.Errorf("invalid %s/%s hint: %w", hintsPrefix, configHint, err)
+		}
+		h.config = cfg
+	}
+	if v, ok := lookup(resourceAttributesHint); ok {
+		if err := yaml.Unmarshal([]byte(v), &h.resourceAttributes); err != nil {
+			return hints{}, fmt.Errorf("invalid %s/%s hint: %w", hintsPrefix, resourceAttributesHint, err)
+		}
+		for k, v := range h.resourceAttributes {
+			if _, ok := v.(string); !ok {
+				return hints{}, fmt.Errorf("invalid %s/%s hint: unsupported value %v for %q", hintsPrefix, resourceAttributesHint, v, k)
+			}
+		}
+	}
+	return h, nil
+}
+
+// applyHints returns a copy of the receiver template with the config and resource attributes of the hints
+// merged over its own.
+func applyHints(template receiverTemplate, h hints) (receiverTemplate, error) {
+	if len(h.config) > 0 {
+		merged := confmap.NewFromStringMap(template.config)
+		if err := merged.Merge(confmap.NewFromStringMap(h.config)); err != nil {
+			return receiverTemplate{}, fmt.Errorf("failed to merge %s/%s hint: %w", hintsPrefix, configHint, err)
+		}
+		template.config = merged.ToStringMap()
+	}
+	if len(h.resourceAttributes) > 0 {
+		attrs := make(map[string]any, len(template.ResourceAttributes)+len(h.resourceAttributes))
+		for k, v := range template.ResourceAttributes {
+			attrs[k] = v
+		}
+		for k, v := range h.resourceAttributes {
+			attrs[k] = v
+		}
+		template.ResourceAttributes = attrs
+	}
+	return template, nil
+}
+
+// discoveredTemplate returns the receiver template of the receiver hint, for endpoints that can be scraped.
+func discoveredTemplate(e observer.Endpoint, h hints) (receiverTemplate, bool, error) {
+	if h.receiverType == "" {
+		return receiverTemplate{}, false, nil
+	}
+	switch e.Details.Type() {
+	case observer.PortType, observer.ContainerType:
+	default:
+		return receiverTemplate{}, false, nil
+	}
+
+	template, err := newReceiverTemplate(h.receiverType+"/"+discoveredReceiverName, userConfigMap{})
+	if err != nil {
+		return receiverTemplate{}, false, fmt.Errorf("invalid %s/%s hint: %w", hintsPrefix, receiverHint, err)
+	}
+	return template, true, nil
+}
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func portEndpointWithAnnotations(annotations map[string]string) observer.Endpoint {
+	p := pod
+	p.Annotations = annotations
+	return observer.Endpoint{
+		ID:     "port-1",
+		Target: "localhost:1234",
+		Details: &observer.Port{
+			Name:      "http",
+			Pod:       p,
+			Port:      1234,
+			Transport: observer.ProtocolTCP,
+		},
+	}
+}
+
+func TestDiscoveryConfigValidate(t *testing.T) {
+	assert.NoError(t, (&DiscoveryConfig{}).validate())
+	assert.NoError(t, (&DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"redis"}}).validate())
+	assert.EqualError(t, (&DiscoveryConfig{Enabled: true}).validate(), "discovery requires at least one of `allowed_receivers`")
+	assert.ErrorContains(t, (&DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"not a type"}}).validate(), `invalid allowed receiver type "not a type"`)
+
+	d := DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"redis"}}
+	assert.True(t, d.allows(component.MustNewType("redis")))
+	assert.False(t, d.allows(component.MustNewType("nginx")))
+	d.Enabled = false
+	assert.False(t, d.allows(component.MustNewType("redis")))
+}
+
+func TestEndpointHints(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoint    observer.Endpoint
+		expected    hints
+		expectedErr string
+	}{
+		{
+			name:     "no hints",
+			endpoint: portEndpoint,
+		},
+		{
+			name: "pod hints",
+			endpoint: portEndpointWithAnnotations(map[string]string{
+				"io.opentelemetry.discovery.metrics/receiver":            "redis",
+				"io.opentelemetry.discovery.metrics/config":              "collection_interval: 20s\ntls:\n  insecure: true",
+				"io.opentelemetry.discovery.metrics/resource_attributes": "service.name: '`pod.labels[\"app\"]`'",
+			}),
+			expected: hints{
+				receiverType:       "redis",
+				config:             userConfigMap{"collection_interval": "20s", "tls": map[string]any{"insecure": true}},
+				resourceAttributes: map[string]any{"service.name": "`pod.labels[\"app\"]`"},
+			},
+		},
+		{
+			name: "port hints take precedence",
+			endpoint: portEndpointWithAnnotations(map[string]string{
+				"io.opentelemetry.discovery.metrics/config":      "collection_interval: 20s",
+				"io.opentelemetry.discovery.metrics.1234/config": "collection_interval: 30s",
+				"io.opentelemetry.discovery.metrics.8080/config": "collection_interval: 40s",
+			}),
+			expected: hints{
+				config: userConfigMap{"collection_interval": "30s"},
+			},
+		},
+		{
+			name: "container labels",
+			endpoint: observer.Endpoint{
+				ID:     "container-1",
+				Target: "localhost:8080",
+				Details: &observer.Container{
+					Port: 8080,
+					Labels: map[string]string{
+						"io.opentelemetry.discovery.metrics/receiver": "redis",
+					},
+				},
+			},
+			expected: hints{receiverType: "redis"},
+		},
+		{
+			name: "invalid config",
+			endpoint: portEndpointWithAnnotations(map[string]string{
+				"io.opentelemetry.discovery.metrics/config": "- not a map",
+			}),
+			expectedErr: "invalid io.opentelemetry.discovery.metrics/config hint",
+		},
+		{
+			name: "invalid resource attributes",
+			endpoint: portEndpointWithAnnotations(map[string]string{
+				"io.opentelemetry.discovery.metrics/resource_attributes": "team:\n  name: a",
+			}),
+			expectedErr: `invalid io.opentelemetry.discovery.metrics/resource_attributes hint: unsupported value map[name:a] for "team"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := endpointHints(tt.endpoint)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, h)
+		})
+	}
+}
+
+func TestApplyHints(t *testing.T) {
+	template, err := newReceiverTemplate("redis/1", userConfigMap{
+		"collection_interval": "10s",
+		"tls":                 map[string]any{"insecure": false, "ca_file": "/ca.pem"},
+	})
+	require.NoError(t, err)
+	template.ResourceAttributes = map[string]any{"one": "two", "three": "four"}
+
+	hinted, err := applyHints(template, hints{
+		config:             userConfigMap{"tls": map[string]any{"insecure": true}},
+		resourceAttributes: map[string]any{"three": "five"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, userConfigMap{
+		"collection_interval": "10s",
+		"tls":                 map[string]any{"insecure": true, "ca_file": "/ca.pem"},
+	}, hinted.config)
+	assert.Equal(t, map[string]any{"one": "two", "three": "five"}, hinted.ResourceAttributes)
+
+	// the template is left untouched
+	assert.Equal(t, map[string]any{"insecure": false, "ca_file": "/ca.pem"}, template.config["tls"])
+	assert.Equal(t, map[string]any{"one": "two", "three": "four"}, template.ResourceAttributes)
+}
+
+func TestOnAddWithDiscovery(t *testing.T) {
+	endpoint := portEndpointWithAnnotations(map[string]string{
+		"io.opentelemetry.discovery.metrics/config":              "int_field: 20",
+		"io.opentelemetry.discovery.metrics/resource_attributes": "app: '`pod.labels[\"app\"]`'",
+	})
+	discoveredEndpoint := portEndpointWithAnnotations(map[string]string{
+		"io.opentelemetry.discovery.metrics/receiver": "with_endpoint",
+		"io.opentelemetry.discovery.metrics/config":   "int_field: 30",
+	})
+
+	tests := []struct {
+		name             string
+		discovery        DiscoveryConfig
+		endpoint         observer.Endpoint
+		withTemplate     bool
+		expectedConfig   *nopWithEndpointConfig
+		expectedAppLabel string
+	}{
+		{
+			name:           "disabled",
+			endpoint:       endpoint,
+			withTemplate:   true,
+			expectedConfig: &nopWithEndpointConfig{IntField: 10, Endpoint: "localhost:1234"},
+		},
+		{
+			name:           "receiver not allowed",
+			discovery:      DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"without_endpoint"}},
+			endpoint:       endpoint,
+			withTemplate:   true,
+			expectedConfig: &nopWithEndpointConfig{IntField: 10, Endpoint: "localhost:1234"},
+		},
+		{
+			name:             "merged over template",
+			discovery:        DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"with_endpoint"}},
+			endpoint:         endpoint,
+			withTemplate:     true,
+			expectedConfig:   &nopWithEndpointConfig{IntField: 20, Endpoint: "localhost:1234"},
+			expectedAppLabel: "redis",
+		},
+		{
+			name:           "discovered receiver",
+			discovery:      DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"with_endpoint"}},
+			endpoint:       discoveredEndpoint,
+			expectedConfig: &nopWithEndpointConfig{IntField: 30, Endpoint: "localhost:1234"},
+		},
+		{
+			name:      "discovered receiver not allowed",
+			discovery: DiscoveryConfig{Enabled: true, AllowedReceivers: []string{"without_endpoint"}},
+			endpoint:  discoveredEndpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Discovery = tt.discovery
+			if tt.withTemplate {
+				rcvrCfg := receiverConfig{
+					id:         component.MustNewIDWithName("with_endpoint", "some.name"),
+					config:     userConfigMap{"int_field": 10},
+					endpointID: tt.endpoint.ID,
+				}
+				cfg.receiverTemplates = map[string]receiverTemplate{
+					rcvrCfg.id.String(): {
+						receiverConfig:     rcvrCfg,
+						rule:               portRule,
+						Rule:               `type == "port"`,
+						ResourceAttributes: map[string]any{},
+					},
+				}
+			}
+
+			handler, mr := newObserverHandler(t, cfg, nil, consumertest.NewNop(), nil)
+			handler.OnAdd([]observer.Endpoint{tt.endpoint})
+
+			if tt.expectedConfig == nil {
+				assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+				return
+			}
+			assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+			require.NoError(t, mr.lastError)
+			wr, ok := mr.startedComponent.(*wrappedReceiver)
+			require.True(t, ok)
+			rcvr, ok := wr.metrics.(*nopWithEndpointReceiver)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedConfig, rcvr.cfg)
+
+			if tt.expectedAppLabel != "" {
+				consumer := rcvr.Metrics.(*enhancingConsumer)
+				assert.Equal(t, tt.expectedAppLabel, consumer.attrs["app"])
+			}
+		})
+	}
+}
@@ -45,6 +45,25 @@ var serviceEndpoint = observer.Endpoint{
 	Details: &service,
 }
 
+var ingress = observer.K8sIngress{
+	UID:       "uid-1",
+	Namespace: "default",
+	Name:      "ingress-1",
+	Labels: map[string]string{
+		"app":    "redis2",
+		"region": "west-1",
+	},
+	Scheme: "https",
+	Host:   "example.com",
+	Path:   "/metrics",
+}
+
+var ingressEndpoint = observer.Endpoint{
+	ID:      "ingress-1",
+	Target:  "https://example.com/metrics",
+	Details: &ingress,
+}
+
 var portEndpoint = observer.Endpoint{
 	ID:     "port-1",
 	Target: "localhost:1234",
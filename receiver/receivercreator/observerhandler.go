@@ -85,6 +85,15 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 
 		obs.params.TelemetrySettings.Logger.Debug("handling added endpoint", zap.Any("env", env))
 
+		var h hints
+		if obs.config.Discovery.Enabled {
+			if h, err = endpointHints(e); err != nil {
+				obs.params.TelemetrySettings.Logger.Error("ignoring invalid discovery hints", zap.String("endpoint", string(e.ID)), zap.Error(err))
+				h = hints{}
+			}
+		}
+
+		matchedTypes := map[component.Type]bool{}
 		for _, template := range obs.config.receiverTemplates {
 			if matches, e := template.rule.eval(env); e != nil {
 				obs.params.TelemetrySettings.Logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(e))
@@ -92,77 +101,108 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 			} else if !matches {
 				continue
 			}
+			matchedTypes[template.id.Type()] = true
 
-			obs.params.TelemetrySettings.Logger.Info("starting receiver",
-				zap.String("name", template.id.String()),
-				zap.String("endpoint", e.Target),
-				zap.String("endpoint_id", string(e.ID)))
-
-			resolvedConfig, err := expandConfig(template.config, env)
-			if err != nil {
-				obs.params.TelemetrySettings.Logger.Error("unable to resolve template config", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
-			}
-
-			discoveredCfg := userConfigMap{}
-			// If user didn't set endpoint set to default value as well as
-			// flag indicating we've done this for later validation.
-			if _, ok := resolvedConfig[endpointConfigKey]; !ok {
-				discoveredCfg[endpointConfigKey] = e.Target
-				discoveredCfg[tmpSetEndpointConfigKey] = struct{}{}
+			if !h.empty() && obs.config.Discovery.allows(template.id.Type()) {
+				hinted, err := applyHints(template, h)
+				if err != nil {
+					obs.params.TelemetrySettings.Logger.Error("unable to apply discovery hints", zap.String("receiver", template.id.String()), zap.Error(err))
+					continue
+				}
+				template = hinted
 			}
+			obs.startReceiver(template, env, e)
+		}
 
-			// Though not necessary with contrib provided observers, nothing is stopping custom
-			// ones from using expr in their Target values.
-			discoveredConfig, err := expandConfig(discoveredCfg, env)
+		// Endpoints can ask for a receiver no template creates for them.
+		template, ok, err := discoveredTemplate(e, h)
+		switch {
+		case err != nil:
+			obs.params.TelemetrySettings.Logger.Error("ignoring invalid discovery hints", zap.String("endpoint", string(e.ID)), zap.Error(err))
+		case !ok || matchedTypes[template.id.Type()]:
+		case !obs.config.Discovery.allows(template.id.Type()):
+			obs.params.TelemetrySettings.Logger.Warn("receiver type is not allowed to be discovered", zap.String("receiver", template.id.String()), zap.String("endpoint", string(e.ID)))
+		default:
+			hinted, err := applyHints(template, h)
 			if err != nil {
-				obs.params.TelemetrySettings.Logger.Error("unable to resolve discovered config", zap.String("receiver", template.id.String()), zap.Error(err))
+				obs.params.TelemetrySettings.Logger.Error("unable to apply discovery hints", zap.String("receiver", template.id.String()), zap.Error(err))
 				continue
 			}
+			obs.startReceiver(hinted, env, e)
+		}
+	}
+}
 
-			resAttrs := map[string]string{}
-			for k, v := range template.ResourceAttributes {
-				strVal, ok := v.(string)
-				if !ok {
-					obs.params.TelemetrySettings.Logger.Info(fmt.Sprintf("ignoring unsupported `resource_attributes` %q value %v", k, v))
-					continue
-				}
-				resAttrs[k] = strVal
-			}
+// startReceiver starts a receiver for the endpoint from the receiver template.
+func (obs *observerHandler) startReceiver(template receiverTemplate, env observer.EndpointEnv, e observer.Endpoint) {
+	obs.params.TelemetrySettings.Logger.Info("starting receiver",
+		zap.String("name", template.id.String()),
+		zap.String("endpoint", e.Target),
+		zap.String("endpoint_id", string(e.ID)))
+
+	resolvedConfig, err := expandConfig(template.config, env)
+	if err != nil {
+		obs.params.TelemetrySettings.Logger.Error("unable to resolve template config", zap.String("receiver", template.id.String()), zap.Error(err))
+		return
+	}
 
-			// Adds default and/or configured resource attributes (e.g. k8s.pod.uid) to resources
-			// as telemetry is emitted.
-			var consumer *enhancingConsumer
-			if consumer, err = newEnhancingConsumer(
-				obs.config.ResourceAttributes,
-				resAttrs,
-				env,
-				e,
-				obs.nextLogsConsumer,
-				obs.nextMetricsConsumer,
-				obs.nextTracesConsumer,
-			); err != nil {
-				obs.params.TelemetrySettings.Logger.Error("failed creating resource enhancer", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
-			}
+	discoveredCfg := userConfigMap{}
+	// If user didn't set endpoint set to default value as well as
+	// flag indicating we've done this for later validation.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredCfg[endpointConfigKey] = e.Target
+		discoveredCfg[tmpSetEndpointConfigKey] = struct{}{}
+	}
 
-			var receiver component.Component
-			if receiver, err = obs.runner.start(
-				receiverConfig{
-					id:         template.id,
-					config:     resolvedConfig,
-					endpointID: e.ID,
-				},
-				discoveredConfig,
-				consumer,
-			); err != nil {
-				obs.params.TelemetrySettings.Logger.Error("failed to start receiver", zap.String("receiver", template.id.String()), zap.Error(err))
-				continue
-			}
+	// Though not necessary with contrib provided observers, nothing is stopping custom
+	// ones from using expr in their Target values.
+	discoveredConfig, err := expandConfig(discoveredCfg, env)
+	if err != nil {
+		obs.params.TelemetrySettings.Logger.Error("unable to resolve discovered config", zap.String("receiver", template.id.String()), zap.Error(err))
+		return
+	}
 
-			obs.receiversByEndpointID.Put(e.ID, receiver)
+	resAttrs := map[string]string{}
+	for k, v := range template.ResourceAttributes {
+		strVal, ok := v.(string)
+		if !ok {
+			obs.params.TelemetrySettings.Logger.Info(fmt.Sprintf("ignoring unsupported `resource_attributes` %q value %v", k, v))
+			continue
 		}
+		resAttrs[k] = strVal
+	}
+
+	// Adds default and/or configured resource attributes (e.g. k8s.pod.uid) to resources
+	// as telemetry is emitted.
+	var consumer *enhancingConsumer
+	if consumer, err = newEnhancingConsumer(
+		obs.config.ResourceAttributes,
+		resAttrs,
+		env,
+		e,
+		obs.nextLogsConsumer,
+		obs.nextMetricsConsumer,
+		obs.nextTracesConsumer,
+	); err != nil {
+		obs.params.TelemetrySettings.Logger.Error("failed creating resource enhancer", zap.String("receiver", template.id.String()), zap.Error(err))
+		return
 	}
+
+	var receiver component.Component
+	if receiver, err = obs.runner.start(
+		receiverConfig{
+			id:         template.id,
+			config:     resolvedConfig,
+			endpointID: e.ID,
+		},
+		discoveredConfig,
+		consumer,
+	); err != nil {
+		obs.params.TelemetrySettings.Logger.Error("failed to start receiver", zap.String("receiver", template.id.String()), zap.Error(err))
+		return
+	}
+
+	obs.receiversByEndpointID.Put(e.ID, receiver)
 }
 
 // OnRemove responds to endpoint removal notifications.